@@ -23,6 +23,8 @@ const (
 	MD5AgentDeploymentAnnotationKey = "agent.datadoghq.com/agentspechash"
 	// MD5ChecksumAnnotationKey annotation key is used to identify customConfig configurations
 	MD5ChecksumAnnotationKey = "checksum/%s-custom-config"
+	// MD5ReferencedObjectsChecksumAnnotationKey annotation key is used to roll the pods when a Secret or a ConfigMap referenced in the DatadogAgent changes
+	MD5ReferencedObjectsChecksumAnnotationKey = "checksum/referenced-objects"
 	// MD5ChecksumAnnotationKey is part of the key name to identify custom seccomp configurations
 	MD5ChecksumSeccompProfileAnnotationName = "%s-seccomp"
 
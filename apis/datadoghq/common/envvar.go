@@ -125,6 +125,7 @@ const (
 	DDSBOMContainerImageAnalyzers                     = "DD_SBOM_CONTAINER_IMAGE_ANALYZERS"
 	DDSBOMHostEnabled                                 = "DD_SBOM_HOST_ENABLED"
 	DDSBOMHostAnalyzers                               = "DD_SBOM_HOST_ANALYZERS"
+	DDSecretBackendArgs                               = "DD_SECRET_BACKEND_ARGS"
 	DDSecretBackendCommand                            = "DD_SECRET_BACKEND_COMMAND"
 	DDSite                                            = "DD_SITE"
 	DDSystemProbeAgentEnabled                         = "DD_SYSTEM_PROBE_ENABLED"
@@ -0,0 +1,31 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+const (
+	// DatadogCredentialsSiteKey is the key of the Datadog site in a Secret holding Datadog credentials
+	DatadogCredentialsSiteKey = "site"
+	// DatadogCredentialsDefaultLabelKey is the label of the Secret holding the default Datadog credentials of a namespace
+	DatadogCredentialsDefaultLabelKey = "datadoghq.com/default-credentials"
+)
+
+// DatadogCredentialsReference references the Secret holding the Datadog credentials used to manage a resource in Datadog
+// +k8s:openapi-gen=true
+type DatadogCredentialsReference struct {
+	// SecretName is the name of a Secret of the namespace of the resource. The Secret holds the API key in the `api_key` key,
+	// the application key in the `app_key` key, and optionally the Datadog site, for example `datadoghq.eu`, in the `site` key.
+	SecretName string `json:"secretName"`
+}
+
+// DatadogCredentialsStatus records the credentials a resource was created with in Datadog, so that it is deleted with
+// the same credentials even if the spec or the default credentials of the namespace changed since
+// +k8s:openapi-gen=true
+type DatadogCredentialsStatus struct {
+	// SecretName is the name of the Secret holding the credentials, in the namespace of the resource.
+	// It is empty when the resource was created with the credentials of the operator.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
@@ -9,7 +9,9 @@ import (
 	commonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 type ComponentName string
@@ -23,10 +25,125 @@ const (
 type DatadogAgentProfileSpec struct {
 	ProfileAffinity *ProfileAffinity `json:"profileAffinity,omitempty"`
 	Config          *Config          `json:"config,omitempty"`
+
+	// Priority decides which profile takes precedence when several profiles
+	// match the same node: the profile with the highest priority wins. When two
+	// profiles have the same priority, the oldest one wins, and then the one
+	// whose name is alphabetically first.
+	// Default: 0
+	// +optional
+	Priority *int32 `json:"priority,omitempty"`
+
+	// ConflictMode defines what happens when the profile matches a node that a
+	// profile with precedence already applies to. With `Skip`, the profile is
+	// not applied. With `Merge`, the overrides of the profile are combined with
+	// the ones of the other profiles on these nodes, as long as they don't
+	// override the same settings. Otherwise, the profile is not applied.
+	// Valid values are `Skip` and `Merge`. Default: `Skip`
+	// +optional
+	ConflictMode ConflictMode `json:"conflictMode,omitempty"`
+
+	// RolloutPolicy limits how fast the nodes move to the Agent DaemonSet of the
+	// profile, or leave it. Without a rollout policy, the Agent pods of all the
+	// nodes are replaced at once when the profile applied on them changes.
+	// +optional
+	RolloutPolicy *ProfileRolloutPolicy `json:"rolloutPolicy,omitempty"`
+
+	// DatadogAgentRef is the DatadogAgent that the profile applies to. When
+	// neither DatadogAgentRef nor DatadogAgentSelector are set, the profile
+	// applies to all the DatadogAgents.
+	// +optional
+	DatadogAgentRef *DatadogAgentReference `json:"datadogAgentRef,omitempty"`
+
+	// DatadogAgentSelector selects the DatadogAgents that the profile applies
+	// to by their labels. It can't be used together with DatadogAgentRef.
+	// +optional
+	DatadogAgentSelector *metav1.LabelSelector `json:"datadogAgentSelector,omitempty"`
+}
+
+// DatadogAgentReference is a reference to a DatadogAgent.
+type DatadogAgentReference struct {
+	// Name is the name of the DatadogAgent.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the DatadogAgent.
+	// Default: the namespace of the profile
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ProfileRolloutPolicy defines how the nodes move from one profile to another.
+type ProfileRolloutPolicy struct {
+	// MaxUnavailable is the maximum number of nodes of the destination profile
+	// whose Agent pod can be unavailable while nodes move to it. Value can be an
+	// absolute number (ex: 5) or a percentage of the nodes of the destination
+	// profile (ex: 10%). The percentage is rounded up.
+	// Default: 1
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// MinReadySeconds is the minimum number of seconds for which the new Agent
+	// pod of a node must be ready, before the node is considered available and
+	// the next nodes can move.
+	// Default: 0
+	// +optional
+	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
 }
 
+// ConflictMode defines how a profile is applied when it conflicts with another profile.
+type ConflictMode string
+
+const (
+	// ConflictModeSkip doesn't apply a profile that conflicts with a profile with precedence.
+	ConflictModeSkip ConflictMode = "Skip"
+	// ConflictModeMerge combines the overrides of conflicting profiles when they don't overlap.
+	ConflictModeMerge ConflictMode = "Merge"
+)
+
+// ProfileAffinity selects the nodes of a profile. A node is selected when it
+// matches all the requirements that are defined.
 type ProfileAffinity struct {
+	// ProfileNodeAffinity is a list of requirements on the node labels.
 	ProfileNodeAffinity []corev1.NodeSelectorRequirement `json:"profileNodeAffinity,omitempty"`
+
+	// ProfileNodeAnnotations is a list of requirements on the node annotations.
+	// Valid operators are `In`, `NotIn`, `Exists` and `DoesNotExist`.
+	// +optional
+	ProfileNodeAnnotations []corev1.NodeSelectorRequirement `json:"profileNodeAnnotations,omitempty"`
+
+	// ProfileNodeTaints is a list of taints that the nodes must have. When the
+	// value of a taint is empty, any value matches. The Agent pods of the
+	// profile tolerate these taints.
+	// +optional
+	// +listType=atomic
+	ProfileNodeTaints []corev1.Taint `json:"profileNodeTaints,omitempty"`
+
+	// ProfileNodeResources defines the ranges of allocatable resources of the nodes.
+	// +optional
+	ProfileNodeResources *ProfileNodeResources `json:"profileNodeResources,omitempty"`
+}
+
+// ProfileNodeResources defines the ranges of allocatable CPU and memory of the nodes of a profile.
+type ProfileNodeResources struct {
+	// CPU is the range of allocatable CPU of the nodes.
+	// +optional
+	CPU *ResourceRange `json:"cpu,omitempty"`
+
+	// Memory is the range of allocatable memory of the nodes.
+	// +optional
+	Memory *ResourceRange `json:"memory,omitempty"`
+}
+
+// ResourceRange is a range of quantities of a resource. The minimum is
+// included and the maximum is excluded, so that ranges can follow each other.
+type ResourceRange struct {
+	// Min is the minimum quantity, included.
+	// +optional
+	Min *resource.Quantity `json:"min,omitempty"`
+
+	// Max is the maximum quantity, excluded.
+	// +optional
+	Max *resource.Quantity `json:"max,omitempty"`
 }
 
 type Config struct {
@@ -46,21 +163,124 @@ type Override struct {
 	// default.
 	// +optional
 	PriorityClassName *string `json:"priorityClassName,omitempty"`
+
+	// Specify additional environment variables for all the containers of the Agent.
+	// Priority is Container > Component.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Specify additional volumes in the Agent pods.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+
+	// Configure additional tolerations for the Agent pods.
+	// +optional
+	// +listType=atomic
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// NodeSelector is a selector which must be true for the Agent pods to fit on a node,
+	// in addition to the profile affinity.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Annotations provide annotations that are added to the Agent pods.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Features allows to turn off, on the nodes of the profile, some of the features enabled in the DatadogAgent.
+	// The keys are the feature identifiers, for example `npm`, `cws` or `log_collection`.
+	// A profile can't turn on a feature that is not enabled in the DatadogAgent.
+	// +optional
+	Features map[string]*FeatureToggle `json:"features,omitempty"`
+}
+
+// FeatureToggle turns a feature on or off.
+type FeatureToggle struct {
+	// Enabled turns the feature on or off. Only `false` is supported in a profile.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
 }
 
 type Container struct {
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Specify additional environment variables in the container.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Specify additional volume mounts in the container.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +listMapKey=mountPath
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+
+	// Args allows the specification of extra args to the container `Command`.
+	// +optional
+	// +listType=atomic
+	Args []string `json:"args,omitempty"`
 }
 
 // DatadogAgentProfileStatus defines the observed state of DatadogAgentProfile
 type DatadogAgentProfileStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Conditions represents the latest available observations of the state of the DatadogAgentProfile.
+	// The condition types are Valid, Applied and Conflict.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// MatchedNodes is the number of nodes that match the profile affinity.
+	// +optional
+	MatchedNodes int32 `json:"matchedNodes,omitempty"`
+
+	// MigratedNodes is the number of nodes on which the profile is applied, and
+	// that have moved to its Agent DaemonSet.
+	// +optional
+	MigratedNodes int32 `json:"migratedNodes,omitempty"`
+
+	// PendingNodes is the number of nodes on which the profile is applied, but
+	// that are still waiting to move to its Agent DaemonSet because of the
+	// rollout policy.
+	// +optional
+	PendingNodes int32 `json:"pendingNodes,omitempty"`
+
+	// DaemonSetNames are the names of the Agent DaemonSets generated for the profile,
+	// one per DatadogAgent on which it is applied.
+	// +optional
+	// +listType=set
+	DaemonSetNames []string `json:"daemonSetNames,omitempty"`
+
+	// ConflictingProfile is the namespace/name of the profile that takes precedence on some of the nodes
+	// matched by this profile. It is only set when the profile is in conflict.
+	// +optional
+	ConflictingProfile string `json:"conflictingProfile,omitempty"`
 }
 
+const (
+	// DatadogAgentProfileValidConditionType is true when the profile spec is valid.
+	DatadogAgentProfileValidConditionType = "Valid"
+	// DatadogAgentProfileAppliedConditionType is true when the profile is applied on the nodes it matches.
+	DatadogAgentProfileAppliedConditionType = "Applied"
+	// DatadogAgentProfileConflictConditionType is true when the profile conflicts with a profile that takes precedence.
+	DatadogAgentProfileConflictConditionType = "Conflict"
+)
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:path=datadogagentprofiles,shortName=dap
+//+kubebuilder:printcolumn:name="valid",type="string",JSONPath=".status.conditions[?(@.type=='Valid')].status"
+//+kubebuilder:printcolumn:name="applied",type="string",JSONPath=".status.conditions[?(@.type=='Applied')].status"
+//+kubebuilder:printcolumn:name="nodes",type="integer",JSONPath=".status.matchedNodes"
+//+kubebuilder:printcolumn:name="migrated",type="integer",JSONPath=".status.migratedNodes"
+//+kubebuilder:printcolumn:name="pending",type="integer",JSONPath=".status.pendingNodes"
+//+kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // DatadogAgentProfile is the Schema for the datadogagentprofiles API
 type DatadogAgentProfile struct {
@@ -7,6 +7,10 @@ package v1alpha1
 
 import (
 	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // ValidateDatadogAgentProfileSpec is used to check if a DatadogAgentProfileSpec is valid
@@ -15,11 +19,22 @@ func ValidateDatadogAgentProfileSpec(spec *DatadogAgentProfileSpec) error {
 	if spec.ProfileAffinity == nil {
 		return fmt.Errorf("profileAffinity must be defined")
 	}
-	if spec.ProfileAffinity.ProfileNodeAffinity == nil {
-		return fmt.Errorf("profileNodeAffinity must be defined")
+	if err := validateProfileAffinity(spec.ProfileAffinity); err != nil {
+		return err
+	}
+
+	switch spec.ConflictMode {
+	case "", ConflictModeSkip, ConflictModeMerge:
+	default:
+		return fmt.Errorf("conflictMode must be %s or %s", ConflictModeSkip, ConflictModeMerge)
 	}
-	if len(spec.ProfileAffinity.ProfileNodeAffinity) < 1 {
-		return fmt.Errorf("profileNodeAffinity must have at least 1 requirement")
+
+	if err := validateRolloutPolicy(spec.RolloutPolicy); err != nil {
+		return err
+	}
+
+	if err := validateDatadogAgentTarget(spec); err != nil {
+		return err
 	}
 
 	// validate config
@@ -29,21 +44,130 @@ func ValidateDatadogAgentProfileSpec(spec *DatadogAgentProfileSpec) error {
 	if spec.Config.Override == nil {
 		return fmt.Errorf("config override must be defined")
 	}
-	if spec.Config.Override[NodeAgentComponentName] == nil {
+	nodeAgentOverride := spec.Config.Override[NodeAgentComponentName]
+	if nodeAgentOverride == nil {
 		return fmt.Errorf("node agent override must be defined")
 	}
-	if spec.Config.Override[NodeAgentComponentName].Containers == nil {
+	for featureID, toggle := range nodeAgentOverride.Features {
+		if toggle != nil && toggle.Enabled != nil && *toggle.Enabled {
+			return fmt.Errorf("feature %s can't be enabled by a profile, only disabled", featureID)
+		}
+	}
+
+	// The container overrides are only required when the profile doesn't override any pod setting
+	if hasPodOverride(nodeAgentOverride) {
+		return nil
+	}
+	if nodeAgentOverride.Containers == nil {
 		return fmt.Errorf("node agent container must be defined")
 	}
-	containsAtLeastOneContainerResourceOverride := false
-	for _, container := range spec.Config.Override[NodeAgentComponentName].Containers {
-		if container.Resources != nil {
-			containsAtLeastOneContainerResourceOverride = true
+	containsAtLeastOneContainerOverride := false
+	for _, container := range nodeAgentOverride.Containers {
+		if container.Resources != nil || len(container.Env) > 0 || len(container.VolumeMounts) > 0 || container.Args != nil {
+			containsAtLeastOneContainerOverride = true
 		}
 	}
-	if !containsAtLeastOneContainerResourceOverride {
+	if !containsAtLeastOneContainerOverride {
 		return fmt.Errorf("at least one container resource must be defined")
 	}
 
 	return nil
 }
+
+// hasPodOverride returns true if the override defines settings at the pod level.
+func hasPodOverride(override *Override) bool {
+	return override.PriorityClassName != nil ||
+		len(override.Env) > 0 ||
+		len(override.Volumes) > 0 ||
+		len(override.Tolerations) > 0 ||
+		len(override.NodeSelector) > 0 ||
+		len(override.Annotations) > 0 ||
+		len(override.Features) > 0
+}
+
+// validateProfileAffinity checks that the profile affinity contains at least one requirement, and that the
+// requirements on the node annotations, taints and resources are valid.
+func validateProfileAffinity(affinity *ProfileAffinity) error {
+	// The node label requirements are only optional when the nodes are selected in another way
+	if len(affinity.ProfileNodeAnnotations) == 0 && len(affinity.ProfileNodeTaints) == 0 && affinity.ProfileNodeResources == nil {
+		if affinity.ProfileNodeAffinity == nil {
+			return fmt.Errorf("profileNodeAffinity must be defined")
+		}
+		if len(affinity.ProfileNodeAffinity) < 1 {
+			return fmt.Errorf("profileNodeAffinity must have at least 1 requirement")
+		}
+	}
+
+	for _, requirement := range affinity.ProfileNodeAnnotations {
+		switch requirement.Operator {
+		case corev1.NodeSelectorOpIn, corev1.NodeSelectorOpNotIn, corev1.NodeSelectorOpExists, corev1.NodeSelectorOpDoesNotExist:
+		default:
+			return fmt.Errorf("profileNodeAnnotations operator %s is not supported", requirement.Operator)
+		}
+	}
+
+	for _, taint := range affinity.ProfileNodeTaints {
+		if taint.Key == "" {
+			return fmt.Errorf("profileNodeTaints key must be defined")
+		}
+	}
+
+	if affinity.ProfileNodeResources != nil {
+		if err := validateResourceRange(corev1.ResourceCPU, affinity.ProfileNodeResources.CPU); err != nil {
+			return err
+		}
+		if err := validateResourceRange(corev1.ResourceMemory, affinity.ProfileNodeResources.Memory); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateResourceRange(resourceName corev1.ResourceName, resourceRange *ResourceRange) error {
+	if resourceRange == nil || resourceRange.Min == nil || resourceRange.Max == nil {
+		return nil
+	}
+	if resourceRange.Min.Cmp(*resourceRange.Max) >= 0 {
+		return fmt.Errorf("profileNodeResources %s min must be lower than max", resourceName)
+	}
+	return nil
+}
+
+// validateRolloutPolicy checks that the rollout policy lets at least one node move at a time.
+func validateRolloutPolicy(policy *ProfileRolloutPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	if policy.MinReadySeconds < 0 {
+		return fmt.Errorf("rolloutPolicy minReadySeconds must be greater than or equal to 0")
+	}
+	if policy.MaxUnavailable == nil {
+		return nil
+	}
+	// The percentage is applied on 100 nodes to check its format and its value
+	maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(policy.MaxUnavailable, 100, true)
+	if err != nil {
+		return fmt.Errorf("rolloutPolicy maxUnavailable is invalid: %w", err)
+	}
+	if maxUnavailable < 1 {
+		return fmt.Errorf("rolloutPolicy maxUnavailable must be greater than 0")
+	}
+	return nil
+}
+
+// validateDatadogAgentTarget checks that the profile targets the DatadogAgents either by reference or by selector.
+func validateDatadogAgentTarget(spec *DatadogAgentProfileSpec) error {
+	if spec.DatadogAgentRef != nil && spec.DatadogAgentSelector != nil {
+		return fmt.Errorf("datadogAgentRef and datadogAgentSelector can't be used together")
+	}
+	if spec.DatadogAgentRef != nil && spec.DatadogAgentRef.Name == "" {
+		return fmt.Errorf("datadogAgentRef name must be defined")
+	}
+	if spec.DatadogAgentSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(spec.DatadogAgentSelector); err != nil {
+			return fmt.Errorf("datadogAgentSelector is invalid: %w", err)
+		}
+	}
+	return nil
+}
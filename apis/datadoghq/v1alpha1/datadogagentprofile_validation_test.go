@@ -9,10 +9,13 @@ import (
 	"testing"
 
 	commonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func TestIsValidDatadogAgentProfile(t *testing.T) {
@@ -152,6 +155,83 @@ func TestIsValidDatadogAgentProfile(t *testing.T) {
 		ProfileAffinity: &ProfileAffinity{},
 	}
 	missingProfileAffinity := &DatadogAgentProfileSpec{}
+	validMergeConflictMode := valid.DeepCopy()
+	validMergeConflictMode.ConflictMode = ConflictModeMerge
+	invalidConflictMode := valid.DeepCopy()
+	invalidConflictMode.ConflictMode = "Replace"
+	validTolerationsOnly := missingContainer.DeepCopy()
+	validTolerationsOnly.Config.Override[NodeAgentComponentName].Tolerations = []corev1.Toleration{
+		{
+			Key:      "nvidia.com/gpu",
+			Operator: corev1.TolerationOpExists,
+		},
+	}
+	validArgsOnly := missingResources.DeepCopy()
+	validArgsOnly.Config.Override[NodeAgentComponentName].Containers[commonv1.CoreAgentContainerName].Args = []string{"run"}
+	validDisabledFeature := missingContainer.DeepCopy()
+	validDisabledFeature.Config.Override[NodeAgentComponentName].Features = map[string]*FeatureToggle{
+		"npm": {Enabled: apiutils.NewBoolPointer(false)},
+	}
+	invalidEnabledFeature := valid.DeepCopy()
+	invalidEnabledFeature.Config.Override[NodeAgentComponentName].Features = map[string]*FeatureToggle{
+		"npm": {Enabled: apiutils.NewBoolPointer(true)},
+	}
+	validRolloutPolicy := valid.DeepCopy()
+	validRolloutPolicy.RolloutPolicy = &ProfileRolloutPolicy{
+		MaxUnavailable:  &intstr.IntOrString{Type: intstr.String, StrVal: "10%"},
+		MinReadySeconds: 30,
+	}
+	invalidMaxUnavailable := valid.DeepCopy()
+	invalidMaxUnavailable.RolloutPolicy = &ProfileRolloutPolicy{
+		MaxUnavailable: &intstr.IntOrString{Type: intstr.Int, IntVal: 0},
+	}
+	invalidMaxUnavailablePercentage := valid.DeepCopy()
+	invalidMaxUnavailablePercentage.RolloutPolicy = &ProfileRolloutPolicy{
+		MaxUnavailable: &intstr.IntOrString{Type: intstr.String, StrVal: "ten"},
+	}
+	invalidMinReadySeconds := valid.DeepCopy()
+	invalidMinReadySeconds.RolloutPolicy = &ProfileRolloutPolicy{
+		MinReadySeconds: -1,
+	}
+	validDatadogAgentRef := valid.DeepCopy()
+	validDatadogAgentRef.DatadogAgentRef = &DatadogAgentReference{Name: "datadog"}
+	invalidDatadogAgentRef := valid.DeepCopy()
+	invalidDatadogAgentRef.DatadogAgentRef = &DatadogAgentReference{Namespace: "datadog"}
+	validDatadogAgentSelector := valid.DeepCopy()
+	validDatadogAgentSelector.DatadogAgentSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"team": "infra"}}
+	invalidDatadogAgentSelector := valid.DeepCopy()
+	invalidDatadogAgentSelector.DatadogAgentSelector = &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Equals"}},
+	}
+	invalidDatadogAgentRefAndSelector := validDatadogAgentSelector.DeepCopy()
+	invalidDatadogAgentRefAndSelector.DatadogAgentRef = &DatadogAgentReference{Name: "datadog"}
+	validTaintsOnly := valid.DeepCopy()
+	validTaintsOnly.ProfileAffinity = &ProfileAffinity{
+		ProfileNodeTaints: []corev1.Taint{{Key: "dedicated", Value: "gpu"}},
+	}
+	invalidTaint := valid.DeepCopy()
+	invalidTaint.ProfileAffinity.ProfileNodeTaints = []corev1.Taint{{Value: "gpu"}}
+	validResourcesOnly := valid.DeepCopy()
+	validResourcesOnly.ProfileAffinity = &ProfileAffinity{
+		ProfileNodeResources: &ProfileNodeResources{
+			CPU:    &ResourceRange{Min: resource.NewQuantity(16, resource.DecimalSI)},
+			Memory: &ResourceRange{Min: resource.NewQuantity(1<<30, resource.BinarySI), Max: resource.NewQuantity(1<<36, resource.BinarySI)},
+		},
+	}
+	invalidResourceRange := valid.DeepCopy()
+	invalidResourceRange.ProfileAffinity.ProfileNodeResources = &ProfileNodeResources{
+		CPU: &ResourceRange{Min: resource.NewQuantity(16, resource.DecimalSI), Max: resource.NewQuantity(8, resource.DecimalSI)},
+	}
+	validAnnotationsOnly := valid.DeepCopy()
+	validAnnotationsOnly.ProfileAffinity = &ProfileAffinity{
+		ProfileNodeAnnotations: []corev1.NodeSelectorRequirement{
+			{Key: "example.com/pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"large nodes"}},
+		},
+	}
+	invalidAnnotationOperator := valid.DeepCopy()
+	invalidAnnotationOperator.ProfileAffinity.ProfileNodeAnnotations = []corev1.NodeSelectorRequirement{
+		{Key: "example.com/cores", Operator: corev1.NodeSelectorOpGt, Values: []string{"8"}},
+	}
 
 	testCases := []struct {
 		name    string
@@ -206,6 +286,101 @@ func TestIsValidDatadogAgentProfile(t *testing.T) {
 			spec:    missingProfileAffinity,
 			wantErr: "profileAffinity must be defined",
 		},
+		{
+			name: "valid dap, merge conflict mode",
+			spec: validMergeConflictMode,
+		},
+		{
+			name:    "invalid conflict mode",
+			spec:    invalidConflictMode,
+			wantErr: "conflictMode must be Skip or Merge",
+		},
+		{
+			name: "valid dap, tolerations only",
+			spec: validTolerationsOnly,
+		},
+		{
+			name: "valid dap, container args only",
+			spec: validArgsOnly,
+		},
+		{
+			name: "valid dap, disabled feature only",
+			spec: validDisabledFeature,
+		},
+		{
+			name:    "enabled feature",
+			spec:    invalidEnabledFeature,
+			wantErr: "feature npm can't be enabled by a profile, only disabled",
+		},
+		{
+			name: "valid dap, rollout policy",
+			spec: validRolloutPolicy,
+		},
+		{
+			name:    "invalid rollout policy max unavailable",
+			spec:    invalidMaxUnavailable,
+			wantErr: "rolloutPolicy maxUnavailable must be greater than 0",
+		},
+		{
+			name:    "invalid rollout policy max unavailable percentage",
+			spec:    invalidMaxUnavailablePercentage,
+			wantErr: "rolloutPolicy maxUnavailable is invalid: invalid value for IntOrString: invalid type: string is not a percentage",
+		},
+		{
+			name:    "invalid rollout policy min ready seconds",
+			spec:    invalidMinReadySeconds,
+			wantErr: "rolloutPolicy minReadySeconds must be greater than or equal to 0",
+		},
+		{
+			name: "valid dap, taints only",
+			spec: validTaintsOnly,
+		},
+		{
+			name:    "taint without key",
+			spec:    invalidTaint,
+			wantErr: "profileNodeTaints key must be defined",
+		},
+		{
+			name: "valid dap, resources only",
+			spec: validResourcesOnly,
+		},
+		{
+			name:    "invalid resource range",
+			spec:    invalidResourceRange,
+			wantErr: "profileNodeResources cpu min must be lower than max",
+		},
+		{
+			name: "valid dap, annotations only",
+			spec: validAnnotationsOnly,
+		},
+		{
+			name:    "invalid annotation operator",
+			spec:    invalidAnnotationOperator,
+			wantErr: "profileNodeAnnotations operator Gt is not supported",
+		},
+		{
+			name: "valid dap, datadog agent ref",
+			spec: validDatadogAgentRef,
+		},
+		{
+			name:    "datadog agent ref without name",
+			spec:    invalidDatadogAgentRef,
+			wantErr: "datadogAgentRef name must be defined",
+		},
+		{
+			name: "valid dap, datadog agent selector",
+			spec: validDatadogAgentSelector,
+		},
+		{
+			name:    "invalid datadog agent selector",
+			spec:    invalidDatadogAgentSelector,
+			wantErr: "datadogAgentSelector is invalid: \"Equals\" is not a valid pod selector operator",
+		},
+		{
+			name:    "datadog agent ref and selector",
+			spec:    invalidDatadogAgentRefAndSelector,
+			wantErr: "datadogAgentRef and datadogAgentSelector can't be used together",
+		},
 	}
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
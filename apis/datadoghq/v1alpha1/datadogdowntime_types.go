@@ -0,0 +1,199 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DatadogDowntimeSpec defines the desired state of a DatadogDowntime.
+// +k8s:openapi-gen=true
+type DatadogDowntimeSpec struct {
+	// Scope is the list of scopes to which the downtime applies, in key:value format, for example host:app2.
+	// The downtime applies to the sources that match all the scopes. Defaults to all the sources (*).
+	// +listType=set
+	Scope []string `json:"scope,omitempty"`
+
+	// MonitorSelector selects the monitors to which the downtime applies. Defaults to all the monitors.
+	MonitorSelector *DatadogDowntimeMonitorSelector `json:"monitorSelector,omitempty"`
+
+	// Message is a message to include with the notifications of the downtime.
+	Message string `json:"message,omitempty"`
+
+	// Schedule defines when the downtime is in effect.
+	Schedule DatadogDowntimeSchedule `json:"schedule,omitempty"`
+}
+
+// DatadogDowntimeMonitorSelector selects the monitors to which a downtime applies.
+// Only one of its fields can be defined.
+// +k8s:openapi-gen=true
+type DatadogDowntimeMonitorSelector struct {
+	// ID is the ID of a Datadog monitor.
+	ID int64 `json:"id,omitempty"`
+
+	// Tags is a list of monitor tags. The downtime applies to the monitors that have all the tags.
+	// +listType=set
+	Tags []string `json:"tags,omitempty"`
+
+	// DatadogMonitorSelector is a label selector of the DatadogMonitors of the namespace of the DatadogDowntime.
+	// The downtime applies to their monitors, a downtime is created in Datadog for each monitor.
+	DatadogMonitorSelector *metav1.LabelSelector `json:"datadogMonitorSelector,omitempty"`
+}
+
+// DatadogDowntimeSchedule defines when a downtime is in effect.
+// +k8s:openapi-gen=true
+type DatadogDowntimeSchedule struct {
+	// Start is the time the downtime starts. Defaults to the time the downtime is created. It is required with a recurrence.
+	Start *metav1.Time `json:"start,omitempty"`
+
+	// End is the time the downtime ends. Without end, the downtime is in effect until the DatadogDowntime is deleted.
+	// For a recurring downtime, start and end define the first occurrence of the downtime.
+	End *metav1.Time `json:"end,omitempty"`
+
+	// Timezone is the timezone of the downtime, for example Europe/Paris. Defaults to UTC.
+	Timezone string `json:"timezone,omitempty"`
+
+	// Recurrence makes the downtime recurring. Without recurrence, the downtime is a one-off downtime.
+	Recurrence *DatadogDowntimeRecurrence `json:"recurrence,omitempty"`
+}
+
+// DatadogDowntimeRecurrence defines the recurrence of a downtime.
+// +k8s:openapi-gen=true
+type DatadogDowntimeRecurrence struct {
+	// Type is the type of recurrence: days, weeks, months, years or rrule.
+	Type DatadogDowntimeRecurrenceType `json:"type"`
+
+	// Period is how often the downtime is repeated, for example every 3 days with the days type and a period of 3.
+	// It isn't used with the rrule type.
+	Period int32 `json:"period,omitempty"`
+
+	// WeekDays is the list of week days on which the downtime is repeated: Mon, Tue, Wed, Thu, Fri, Sat or Sun.
+	// It is only used with the weeks type.
+	// +listType=set
+	WeekDays []string `json:"weekDays,omitempty"`
+
+	// RRule is the recurrence rule of the downtime in the iCalendar RRULE format, for example FREQ=MONTHLY;BYMONTHDAY=1.
+	// It is required with the rrule type.
+	RRule string `json:"rrule,omitempty"`
+
+	// UntilDate is the time after which the downtime isn't repeated anymore.
+	UntilDate *metav1.Time `json:"untilDate,omitempty"`
+
+	// UntilOccurrences is the number of times the downtime is repeated.
+	UntilOccurrences *int32 `json:"untilOccurrences,omitempty"`
+}
+
+// DatadogDowntimeRecurrenceType is the type of recurrence of a downtime.
+type DatadogDowntimeRecurrenceType string
+
+const (
+	// DatadogDowntimeRecurrenceTypeDays repeats the downtime every period days.
+	DatadogDowntimeRecurrenceTypeDays DatadogDowntimeRecurrenceType = "days"
+	// DatadogDowntimeRecurrenceTypeWeeks repeats the downtime every period weeks.
+	DatadogDowntimeRecurrenceTypeWeeks DatadogDowntimeRecurrenceType = "weeks"
+	// DatadogDowntimeRecurrenceTypeMonths repeats the downtime every period months.
+	DatadogDowntimeRecurrenceTypeMonths DatadogDowntimeRecurrenceType = "months"
+	// DatadogDowntimeRecurrenceTypeYears repeats the downtime every period years.
+	DatadogDowntimeRecurrenceTypeYears DatadogDowntimeRecurrenceType = "years"
+	// DatadogDowntimeRecurrenceTypeRRule repeats the downtime according to a recurrence rule.
+	DatadogDowntimeRecurrenceTypeRRule DatadogDowntimeRecurrenceType = "rrule"
+)
+
+// IsValid returns true if the recurrence type is supported.
+func (t DatadogDowntimeRecurrenceType) IsValid() bool {
+	switch t {
+	case DatadogDowntimeRecurrenceTypeDays, DatadogDowntimeRecurrenceTypeWeeks, DatadogDowntimeRecurrenceTypeMonths, DatadogDowntimeRecurrenceTypeYears, DatadogDowntimeRecurrenceTypeRRule:
+		return true
+	default:
+		return false
+	}
+}
+
+// DatadogDowntimeStatus defines the observed state of a DatadogDowntime.
+// +k8s:openapi-gen=true
+type DatadogDowntimeStatus struct {
+	// Conditions represents the latest available observations of the state of a DatadogDowntime.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ID is the downtime ID generated in Datadog.
+	// It isn't set when the downtime applies to DatadogMonitors, see MonitorDowntimes.
+	ID string `json:"id,omitempty"`
+
+	// MonitorDowntimes are the downtimes generated in Datadog for the monitors of the DatadogMonitors
+	// selected by the monitor selector, one downtime per monitor.
+	// +listType=map
+	// +listMapKey=monitorID
+	MonitorDowntimes []DatadogDowntimeMonitorDowntime `json:"monitorDowntimes,omitempty"`
+
+	// Active is true when the downtime is currently in effect.
+	Active bool `json:"active,omitempty"`
+
+	// SyncStatus shows the health of syncing the downtime to Datadog.
+	SyncStatus DatadogDowntimeSyncStatus `json:"syncStatus,omitempty"`
+
+	// LastForceSyncTime is the last time the API downtime was last force synced with the DatadogDowntime resource.
+	LastForceSyncTime *metav1.Time `json:"lastForceSyncTime,omitempty"`
+
+	// CurrentHash tracks the hash of the current DatadogDowntimeSpec to know
+	// if the Spec has changed and needs an update.
+	CurrentHash string `json:"currentHash,omitempty"`
+}
+
+// DatadogDowntimeMonitorDowntime is the downtime generated in Datadog for the monitor of a DatadogMonitor.
+// +k8s:openapi-gen=true
+type DatadogDowntimeMonitorDowntime struct {
+	// MonitorID is the ID of the monitor.
+	MonitorID int `json:"monitorID"`
+
+	// ID is the downtime ID generated in Datadog.
+	ID string `json:"id"`
+}
+
+// DatadogDowntimeSyncStatus is the message reflecting the health of downtime syncs to Datadog.
+type DatadogDowntimeSyncStatus string
+
+const (
+	// DatadogDowntimeSyncStatusOK means syncing is OK.
+	DatadogDowntimeSyncStatusOK DatadogDowntimeSyncStatus = "OK"
+	// DatadogDowntimeSyncStatusValidateError means there is a downtime validation error.
+	DatadogDowntimeSyncStatusValidateError DatadogDowntimeSyncStatus = "error validating downtime"
+	// DatadogDowntimeSyncStatusSelectError means there is an error selecting the DatadogMonitors of the downtime.
+	DatadogDowntimeSyncStatusSelectError DatadogDowntimeSyncStatus = "error selecting monitors"
+	// DatadogDowntimeSyncStatusSyncError means there is an error creating, updating or getting the downtime.
+	DatadogDowntimeSyncStatusSyncError DatadogDowntimeSyncStatus = "error syncing downtime"
+)
+
+// DatadogDowntime allows a user to define and manage Datadog downtimes from Kubernetes cluster.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=datadogdowntimes,scope=Namespaced,shortName=dddt
+// +kubebuilder:printcolumn:name="id",type="string",JSONPath=".status.id"
+// +kubebuilder:printcolumn:name="active",type="boolean",JSONPath=".status.active"
+// +kubebuilder:printcolumn:name="sync status",type="string",JSONPath=".status.syncStatus"
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// +k8s:openapi-gen=true
+// +genclient
+type DatadogDowntime struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DatadogDowntimeSpec   `json:"spec,omitempty"`
+	Status DatadogDowntimeStatus `json:"status,omitempty"`
+}
+
+// DatadogDowntimeList contains a list of DatadogDowntimes.
+// +kubebuilder:object:root=true
+type DatadogDowntimeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatadogDowntime `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatadogDowntime{}, &DatadogDowntimeList{})
+}
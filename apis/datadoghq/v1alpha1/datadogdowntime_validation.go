@@ -0,0 +1,98 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilserrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+var downtimeWeekDays = map[string]bool{
+	"Mon": true,
+	"Tue": true,
+	"Wed": true,
+	"Thu": true,
+	"Fri": true,
+	"Sat": true,
+	"Sun": true,
+}
+
+// IsValidDatadogDowntime use to check if a DatadogDowntimeSpec is valid by checking
+// the monitor selector and the schedule
+func IsValidDatadogDowntime(spec *DatadogDowntimeSpec) error {
+	var errs []error
+
+	if selector := spec.MonitorSelector; selector != nil {
+		defined := 0
+		if selector.ID != 0 {
+			defined++
+		}
+		if len(selector.Tags) > 0 {
+			defined++
+		}
+		if selector.DatadogMonitorSelector != nil {
+			defined++
+			if _, err := metav1.LabelSelectorAsSelector(selector.DatadogMonitorSelector); err != nil {
+				errs = append(errs, fmt.Errorf("spec.MonitorSelector.DatadogMonitorSelector is invalid: %w", err))
+			}
+		}
+		if defined > 1 {
+			errs = append(errs, fmt.Errorf("only one of spec.MonitorSelector.ID, spec.MonitorSelector.Tags and spec.MonitorSelector.DatadogMonitorSelector can be defined"))
+		}
+	}
+
+	schedule := spec.Schedule
+	if schedule.Start != nil && schedule.End != nil && !schedule.End.After(schedule.Start.Time) {
+		errs = append(errs, fmt.Errorf("spec.Schedule.End must be after spec.Schedule.Start"))
+	}
+
+	if schedule.Timezone != "" {
+		if _, err := time.LoadLocation(schedule.Timezone); err != nil {
+			errs = append(errs, fmt.Errorf("spec.Schedule.Timezone is invalid: %w", err))
+		}
+	}
+
+	if recurrence := schedule.Recurrence; recurrence != nil {
+		if schedule.Start == nil {
+			errs = append(errs, fmt.Errorf("spec.Schedule.Start must be defined when spec.Schedule.Recurrence is defined"))
+		}
+		if schedule.End == nil {
+			errs = append(errs, fmt.Errorf("spec.Schedule.End must be defined when spec.Schedule.Recurrence is defined"))
+		}
+
+		if !recurrence.Type.IsValid() {
+			errs = append(errs, fmt.Errorf("spec.Schedule.Recurrence.Type must be one of the values: %s, %s, %s, %s or %s", DatadogDowntimeRecurrenceTypeDays, DatadogDowntimeRecurrenceTypeWeeks, DatadogDowntimeRecurrenceTypeMonths, DatadogDowntimeRecurrenceTypeYears, DatadogDowntimeRecurrenceTypeRRule))
+		}
+
+		if recurrence.Type == DatadogDowntimeRecurrenceTypeRRule && recurrence.RRule == "" {
+			errs = append(errs, fmt.Errorf("spec.Schedule.Recurrence.RRule must be defined when spec.Schedule.Recurrence.Type is rrule"))
+		}
+
+		if recurrence.Type != DatadogDowntimeRecurrenceTypeRRule && recurrence.Period < 1 {
+			errs = append(errs, fmt.Errorf("spec.Schedule.Recurrence.Period must be greater than 0"))
+		}
+
+		for _, day := range recurrence.WeekDays {
+			if !downtimeWeekDays[day] {
+				errs = append(errs, fmt.Errorf("spec.Schedule.Recurrence.WeekDays must only contain the values: Mon, Tue, Wed, Thu, Fri, Sat or Sun"))
+				break
+			}
+		}
+
+		if len(recurrence.WeekDays) > 0 && recurrence.Type != DatadogDowntimeRecurrenceTypeWeeks {
+			errs = append(errs, fmt.Errorf("spec.Schedule.Recurrence.WeekDays can only be defined when spec.Schedule.Recurrence.Type is weeks"))
+		}
+
+		if recurrence.UntilDate != nil && recurrence.UntilOccurrences != nil {
+			errs = append(errs, fmt.Errorf("spec.Schedule.Recurrence.UntilDate and spec.Schedule.Recurrence.UntilOccurrences can't be defined together"))
+		}
+	}
+
+	return utilserrors.NewAggregate(errs)
+}
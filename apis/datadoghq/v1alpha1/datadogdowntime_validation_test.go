@@ -0,0 +1,172 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsValidDatadogDowntime(t *testing.T) {
+	start := metav1.NewTime(time.Date(2023, 5, 1, 22, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(2 * time.Hour))
+	occurrences := int32(4)
+
+	tests := []struct {
+		name     string
+		spec     *DatadogDowntimeSpec
+		expected error
+	}{
+		{
+			name:     "Valid empty spec, downtime of all the monitors starting now",
+			spec:     &DatadogDowntimeSpec{},
+			expected: nil,
+		},
+		{
+			name: "Valid recurring downtime of DatadogMonitors",
+			spec: &DatadogDowntimeSpec{
+				Scope: []string{"env:staging"},
+				MonitorSelector: &DatadogDowntimeMonitorSelector{
+					DatadogMonitorSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "foo"}},
+				},
+				Schedule: DatadogDowntimeSchedule{
+					Start: &start,
+					End:   &end,
+					Recurrence: &DatadogDowntimeRecurrence{
+						Type:             DatadogDowntimeRecurrenceTypeWeeks,
+						Period:           1,
+						WeekDays:         []string{"Mon", "Fri"},
+						UntilOccurrences: &occurrences,
+					},
+				},
+			},
+			expected: nil,
+		},
+		{
+			name: "Valid rrule recurrence",
+			spec: &DatadogDowntimeSpec{
+				Schedule: DatadogDowntimeSchedule{
+					Start: &start,
+					End:   &end,
+					Recurrence: &DatadogDowntimeRecurrence{
+						Type:  DatadogDowntimeRecurrenceTypeRRule,
+						RRule: "FREQ=MONTHLY;BYMONTHDAY=1",
+					},
+				},
+			},
+			expected: nil,
+		},
+		{
+			name: "Several monitor selectors",
+			spec: &DatadogDowntimeSpec{
+				MonitorSelector: &DatadogDowntimeMonitorSelector{
+					ID:   1234,
+					Tags: []string{"team:foo"},
+				},
+			},
+			expected: errors.New("only one of spec.MonitorSelector.ID, spec.MonitorSelector.Tags and spec.MonitorSelector.DatadogMonitorSelector can be defined"),
+		},
+		{
+			name: "Invalid DatadogMonitor selector",
+			spec: &DatadogDowntimeSpec{
+				MonitorSelector: &DatadogDowntimeMonitorSelector{
+					DatadogMonitorSelector: &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Unknown"}},
+					},
+				},
+			},
+			expected: errors.New("spec.MonitorSelector.DatadogMonitorSelector is invalid: \"Unknown\" is not a valid pod selector operator"),
+		},
+		{
+			name: "End before start",
+			spec: &DatadogDowntimeSpec{
+				Schedule: DatadogDowntimeSchedule{Start: &end, End: &start},
+			},
+			expected: errors.New("spec.Schedule.End must be after spec.Schedule.Start"),
+		},
+		{
+			name: "Invalid recurrence",
+			spec: &DatadogDowntimeSpec{
+				Schedule: DatadogDowntimeSchedule{
+					Start: &start,
+					Recurrence: &DatadogDowntimeRecurrence{
+						Type:             DatadogDowntimeRecurrenceTypeDays,
+						WeekDays:         []string{"Monday"},
+						UntilDate:        &end,
+						UntilOccurrences: &occurrences,
+					},
+				},
+			},
+			expected: errors.New("[spec.Schedule.End must be defined when spec.Schedule.Recurrence is defined, " +
+				"spec.Schedule.Recurrence.Period must be greater than 0, " +
+				"spec.Schedule.Recurrence.WeekDays must only contain the values: Mon, Tue, Wed, Thu, Fri, Sat or Sun, " +
+				"spec.Schedule.Recurrence.WeekDays can only be defined when spec.Schedule.Recurrence.Type is weeks, " +
+				"spec.Schedule.Recurrence.UntilDate and spec.Schedule.Recurrence.UntilOccurrences can't be defined together]"),
+		},
+		{
+			name: "Invalid recurrence type",
+			spec: &DatadogDowntimeSpec{
+				Schedule: DatadogDowntimeSchedule{
+					Start: &start,
+					End:   &end,
+					Recurrence: &DatadogDowntimeRecurrence{
+						Type:   "hours",
+						Period: 1,
+					},
+				},
+			},
+			expected: errors.New("spec.Schedule.Recurrence.Type must be one of the values: days, weeks, months, years or rrule"),
+		},
+		{
+			name: "Missing rrule",
+			spec: &DatadogDowntimeSpec{
+				Schedule: DatadogDowntimeSchedule{
+					Start: &start,
+					End:   &end,
+					Recurrence: &DatadogDowntimeRecurrence{
+						Type: DatadogDowntimeRecurrenceTypeRRule,
+					},
+				},
+			},
+			expected: errors.New("spec.Schedule.Recurrence.RRule must be defined when spec.Schedule.Recurrence.Type is rrule"),
+		},
+		{
+			name: "Recurrence without start",
+			spec: &DatadogDowntimeSpec{
+				Schedule: DatadogDowntimeSchedule{
+					End: &end,
+					Recurrence: &DatadogDowntimeRecurrence{
+						Type:  DatadogDowntimeRecurrenceTypeRRule,
+						RRule: "FREQ=MONTHLY;BYMONTHDAY=1",
+					},
+				},
+			},
+			expected: errors.New("spec.Schedule.Start must be defined when spec.Schedule.Recurrence is defined"),
+		},
+		{
+			name: "Invalid timezone",
+			spec: &DatadogDowntimeSpec{
+				Schedule: DatadogDowntimeSchedule{Timezone: "Europe/Nowhere"},
+			},
+			expected: errors.New("spec.Schedule.Timezone is invalid: unknown time zone Europe/Nowhere"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := IsValidDatadogDowntime(tt.spec)
+			if tt.expected != nil {
+				assert.EqualError(t, result, tt.expected.Error())
+			} else {
+				assert.Nil(t, result)
+			}
+		})
+	}
+}
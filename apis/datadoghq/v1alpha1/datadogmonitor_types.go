@@ -19,7 +19,8 @@ type DatadogMonitorSpec struct {
 	Message string `json:"message,omitempty"`
 	// Priority is an integer from 1 (high) to 5 (low) indicating alert severity
 	Priority int64 `json:"priority,omitempty"`
-	// Query is the Datadog monitor query
+	// Query is the Datadog monitor query. The query of a composite monitor can refer to other DatadogMonitors
+	// with ${name} or ${namespace/name}, they are replaced by the IDs of their monitors.
 	Query string `json:"query,omitempty"`
 	// RestrictedRoles is a list of unique role identifiers to define which roles are allowed to edit the monitor.
 	// `restricted_roles` is the successor of `locked`. For more information about `locked` and `restricted_roles`,
@@ -36,8 +37,25 @@ type DatadogMonitorSpec struct {
 
 	// ControllerOptions are the optional parameters in the DatadogMonitor controller
 	ControllerOptions DatadogMonitorControllerOptions `json:"controllerOptions,omitempty"`
+
+	// AdoptID is the ID of an existing Datadog monitor that the DatadogMonitor takes ownership of, instead of creating a new monitor.
+	// A monitor can only be adopted by one DatadogMonitor. The adopted monitor isn't modified while AdoptID is set: its differences
+	// with the spec are reported in the Adopted condition, and overwritten once AdoptID is removed. It can also be set with the
+	// monitor.datadoghq.com/adopt-id annotation.
+	AdoptID int64 `json:"adoptID,omitempty"`
+
+	// Credentials references the Secret holding the Datadog credentials used to manage the monitor.
+	// Defaults to the Secret of the namespace labeled datadoghq.com/default-credentials, otherwise to the credentials of the Datadog Operator.
+	Credentials *DatadogCredentialsReference `json:"credentials,omitempty"`
+
+	// DeletionPolicy defines what happens to the monitor when the DatadogMonitor is deleted: Delete (default) deletes the monitor,
+	// Orphan keeps it and removes the generated:kubernetes tag, so that it is no longer managed by Kubernetes.
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
+// DatadogMonitorAdoptIDAnnotationKey is the annotation used instead of spec.adoptID to adopt an existing monitor
+const DatadogMonitorAdoptIDAnnotationKey = "monitor.datadoghq.com/adopt-id"
+
 // DatadogMonitorType defines the type of monitor
 type DatadogMonitorType string
 
@@ -167,6 +185,9 @@ type DatadogMonitorOptionsThresholdWindows struct {
 type DatadogMonitorControllerOptions struct {
 	// DisableRequiredTags disables the automatic addition of required tags to monitors.
 	DisableRequiredTags *bool `json:"disableRequiredTags,omitempty"`
+	// DriftPolicy defines what the controller does when the monitor is modified outside Kubernetes: revert, report or ignore.
+	// Defaults to the drift policy of the Datadog Operator.
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
 }
 
 // DatadogMonitorStatus defines the observed state of DatadogMonitor
@@ -207,6 +228,15 @@ type DatadogMonitorStatus struct {
 	// CurrentHash tracks the hash of the current DatadogMonitorSpec to know
 	// if the Spec has changed and needs an update
 	CurrentHash string `json:"currentHash,omitempty"`
+
+	// DriftedFields are the fields of the monitor modified outside Kubernetes, found by the last drift check.
+	// With the revert drift policy, they were overwritten by the spec.
+	// +listType=set
+	DriftedFields []string `json:"driftedFields,omitempty"`
+
+	// Credentials records the credentials the monitor was created with, which are used to delete it.
+	// It is unset for the monitors created before the credentials were recorded.
+	Credentials *DatadogCredentialsStatus `json:"credentials,omitempty"`
 }
 
 // DatadogMonitorCondition describes the current state of a DatadogMonitor
@@ -242,6 +272,8 @@ const (
 	DatadogMonitorConditionTypeUpdated DatadogMonitorConditionType = "Updated"
 	// DatadogMonitorConditionTypeError means the DatadogMonitor has an error
 	DatadogMonitorConditionTypeError DatadogMonitorConditionType = "Error"
+	// DatadogMonitorConditionTypeAdopted means the DatadogMonitor adopted an existing monitor
+	DatadogMonitorConditionTypeAdopted DatadogMonitorConditionType = "Adopted"
 )
 
 // DatadogMonitorState represents the overall DatadogMonitor state
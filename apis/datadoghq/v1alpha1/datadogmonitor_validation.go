@@ -31,5 +31,21 @@ func IsValidDatadogMonitor(spec *DatadogMonitorSpec) error {
 		errs = append(errs, fmt.Errorf("spec.Message must be defined"))
 	}
 
+	if spec.AdoptID < 0 {
+		errs = append(errs, fmt.Errorf("spec.AdoptID must be a positive monitor ID"))
+	}
+
+	if spec.ControllerOptions.DriftPolicy != "" && !spec.ControllerOptions.DriftPolicy.IsValid() {
+		errs = append(errs, fmt.Errorf("spec.ControllerOptions.DriftPolicy must be one of the values: %s, %s or %s", DriftPolicyRevert, DriftPolicyReport, DriftPolicyIgnore))
+	}
+
+	if spec.Credentials != nil && spec.Credentials.SecretName == "" {
+		errs = append(errs, fmt.Errorf("spec.Credentials.SecretName must be defined"))
+	}
+
+	if spec.DeletionPolicy != "" && !spec.DeletionPolicy.IsValid() {
+		errs = append(errs, fmt.Errorf("spec.DeletionPolicy must be one of the values: %s or %s", DeletionPolicyDelete, DeletionPolicyOrphan))
+	}
+
 	return utilserrors.NewAggregate(errs)
 }
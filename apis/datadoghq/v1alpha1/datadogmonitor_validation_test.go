@@ -39,6 +39,37 @@ func TestIsValidDatadogMonitor(t *testing.T) {
 		Type:  "metric alert",
 		Name:  "Test Monitor",
 	}
+	invalidAdoptID := &DatadogMonitorSpec{
+		Query:   "avg(last_10m):avg:system.disk.in_use{*} by {host} > 0.05",
+		Type:    "metric alert",
+		Name:    "Test Monitor",
+		Message: "Something is wrong",
+		AdoptID: -1,
+	}
+	invalidDriftPolicy := &DatadogMonitorSpec{
+		Query:   "avg(last_10m):avg:system.disk.in_use{*} by {host} > 0.05",
+		Type:    "metric alert",
+		Name:    "Test Monitor",
+		Message: "Something is wrong",
+		ControllerOptions: DatadogMonitorControllerOptions{
+			DriftPolicy: "overwrite",
+		},
+	}
+	missingCredentialsSecret := &DatadogMonitorSpec{
+		Query:       "avg(last_10m):avg:system.disk.in_use{*} by {host} > 0.05",
+		Type:        "metric alert",
+		Name:        "Test Monitor",
+		Message:     "Something is wrong",
+		Credentials: &DatadogCredentialsReference{},
+	}
+
+	invalidDeletionPolicy := &DatadogMonitorSpec{
+		Query:          "avg(last_10m):avg:system.disk.in_use{*} by {host} > 0.05",
+		Type:           "metric alert",
+		Name:           "Test Monitor",
+		Message:        "Something is wrong",
+		DeletionPolicy: "Retain",
+	}
 
 	testCases := []struct {
 		name    string
@@ -69,6 +100,26 @@ func TestIsValidDatadogMonitor(t *testing.T) {
 			spec:    missingMessage,
 			wantErr: "spec.Message must be defined",
 		},
+		{
+			name:    "monitor with invalid adopt ID",
+			spec:    invalidAdoptID,
+			wantErr: "spec.AdoptID must be a positive monitor ID",
+		},
+		{
+			name:    "monitor with invalid drift policy",
+			spec:    invalidDriftPolicy,
+			wantErr: "spec.ControllerOptions.DriftPolicy must be one of the values: revert, report or ignore",
+		},
+		{
+			name:    "monitor with credentials missing the Secret name",
+			spec:    missingCredentialsSecret,
+			wantErr: "spec.Credentials.SecretName must be defined",
+		},
+		{
+			name:    "monitor with invalid deletion policy",
+			spec:    invalidDeletionPolicy,
+			wantErr: "spec.DeletionPolicy must be one of the values: Delete or Orphan",
+		},
 	}
 	for _, test := range testCases {
 		t.Run(test.name, func(t *testing.T) {
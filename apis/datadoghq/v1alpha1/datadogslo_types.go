@@ -53,6 +53,14 @@ type DatadogSLOSpec struct {
 
 	// ControllerOptions are the optional parameters in the DatadogSLO controller
 	ControllerOptions *DatadogSLOControllerOptions `json:"controllerOptions,omitempty"`
+
+	// Credentials references the Secret holding the Datadog credentials used to manage the SLO.
+	// Defaults to the Secret of the namespace labeled datadoghq.com/default-credentials, otherwise to the credentials of the Datadog Operator.
+	Credentials *DatadogCredentialsReference `json:"credentials,omitempty"`
+
+	// DeletionPolicy defines what happens to the SLO when the DatadogSLO is deleted: Delete (default) deletes the SLO,
+	// Orphan keeps it and removes the generated:kubernetes tag, so that it is no longer managed by Kubernetes.
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -92,6 +100,9 @@ const (
 type DatadogSLOControllerOptions struct {
 	// DisableRequiredTags disables the automatic addition of required tags to SLOs.
 	DisableRequiredTags *bool `json:"disableRequiredTags,omitempty"`
+	// DriftPolicy defines what the controller does when the SLO is modified outside Kubernetes: revert, report or ignore.
+	// Defaults to the drift policy of the Datadog Operator.
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
 }
 
 // DatadogSLOStatus defines the observed state of a DatadogSLO.
@@ -120,6 +131,15 @@ type DatadogSLOStatus struct {
 	// CurrentHash tracks the hash of the current DatadogSLOSpec to know
 	// if the Spec has changed and needs an update.
 	CurrentHash string `json:"currentHash,omitempty"`
+
+	// DriftedFields are the fields of the SLO modified outside Kubernetes, found by the last drift check.
+	// With the revert drift policy, they were overwritten by the spec.
+	// +listType=set
+	DriftedFields []string `json:"driftedFields,omitempty"`
+
+	// Credentials records the credentials the SLO was created with, which are used to delete it.
+	// It is unset for the SLOs created before the credentials were recorded.
+	Credentials *DatadogCredentialsStatus `json:"credentials,omitempty"`
 }
 
 // DatadogSLOSyncStatus is the message reflecting the health of SLO state syncs to Datadog.
@@ -134,6 +154,8 @@ const (
 	DatadogSLOSyncStatusUpdateError DatadogSLOSyncStatus = "error updating SLO"
 	// DatadogSLOSyncStatusCreateError means there is an error getting the SLO.
 	DatadogSLOSyncStatusCreateError DatadogSLOSyncStatus = "error creating SLO"
+	// DatadogSLOSyncStatusCredentialsError means there is an error getting the Datadog credentials of the SLO.
+	DatadogSLOSyncStatusCredentialsError DatadogSLOSyncStatus = "error getting the Datadog credentials"
 )
 
 // DatadogSLO allows a user to define and manage datadog SLOs from Kubernetes cluster.
@@ -50,5 +50,17 @@ func IsValidDatadogSLO(spec *DatadogSLOSpec) error {
 		errs = append(errs, fmt.Errorf("spec.Timeframe must be defined as one of the values: 7d, 30d, or 90d"))
 	}
 
+	if spec.ControllerOptions != nil && spec.ControllerOptions.DriftPolicy != "" && !spec.ControllerOptions.DriftPolicy.IsValid() {
+		errs = append(errs, fmt.Errorf("spec.ControllerOptions.DriftPolicy must be one of the values: %s, %s or %s", DriftPolicyRevert, DriftPolicyReport, DriftPolicyIgnore))
+	}
+
+	if spec.Credentials != nil && spec.Credentials.SecretName == "" {
+		errs = append(errs, fmt.Errorf("spec.Credentials.SecretName must be defined"))
+	}
+
+	if spec.DeletionPolicy != "" && !spec.DeletionPolicy.IsValid() {
+		errs = append(errs, fmt.Errorf("spec.DeletionPolicy must be one of the values: %s or %s", DeletionPolicyDelete, DeletionPolicyOrphan))
+	}
+
 	return utilserrors.NewAggregate(errs)
 }
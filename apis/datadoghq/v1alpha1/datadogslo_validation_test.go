@@ -157,6 +157,53 @@ func TestIsValidDatadogSLO(t *testing.T) {
 			},
 			expected: errors.New("spec.Timeframe must be defined as one of the values: 7d, 30d, or 90d"),
 		},
+		{
+			name: "Invalid Drift Policy",
+			spec: &DatadogSLOSpec{
+				Name: "MySLO",
+				Query: &DatadogSLOQuery{
+					Numerator:   "good",
+					Denominator: "total",
+				},
+				Type:            DatadogSLOTypeMetric,
+				TargetThreshold: resource.MustParse("98.00"),
+				Timeframe:       DatadogSLOTimeFrame7d,
+				ControllerOptions: &DatadogSLOControllerOptions{
+					DriftPolicy: "overwrite",
+				},
+			},
+			expected: errors.New("spec.ControllerOptions.DriftPolicy must be one of the values: revert, report or ignore"),
+		},
+		{
+			name: "Missing Credentials Secret Name",
+			spec: &DatadogSLOSpec{
+				Name: "MySLO",
+				Query: &DatadogSLOQuery{
+					Numerator:   "good",
+					Denominator: "total",
+				},
+				Type:            DatadogSLOTypeMetric,
+				TargetThreshold: resource.MustParse("98.00"),
+				Timeframe:       DatadogSLOTimeFrame7d,
+				Credentials:     &DatadogCredentialsReference{},
+			},
+			expected: errors.New("spec.Credentials.SecretName must be defined"),
+		},
+		{
+			name: "Invalid Deletion Policy",
+			spec: &DatadogSLOSpec{
+				Name: "MySLO",
+				Query: &DatadogSLOQuery{
+					Numerator:   "good",
+					Denominator: "total",
+				},
+				Type:            DatadogSLOTypeMetric,
+				TargetThreshold: resource.MustParse("98.00"),
+				Timeframe:       DatadogSLOTimeFrame7d,
+				DeletionPolicy:  "Retain",
+			},
+			expected: errors.New("spec.DeletionPolicy must be one of the values: Delete or Orphan"),
+		},
 	}
 
 	for _, tt := range tests {
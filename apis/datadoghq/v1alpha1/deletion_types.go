@@ -0,0 +1,33 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+// DeletionPolicy defines what a controller does with the Datadog resource managed by a custom resource
+// when the custom resource is deleted
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the Datadog resource
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyOrphan keeps the Datadog resource, without the tags marking it as managed by Kubernetes
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+)
+
+// IsValid returns true if the deletion policy is supported
+func (p DeletionPolicy) IsValid() bool {
+	switch p {
+	case DeletionPolicyDelete, DeletionPolicyOrphan:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsOrphan returns true if the Datadog resource must be kept when the custom resource is deleted.
+// The default deletion policy is Delete.
+func (p DeletionPolicy) IsOrphan() bool {
+	return p == DeletionPolicyOrphan
+}
@@ -0,0 +1,41 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+// DriftPolicy defines what a controller does when the Datadog resource managed by a custom resource
+// was modified outside Kubernetes
+type DriftPolicy string
+
+const (
+	// DriftPolicyRevert overwrites the drifted fields with the spec
+	DriftPolicyRevert DriftPolicy = "revert"
+	// DriftPolicyReport reports the drifted fields without modifying the Datadog resource
+	DriftPolicyReport DriftPolicy = "report"
+	// DriftPolicyIgnore doesn't check whether the Datadog resource drifted
+	DriftPolicyIgnore DriftPolicy = "ignore"
+)
+
+// IsValid returns true if the drift policy is supported
+func (p DriftPolicy) IsValid() bool {
+	switch p {
+	case DriftPolicyRevert, DriftPolicyReport, DriftPolicyIgnore:
+		return true
+	default:
+		return false
+	}
+}
+
+// GetDriftPolicy returns the drift policy of a custom resource: its own policy if defined, otherwise
+// the default policy of the controller, otherwise revert
+func GetDriftPolicy(policy, defaultPolicy DriftPolicy) DriftPolicy {
+	if policy != "" {
+		return policy
+	}
+	if defaultPolicy != "" {
+		return defaultPolicy
+	}
+	return DriftPolicyRevert
+}
@@ -0,0 +1,18 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDriftPolicy(t *testing.T) {
+	assert.Equal(t, DriftPolicyRevert, GetDriftPolicy("", ""))
+	assert.Equal(t, DriftPolicyReport, GetDriftPolicy("", DriftPolicyReport))
+	assert.Equal(t, DriftPolicyIgnore, GetDriftPolicy(DriftPolicyIgnore, DriftPolicyReport))
+}
@@ -41,6 +41,15 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"./apis/datadoghq/v1alpha1.DatadogAgentSpecClusterChecksRunnerSpec": schema__apis_datadoghq_v1alpha1_DatadogAgentSpecClusterChecksRunnerSpec(ref),
 		"./apis/datadoghq/v1alpha1.DatadogAgentStatus":                      schema__apis_datadoghq_v1alpha1_DatadogAgentStatus(ref),
 		"./apis/datadoghq/v1alpha1.DatadogCredentials":                      schema__apis_datadoghq_v1alpha1_DatadogCredentials(ref),
+		"./apis/datadoghq/v1alpha1.DatadogCredentialsReference":             schema__apis_datadoghq_v1alpha1_DatadogCredentialsReference(ref),
+		"./apis/datadoghq/v1alpha1.DatadogCredentialsStatus":                schema__apis_datadoghq_v1alpha1_DatadogCredentialsStatus(ref),
+		"./apis/datadoghq/v1alpha1.DatadogDowntime":                         schema__apis_datadoghq_v1alpha1_DatadogDowntime(ref),
+		"./apis/datadoghq/v1alpha1.DatadogDowntimeMonitorDowntime":          schema__apis_datadoghq_v1alpha1_DatadogDowntimeMonitorDowntime(ref),
+		"./apis/datadoghq/v1alpha1.DatadogDowntimeMonitorSelector":          schema__apis_datadoghq_v1alpha1_DatadogDowntimeMonitorSelector(ref),
+		"./apis/datadoghq/v1alpha1.DatadogDowntimeRecurrence":               schema__apis_datadoghq_v1alpha1_DatadogDowntimeRecurrence(ref),
+		"./apis/datadoghq/v1alpha1.DatadogDowntimeSchedule":                 schema__apis_datadoghq_v1alpha1_DatadogDowntimeSchedule(ref),
+		"./apis/datadoghq/v1alpha1.DatadogDowntimeSpec":                     schema__apis_datadoghq_v1alpha1_DatadogDowntimeSpec(ref),
+		"./apis/datadoghq/v1alpha1.DatadogDowntimeStatus":                   schema__apis_datadoghq_v1alpha1_DatadogDowntimeStatus(ref),
 		"./apis/datadoghq/v1alpha1.DatadogFeatures":                         schema__apis_datadoghq_v1alpha1_DatadogFeatures(ref),
 		"./apis/datadoghq/v1alpha1.DatadogMetric":                           schema__apis_datadoghq_v1alpha1_DatadogMetric(ref),
 		"./apis/datadoghq/v1alpha1.DatadogMetricCondition":                  schema__apis_datadoghq_v1alpha1_DatadogMetricCondition(ref),
@@ -1785,6 +1794,432 @@ func schema__apis_datadoghq_v1alpha1_DatadogCredentials(ref common.ReferenceCall
 	}
 }
 
+func schema__apis_datadoghq_v1alpha1_DatadogCredentialsReference(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogCredentialsReference references the Secret holding the Datadog credentials used to manage a resource in Datadog",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"secretName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SecretName is the name of a Secret of the namespace of the resource. The Secret holds the API key in the `api_key` key, the application key in the `app_key` key, and optionally the Datadog site, for example `datadoghq.eu`, in the `site` key.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"secretName"},
+			},
+		},
+	}
+}
+
+func schema__apis_datadoghq_v1alpha1_DatadogCredentialsStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogCredentialsStatus records the credentials a resource was created with in Datadog, so that it is deleted with the same credentials even if the spec or the default credentials of the namespace changed since",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"secretName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SecretName is the name of the Secret holding the credentials, in the namespace of the resource. It is empty when the resource was created with the credentials of the operator.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema__apis_datadoghq_v1alpha1_DatadogDowntime(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogDowntime allows a user to define and manage Datadog downtimes from Kubernetes cluster.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("./apis/datadoghq/v1alpha1.DatadogDowntimeSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("./apis/datadoghq/v1alpha1.DatadogDowntimeStatus"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./apis/datadoghq/v1alpha1.DatadogDowntimeSpec", "./apis/datadoghq/v1alpha1.DatadogDowntimeStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema__apis_datadoghq_v1alpha1_DatadogDowntimeMonitorDowntime(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogDowntimeMonitorDowntime is the downtime generated in Datadog for the monitor of a DatadogMonitor.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"monitorID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MonitorID is the ID of the monitor.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"id": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID is the downtime ID generated in Datadog.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"monitorID", "id"},
+			},
+		},
+	}
+}
+
+func schema__apis_datadoghq_v1alpha1_DatadogDowntimeMonitorSelector(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogDowntimeMonitorSelector selects the monitors to which a downtime applies. Only one of its fields can be defined.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"id": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID is the ID of a Datadog monitor.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"tags": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Tags is a list of monitor tags. The downtime applies to the monitors that have all the tags.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"datadogMonitorSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DatadogMonitorSelector is a label selector of the DatadogMonitors of the namespace of the DatadogDowntime. The downtime applies to their monitors, a downtime is created in Datadog for each monitor.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+	}
+}
+
+func schema__apis_datadoghq_v1alpha1_DatadogDowntimeRecurrence(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogDowntimeRecurrence defines the recurrence of a downtime.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type is the type of recurrence: days, weeks, months, years or rrule.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"period": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Period is how often the downtime is repeated, for example every 3 days with the days type and a period of 3. It isn't used with the rrule type.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"weekDays": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "WeekDays is the list of week days on which the downtime is repeated: Mon, Tue, Wed, Thu, Fri, Sat or Sun. It is only used with the weeks type.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"rrule": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RRule is the recurrence rule of the downtime in the iCalendar RRULE format, for example FREQ=MONTHLY;BYMONTHDAY=1. It is required with the rrule type.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"untilDate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UntilDate is the time after which the downtime isn't repeated anymore.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"untilOccurrences": {
+						SchemaProps: spec.SchemaProps{
+							Description: "UntilOccurrences is the number of times the downtime is repeated.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+				},
+				Required: []string{"type"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema__apis_datadoghq_v1alpha1_DatadogDowntimeSchedule(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogDowntimeSchedule defines when a downtime is in effect.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"start": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Start is the time the downtime starts. Defaults to the time the downtime is created. It is required with a recurrence.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"end": {
+						SchemaProps: spec.SchemaProps{
+							Description: "End is the time the downtime ends. Without end, the downtime is in effect until the DatadogDowntime is deleted. For a recurring downtime, start and end define the first occurrence of the downtime.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"timezone": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timezone is the timezone of the downtime, for example Europe/Paris. Defaults to UTC.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"recurrence": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Recurrence makes the downtime recurring. Without recurrence, the downtime is a one-off downtime.",
+							Ref:         ref("./apis/datadoghq/v1alpha1.DatadogDowntimeRecurrence"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./apis/datadoghq/v1alpha1.DatadogDowntimeRecurrence", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema__apis_datadoghq_v1alpha1_DatadogDowntimeSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogDowntimeSpec defines the desired state of a DatadogDowntime.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"scope": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Scope is the list of scopes to which the downtime applies, in key:value format, for example host:app2. The downtime applies to the sources that match all the scopes. Defaults to all the sources (*).",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"monitorSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MonitorSelector selects the monitors to which the downtime applies. Defaults to all the monitors.",
+							Ref:         ref("./apis/datadoghq/v1alpha1.DatadogDowntimeMonitorSelector"),
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message is a message to include with the notifications of the downtime.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"schedule": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Schedule defines when the downtime is in effect.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("./apis/datadoghq/v1alpha1.DatadogDowntimeSchedule"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./apis/datadoghq/v1alpha1.DatadogDowntimeMonitorSelector", "./apis/datadoghq/v1alpha1.DatadogDowntimeSchedule"},
+	}
+}
+
+func schema__apis_datadoghq_v1alpha1_DatadogDowntimeStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogDowntimeStatus defines the observed state of a DatadogDowntime.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"conditions": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"type",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions represents the latest available observations of the state of a DatadogDowntime.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Condition"),
+									},
+								},
+							},
+						},
+					},
+					"id": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID is the downtime ID generated in Datadog. It isn't set when the downtime applies to DatadogMonitors, see MonitorDowntimes.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"monitorDowntimes": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"monitorID",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "MonitorDowntimes are the downtimes generated in Datadog for the monitors of the DatadogMonitors selected by the monitor selector, one downtime per monitor.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("./apis/datadoghq/v1alpha1.DatadogDowntimeMonitorDowntime"),
+									},
+								},
+							},
+						},
+					},
+					"active": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Active is true when the downtime is currently in effect.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"syncStatus": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SyncStatus shows the health of syncing the downtime to Datadog.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastForceSyncTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastForceSyncTime is the last time the API downtime was last force synced with the DatadogDowntime resource.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"currentHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CurrentHash tracks the hash of the current DatadogDowntimeSpec to know if the Spec has changed and needs an update.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./apis/datadoghq/v1alpha1.DatadogDowntimeMonitorDowntime", "k8s.io/apimachinery/pkg/apis/meta/v1.Condition", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema__apis_datadoghq_v1alpha1_DatadogFeatures(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2058,6 +2493,13 @@ func schema__apis_datadoghq_v1alpha1_DatadogMonitorControllerOptions(ref common.
 							Format:      "",
 						},
 					},
+					"driftPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DriftPolicy defines what the controller does when the monitor is modified outside Kubernetes: revert, report or ignore. Defaults to the drift policy of the Datadog Operator.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -2355,7 +2797,7 @@ func schema__apis_datadoghq_v1alpha1_DatadogMonitorSpec(ref common.ReferenceCall
 					},
 					"query": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Query is the Datadog monitor query",
+							Description: "Query is the Datadog monitor query. The query of a composite monitor can refer to other DatadogMonitors with ${name} or ${namespace/name}, they are replaced by the IDs of their monitors.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -2421,11 +2863,31 @@ func schema__apis_datadoghq_v1alpha1_DatadogMonitorSpec(ref common.ReferenceCall
 							Ref:         ref("./apis/datadoghq/v1alpha1.DatadogMonitorControllerOptions"),
 						},
 					},
+					"adoptID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AdoptID is the ID of an existing Datadog monitor that the DatadogMonitor takes ownership of, instead of creating a new monitor. A monitor can only be adopted by one DatadogMonitor. The adopted monitor isn't modified while AdoptID is set: its differences with the spec are reported in the Adopted condition, and overwritten once AdoptID is removed. It can also be set with the monitor.datadoghq.com/adopt-id annotation.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"credentials": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Credentials references the Secret holding the Datadog credentials used to manage the monitor. Defaults to the Secret of the namespace labeled datadoghq.com/default-credentials, otherwise to the credentials of the Datadog Operator.",
+							Ref:         ref("./apis/datadoghq/v1alpha1.DatadogCredentialsReference"),
+						},
+					},
+					"deletionPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeletionPolicy defines what happens to the monitor when the DatadogMonitor is deleted: Delete (default) deletes the monitor, Orphan keeps it and removes the generated:kubernetes tag, so that it is no longer managed by Kubernetes.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"./apis/datadoghq/v1alpha1.DatadogMonitorControllerOptions", "./apis/datadoghq/v1alpha1.DatadogMonitorOptions"},
+			"./apis/datadoghq/v1alpha1.DatadogCredentialsReference", "./apis/datadoghq/v1alpha1.DatadogMonitorControllerOptions", "./apis/datadoghq/v1alpha1.DatadogMonitorOptions"},
 	}
 }
 
@@ -2553,11 +3015,37 @@ func schema__apis_datadoghq_v1alpha1_DatadogMonitorStatus(ref common.ReferenceCa
 							Format:      "",
 						},
 					},
+					"driftedFields": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "DriftedFields are the fields of the monitor modified outside Kubernetes, found by the last drift check. With the revert drift policy, they were overwritten by the spec.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"credentials": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Credentials records the credentials the monitor was created with, which are used to delete it. It is unset for the monitors created before the credentials were recorded.",
+							Ref:         ref("./apis/datadoghq/v1alpha1.DatadogCredentialsStatus"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"./apis/datadoghq/v1alpha1.DatadogMonitorCondition", "./apis/datadoghq/v1alpha1.DatadogMonitorDowntimeStatus", "./apis/datadoghq/v1alpha1.DatadogMonitorTriggeredState", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"./apis/datadoghq/v1alpha1.DatadogCredentialsStatus", "./apis/datadoghq/v1alpha1.DatadogMonitorCondition", "./apis/datadoghq/v1alpha1.DatadogMonitorDowntimeStatus", "./apis/datadoghq/v1alpha1.DatadogMonitorTriggeredState", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
@@ -2658,6 +3146,13 @@ func schema__apis_datadoghq_v1alpha1_DatadogSLOControllerOptions(ref common.Refe
 							Format:      "",
 						},
 					},
+					"driftPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DriftPolicy defines what the controller does when the SLO is modified outside Kubernetes: revert, report or ignore. Defaults to the drift policy of the Datadog Operator.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -2815,12 +3310,25 @@ func schema__apis_datadoghq_v1alpha1_DatadogSLOSpec(ref common.ReferenceCallback
 							Ref:         ref("./apis/datadoghq/v1alpha1.DatadogSLOControllerOptions"),
 						},
 					},
+					"credentials": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Credentials references the Secret holding the Datadog credentials used to manage the SLO. Defaults to the Secret of the namespace labeled datadoghq.com/default-credentials, otherwise to the credentials of the Datadog Operator.",
+							Ref:         ref("./apis/datadoghq/v1alpha1.DatadogCredentialsReference"),
+						},
+					},
+					"deletionPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeletionPolicy defines what happens to the SLO when the DatadogSLO is deleted: Delete (default) deletes the SLO, Orphan keeps it and removes the generated:kubernetes tag, so that it is no longer managed by Kubernetes.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"name", "type", "timeframe", "targetThreshold"},
 			},
 		},
 		Dependencies: []string{
-			"./apis/datadoghq/v1alpha1.DatadogSLOControllerOptions", "./apis/datadoghq/v1alpha1.DatadogSLOQuery", "k8s.io/apimachinery/pkg/api/resource.Quantity"},
+			"./apis/datadoghq/v1alpha1.DatadogCredentialsReference", "./apis/datadoghq/v1alpha1.DatadogSLOControllerOptions", "./apis/datadoghq/v1alpha1.DatadogSLOQuery", "k8s.io/apimachinery/pkg/api/resource.Quantity"},
 	}
 }
 
@@ -2893,11 +3401,37 @@ func schema__apis_datadoghq_v1alpha1_DatadogSLOStatus(ref common.ReferenceCallba
 							Format:      "",
 						},
 					},
+					"driftedFields": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "DriftedFields are the fields of the SLO modified outside Kubernetes, found by the last drift check. With the revert drift policy, they were overwritten by the spec.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"credentials": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Credentials records the credentials the SLO was created with, which are used to delete it. It is unset for the SLOs created before the credentials were recorded.",
+							Ref:         ref("./apis/datadoghq/v1alpha1.DatadogCredentialsStatus"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Condition", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"./apis/datadoghq/v1alpha1.DatadogCredentialsStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.Condition", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
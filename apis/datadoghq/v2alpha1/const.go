@@ -12,6 +12,18 @@ const (
 	OverrideReconcileConflictConditionType = "OverrideReconcileConflict"
 	// DatadogAgentReconcileErrorConditionType ReconcileConditionType for DatadogAgent reconcile error
 	DatadogAgentReconcileErrorConditionType = "DatadogAgentReconcileError"
+	// PausedConditionType ReconcileConditionType for a DatadogAgent paused with the PausedAnnotationKey annotation
+	PausedConditionType = "Paused"
+	// DryRunConditionType ReconcileConditionType for a DatadogAgent reconciled in dry-run mode with the DryRunAnnotationKey annotation
+	DryRunConditionType = "DryRun"
+	// DependenciesApplyConflictConditionType ReconcileConditionType for the dependencies whose apply conflicts with fields owned by another field manager
+	DependenciesApplyConflictConditionType = "DependenciesApplyConflict"
+
+	// PausedAnnotationKey annotation key used to pause the reconcile of a DatadogAgent, when set to "true"
+	PausedAnnotationKey = "agent.datadoghq.com/paused"
+	// DryRunAnnotationKey annotation key used to reconcile a DatadogAgent in dry-run mode, when set to "true":
+	// the changes are reported in the status but not applied
+	DryRunAnnotationKey = "agent.datadoghq.com/dry-run"
 
 	// ExtraConfdConfigMapName is the name of the ConfigMap storing Custom Confd data
 	ExtraConfdConfigMapName = "%s-extra-confd"
@@ -8,6 +8,7 @@ package v2alpha1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	commonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
 )
@@ -849,6 +850,11 @@ type GlobalConfig struct {
 
 	// FIPS contains configuration used to customize the FIPS proxy sidecar.
 	FIPS *FIPSConfig `json:"fips,omitempty"`
+
+	// SecretBackend configures the secret backend used by the Agents, the Cluster Agent and the Cluster Checks Runner
+	// to retrieve the secrets referenced with the ENC[] notation.
+	// +optional
+	SecretBackend *SecretBackendConfig `json:"secretBackend,omitempty"`
 }
 
 // DatadogCredentials is a generic structure that holds credentials to access Datadog.
@@ -882,6 +888,24 @@ type SecretBackendConfig struct {
 
 	// Args defines the list of arguments to pass to the command
 	Args []string `json:"args,omitempty"`
+
+	// Roles grants the Agents, the Cluster Agent and the Cluster Checks Runner read access to a list of Secrets,
+	// as needed by the built-in "/readsecret_multiple_providers.sh" script to read Kubernetes Secrets.
+	// +optional
+	// +listType=atomic
+	Roles []SecretBackendRolesConfig `json:"roles,omitempty"`
+}
+
+// SecretBackendRolesConfig provides the configuration of the Role created to read a list of Secrets.
+type SecretBackendRolesConfig struct {
+	// Namespace defines the namespace of the Secrets.
+	// Default: the namespace of the DatadogAgent
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+
+	// Secrets defines the names of the Secrets that can be read.
+	// +listType=set
+	Secrets []string `json:"secrets,omitempty"`
 }
 
 // NetworkPolicyFlavor specifies which flavor of Network Policy to use.
@@ -1056,11 +1080,64 @@ type DatadogAgentComponentOverride struct {
 	// +optional
 	HostPID *bool `json:"hostPID,omitempty"`
 
+	// PodDisruptionBudget configures the PodDisruptionBudget of the component.
+	// Only supported by the Cluster Agent and the Cluster Checks Runner.
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetConfig `json:"podDisruptionBudget,omitempty"`
+
+	// UpdateStrategy is the strategy used to replace the pods of the component:
+	// the DaemonSet update strategy for the Node Agent, the Deployment strategy for the Cluster Agent and the Cluster Checks Runner.
+	// Not applicable for an ExtendedDaemonSet deployment.
+	// +optional
+	UpdateStrategy *UpdateStrategy `json:"updateStrategy,omitempty"`
+
 	// Disabled force disables a component.
 	// +optional
 	Disabled *bool `json:"disabled,omitempty"`
 }
 
+// PodDisruptionBudgetConfig contains the configuration of the PodDisruptionBudget of a component.
+// Only one of MinAvailable and MaxUnavailable can be set.
+// +k8s:openapi-gen=true
+type PodDisruptionBudgetConfig struct {
+	// MinAvailable is the number of pods of the component that must remain available during a voluntary disruption,
+	// as an absolute number or a percentage.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is the number of pods of the component that can be unavailable during a voluntary disruption,
+	// as an absolute number or a percentage.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// UpdateStrategy is the strategy used to replace the pods of a DaemonSet or a Deployment.
+// +k8s:openapi-gen=true
+type UpdateStrategy struct {
+	// Type is the type of the strategy: RollingUpdate or OnDelete for the Node Agent,
+	// RollingUpdate or Recreate for the Cluster Agent and the Cluster Checks Runner.
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// RollingUpdate configures the rolling update. Only used when Type is RollingUpdate.
+	// +optional
+	RollingUpdate *RollingUpdate `json:"rollingUpdate,omitempty"`
+}
+
+// RollingUpdate configures a rolling update.
+// +k8s:openapi-gen=true
+type RollingUpdate struct {
+	// MaxUnavailable is the maximum number of pods that can be unavailable during the update,
+	// as an absolute number or a percentage.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// MaxSurge is the maximum number of pods that can be created above the desired number of pods during the update,
+	// as an absolute number or a percentage.
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+}
+
 // DatadogAgentGenericContainer is the generic structure describing any container's common configuration.
 // +k8s:openapi-gen=true
 type DatadogAgentGenericContainer struct {
@@ -1161,6 +1238,60 @@ type DatadogAgentStatus struct {
 	// The actual state of the Cluster Checks Runner as a deployment.
 	// +optional
 	ClusterChecksRunner *commonv1.DeploymentStatus `json:"clusterChecksRunner,omitempty"`
+	// ObservedGeneration is the most recent generation of the DatadogAgent successfully reconciled by the operator.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// SpecHash is the MD5 hash of the DatadogAgent spec, once defaulted, applied during the last successful reconcile.
+	// +optional
+	SpecHash string `json:"specHash,omitempty"`
+	// Features lists the features enabled during the last successful reconcile and the components they require.
+	// +optional
+	// +listType=map
+	// +listMapKey=id
+	Features []FeatureStatus `json:"features,omitempty"`
+	// PlannedChanges lists the changes that the operator would apply if the DatadogAgent was not in dry-run mode.
+	// +optional
+	// +listType=atomic
+	PlannedChanges []PlannedChangeStatus `json:"plannedChanges,omitempty"`
+}
+
+// PlannedChangeStatus reports a change to a resource planned in dry-run mode.
+// +k8s:openapi-gen=true
+type PlannedChangeStatus struct {
+	// Action is the action planned on the resource: create, update or delete.
+	Action string `json:"action"`
+	// Kind is the kind of the resource.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the resource, empty for cluster-scoped resources.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the resource.
+	Name string `json:"name"`
+}
+
+// FeatureStatus reports a feature enabled in the DatadogAgent and the components it requires.
+// +k8s:openapi-gen=true
+type FeatureStatus struct {
+	// ID is the identifier of the feature.
+	ID string `json:"id"`
+	// RequiredComponents lists the components requested by the feature.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	RequiredComponents []RequiredComponentStatus `json:"requiredComponents,omitempty"`
+}
+
+// RequiredComponentStatus reports how a feature uses one of the DatadogAgent components.
+// +k8s:openapi-gen=true
+type RequiredComponentStatus struct {
+	// Name is the name of the component.
+	Name ComponentName `json:"name"`
+	// Required is false when the feature does not need the component, but configures it if it runs.
+	Required bool `json:"required"`
+	// Containers lists the containers of the component requested by the feature.
+	// +optional
+	// +listType=set
+	Containers []commonv1.AgentContainerName `json:"containers,omitempty"`
 }
 
 // FIPSConfig contains the FIPS configuration.
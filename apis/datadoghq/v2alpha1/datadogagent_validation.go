@@ -0,0 +1,185 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v2alpha1
+
+import (
+	"sort"
+
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// IsValidDatadogAgent is used to check if a DatadogAgentSpec is valid.
+// The spec is validated once the defaults have been applied, like it is used by the reconciler.
+func IsValidDatadogAgent(spec *DatadogAgentSpec) error {
+	return ValidateDatadogAgentSpec(spec, field.NewPath("spec")).ToAggregate()
+}
+
+// ValidateDatadogAgentSpec validates a DatadogAgentSpec and returns the list of errors with their field path.
+func ValidateDatadogAgentSpec(spec *DatadogAgentSpec, fldPath *field.Path) field.ErrorList {
+	// Validate a defaulted copy of the spec to get the same view as the reconciler.
+	dda := &DatadogAgent{Spec: *spec.DeepCopy()}
+	DefaultDatadogAgent(dda)
+	defaulted := &dda.Spec
+
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateFeatures(defaulted, fldPath.Child("features"))...)
+	allErrs = append(allErrs, validateGlobalConfig(defaulted, fldPath.Child("global"))...)
+	allErrs = append(allErrs, validateOverrides(defaulted.Override, fldPath.Child("override"))...)
+
+	return allErrs
+}
+
+func validateFeatures(spec *DatadogAgentSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	features := spec.Features
+
+	apm := features.APM
+	if apiutils.BoolValue(apm.Enabled) {
+		ssi := apm.SingleStepInstrumentation
+		if ssi != nil && apiutils.BoolValue(ssi.Enabled) {
+			ssiPath := fldPath.Child("apm", "instrumentation")
+			if !apiutils.BoolValue(features.AdmissionController.Enabled) {
+				allErrs = append(allErrs, field.Invalid(ssiPath.Child("enabled"), true, "single step instrumentation requires features.admissionController.enabled to be true"))
+			}
+			if len(ssi.EnabledNamespaces) > 0 && len(ssi.DisabledNamespaces) > 0 {
+				allErrs = append(allErrs, field.Forbidden(ssiPath.Child("disabledNamespaces"), "enabledNamespaces and disabledNamespaces cannot be set at the same time"))
+			}
+		}
+
+		dsd := features.Dogstatsd
+		if apiutils.BoolValue(apm.HostPortConfig.Enabled) && apiutils.BoolValue(dsd.HostPortConfig.Enabled) &&
+			*apm.HostPortConfig.Port == *dsd.HostPortConfig.Port {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("dogstatsd", "hostPortConfig", "hostPort"), *dsd.HostPortConfig.Port))
+		}
+	}
+
+	allErrs = append(allErrs, validateCustomConfig(features.CSPM.CustomBenchmarks, fldPath.Child("cspm", "customBenchmarks"))...)
+	allErrs = append(allErrs, validateCustomConfig(features.CWS.CustomPolicies, fldPath.Child("cws", "customPolicies"))...)
+	allErrs = append(allErrs, validateCustomConfig(features.Dogstatsd.MapperProfiles, fldPath.Child("dogstatsd", "mapperProfiles"))...)
+	allErrs = append(allErrs, validateCustomConfig(features.OrchestratorExplorer.Conf, fldPath.Child("orchestratorExplorer", "conf"))...)
+	allErrs = append(allErrs, validateCustomConfig(features.KubeStateMetricsCore.Conf, fldPath.Child("kubeStateMetricsCore", "conf"))...)
+
+	return allErrs
+}
+
+func validateGlobalConfig(spec *DatadogAgentSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	global := spec.Global
+
+	// Only the optimized and single container strategies are supported
+	if global.ContainerStrategy != nil {
+		switch *global.ContainerStrategy {
+		case OptimizedContainerStrategy, SingleContainerStrategy:
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("containerStrategy"), *global.ContainerStrategy, []string{string(OptimizedContainerStrategy), string(SingleContainerStrategy)}))
+		}
+	}
+
+	if global.FIPS != nil {
+		allErrs = append(allErrs, validateCustomConfig(global.FIPS.CustomFIPSConfig, fldPath.Child("fips", "customFIPSConfig"))...)
+	}
+
+	return allErrs
+}
+
+func validateOverrides(overrides map[ComponentName]*DatadogAgentComponentOverride, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	// Sort the components to always return the errors in the same order
+	components := make([]string, 0, len(overrides))
+	for name := range overrides {
+		components = append(components, string(name))
+	}
+	sort.Strings(components)
+
+	for _, name := range components {
+		override := overrides[ComponentName(name)]
+		if override == nil {
+			continue
+		}
+		componentPath := fldPath.Key(name)
+
+		files := make([]string, 0, len(override.CustomConfigurations))
+		for file := range override.CustomConfigurations {
+			files = append(files, string(file))
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			config := override.CustomConfigurations[AgentConfigFileName(file)]
+			allErrs = append(allErrs, validateCustomConfig(&config, componentPath.Child("customConfigurations").Key(file))...)
+		}
+
+		allErrs = append(allErrs, validateMultiCustomConfig(override.ExtraConfd, componentPath.Child("extraConfd"))...)
+		allErrs = append(allErrs, validateMultiCustomConfig(override.ExtraChecksd, componentPath.Child("extraChecksd"))...)
+		allErrs = append(allErrs, validatePodDisruptionBudget(ComponentName(name), override.PodDisruptionBudget, componentPath.Child("podDisruptionBudget"))...)
+		allErrs = append(allErrs, validateUpdateStrategy(ComponentName(name), override.UpdateStrategy, componentPath.Child("updateStrategy"))...)
+	}
+
+	return allErrs
+}
+
+// validateCustomConfig checks that `configData` and `configMap` are not set at the same time.
+func validateCustomConfig(config *CustomConfig, fldPath *field.Path) field.ErrorList {
+	if config != nil && config.ConfigData != nil && config.ConfigMap != nil {
+		return field.ErrorList{field.Forbidden(fldPath, "'configData' and 'configMap' should not be set at the same time")}
+	}
+	return nil
+}
+
+// validateMultiCustomConfig checks that `configDataMap` and `configMap` are not set at the same time.
+func validateMultiCustomConfig(config *MultiCustomConfig, fldPath *field.Path) field.ErrorList {
+	if config != nil && len(config.ConfigDataMap) > 0 && config.ConfigMap != nil {
+		return field.ErrorList{field.Forbidden(fldPath, "'configDataMap' and 'configMap' should not be set at the same time")}
+	}
+	return nil
+}
+
+// validatePodDisruptionBudget checks that the PodDisruptionBudget is set on a Deployment component,
+// and that exactly one of `minAvailable` and `maxUnavailable` is set.
+func validatePodDisruptionBudget(component ComponentName, config *PodDisruptionBudgetConfig, fldPath *field.Path) field.ErrorList {
+	if config == nil {
+		return nil
+	}
+	if component != ClusterAgentComponentName && component != ClusterChecksRunnerComponentName {
+		return field.ErrorList{field.Forbidden(fldPath, "only supported by the clusterAgent and clusterChecksRunner components")}
+	}
+	if config.MinAvailable != nil && config.MaxUnavailable != nil {
+		return field.ErrorList{field.Forbidden(fldPath, "'minAvailable' and 'maxUnavailable' should not be set at the same time")}
+	}
+	if config.MinAvailable == nil && config.MaxUnavailable == nil {
+		return field.ErrorList{field.Required(fldPath, "one of 'minAvailable' and 'maxUnavailable' should be set")}
+	}
+	return nil
+}
+
+// validateUpdateStrategy checks that the type of the UpdateStrategy is supported by the workload of the component,
+// and that `rollingUpdate` is only set with the RollingUpdate type.
+func validateUpdateStrategy(component ComponentName, strategy *UpdateStrategy, fldPath *field.Path) field.ErrorList {
+	if strategy == nil {
+		return nil
+	}
+
+	var supportedTypes []string
+	switch component {
+	case NodeAgentComponentName:
+		supportedTypes = []string{string(appsv1.RollingUpdateDaemonSetStrategyType), string(appsv1.OnDeleteDaemonSetStrategyType)}
+	case ClusterAgentComponentName, ClusterChecksRunnerComponentName:
+		supportedTypes = []string{string(appsv1.RollingUpdateDeploymentStrategyType), string(appsv1.RecreateDeploymentStrategyType)}
+	default:
+		return field.ErrorList{field.Forbidden(fldPath, "only supported by the nodeAgent, clusterAgent and clusterChecksRunner components")}
+	}
+
+	var allErrs field.ErrorList
+	if strategy.Type != "" && strategy.Type != supportedTypes[0] && strategy.Type != supportedTypes[1] {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("type"), strategy.Type, supportedTypes))
+	}
+	if strategy.RollingUpdate != nil && strategy.Type != "" && strategy.Type != supportedTypes[0] {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("rollingUpdate"), "only supported with the RollingUpdate type"))
+	}
+	return allErrs
+}
@@ -0,0 +1,265 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v2alpha1
+
+import (
+	"testing"
+
+	commonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateDatadogAgentSpec(t *testing.T) {
+	singleStrategy := SingleContainerStrategy
+	unknownStrategy := ContainerStrategyType("unknown")
+	intOne := intstr.FromInt(1)
+
+	tests := []struct {
+		name       string
+		spec       *DatadogAgentSpec
+		wantFields []string
+	}{
+		{
+			name:       "empty spec is valid",
+			spec:       &DatadogAgentSpec{},
+			wantFields: nil,
+		},
+		{
+			name: "single step instrumentation without admission controller",
+			spec: &DatadogAgentSpec{
+				Features: &DatadogFeatures{
+					APM: &APMFeatureConfig{
+						Enabled: apiutils.NewBoolPointer(true),
+						SingleStepInstrumentation: &SingleStepInstrumentation{
+							Enabled: apiutils.NewBoolPointer(true),
+						},
+					},
+					AdmissionController: &AdmissionControllerFeatureConfig{
+						Enabled: apiutils.NewBoolPointer(false),
+					},
+				},
+			},
+			wantFields: []string{"spec.features.apm.instrumentation.enabled"},
+		},
+		{
+			name: "single step instrumentation with enabled and disabled namespaces",
+			spec: &DatadogAgentSpec{
+				Features: &DatadogFeatures{
+					APM: &APMFeatureConfig{
+						SingleStepInstrumentation: &SingleStepInstrumentation{
+							Enabled:            apiutils.NewBoolPointer(true),
+							EnabledNamespaces:  []string{"foo"},
+							DisabledNamespaces: []string{"bar"},
+						},
+					},
+				},
+			},
+			wantFields: []string{"spec.features.apm.instrumentation.disabledNamespaces"},
+		},
+		{
+			name: "apm and dogstatsd host ports collide",
+			spec: &DatadogAgentSpec{
+				Features: &DatadogFeatures{
+					APM: &APMFeatureConfig{
+						HostPortConfig: &HostPortConfig{
+							Enabled: apiutils.NewBoolPointer(true),
+							Port:    apiutils.NewInt32Pointer(8125),
+						},
+					},
+					Dogstatsd: &DogstatsdFeatureConfig{
+						HostPortConfig: &HostPortConfig{
+							Enabled: apiutils.NewBoolPointer(true),
+						},
+					},
+				},
+			},
+			wantFields: []string{"spec.features.dogstatsd.hostPortConfig.hostPort"},
+		},
+		{
+			name: "apm and dogstatsd host ports differ",
+			spec: &DatadogAgentSpec{
+				Features: &DatadogFeatures{
+					APM: &APMFeatureConfig{
+						HostPortConfig: &HostPortConfig{
+							Enabled: apiutils.NewBoolPointer(true),
+						},
+					},
+					Dogstatsd: &DogstatsdFeatureConfig{
+						HostPortConfig: &HostPortConfig{
+							Enabled: apiutils.NewBoolPointer(true),
+						},
+					},
+				},
+			},
+			wantFields: nil,
+		},
+		{
+			name: "single container strategy with privileged feature falls back to optimized",
+			spec: &DatadogAgentSpec{
+				Global: &GlobalConfig{
+					ContainerStrategy: &singleStrategy,
+				},
+				Features: &DatadogFeatures{
+					NPM: &NPMFeatureConfig{
+						Enabled: apiutils.NewBoolPointer(true),
+					},
+				},
+			},
+			wantFields: nil,
+		},
+		{
+			name: "single container strategy without privileged feature",
+			spec: &DatadogAgentSpec{
+				Global: &GlobalConfig{
+					ContainerStrategy: &singleStrategy,
+				},
+			},
+			wantFields: nil,
+		},
+		{
+			name: "unknown container strategy",
+			spec: &DatadogAgentSpec{
+				Global: &GlobalConfig{
+					ContainerStrategy: &unknownStrategy,
+				},
+			},
+			wantFields: []string{"spec.global.containerStrategy"},
+		},
+		{
+			name: "custom configs with both configData and configMap",
+			spec: &DatadogAgentSpec{
+				Features: &DatadogFeatures{
+					KubeStateMetricsCore: &KubeStateMetricsCoreFeatureConfig{
+						Conf: &CustomConfig{
+							ConfigData: apiutils.NewStringPointer("foo: bar"),
+							ConfigMap:  &commonv1.ConfigMapConfig{Name: "foo"},
+						},
+					},
+				},
+				Override: map[ComponentName]*DatadogAgentComponentOverride{
+					NodeAgentComponentName: {
+						CustomConfigurations: map[AgentConfigFileName]CustomConfig{
+							AgentGeneralConfigFile: {
+								ConfigData: apiutils.NewStringPointer("foo: bar"),
+								ConfigMap:  &commonv1.ConfigMapConfig{Name: "foo"},
+							},
+						},
+						ExtraConfd: &MultiCustomConfig{
+							ConfigDataMap: map[string]string{"check.yaml": "foo: bar"},
+							ConfigMap:     &commonv1.ConfigMapConfig{Name: "foo"},
+						},
+					},
+				},
+			},
+			wantFields: []string{
+				"spec.features.kubeStateMetricsCore.conf",
+				"spec.override[nodeAgent].customConfigurations[datadog.yaml]",
+				"spec.override[nodeAgent].extraConfd",
+			},
+		},
+		{
+			name: "pod disruption budgets",
+			spec: &DatadogAgentSpec{
+				Override: map[ComponentName]*DatadogAgentComponentOverride{
+					NodeAgentComponentName: {
+						PodDisruptionBudget: &PodDisruptionBudgetConfig{MinAvailable: &intOne},
+					},
+					ClusterAgentComponentName: {
+						PodDisruptionBudget: &PodDisruptionBudgetConfig{MinAvailable: &intOne, MaxUnavailable: &intOne},
+					},
+					ClusterChecksRunnerComponentName: {
+						PodDisruptionBudget: &PodDisruptionBudgetConfig{},
+					},
+				},
+			},
+			wantFields: []string{
+				"spec.override[clusterAgent].podDisruptionBudget",
+				"spec.override[clusterChecksRunner].podDisruptionBudget",
+				"spec.override[nodeAgent].podDisruptionBudget",
+			},
+		},
+		{
+			name: "valid pod disruption budget",
+			spec: &DatadogAgentSpec{
+				Override: map[ComponentName]*DatadogAgentComponentOverride{
+					ClusterAgentComponentName: {
+						PodDisruptionBudget: &PodDisruptionBudgetConfig{MaxUnavailable: &intOne},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid update strategies",
+			spec: &DatadogAgentSpec{
+				Override: map[ComponentName]*DatadogAgentComponentOverride{
+					NodeAgentComponentName: {
+						UpdateStrategy: &UpdateStrategy{Type: "Recreate"},
+					},
+					ClusterAgentComponentName: {
+						UpdateStrategy: &UpdateStrategy{Type: "Recreate", RollingUpdate: &RollingUpdate{MaxSurge: &intOne}},
+					},
+				},
+			},
+			wantFields: []string{
+				"spec.override[clusterAgent].updateStrategy.rollingUpdate",
+				"spec.override[nodeAgent].updateStrategy.type",
+			},
+		},
+		{
+			name: "valid update strategies",
+			spec: &DatadogAgentSpec{
+				Override: map[ComponentName]*DatadogAgentComponentOverride{
+					NodeAgentComponentName: {
+						UpdateStrategy: &UpdateStrategy{Type: "RollingUpdate", RollingUpdate: &RollingUpdate{MaxUnavailable: &intOne}},
+					},
+					ClusterChecksRunnerComponentName: {
+						UpdateStrategy: &UpdateStrategy{Type: "Recreate"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateDatadogAgentSpec(tt.spec, field.NewPath("spec"))
+			var gotFields []string
+			for _, err := range errs {
+				gotFields = append(gotFields, err.Field)
+			}
+			assert.Equal(t, tt.wantFields, gotFields)
+			assert.Equal(t, len(tt.wantFields) == 0, IsValidDatadogAgent(tt.spec) == nil)
+		})
+	}
+}
+
+func TestDatadogAgentValidateCreate(t *testing.T) {
+	dda := &DatadogAgent{
+		Spec: DatadogAgentSpec{
+			Features: &DatadogFeatures{
+				APM: &APMFeatureConfig{
+					SingleStepInstrumentation: &SingleStepInstrumentation{
+						Enabled: apiutils.NewBoolPointer(true),
+					},
+				},
+				AdmissionController: &AdmissionControllerFeatureConfig{
+					Enabled: apiutils.NewBoolPointer(false),
+				},
+			},
+		},
+	}
+	dda.Name = "foo"
+
+	err := dda.ValidateCreate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "spec.features.apm.instrumentation.enabled")
+
+	// Validation must not default the object itself
+	assert.Nil(t, dda.Spec.Global)
+}
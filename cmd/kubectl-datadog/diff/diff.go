@@ -0,0 +1,217 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent"
+	componentagent "github.com/DataDog/datadog-operator/controllers/datadogagent/component/agent"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+	"github.com/DataDog/datadog-operator/pkg/plugin/common"
+
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var diffExample = `
+  # diff the live DatadogAgent foo against the resources in the cluster
+  %[1]s diff foo
+
+  # diff the DatadogAgent defined in dda.yaml against the resources in the cluster
+  %[1]s diff -f dda.yaml
+`
+
+// options provides information required by Datadog diff command.
+type options struct {
+	genericclioptions.IOStreams
+	common.Options
+	args                 []string
+	userDatadogAgentName string
+	filename             string
+	supportCilium        bool
+	edsEnabled           bool
+}
+
+// newOptions provides an instance of options with default values.
+func newOptions(streams genericclioptions.IOStreams) *options {
+	o := &options{
+		IOStreams: streams,
+	}
+	o.SetConfigFlags()
+	return o
+}
+
+// New provides a cobra command wrapping options for "diff" sub command.
+func New(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(streams)
+	cmd := &cobra.Command{
+		Use:          "diff [DatadogAgent name] [-f DatadogAgent manifest]",
+		Short:        "Diff the resources of a DatadogAgent against the ones in the cluster",
+		Example:      fmt.Sprintf(diffExample, "kubectl datadog"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.filename, "filename", "f", "", "The file that contains the DatadogAgent (v2alpha1) manifest, the live DatadogAgent is used if not set")
+	cmd.Flags().BoolVar(&o.supportCilium, "support-cilium", false, "The operator manages the Cilium network policies")
+	cmd.Flags().BoolVar(&o.edsEnabled, "eds", false, "The operator deploys the Agent with an ExtendedDaemonSet")
+
+	o.ConfigFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// complete sets all information required for processing the command.
+func (o *options) complete(cmd *cobra.Command, args []string) error {
+	o.args = args
+	if len(args) > 0 {
+		o.userDatadogAgentName = args[0]
+	}
+	return o.Init(cmd)
+}
+
+// validate ensures that all required arguments and flag values are provided.
+func (o *options) validate() error {
+	if o.filename == "" && o.userDatadogAgentName == "" {
+		return errors.New("either the DatadogAgent name or its manifest (-f) is required")
+	}
+	if o.filename != "" && o.userDatadogAgentName != "" {
+		return errors.New("the DatadogAgent name and its manifest (-f) cannot be used together")
+	}
+	if !o.IsDatadogAgentV2Available() {
+		return errors.New("the diff command requires the v2alpha1 DatadogAgent")
+	}
+	return nil
+}
+
+// run runs the diff command.
+func (o *options) run() error {
+	ctx := context.TODO()
+
+	dda, err := o.getDatadogAgent(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The client needs to know all the types that can be rendered
+	scheme := common.NewRenderScheme()
+	restConfig, err := o.ConfigFlags.ToRESTConfig()
+	if err != nil {
+		return fmt.Errorf("unable to get rest client config: %w", err)
+	}
+	k8sClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("unable to instantiate client: %w", err)
+	}
+
+	renderOptions, err := o.renderOptions(scheme)
+	if err != nil {
+		return err
+	}
+	// The checksum of the referenced Secrets and ConfigMaps is part of the pod templates
+	renderOptions.Client = k8sClient
+	rendered, err := datadogagent.Render(dda, renderOptions)
+	if err != nil {
+		return fmt.Errorf("unable to render the DatadogAgent %s/%s: %w", dda.Namespace, dda.Name, err)
+	}
+
+	changes, err := rendered.Plan(ctx, k8sClient)
+	if err != nil {
+		return fmt.Errorf("unable to compare the DatadogAgent resources with the cluster: %w", err)
+	}
+
+	results := make([]*result, 0, len(changes))
+	for _, change := range changes {
+		res, diffErr := diffChange(ctx, k8sClient, scheme, change)
+		if diffErr != nil {
+			return diffErr
+		}
+		results = append(results, res)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].id < results[j].id
+	})
+
+	return printResults(o.Out, results)
+}
+
+// getDatadogAgent returns the DatadogAgent to render: either the one defined in the manifest or the live one.
+func (o *options) getDatadogAgent(ctx context.Context) (*v2alpha1.DatadogAgent, error) {
+	live := &v2alpha1.DatadogAgent{}
+	if o.filename == "" {
+		if err := o.Client.Get(ctx, client.ObjectKey{Namespace: o.UserNamespace, Name: o.userDatadogAgentName}, live); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("DatadogAgent %s/%s not found", o.UserNamespace, o.userDatadogAgentName)
+			}
+			return nil, fmt.Errorf("unable to get DatadogAgent: %w", err)
+		}
+		return live, nil
+	}
+
+	data, err := os.ReadFile(o.filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", o.filename, err)
+	}
+	dda, err := common.DecodeDatadogAgent(data)
+	if err != nil {
+		return nil, err
+	}
+	if dda.Namespace == "" {
+		dda.Namespace = o.UserNamespace
+	}
+
+	// The owner references and the generated Cluster Agent token depend on the live DatadogAgent
+	if err = o.Client.Get(ctx, client.ObjectKey{Namespace: dda.Namespace, Name: dda.Name}, live); err == nil {
+		dda.UID = live.UID
+		dda.Status = live.Status
+	} else if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("unable to get DatadogAgent: %w", err)
+	}
+
+	return dda, nil
+}
+
+func (o *options) renderOptions(scheme *runtime.Scheme) (datadogagent.RenderOptions, error) {
+	discoveryClient := o.Clientset.Discovery()
+	versionInfo, err := discoveryClient.ServerVersion()
+	if err != nil {
+		return datadogagent.RenderOptions{}, fmt.Errorf("unable to get the Kubernetes version: %w", err)
+	}
+	groups, resources, err := discoveryClient.ServerGroupsAndResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return datadogagent.RenderOptions{}, fmt.Errorf("unable to get API resource versions: %w", err)
+	}
+
+	return datadogagent.RenderOptions{
+		ExtendedDaemonsetOptions: componentagent.ExtendedDaemonsetOptions{
+			Enabled: o.edsEnabled,
+		},
+		SupportCilium: o.supportCilium,
+		VersionInfo:   versionInfo,
+		PlatformInfo:  kubernetes.NewPlatformInfo(versionInfo, groups, resources),
+		Scheme:        scheme,
+		Logger:        logr.Discard(),
+	}, nil
+}
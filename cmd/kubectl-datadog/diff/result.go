@@ -0,0 +1,220 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package diff
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/DataDog/datadog-operator/controllers/datadogagent"
+
+	"github.com/pmezard/go-difflib/difflib"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+// podsRestart tells if the pods of a workload are restarted by a change.
+type podsRestart string
+
+const (
+	podsRestartNone    podsRestart = ""
+	podsRestartYes     podsRestart = "pods will restart"
+	podsRestartUnknown podsRestart = "pods may restart"
+)
+
+// workloadKinds are the kinds of the objects that manage pods.
+var workloadKinds = map[string]struct{}{
+	"DaemonSet":         {},
+	"Deployment":        {},
+	"ExtendedDaemonSet": {},
+}
+
+// result is the diff of one object.
+type result struct {
+	id      string
+	action  datadogagent.PlannedAction
+	kind    string
+	name    string
+	diff    string
+	restart podsRestart
+}
+
+// diffChange returns the diff between the current object and the one the reconciler would apply.
+// The desired object goes through a server-side dry-run to get the defaulted values from the api-server,
+// when it fails the rendered object is used as is.
+func diffChange(ctx context.Context, k8sClient client.Client, scheme *runtime.Scheme, change datadogagent.PlannedChange) (*result, error) {
+	obj := change.Desired
+	if obj == nil {
+		obj = change.Current
+	}
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the kind of %s: %w", obj.GetName(), err)
+	}
+
+	name := obj.GetName()
+	if obj.GetNamespace() != "" {
+		name = obj.GetNamespace() + "/" + name
+	}
+	res := &result{
+		id:     fmt.Sprintf("%s/%s", gvk.Kind, name),
+		action: change.Action,
+		kind:   gvk.Kind,
+		name:   name,
+	}
+
+	desired, dryRunErr := dryRun(ctx, k8sClient, change)
+	currentData, err := normalizedYAML(change.Current, scheme)
+	if err != nil {
+		return nil, err
+	}
+	desiredData, err := normalizedYAML(desired, scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	res.diff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(currentData),
+		B:        splitLines(desiredData),
+		FromFile: "live/" + res.id,
+		ToFile:   "desired/" + res.id,
+		Context:  3,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, isWorkload := workloadKinds[gvk.Kind]; isWorkload && change.Action == datadogagent.PlannedUpdate {
+		switch {
+		case dryRunErr != nil:
+			res.restart = podsRestartUnknown
+		case podTemplateChanged(change.Current, desired, scheme):
+			res.restart = podsRestartYes
+		}
+	}
+
+	return res, nil
+}
+
+// dryRun sends the change to the api-server in dry-run mode and returns the resulting object.
+func dryRun(ctx context.Context, k8sClient client.Client, change datadogagent.PlannedChange) (client.Object, error) {
+	if change.Desired == nil {
+		return nil, nil
+	}
+
+	obj := change.Desired.DeepCopyObject().(client.Object)
+	var err error
+	switch change.Action {
+	case datadogagent.PlannedCreate:
+		err = k8sClient.Create(ctx, obj, client.DryRunAll)
+	case datadogagent.PlannedUpdate:
+		obj.SetResourceVersion(change.Current.GetResourceVersion())
+		err = k8sClient.Update(ctx, obj, client.DryRunAll)
+	}
+	if err != nil {
+		return change.Desired, err
+	}
+	return obj, nil
+}
+
+// normalizedYAML returns the YAML representation of an object without the fields set by the api-server.
+func normalizedYAML(obj client.Object, scheme *runtime.Scheme) (string, error) {
+	content, err := toUnstructured(obj, scheme)
+	if err != nil || content == nil {
+		return "", err
+	}
+
+	for _, field := range []string{"managedFields", "resourceVersion", "uid", "generation", "creationTimestamp", "selfLink"} {
+		unstructured.RemoveNestedField(content, "metadata", field)
+	}
+	// The pod templates are rendered without creationTimestamp, it's serialized as null
+	unstructured.RemoveNestedField(content, "spec", "template", "metadata", "creationTimestamp")
+	delete(content, "status")
+
+	data, err := yaml.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("unable to encode %s: %w", obj.GetName(), err)
+	}
+	return string(data), nil
+}
+
+func splitLines(data string) []string {
+	if data == "" {
+		return nil
+	}
+	return difflib.SplitLines(data)
+}
+
+// podTemplateChanged returns true if the pod templates of the two workloads are different.
+func podTemplateChanged(current, desired client.Object, scheme *runtime.Scheme) bool {
+	currentContent, err := toUnstructured(current, scheme)
+	if err != nil {
+		return true
+	}
+	desiredContent, err := toUnstructured(desired, scheme)
+	if err != nil {
+		return true
+	}
+
+	currentTemplate, _, _ := unstructured.NestedFieldNoCopy(currentContent, "spec", "template")
+	desiredTemplate, _, _ := unstructured.NestedFieldNoCopy(desiredContent, "spec", "template")
+	return !apiequality.Semantic.DeepEqual(currentTemplate, desiredTemplate)
+}
+
+func toUnstructured(obj client.Object, scheme *runtime.Scheme) (map[string]interface{}, error) {
+	if obj == nil {
+		return nil, nil
+	}
+
+	obj = obj.DeepCopyObject().(client.Object)
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the kind of %s: %w", obj.GetName(), err)
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert %s: %w", obj.GetName(), err)
+	}
+	return content, nil
+}
+
+// printResults writes the diff of each object followed by a summary of the changes.
+func printResults(out io.Writer, results []*result) error {
+	if len(results) == 0 {
+		_, err := fmt.Fprintln(out, "No differences found")
+		return err
+	}
+
+	counts := map[datadogagent.PlannedAction]int{}
+	for _, res := range results {
+		counts[res.action]++
+		if _, err := fmt.Fprint(out, res.diff); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintf(out, "\nSummary: %d to create, %d to update, %d to delete\n",
+		counts[datadogagent.PlannedCreate], counts[datadogagent.PlannedUpdate], counts[datadogagent.PlannedDelete]); err != nil {
+		return err
+	}
+	for _, res := range results {
+		line := fmt.Sprintf("  %-6s %s %s", res.action, res.kind, res.name)
+		if res.restart != podsRestartNone {
+			line = fmt.Sprintf("%s (%s)", line, res.restart)
+		}
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
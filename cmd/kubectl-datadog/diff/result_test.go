@@ -0,0 +1,131 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package diff
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/DataDog/datadog-operator/controllers/datadogagent"
+	"github.com/DataDog/datadog-operator/pkg/plugin/common"
+
+	assert "github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testDaemonSet(image string, labels map[string]string) *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "bar",
+			Name:      "foo-agent",
+			Labels:    labels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "agent", Image: image}},
+				},
+			},
+		},
+	}
+}
+
+func Test_diffChange(t *testing.T) {
+	scheme := common.NewRenderScheme()
+
+	tests := []struct {
+		name         string
+		change       func(current client.Object) datadogagent.PlannedChange
+		existing     bool
+		wantAction   datadogagent.PlannedAction
+		wantRestart  podsRestart
+		wantContains []string
+	}{
+		{
+			name: "create",
+			change: func(_ client.Object) datadogagent.PlannedChange {
+				return datadogagent.PlannedChange{Action: datadogagent.PlannedCreate, Desired: testDaemonSet("agent:7.50.0", nil)}
+			},
+			wantAction:   datadogagent.PlannedCreate,
+			wantRestart:  podsRestartNone,
+			wantContains: []string{"+++ desired/DaemonSet/bar/foo-agent", "+      - image: agent:7.50.0"},
+		},
+		{
+			name:     "update image",
+			existing: true,
+			change: func(current client.Object) datadogagent.PlannedChange {
+				return datadogagent.PlannedChange{Action: datadogagent.PlannedUpdate, Desired: testDaemonSet("agent:7.51.0", nil), Current: current}
+			},
+			wantAction:   datadogagent.PlannedUpdate,
+			wantRestart:  podsRestartYes,
+			wantContains: []string{"-      - image: agent:7.50.0", "+      - image: agent:7.51.0"},
+		},
+		{
+			name:     "update labels",
+			existing: true,
+			change: func(current client.Object) datadogagent.PlannedChange {
+				return datadogagent.PlannedChange{Action: datadogagent.PlannedUpdate, Desired: testDaemonSet("agent:7.50.0", map[string]string{"foo": "bar"}), Current: current}
+			},
+			wantAction:   datadogagent.PlannedUpdate,
+			wantRestart:  podsRestartNone,
+			wantContains: []string{"+    foo: bar"},
+		},
+		{
+			name:     "delete",
+			existing: true,
+			change: func(current client.Object) datadogagent.PlannedChange {
+				return datadogagent.PlannedChange{Action: datadogagent.PlannedDelete, Current: current}
+			},
+			wantAction:   datadogagent.PlannedDelete,
+			wantRestart:  podsRestartNone,
+			wantContains: []string{"--- live/DaemonSet/bar/foo-agent", "-      - image: agent:7.50.0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			current := testDaemonSet("agent:7.50.0", nil)
+			if tt.existing {
+				builder = builder.WithObjects(current)
+			}
+			k8sClient := builder.Build()
+			if tt.existing {
+				assert.NoError(t, k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(current), current))
+			}
+
+			res, err := diffChange(context.TODO(), k8sClient, scheme, tt.change(current))
+			assert.NoError(t, err)
+			assert.Equal(t, "DaemonSet/bar/foo-agent", res.id)
+			assert.Equal(t, tt.wantAction, res.action)
+			assert.Equal(t, tt.wantRestart, res.restart)
+			for _, s := range tt.wantContains {
+				assert.Contains(t, res.diff, s)
+			}
+			assert.NotContains(t, res.diff, "resourceVersion")
+			assert.NotContains(t, res.diff, "creationTimestamp")
+		})
+	}
+}
+
+func Test_printResults(t *testing.T) {
+	out := &bytes.Buffer{}
+	assert.NoError(t, printResults(out, nil))
+	assert.Equal(t, "No differences found\n", out.String())
+
+	out.Reset()
+	assert.NoError(t, printResults(out, []*result{
+		{action: datadogagent.PlannedCreate, kind: "ConfigMap", name: "bar/foo-confd", diff: "+data\n"},
+		{action: datadogagent.PlannedUpdate, kind: "DaemonSet", name: "bar/foo-agent", diff: "+image\n", restart: podsRestartYes},
+	}))
+	assert.Equal(t, "+data\n+image\n\nSummary: 1 to create, 1 to update, 0 to delete\n"+
+		"  create ConfigMap bar/foo-confd\n"+
+		"  update DaemonSet bar/foo-agent (pods will restart)\n", out.String())
+}
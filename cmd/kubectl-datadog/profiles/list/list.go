@@ -0,0 +1,149 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package list
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/DataDog/datadog-operator/pkg/agentprofile"
+	"github.com/DataDog/datadog-operator/pkg/plugin/common"
+
+	"github.com/go-logr/logr"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var listExample = `
+  # view all the DatadogAgentProfiles and the number of nodes they apply on
+  %[1]s list
+`
+
+// options provides information required by profiles list command.
+type options struct {
+	genericclioptions.IOStreams
+	common.Options
+	args []string
+}
+
+// newOptions provides an instance of options with default values.
+func newOptions(streams genericclioptions.IOStreams) *options {
+	o := &options{
+		IOStreams: streams,
+	}
+	o.SetConfigFlags()
+	return o
+}
+
+// New provides a cobra command wrapping options for "list" sub command.
+func New(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(streams)
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List the DatadogAgentProfiles and the number of nodes they apply on",
+		Example:      fmt.Sprintf(listExample, "kubectl datadog profiles"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			return o.run()
+		},
+	}
+
+	o.ConfigFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// complete sets all information required for processing the command.
+func (o *options) complete(cmd *cobra.Command, args []string) error {
+	o.args = args
+	return o.Init(cmd)
+}
+
+// validate ensures that all required arguments and flag values are provided.
+func (o *options) validate() error {
+	if len(o.args) > 0 {
+		return errors.New("no arguments are allowed")
+	}
+	return nil
+}
+
+// run runs the list command.
+func (o *options) run() error {
+	ctx := context.TODO()
+	profiles, nodes, err := common.ListProfilesAndNodes(ctx, o.Client)
+	if err != nil {
+		return err
+	}
+	var ddas []metav1.Object
+	if o.IsDatadogAgentV2Available() {
+		if ddas, err = common.ListDatadogAgents(ctx, o.Client); err != nil {
+			return err
+		}
+	}
+
+	statuses, err := agentprofile.ProfilesStatusForDatadogAgents(profiles, ddas, nodes, logr.Discard())
+	if err != nil {
+		return fmt.Errorf("unable to evaluate the DatadogAgentProfiles: %w", err)
+	}
+
+	table := newTable(o.Out)
+	for _, profile := range profiles {
+		status := statuses[types.NamespacedName{Namespace: profile.Namespace, Name: profile.Name}]
+		table.Append([]string{
+			profile.Namespace,
+			profile.Name,
+			profileState(status),
+			strconv.Itoa(status.MatchedNodes),
+			strconv.Itoa(status.MigratedNodes + status.PendingNodes),
+			common.GetDurationAsString(&profile),
+		})
+	}
+	table.Render()
+
+	return nil
+}
+
+// profileState returns a short description of the status of a profile.
+func profileState(status agentprofile.ProfileStatus) string {
+	switch {
+	case status.ValidationError != nil:
+		return "Invalid"
+	case status.ConflictingProfile != nil:
+		return fmt.Sprintf("Conflict with %s", status.ConflictingProfile.String())
+	case status.NoDatadogAgent:
+		return "No DatadogAgent"
+	case status.Applied:
+		return "Applied"
+	default:
+		return ""
+	}
+}
+
+func newTable(out io.Writer) *tablewriter.Table {
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"Namespace", "Name", "Status", "Matched", "Nodes", "Age"})
+	table.SetBorders(tablewriter.Border{Left: false, Top: false, Right: false, Bottom: false})
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetRowLine(false)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeaderLine(false)
+	return table
+}
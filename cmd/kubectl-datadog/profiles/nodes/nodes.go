@@ -0,0 +1,125 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package nodes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/DataDog/datadog-operator/pkg/agentprofile"
+	"github.com/DataDog/datadog-operator/pkg/plugin/common"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var nodesExample = `
+  # view the DatadogAgentProfile of each node
+  %[1]s nodes
+`
+
+// options provides information required by profiles nodes command.
+type options struct {
+	genericclioptions.IOStreams
+	common.Options
+	args []string
+}
+
+// newOptions provides an instance of options with default values.
+func newOptions(streams genericclioptions.IOStreams) *options {
+	o := &options{
+		IOStreams: streams,
+	}
+	o.SetConfigFlags()
+	return o
+}
+
+// New provides a cobra command wrapping options for "nodes" sub command.
+func New(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(streams)
+	cmd := &cobra.Command{
+		Use:          "nodes",
+		Short:        "Show the DatadogAgentProfile of each node",
+		Example:      fmt.Sprintf(nodesExample, "kubectl datadog profiles"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			return o.run()
+		},
+	}
+
+	o.ConfigFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// complete sets all information required for processing the command.
+func (o *options) complete(cmd *cobra.Command, args []string) error {
+	o.args = args
+	return o.Init(cmd)
+}
+
+// validate ensures that all required arguments and flag values are provided.
+func (o *options) validate() error {
+	if len(o.args) > 0 {
+		return errors.New("no arguments are allowed")
+	}
+	return nil
+}
+
+// run runs the nodes command.
+// The profile of a node is read from its profile label, the nodes without the
+// label get the default profile.
+func (o *options) run() error {
+	profiles, nodes, err := common.ListProfilesAndNodes(context.TODO(), o.Client)
+	if err != nil {
+		return err
+	}
+
+	profilesByLabel := make(map[string]string, len(profiles)+1)
+	profilesByLabel[""] = common.DefaultProfileDisplayName
+	for _, profile := range profiles {
+		namespacedName := types.NamespacedName{Namespace: profile.Namespace, Name: profile.Name}
+		profilesByLabel[agentprofile.NodeLabelValue(namespacedName)] = common.ProfileDisplayName(namespacedName)
+	}
+
+	table := newTable(o.Out)
+	for _, node := range nodes {
+		label := node.Labels[agentprofile.ProfileLabelKey]
+		profile, found := profilesByLabel[label]
+		if !found {
+			// Merged profiles and profiles that were deleted are displayed with the label value
+			profile = label
+		}
+		table.Append([]string{node.Name, profile})
+	}
+	table.Render()
+
+	return nil
+}
+
+func newTable(out io.Writer) *tablewriter.Table {
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"Node", "Profile"})
+	table.SetBorders(tablewriter.Border{Left: false, Top: false, Right: false, Bottom: false})
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetRowLine(false)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeaderLine(false)
+	return table
+}
@@ -0,0 +1,45 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package profiles
+
+import (
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/profiles/list"
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/profiles/nodes"
+	"github.com/DataDog/datadog-operator/cmd/kubectl-datadog/profiles/simulate"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// options provides information required by profiles command
+type options struct {
+	genericclioptions.IOStreams
+	configFlags *genericclioptions.ConfigFlags
+}
+
+// newOptions provides an instance of options with default values
+func newOptions(streams genericclioptions.IOStreams) *options {
+	return &options{
+		configFlags: genericclioptions.NewConfigFlags(false),
+		IOStreams:   streams,
+	}
+}
+
+// New provides a cobra command wrapping options for "profiles" sub command
+func New(streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use: "profiles [subcommand] [flags]",
+	}
+
+	cmd.AddCommand(list.New(streams))
+	cmd.AddCommand(nodes.New(streams))
+	cmd.AddCommand(simulate.New(streams))
+
+	o := newOptions(streams)
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
@@ -0,0 +1,318 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package simulate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/agentprofile"
+	"github.com/DataDog/datadog-operator/pkg/plugin/common"
+
+	"github.com/go-logr/logr"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var simulateExample = `
+  # view the nodes whose profile would change if the profile.yaml DatadogAgentProfile was applied
+  %[1]s simulate -f profile.yaml
+`
+
+// options provides information required by profiles simulate command.
+type options struct {
+	genericclioptions.IOStreams
+	common.Options
+	args     []string
+	filename string
+}
+
+// nodeChange is a node whose profile would change for a DatadogAgent.
+type nodeChange struct {
+	datadogAgent string
+	node         string
+	from         types.NamespacedName
+	to           types.NamespacedName
+}
+
+// nodeConflict is a node that matches the candidate profile, but on which it
+// would not be applied for a DatadogAgent because another profile takes precedence.
+type nodeConflict struct {
+	datadogAgent string
+	node         string
+	profile      types.NamespacedName
+}
+
+// result is the outcome of the simulation of a candidate profile.
+type result struct {
+	// validationError is the error returned by the validation of the candidate profile.
+	validationError error
+	// datadogAgents are the DatadogAgents that the candidate profile applies to.
+	// It's empty when there are no DatadogAgents to evaluate the profiles for.
+	datadogAgents []string
+	// changes are the nodes whose profile would change.
+	changes []nodeChange
+	// conflicts are the nodes on which the candidate profile would not be applied.
+	conflicts []nodeConflict
+}
+
+// newOptions provides an instance of options with default values.
+func newOptions(streams genericclioptions.IOStreams) *options {
+	o := &options{
+		IOStreams: streams,
+	}
+	o.SetConfigFlags()
+	return o
+}
+
+// New provides a cobra command wrapping options for "simulate" sub command.
+func New(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(streams)
+	cmd := &cobra.Command{
+		Use:          "simulate -f <file>",
+		Short:        "Show the nodes whose profile would change if a DatadogAgentProfile was applied",
+		Example:      fmt.Sprintf(simulateExample, "kubectl datadog profiles"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.filename, "filename", "f", "", "The file that contains the DatadogAgentProfile (v1alpha1) manifest")
+	o.ConfigFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// complete sets all information required for processing the command.
+func (o *options) complete(cmd *cobra.Command, args []string) error {
+	o.args = args
+	return o.Init(cmd)
+}
+
+// validate ensures that all required arguments and flag values are provided.
+func (o *options) validate() error {
+	if len(o.args) > 0 {
+		return errors.New("no arguments are allowed")
+	}
+	if o.filename == "" {
+		return errors.New("the DatadogAgentProfile manifest (-f) is required")
+	}
+	return nil
+}
+
+// run runs the simulate command.
+func (o *options) run() error {
+	data, err := os.ReadFile(o.filename)
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %w", o.filename, err)
+	}
+	candidate, err := common.DecodeDatadogAgentProfile(data)
+	if err != nil {
+		return err
+	}
+	if candidate.Namespace == "" {
+		candidate.Namespace = o.UserNamespace
+	}
+
+	ctx := context.TODO()
+	profiles, nodes, err := common.ListProfilesAndNodes(ctx, o.Client)
+	if err != nil {
+		return err
+	}
+	var ddas []metav1.Object
+	if o.IsDatadogAgentV2Available() {
+		if ddas, err = common.ListDatadogAgents(ctx, o.Client); err != nil {
+			return err
+		}
+	}
+
+	res, err := simulate(profiles, candidate, ddas, nodes, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return printResult(o.Out, candidate, len(ddas) > 0, res)
+}
+
+// simulate compares the profiles applied on the nodes with the live profiles
+// and with the live profiles plus the candidate profile. The candidate replaces
+// the live profile with the same namespace and name, if any, and keeps its
+// creation timestamp. Otherwise, it's considered as created now.
+// The profiles are evaluated for each of the DatadogAgents that the candidate
+// applies to, with the other profiles that apply to the same DatadogAgent.
+func simulate(profiles []v1alpha1.DatadogAgentProfile, candidate *v1alpha1.DatadogAgentProfile, ddas []metav1.Object, nodes []corev1.Node, now time.Time) (*result, error) {
+	candidate = candidate.DeepCopy()
+	candidate.CreationTimestamp = metav1.NewTime(now)
+
+	simulatedProfiles := make([]v1alpha1.DatadogAgentProfile, 0, len(profiles)+1)
+	for _, profile := range profiles {
+		if profile.Namespace == candidate.Namespace && profile.Name == candidate.Name {
+			candidate.CreationTimestamp = profile.CreationTimestamp
+			continue
+		}
+		simulatedProfiles = append(simulatedProfiles, profile)
+	}
+	simulatedProfiles = append(simulatedProfiles, *candidate)
+
+	res := &result{}
+	if len(ddas) == 0 {
+		// Without DatadogAgents, all the profiles are evaluated together
+		if err := simulateForDatadogAgent(res, profiles, simulatedProfiles, candidate, nil, nodes); err != nil {
+			return nil, err
+		}
+	}
+	for _, dda := range ddas {
+		if !agentprofile.ProfileAppliesToDatadogAgent(candidate, dda) {
+			continue
+		}
+		err := simulateForDatadogAgent(res, agentprofile.ProfilesForDatadogAgent(profiles, dda), agentprofile.ProfilesForDatadogAgent(simulatedProfiles, dda), candidate, dda, nodes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(ddas) > 0 && len(res.datadogAgents) == 0 {
+		res.validationError = v1alpha1.ValidateDatadogAgentProfileSpec(&candidate.Spec)
+	}
+
+	sort.Slice(res.changes, func(i, j int) bool {
+		if res.changes[i].datadogAgent != res.changes[j].datadogAgent {
+			return res.changes[i].datadogAgent < res.changes[j].datadogAgent
+		}
+		return res.changes[i].node < res.changes[j].node
+	})
+	sort.Slice(res.conflicts, func(i, j int) bool {
+		if res.conflicts[i].datadogAgent != res.conflicts[j].datadogAgent {
+			return res.conflicts[i].datadogAgent < res.conflicts[j].datadogAgent
+		}
+		return res.conflicts[i].node < res.conflicts[j].node
+	})
+
+	return res, nil
+}
+
+// simulateForDatadogAgent adds to the result the changes and the conflicts of the candidate profile for a DatadogAgent.
+func simulateForDatadogAgent(res *result, profiles, simulatedProfiles []v1alpha1.DatadogAgentProfile, candidate *v1alpha1.DatadogAgentProfile, dda metav1.Object, nodes []corev1.Node) error {
+	datadogAgent := ""
+	if dda != nil {
+		datadogAgent = types.NamespacedName{Namespace: dda.GetNamespace(), Name: dda.GetName()}.String()
+		res.datadogAgents = append(res.datadogAgents, datadogAgent)
+	}
+
+	_, currentProfilesByNode, err := agentprofile.ProfilesToApply(profiles, nodes, logr.Discard())
+	if err != nil {
+		return fmt.Errorf("unable to evaluate the live DatadogAgentProfiles: %w", err)
+	}
+	_, simulatedProfilesByNode, err := agentprofile.ProfilesToApply(simulatedProfiles, nodes, logr.Discard())
+	if err != nil {
+		return fmt.Errorf("unable to evaluate the DatadogAgentProfiles: %w", err)
+	}
+	statuses, err := agentprofile.ProfilesStatus(simulatedProfiles, nodes, logr.Discard())
+	if err != nil {
+		return fmt.Errorf("unable to evaluate the DatadogAgentProfiles: %w", err)
+	}
+
+	candidateStatus := statuses[types.NamespacedName{Namespace: candidate.Namespace, Name: candidate.Name}]
+	res.validationError = candidateStatus.ValidationError
+	conflictingProfile := candidateStatus.ConflictingProfile
+
+	for _, node := range nodes {
+		if current, simulated := currentProfilesByNode[node.Name], simulatedProfilesByNode[node.Name]; current != simulated {
+			res.changes = append(res.changes, nodeChange{datadogAgent: datadogAgent, node: node.Name, from: current, to: simulated})
+		}
+
+		if conflictingProfile == nil {
+			continue
+		}
+		// A profile that conflicts is not applied on any of the nodes it matches
+		matchesNode, err := agentprofile.ProfileMatchesNode(candidate, &node)
+		if err != nil {
+			return err
+		}
+		if matchesNode {
+			res.conflicts = append(res.conflicts, nodeConflict{datadogAgent: datadogAgent, node: node.Name, profile: *conflictingProfile})
+		}
+	}
+
+	return nil
+}
+
+func printResult(out io.Writer, candidate *v1alpha1.DatadogAgentProfile, hasDatadogAgents bool, res *result) error {
+	if res.validationError != nil {
+		_, err := fmt.Fprintf(out, "DatadogAgentProfile %s/%s is invalid and would not be applied: %v\n", candidate.Namespace, candidate.Name, res.validationError)
+		return err
+	}
+
+	if hasDatadogAgents && len(res.datadogAgents) == 0 {
+		_, err := fmt.Fprintf(out, "DatadogAgentProfile %s/%s doesn't apply to any DatadogAgent\n", candidate.Namespace, candidate.Name)
+		return err
+	}
+
+	for _, conflict := range res.conflicts {
+		if _, err := fmt.Fprintf(out, "DatadogAgentProfile %s/%s conflicts with %s on node %s%s and would not be applied there\n",
+			candidate.Namespace, candidate.Name, conflict.profile.String(), conflict.node, forDatadogAgent(conflict.datadogAgent)); err != nil {
+			return err
+		}
+	}
+
+	if len(res.changes) == 0 {
+		_, err := fmt.Fprintln(out, "No node would change profile")
+		return err
+	}
+
+	table := newTable(out, hasDatadogAgents)
+	for _, change := range res.changes {
+		var row []string
+		if hasDatadogAgents {
+			row = append(row, change.datadogAgent)
+		}
+		table.Append(append(row, change.node, common.ProfileDisplayName(change.from), common.ProfileDisplayName(change.to)))
+	}
+	table.Render()
+
+	return nil
+}
+
+func forDatadogAgent(datadogAgent string) string {
+	if datadogAgent == "" {
+		return ""
+	}
+	return " for DatadogAgent " + datadogAgent
+}
+
+func newTable(out io.Writer, hasDatadogAgents bool) *tablewriter.Table {
+	table := tablewriter.NewWriter(out)
+	header := []string{"Node", "Current Profile", "New Profile"}
+	if hasDatadogAgents {
+		header = append([]string{"DatadogAgent"}, header...)
+	}
+	table.SetHeader(header)
+	table.SetBorders(tablewriter.Border{Left: false, Top: false, Right: false, Bottom: false})
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetRowLine(false)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeaderLine(false)
+	return table
+}
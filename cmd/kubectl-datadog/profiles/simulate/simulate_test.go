@@ -0,0 +1,200 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package simulate
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/DataDog/datadog-operator/pkg/agentprofile"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func Test_simulate(t *testing.T) {
+	now := time.Now()
+	defaultNamespacedName := types.NamespacedName{Name: "default"}
+
+	newNode := func(name, os, pool string) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"os": os, "pool": pool},
+			},
+		}
+	}
+	newProfile := func(name string, created time.Time, key, value string) v1alpha1.DatadogAgentProfile {
+		return v1alpha1.DatadogAgentProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         "agents",
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Spec: v1alpha1.DatadogAgentProfileSpec{
+				ProfileAffinity: &v1alpha1.ProfileAffinity{
+					ProfileNodeAffinity: []corev1.NodeSelectorRequirement{
+						{Key: key, Operator: corev1.NodeSelectorOpIn, Values: []string{value}},
+					},
+				},
+				Config: &v1alpha1.Config{
+					Override: map[v1alpha1.ComponentName]*v1alpha1.Override{
+						v1alpha1.NodeAgentComponentName: {
+							PriorityClassName: apiutils.NewStringPointer(name),
+						},
+					},
+				},
+			},
+		}
+	}
+
+	nodes := []corev1.Node{
+		newNode("node-b", "linux", "gpu"),
+		newNode("node-a", "linux", "default"),
+		newNode("node-c", "windows", "default"),
+	}
+	linux := newProfile("linux", now.Add(-time.Hour), "os", "linux")
+	linuxNamespacedName := types.NamespacedName{Namespace: "agents", Name: "linux"}
+	gpuNamespacedName := types.NamespacedName{Namespace: "agents", Name: "gpu"}
+
+	agentA := &metav1.ObjectMeta{Namespace: "agents", Name: "agent-a"}
+	agentB := &metav1.ObjectMeta{Namespace: "agents", Name: "agent-b"}
+	linuxForAgentA := newProfile("linux", now.Add(-time.Hour), "os", "linux")
+	linuxForAgentA.Spec.DatadogAgentRef = &v1alpha1.DatadogAgentReference{Name: "agent-a"}
+	gpuForAgentC := newProfile("gpu", now, "pool", "gpu")
+	gpuForAgentC.Spec.DatadogAgentRef = &v1alpha1.DatadogAgentReference{Name: "agent-c"}
+
+	tests := []struct {
+		name      string
+		profiles  []v1alpha1.DatadogAgentProfile
+		candidate v1alpha1.DatadogAgentProfile
+		ddas      []metav1.Object
+		want      *result
+	}{
+		{
+			name:      "new profile, the matching nodes change profile",
+			candidate: linux,
+			want: &result{
+				changes: []nodeChange{
+					{node: "node-a", from: defaultNamespacedName, to: linuxNamespacedName},
+					{node: "node-b", from: defaultNamespacedName, to: linuxNamespacedName},
+				},
+			},
+		},
+		{
+			name:      "unchanged profile, no changes",
+			profiles:  []v1alpha1.DatadogAgentProfile{linux},
+			candidate: linux,
+			want:      &result{},
+		},
+		{
+			name:      "updated profile replaces the live one",
+			profiles:  []v1alpha1.DatadogAgentProfile{linux},
+			candidate: newProfile("linux", now, "pool", "gpu"),
+			want: &result{
+				changes: []nodeChange{
+					{node: "node-a", from: linuxNamespacedName, to: defaultNamespacedName},
+				},
+			},
+		},
+		{
+			name:      "newer profile conflicts with the live one",
+			profiles:  []v1alpha1.DatadogAgentProfile{linux},
+			candidate: newProfile("gpu", now, "pool", "gpu"),
+			want: &result{
+				conflicts: []nodeConflict{
+					{node: "node-b", profile: linuxNamespacedName},
+				},
+			},
+		},
+		{
+			name:      "profile of another DatadogAgent doesn't conflict",
+			profiles:  []v1alpha1.DatadogAgentProfile{linuxForAgentA},
+			candidate: newProfile("gpu", now, "pool", "gpu"),
+			ddas:      []metav1.Object{agentA, agentB},
+			want: &result{
+				datadogAgents: []string{"agents/agent-a", "agents/agent-b"},
+				changes: []nodeChange{
+					{datadogAgent: "agents/agent-b", node: "node-b", from: defaultNamespacedName, to: gpuNamespacedName},
+				},
+				conflicts: []nodeConflict{
+					{datadogAgent: "agents/agent-a", node: "node-b", profile: linuxNamespacedName},
+				},
+			},
+		},
+		{
+			name:      "profile that doesn't apply to any DatadogAgent",
+			candidate: gpuForAgentC,
+			ddas:      []metav1.Object{agentA, agentB},
+			want:      &result{},
+		},
+		{
+			name:     "invalid profile",
+			profiles: []v1alpha1.DatadogAgentProfile{linux},
+			candidate: v1alpha1.DatadogAgentProfile{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "agents", Name: "invalid"},
+			},
+			want: &result{
+				validationError: v1alpha1.ValidateDatadogAgentProfileSpec(&v1alpha1.DatadogAgentProfileSpec{}),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := simulate(test.profiles, &test.candidate, test.ddas, nodes, now)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func Test_printResult(t *testing.T) {
+	candidate := &v1alpha1.DatadogAgentProfile{ObjectMeta: metav1.ObjectMeta{Namespace: "agents", Name: "gpu"}}
+	gpu := types.NamespacedName{Namespace: "agents", Name: "gpu"}
+	linux := types.NamespacedName{Namespace: "agents", Name: "linux"}
+	defaultNamespacedName := types.NamespacedName{Name: "default"}
+	require.True(t, agentprofile.IsDefaultProfile(defaultNamespacedName.Namespace, defaultNamespacedName.Name))
+
+	out := &bytes.Buffer{}
+	err := printResult(out, candidate, false, &result{
+		changes: []nodeChange{
+			{node: "node-a", from: defaultNamespacedName, to: gpu},
+		},
+		conflicts: []nodeConflict{
+			{node: "node-b", profile: linux},
+		},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "DatadogAgentProfile agents/gpu conflicts with agents/linux on node node-b and would not be applied there")
+	assert.Regexp(t, `node-a\s+default\s+agents/gpu`, out.String())
+
+	out.Reset()
+	err = printResult(out, candidate, true, &result{
+		datadogAgents: []string{"agents/agent-a"},
+		changes: []nodeChange{
+			{datadogAgent: "agents/agent-a", node: "node-a", from: defaultNamespacedName, to: gpu},
+		},
+	})
+	require.NoError(t, err)
+	assert.Regexp(t, `agents/agent-a\s+node-a\s+default\s+agents/gpu`, out.String())
+
+	out.Reset()
+	err = printResult(out, candidate, true, &result{})
+	require.NoError(t, err)
+	assert.Equal(t, "DatadogAgentProfile agents/gpu doesn't apply to any DatadogAgent\n", out.String())
+
+	out.Reset()
+	err = printResult(out, candidate, false, &result{})
+	require.NoError(t, err)
+	assert.Equal(t, "No node would change profile\n", out.String())
+}
@@ -0,0 +1,144 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package render
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/DataDog/datadog-operator/controllers/datadogagent"
+	componentagent "github.com/DataDog/datadog-operator/controllers/datadogagent/component/agent"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+	"github.com/DataDog/datadog-operator/pkg/plugin/common"
+
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+const defaultKubeVersion = "v1.28.0"
+
+var renderExample = `
+  # render the resources created for the DatadogAgent defined in dda.yaml
+  %[1]s render -f dda.yaml
+
+  # render the resources for a Kubernetes 1.20 cluster that only serves the policy/v1beta1 PodDisruptionBudget
+  %[1]s render -f dda.yaml --kube-version v1.20.0 --api-resource PodDisruptionBudget=policy/v1beta1
+`
+
+// options provides information required by Datadog render command.
+type options struct {
+	genericclioptions.IOStreams
+	filename      string
+	namespace     string
+	kubeVersion   string
+	apiResources  map[string]string
+	supportCilium bool
+	edsEnabled    bool
+
+	versionInfo *version.Info
+}
+
+// newOptions provides an instance of options with default values.
+func newOptions(streams genericclioptions.IOStreams) *options {
+	return &options{
+		IOStreams:    streams,
+		kubeVersion:  defaultKubeVersion,
+		apiResources: map[string]string{"PodDisruptionBudget": "policy/v1"},
+	}
+}
+
+// New provides a cobra command wrapping options for "render" sub command.
+func New(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(streams)
+	cmd := &cobra.Command{
+		Use:          "render -f [DatadogAgent manifest]",
+		Short:        "Render the resources created by the operator for a DatadogAgent, without a cluster",
+		Example:      fmt.Sprintf(renderExample, "kubectl datadog"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.filename, "filename", "f", "", "The file that contains the DatadogAgent (v2alpha1) manifest")
+	cmd.Flags().StringVarP(&o.namespace, "namespace", "n", "default", "The namespace of the DatadogAgent if it's not set in the manifest")
+	cmd.Flags().StringVar(&o.kubeVersion, "kube-version", o.kubeVersion, "The Kubernetes version of the target cluster")
+	cmd.Flags().StringToStringVar(&o.apiResources, "api-resource", o.apiResources, "The preferred group version of the resource kinds served by the target cluster, e.g. PodDisruptionBudget=policy/v1")
+	cmd.Flags().BoolVar(&o.supportCilium, "support-cilium", false, "Render the Cilium network policies")
+	cmd.Flags().BoolVar(&o.edsEnabled, "eds", false, "Render an ExtendedDaemonSet instead of a DaemonSet for the Agent")
+
+	return cmd
+}
+
+// complete sets all information required for processing the command.
+func (o *options) complete(cmd *cobra.Command, args []string) error {
+	v, err := utilversion.ParseGeneric(o.kubeVersion)
+	if err != nil {
+		return fmt.Errorf("invalid Kubernetes version %q: %w", o.kubeVersion, err)
+	}
+	o.versionInfo = &version.Info{
+		Major:      fmt.Sprint(v.Major()),
+		Minor:      fmt.Sprint(v.Minor()),
+		GitVersion: o.kubeVersion,
+	}
+	return nil
+}
+
+// validate ensures that all required arguments and flag values are provided.
+func (o *options) validate() error {
+	if o.filename == "" {
+		return errors.New("the DatadogAgent manifest is required, use -f")
+	}
+	return nil
+}
+
+// run runs the render command.
+func (o *options) run() error {
+	data, err := os.ReadFile(o.filename)
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %w", o.filename, err)
+	}
+
+	dda, err := common.DecodeDatadogAgent(data)
+	if err != nil {
+		return err
+	}
+	if dda.Namespace == "" {
+		dda.Namespace = o.namespace
+	}
+
+	scheme := common.NewRenderScheme()
+	rendered, err := datadogagent.Render(dda, o.renderOptions(scheme))
+	if err != nil {
+		return fmt.Errorf("unable to render the DatadogAgent %s/%s: %w", dda.Namespace, dda.Name, err)
+	}
+
+	return common.PrintObjectsYAML(o.Out, rendered.Objects(), scheme)
+}
+
+func (o *options) renderOptions(scheme *runtime.Scheme) datadogagent.RenderOptions {
+	return datadogagent.RenderOptions{
+		ExtendedDaemonsetOptions: componentagent.ExtendedDaemonsetOptions{
+			Enabled: o.edsEnabled,
+		},
+		SupportCilium: o.supportCilium,
+		VersionInfo:   o.versionInfo,
+		PlatformInfo:  kubernetes.NewPlatformInfoFromVersionMaps(o.versionInfo, o.apiResources, map[string]string{}),
+		Scheme:        scheme,
+		Logger:        logr.Discard(),
+	}
+}
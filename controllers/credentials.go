@@ -0,0 +1,24 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package controllers
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+// removeDeletedCredentials drops the Datadog client of the credentials of a deleted Secret.
+func removeDeletedCredentials(clients *datadogclient.ClientCache) handler.Funcs {
+	return handler.Funcs{
+		DeleteFunc: func(e event.DeleteEvent, _ workqueue.RateLimitingInterface) {
+			clients.Remove(types.NamespacedName{Namespace: e.Object.GetNamespace(), Name: e.Object.GetName()})
+		},
+	}
+}
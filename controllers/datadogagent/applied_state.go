@@ -0,0 +1,109 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"fmt"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+)
+
+// appliedStates keeps, for each DatadogAgent, the state of its last successful dependencies apply.
+// It allows to skip the Apply and the Cleanup of the dependencies Store when nothing changed since then.
+// The drift of the applied objects is caught by the watch events on the owned objects, which invalidate the state.
+type appliedStates struct {
+	mutex  sync.Mutex
+	states map[types.NamespacedName]appliedState
+}
+
+// appliedState is the state of the dependencies applied for a DatadogAgent.
+type appliedState struct {
+	// generation is the generation of the DatadogAgent observed during the apply.
+	generation int64
+	// hashes contains the hash of each object of the dependencies Store, by kind, namespace and name.
+	hashes map[string]string
+}
+
+func newAppliedStates() *appliedStates {
+	return &appliedStates{
+		states: make(map[types.NamespacedName]appliedState),
+	}
+}
+
+// isUnchanged returns true if the dependencies were already applied with the same DatadogAgent generation and objects.
+func (s *appliedStates) isUnchanged(key types.NamespacedName, state appliedState) bool {
+	if s == nil {
+		return false
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	previous, found := s.states[key]
+	if !found || previous.generation != state.generation || len(previous.hashes) != len(state.hashes) {
+		return false
+	}
+	for id, hash := range state.hashes {
+		if previous.hashes[id] != hash {
+			return false
+		}
+	}
+	return true
+}
+
+// set records the state of the dependencies applied for a DatadogAgent.
+func (s *appliedStates) set(key types.NamespacedName, state appliedState) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.states[key] = state
+}
+
+// invalidate forgets the state of a DatadogAgent, its dependencies are applied during the next reconcile.
+func (s *appliedStates) invalidate(key types.NamespacedName) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.states, key)
+}
+
+// buildAppliedState returns the state of the dependencies Store of a DatadogAgent.
+// It must be called before the Store is applied, since the apply updates the objects with the api-server response.
+func buildAppliedState(dda metav1.Object, store *dependencies.Store) (appliedState, error) {
+	state := appliedState{
+		generation: dda.GetGeneration(),
+		hashes:     make(map[string]string),
+	}
+	for _, obj := range store.Objects() {
+		hash, err := comparison.GenerateMD5ForSpec(obj)
+		if err != nil {
+			return state, err
+		}
+		state.hashes[appliedObjectID(obj)] = hash
+	}
+	return state, nil
+}
+
+func appliedObjectID(obj client.Object) string {
+	return fmt.Sprintf("%T/%s/%s", obj, obj.GetNamespace(), obj.GetName())
+}
+
+// InvalidateAppliedState forgets the state of the last dependencies apply of a DatadogAgent,
+// so that its dependencies are applied again during the next reconcile.
+func (r *Reconciler) InvalidateAppliedState(key types.NamespacedName) {
+	r.appliedStates.invalidate(key)
+}
@@ -0,0 +1,71 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"testing"
+
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func Test_appliedStates(t *testing.T) {
+	dda := &datadoghqv2alpha1.DatadogAgent{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo", Generation: 1},
+	}
+	key := types.NamespacedName{Namespace: "bar", Name: "foo"}
+	newStore := func(data string) *dependencies.Store {
+		store := dependencies.NewStore(nil, nil)
+		assert.NoError(t, store.AddOrUpdate(kubernetes.ConfigMapKind, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "config"},
+			Data:       map[string]string{"key": data},
+		}))
+		return store
+	}
+
+	states := newAppliedStates()
+	state, err := buildAppliedState(dda, newStore("value"))
+	assert.NoError(t, err)
+	assert.Len(t, state.hashes, 1)
+	assert.False(t, states.isUnchanged(key, state), "nothing applied yet")
+
+	states.set(key, state)
+	sameState, err := buildAppliedState(dda, newStore("value"))
+	assert.NoError(t, err)
+	assert.True(t, states.isUnchanged(key, sameState))
+
+	// An object of the store changed
+	changedState, err := buildAppliedState(dda, newStore("other-value"))
+	assert.NoError(t, err)
+	assert.False(t, states.isUnchanged(key, changedState))
+
+	// An object was removed from the store
+	emptyState, err := buildAppliedState(dda, dependencies.NewStore(nil, nil))
+	assert.NoError(t, err)
+	assert.False(t, states.isUnchanged(key, emptyState))
+
+	// The DatadogAgent generation changed
+	dda.Generation = 2
+	newGenerationState, err := buildAppliedState(dda, newStore("value"))
+	assert.NoError(t, err)
+	assert.False(t, states.isUnchanged(key, newGenerationState))
+
+	// An owned object event invalidates the state
+	r := &Reconciler{appliedStates: states}
+	r.InvalidateAppliedState(key)
+	assert.False(t, states.isUnchanged(key, state))
+
+	// Without applied states, the dependencies are always applied
+	var nilStates *appliedStates
+	nilStates.set(key, state)
+	assert.False(t, nilStates.isUnchanged(key, state))
+}
@@ -85,6 +85,8 @@ type Reconciler struct {
 	log          logr.Logger
 	recorder     record.EventRecorder
 	forwarders   datadog.MetricForwardersManager
+
+	appliedStates *appliedStates
 }
 
 // NewReconciler returns a reconciler for DatadogAgent
@@ -99,6 +101,8 @@ func NewReconciler(options ReconcilerOptions, client client.Client, versionInfo
 		log:          log,
 		recorder:     recorder,
 		forwarders:   metricForwarder,
+
+		appliedStates: newAppliedStates(),
 	}, nil
 }
 
@@ -38,72 +38,17 @@ func (r *Reconciler) reconcileV2Agent(logger logr.Logger, requiredComponents fea
 	dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers, newStatus *datadoghqv2alpha1.DatadogAgentStatus,
 	provider string, providerList map[string]struct{}, profile *v1alpha1.DatadogAgentProfile) (reconcile.Result, error) {
 	var result reconcile.Result
-	var eds *edsv1alpha1.ExtendedDaemonSet
-	var daemonset *appsv1.DaemonSet
-	var podManagers feature.PodTemplateManagers
 
 	daemonsetLogger := logger.WithValues("component", datadoghqv2alpha1.NodeAgentComponentName)
 
 	// requiredComponents needs to be taken into account in case a feature(s) changes and
 	// a requiredComponent becomes disabled, in addition to taking into account override.Disabled
-	disabledByOverride := false
-
 	agentEnabled := requiredComponents.Agent.IsEnabled()
-	singleContainerStrategyEnabled := requiredComponents.Agent.SingleContainerStrategyEnabled()
-
-	// When EDS is enabled and there are profiles defined, we only create an
-	// EDS for the default profile, for the other profiles we create
-	// DaemonSets.
-	// This is to make deployments simpler. With multiple EDS there would be
-	// multiple canaries, etc.
-	if (r.options.ExtendedDaemonsetOptions.Enabled && !r.options.DatadogAgentProfileEnabled) || (r.options.ExtendedDaemonsetOptions.Enabled &&
-		r.options.DatadogAgentProfileEnabled && agentprofile.IsDefaultProfile(profile.Namespace, profile.Name)) {
-		// Start by creating the Default Agent extendeddaemonset
-		eds = componentagent.NewDefaultAgentExtendedDaemonset(dda, &r.options.ExtendedDaemonsetOptions, requiredComponents.Agent)
-		podManagers = feature.NewPodTemplateManagers(&eds.Spec.Template)
-
-		// Set Global setting on the default extendeddaemonset
-		eds.Spec.Template = *override.ApplyGlobalSettingsNodeAgent(logger, podManagers, dda, resourcesManager, singleContainerStrategyEnabled)
-
-		// Apply features changes on the Deployment.Spec.Template
-		for _, feat := range features {
-			if errFeat := feat.ManageNodeAgent(podManagers, provider); errFeat != nil {
-				return result, errFeat
-			}
-		}
-
-		// If Override is defined for the node agent component, apply the override on the PodTemplateSpec, it will cascade to container.
-		var componentOverrides []*datadoghqv2alpha1.DatadogAgentComponentOverride
-		if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.NodeAgentComponentName]; ok {
-			componentOverrides = append(componentOverrides, componentOverride)
-		}
 
-		if r.options.DatadogAgentProfileEnabled {
-			// Apply overrides from profiles after override from manifest, so they can override what's defined in the DDA.
-			overrideFromProfile := agentprofile.OverrideFromProfile(profile)
-			componentOverrides = append(componentOverrides, &overrideFromProfile)
-		}
-
-		if r.options.IntrospectionEnabled {
-			// use the last name override in the list to generate a provider-specific name
-			overrideName := eds.Name
-			for _, componentOverride := range componentOverrides {
-				if componentOverride.Name != nil && *componentOverride.Name != "" {
-					overrideName = *componentOverride.Name
-				}
-			}
-			overrideFromProvider := kubernetes.ComponentOverrideFromProvider(overrideName, provider, providerList)
-			componentOverrides = append(componentOverrides, &overrideFromProvider)
-		} else {
-			eds.Labels[apicommon.MD5AgentDeploymentProviderLabelKey] = kubernetes.LegacyProvider
-		}
-
-		for _, componentOverride := range componentOverrides {
-			if apiutils.BoolValue(componentOverride.Disabled) {
-				disabledByOverride = true
-			}
-			override.PodTemplateSpec(logger, podManagers, componentOverride, datadoghqv2alpha1.NodeAgentComponentName, dda.Name)
-			override.ExtendedDaemonSet(eds, componentOverride)
+	if r.useV2ExtendedDaemonSet(profile) {
+		eds, disabledByOverride, err := r.buildV2AgentExtendedDaemonSet(logger, requiredComponents, features, dda, resourcesManager, provider, providerList, profile)
+		if err != nil {
+			return result, err
 		}
 
 		if disabledByOverride {
@@ -128,9 +73,103 @@ func (r *Reconciler) reconcileV2Agent(logger logr.Logger, requiredComponents fea
 		return r.createOrUpdateExtendedDaemonset(daemonsetLogger, dda, eds, newStatus, updateEDSStatusV2WithAgent)
 	}
 
+	daemonset, disabledByOverride, err := r.buildV2AgentDaemonSet(logger, requiredComponents, features, dda, resourcesManager, provider, providerList, profile)
+	if err != nil {
+		return result, err
+	}
+
+	if disabledByOverride {
+		if agentEnabled {
+			// The override supersedes what's set in requiredComponents; update status to reflect the conflict
+			datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(
+				newStatus,
+				metav1.NewTime(time.Now()),
+				datadoghqv2alpha1.OverrideReconcileConflictConditionType,
+				metav1.ConditionTrue,
+				"OverrideConflict",
+				"Agent component is set to disabled",
+				true,
+			)
+		}
+		if err := r.deleteV2DaemonSet(daemonsetLogger, dda, daemonset, newStatus); err != nil {
+			return reconcile.Result{}, err
+		}
+		deleteStatusWithAgent(newStatus)
+		return reconcile.Result{}, nil
+	}
+
+	return r.createOrUpdateDaemonset(daemonsetLogger, dda, daemonset, newStatus, updateDSStatusV2WithAgent)
+}
+
+// useV2ExtendedDaemonSet returns true if the Agent of the profile is deployed with an ExtendedDaemonSet.
+// When EDS is enabled and there are profiles defined, we only create an
+// EDS for the default profile, for the other profiles we create
+// DaemonSets.
+// This is to make deployments simpler. With multiple EDS there would be
+// multiple canaries, etc.
+func (r *Reconciler) useV2ExtendedDaemonSet(profile *v1alpha1.DatadogAgentProfile) bool {
+	if !r.options.ExtendedDaemonsetOptions.Enabled {
+		return false
+	}
+	return !r.options.DatadogAgentProfileEnabled || agentprofile.IsDefaultProfile(profile.Namespace, profile.Name)
+}
+
+// buildV2AgentExtendedDaemonSet returns the Agent ExtendedDaemonSet with the global settings, the features
+// and the overrides applied. It also returns true if the component is disabled by one of the overrides.
+func (r *Reconciler) buildV2AgentExtendedDaemonSet(logger logr.Logger, requiredComponents feature.RequiredComponents, features []feature.Feature,
+	dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers,
+	provider string, providerList map[string]struct{}, profile *v1alpha1.DatadogAgentProfile) (*edsv1alpha1.ExtendedDaemonSet, bool, error) {
+	singleContainerStrategyEnabled := requiredComponents.Agent.SingleContainerStrategyEnabled()
+
+	// Start by creating the Default Agent extendeddaemonset
+	eds := componentagent.NewDefaultAgentExtendedDaemonset(dda, &r.options.ExtendedDaemonsetOptions, requiredComponents.Agent)
+	podManagers := feature.NewPodTemplateManagers(&eds.Spec.Template)
+
+	// Set Global setting on the default extendeddaemonset
+	eds.Spec.Template = *override.ApplyGlobalSettingsNodeAgent(logger, podManagers, dda, resourcesManager, singleContainerStrategyEnabled)
+
+	// Apply features changes on the Deployment.Spec.Template
+	for _, feat := range features {
+		if errFeat := feat.ManageNodeAgent(podManagers, provider); errFeat != nil {
+			return eds, false, errFeat
+		}
+	}
+
+	componentOverrides := r.agentComponentOverrides(dda, eds.Name, provider, providerList, profile)
+	if !r.options.IntrospectionEnabled {
+		eds.Labels[apicommon.MD5AgentDeploymentProviderLabelKey] = kubernetes.LegacyProvider
+	}
+
+	disabledByOverride := false
+	for _, componentOverride := range componentOverrides {
+		if apiutils.BoolValue(componentOverride.Disabled) {
+			disabledByOverride = true
+		}
+		override.PodTemplateSpec(logger, podManagers, componentOverride, datadoghqv2alpha1.NodeAgentComponentName, dda.Name)
+		override.ExtendedDaemonSet(eds, componentOverride)
+	}
+
+	if err := r.addReferencedObjectsChecksum(podManagers, dda, datadoghqv2alpha1.NodeAgentComponentName); err != nil {
+		return eds, disabledByOverride, err
+	}
+
+	return eds, disabledByOverride, nil
+}
+
+// buildV2AgentDaemonSet returns the Agent DaemonSet with the global settings, the features
+// and the overrides applied. It also returns true if the component is disabled by one of the overrides.
+func (r *Reconciler) buildV2AgentDaemonSet(logger logr.Logger, requiredComponents feature.RequiredComponents, features []feature.Feature,
+	dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers,
+	provider string, providerList map[string]struct{}, profile *v1alpha1.DatadogAgentProfile) (*appsv1.DaemonSet, bool, error) {
+	singleContainerStrategyEnabled := requiredComponents.Agent.SingleContainerStrategyEnabled()
+
+	if err := r.validateV2AgentUpdateStrategy(dda); err != nil {
+		return nil, false, err
+	}
+
 	// Start by creating the Default Agent daemonset
-	daemonset = componentagent.NewDefaultAgentDaemonset(dda, &r.options.ExtendedDaemonsetOptions, requiredComponents.Agent)
-	podManagers = feature.NewPodTemplateManagers(&daemonset.Spec.Template)
+	daemonset := componentagent.NewDefaultAgentDaemonset(dda, &r.options.ExtendedDaemonsetOptions, requiredComponents.Agent)
+	podManagers := feature.NewPodTemplateManagers(&daemonset.Spec.Template)
 	// Set Global setting on the default daemonset
 	daemonset.Spec.Template = *override.ApplyGlobalSettingsNodeAgent(logger, podManagers, dda, resourcesManager, singleContainerStrategyEnabled)
 
@@ -138,15 +177,58 @@ func (r *Reconciler) reconcileV2Agent(logger logr.Logger, requiredComponents fea
 	for _, feat := range features {
 		if singleContainerStrategyEnabled {
 			if errFeat := feat.ManageSingleContainerNodeAgent(podManagers, provider); errFeat != nil {
-				return result, errFeat
+				return daemonset, false, errFeat
 			}
 		} else {
 			if errFeat := feat.ManageNodeAgent(podManagers, provider); errFeat != nil {
-				return result, errFeat
+				return daemonset, false, errFeat
 			}
 		}
 	}
 
+	componentOverrides := r.agentComponentOverrides(dda, daemonset.Name, provider, providerList, profile)
+	if !r.options.IntrospectionEnabled {
+		daemonset.Labels[apicommon.MD5AgentDeploymentProviderLabelKey] = kubernetes.LegacyProvider
+	}
+
+	disabledByOverride := false
+	for _, componentOverride := range componentOverrides {
+		if apiutils.BoolValue(componentOverride.Disabled) {
+			disabledByOverride = true
+		}
+		override.PodTemplateSpec(logger, podManagers, componentOverride, datadoghqv2alpha1.NodeAgentComponentName, dda.Name)
+		override.DaemonSet(daemonset, componentOverride)
+	}
+
+	if err := r.addReferencedObjectsChecksum(podManagers, dda, datadoghqv2alpha1.NodeAgentComponentName); err != nil {
+		return daemonset, disabledByOverride, err
+	}
+
+	return daemonset, disabledByOverride, nil
+}
+
+// validateV2AgentUpdateStrategy checks that the update strategy of the Agent can be used with the profile DaemonSets.
+// The profiles change the pod template of their DaemonSet when they are updated, with the OnDelete type
+// these changes would never be rolled out on the nodes.
+func (r *Reconciler) validateV2AgentUpdateStrategy(dda *datadoghqv2alpha1.DatadogAgent) error {
+	if !r.options.DatadogAgentProfileEnabled {
+		return nil
+	}
+	componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.NodeAgentComponentName]
+	if !ok || componentOverride.UpdateStrategy == nil {
+		return nil
+	}
+	if componentOverride.UpdateStrategy.Type == string(appsv1.OnDeleteDaemonSetStrategyType) {
+		return fmt.Errorf("spec.override[%s].updateStrategy.type: %s is not supported when the DatadogAgentProfiles are enabled",
+			datadoghqv2alpha1.NodeAgentComponentName, appsv1.OnDeleteDaemonSetStrategyType)
+	}
+	return nil
+}
+
+// agentComponentOverrides returns the list of overrides to apply on the Agent, in order:
+// the one from the DatadogAgent manifest, the one from the profile and the one from the provider.
+func (r *Reconciler) agentComponentOverrides(dda *datadoghqv2alpha1.DatadogAgent, name string, provider string,
+	providerList map[string]struct{}, profile *v1alpha1.DatadogAgentProfile) []*datadoghqv2alpha1.DatadogAgentComponentOverride {
 	// If Override is defined for the node agent component, apply the override on the PodTemplateSpec, it will cascade to container.
 	var componentOverrides []*datadoghqv2alpha1.DatadogAgentComponentOverride
 	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.NodeAgentComponentName]; ok {
@@ -155,13 +237,13 @@ func (r *Reconciler) reconcileV2Agent(logger logr.Logger, requiredComponents fea
 
 	if r.options.DatadogAgentProfileEnabled {
 		// Apply overrides from profiles after override from manifest, so they can override what's defined in the DDA.
-		overrideFromProfile := agentprofile.OverrideFromProfile(profile)
+		overrideFromProfile := agentprofile.OverrideFromProfile(profile, dda)
 		componentOverrides = append(componentOverrides, &overrideFromProfile)
 	}
 
 	if r.options.IntrospectionEnabled {
 		// use the last name override in the list to generate a provider-specific name
-		overrideName := daemonset.Name
+		overrideName := name
 		for _, componentOverride := range componentOverrides {
 			if componentOverride.Name != nil && *componentOverride.Name != "" {
 				overrideName = *componentOverride.Name
@@ -169,39 +251,45 @@ func (r *Reconciler) reconcileV2Agent(logger logr.Logger, requiredComponents fea
 		}
 		overrideFromProvider := kubernetes.ComponentOverrideFromProvider(overrideName, provider, providerList)
 		componentOverrides = append(componentOverrides, &overrideFromProvider)
-	} else {
-		daemonset.Labels[apicommon.MD5AgentDeploymentProviderLabelKey] = kubernetes.LegacyProvider
 	}
 
-	for _, componentOverride := range componentOverrides {
-		if apiutils.BoolValue(componentOverride.Disabled) {
-			disabledByOverride = true
-		}
-		override.PodTemplateSpec(logger, podManagers, componentOverride, datadoghqv2alpha1.NodeAgentComponentName, dda.Name)
-		override.DaemonSet(daemonset, componentOverride)
+	return componentOverrides
+}
+
+// featuresForProfile returns the features to apply on the Agent of the profile, and the components they require.
+// The features turned off by the profile are removed, and the Agent containers only they require are not deployed.
+func featuresForProfile(features []feature.Feature, requiredComponents feature.RequiredComponents,
+	featureRequirements map[feature.IDType]feature.RequiredComponents, profile *v1alpha1.DatadogAgentProfile) ([]feature.Feature, feature.RequiredComponents) {
+	disabledFeatures := agentprofile.DisabledFeatures(profile)
+	if len(disabledFeatures) == 0 {
+		return features, requiredComponents
 	}
 
-	if disabledByOverride {
-		if agentEnabled {
-			// The override supersedes what's set in requiredComponents; update status to reflect the conflict
-			datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(
-				newStatus,
-				metav1.NewTime(time.Now()),
-				datadoghqv2alpha1.OverrideReconcileConflictConditionType,
-				metav1.ConditionTrue,
-				"OverrideConflict",
-				"Agent component is set to disabled",
-				true,
-			)
+	isDisabled := make(map[feature.IDType]bool, len(disabledFeatures))
+	for _, id := range disabledFeatures {
+		isDisabled[feature.IDType(id)] = true
+	}
+	// The default feature configures the Agent itself, it can't be turned off
+	delete(isDisabled, feature.DefaultIDType)
+
+	var profileFeatures []feature.Feature
+	var agentRequiredComponent feature.RequiredComponent
+	for _, feat := range features {
+		if isDisabled[feat.ID()] {
+			continue
 		}
-		if err := r.deleteV2DaemonSet(daemonsetLogger, dda, daemonset, newStatus); err != nil {
-			return reconcile.Result{}, err
+		profileFeatures = append(profileFeatures, feat)
+		if requirements, found := featureRequirements[feat.ID()]; found {
+			agentRequiredComponent.Merge(&requirements.Agent)
 		}
-		deleteStatusWithAgent(newStatus)
-		return reconcile.Result{}, nil
 	}
 
-	return r.createOrUpdateDaemonset(daemonsetLogger, dda, daemonset, newStatus, updateDSStatusV2WithAgent)
+	// With the single container strategy, the Agent containers don't depend on the features
+	if !requiredComponents.Agent.SingleContainerStrategyEnabled() {
+		requiredComponents.Agent = agentRequiredComponent
+	}
+
+	return profileFeatures, requiredComponents
 }
 
 func updateDSStatusV2WithAgent(ds *appsv1.DaemonSet, newStatus *datadoghqv2alpha1.DatadogAgentStatus, updateTime metav1.Time, status metav1.ConditionStatus, reason, message string) {
@@ -261,56 +349,98 @@ func removeStaleStatus(ddaStatus *datadoghqv2alpha1.DatadogAgentStatus, name str
 	}
 }
 
-func (r *Reconciler) handleProfiles(ctx context.Context, profilesByNode map[string]types.NamespacedName, ddaNamespace string) error {
-	if err := r.labelNodesWithProfiles(ctx, profilesByNode); err != nil {
+// handleProfiles labels the nodes with the profile that applies to them, and
+// deletes the Agent pods of the DatadogAgent that run on a node with another
+// profile. The nodes labeled with a profile of another DatadogAgent are left
+// untouched, so that the DatadogAgents don't fight over the node labels.
+func (r *Reconciler) handleProfiles(ctx context.Context, logger logr.Logger, dda metav1.Object, profiles []v1alpha1.DatadogAgentProfile, profilesByNode map[string]types.NamespacedName) error {
+	nodes, err := r.getNodeList(ctx)
+	if err != nil {
+		return err
+	}
+
+	allProfiles := v1alpha1.DatadogAgentProfileList{}
+	if err = r.client.List(ctx, &allProfiles); err != nil {
+		return err
+	}
+	nodesOfOtherDatadogAgents := agentprofile.NodesWithProfileOfOtherDatadogAgents(nodes, allProfiles.Items, dda)
+	if len(nodesOfOtherDatadogAgents) > 0 {
+		ddaProfilesByNode := make(map[string]types.NamespacedName, len(profilesByNode))
+		for node, profile := range profilesByNode {
+			if !nodesOfOtherDatadogAgents[node] {
+				ddaProfilesByNode[node] = profile
+			}
+		}
+		profilesByNode = ddaProfilesByNode
+	}
+
+	agentPods, err := r.listAgentPods(ctx, dda)
+	if err != nil {
+		return err
+	}
+
+	// The nodes move to their new profile progressively, according to the
+	// rollout policies of the profiles
+	profilesToApplyNow, waitingNodes := agentprofile.ProfilesToApplyNow(profiles, profilesByNode, nodes, agentPods, time.Now())
+	if waitingNodes > 0 {
+		logger.Info("Some nodes are waiting to move to their profile because of the rollout policies", "waitingNodes", waitingNodes)
+	}
+
+	if err = r.labelNodesWithProfiles(ctx, dda, profilesToApplyNow); err != nil {
 		return err
 	}
 
-	if err := r.cleanupPodsForProfilesThatNoLongerApply(ctx, profilesByNode, ddaNamespace); err != nil {
+	if err = r.cleanupPodsForProfilesThatNoLongerApply(ctx, profilesToApplyNow, agentPods); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-// labelNodesWithProfiles sets the "agent.datadoghq.com/profile" label only in
-// the nodes where a profile is applied
-func (r *Reconciler) labelNodesWithProfiles(ctx context.Context, profilesByNode map[string]types.NamespacedName) error {
+// labelNodesWithProfiles sets the "agent.datadoghq.com/profile" label, and the
+// profile label of the DatadogAgent, only in the nodes where a profile is
+// applied
+func (r *Reconciler) labelNodesWithProfiles(ctx context.Context, dda metav1.Object, profilesByNode map[string]types.NamespacedName) error {
+	ddaProfileLabelKey := agentprofile.DatadogAgentProfileLabelKey(dda)
+
 	for nodeName, profileNamespacedName := range profilesByNode {
 		isDefaultProfile := agentprofile.IsDefaultProfile(profileNamespacedName.Namespace, profileNamespacedName.Name)
+		expectedProfileLabelValue := agentprofile.NodeLabelValue(profileNamespacedName)
 
 		node := &corev1.Node{}
 		if err := r.client.Get(ctx, types.NamespacedName{Name: nodeName}, node); err != nil {
 			return err
 		}
 
-		_, profileLabelExists := node.Labels[agentprofile.ProfileLabelKey]
+		profileLabelValue, profileLabelExists := node.Labels[agentprofile.ProfileLabelKey]
+		ddaProfileLabelValue, ddaProfileLabelExists := node.Labels[ddaProfileLabelKey]
 
 		var newLabels map[string]string
 
-		// If the profile is the default one and the label exists in the node,
-		// it should be removed.
-		if isDefaultProfile && profileLabelExists {
-			newLabels = make(map[string]string, len(node.Labels)-1)
+		// If the profile is the default one and the labels exist in the node,
+		// they should be removed.
+		if isDefaultProfile && (profileLabelExists || ddaProfileLabelExists) {
+			newLabels = make(map[string]string, len(node.Labels))
 			for label, value := range node.Labels {
-				if label != agentprofile.ProfileLabelKey {
+				if label != agentprofile.ProfileLabelKey && label != ddaProfileLabelKey {
 					newLabels[label] = value
 				}
 			}
 		}
 
-		// If the profile is not the default one and the label does not exist in
-		// the node, it should be added.
-		if !isDefaultProfile && !profileLabelExists {
-			newLabels = make(map[string]string, len(node.Labels)+1)
+		// If the profile is not the default one and the labels do not exist in
+		// the node or have another value, they should be set.
+		if !isDefaultProfile && (profileLabelValue != expectedProfileLabelValue || ddaProfileLabelValue != expectedProfileLabelValue) {
+			newLabels = make(map[string]string, len(node.Labels)+2)
 			for label, value := range node.Labels {
 				newLabels[label] = value
 			}
-			newLabels[agentprofile.ProfileLabelKey] = fmt.Sprintf("%s-%s", profileNamespacedName.Namespace, profileNamespacedName.Name)
+			newLabels[agentprofile.ProfileLabelKey] = expectedProfileLabelValue
+			newLabels[ddaProfileLabelKey] = expectedProfileLabelValue
 		}
 
-		if len(newLabels) == 0 {
-			return nil
+		if newLabels == nil {
+			continue
 		}
 
 		patch := corev1.Node{
@@ -328,35 +458,41 @@ func (r *Reconciler) labelNodesWithProfiles(ctx context.Context, profilesByNode
 	return nil
 }
 
-// cleanupPodsForProfilesThatNoLongerApply deletes the agent pods that should
-// not be running according to the profiles that need to be applied. This is
-// needed because in the affinities we use
-// "RequiredDuringSchedulingIgnoredDuringExecution" which means that the pods
-// might not always be evicted when there's a change in the profiles to apply.
-// Notice that "RequiredDuringSchedulingRequiredDuringExecution" is not
-// available in Kubernetes yet.
-func (r *Reconciler) cleanupPodsForProfilesThatNoLongerApply(ctx context.Context, profilesByNode map[string]types.NamespacedName, ddaNamespace string) error {
+// listAgentPods returns the node agent pods of the DatadogAgent.
+func (r *Reconciler) listAgentPods(ctx context.Context, dda metav1.Object) ([]corev1.Pod, error) {
 	agentPods := &corev1.PodList{}
 	err := r.client.List(
 		ctx,
 		agentPods,
 		client.MatchingLabels(map[string]string{
+			apicommon.AgentDeploymentNameLabelKey:      dda.GetName(),
 			apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 		}),
-		client.InNamespace(ddaNamespace),
+		client.InNamespace(dda.GetNamespace()),
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, agentPod := range agentPods.Items {
+	return agentPods.Items, nil
+}
+
+// cleanupPodsForProfilesThatNoLongerApply deletes the agent pods that should
+// not be running according to the profiles that need to be applied. This is
+// needed because in the affinities we use
+// "RequiredDuringSchedulingIgnoredDuringExecution" which means that the pods
+// might not always be evicted when there's a change in the profiles to apply.
+// Notice that "RequiredDuringSchedulingRequiredDuringExecution" is not
+// available in Kubernetes yet.
+func (r *Reconciler) cleanupPodsForProfilesThatNoLongerApply(ctx context.Context, profilesByNode map[string]types.NamespacedName, agentPods []corev1.Pod) error {
+	for _, agentPod := range agentPods {
 		profileNamespacedName, found := profilesByNode[agentPod.Spec.NodeName]
 		if !found {
 			continue
 		}
 
 		isDefaultProfile := agentprofile.IsDefaultProfile(profileNamespacedName.Namespace, profileNamespacedName.Name)
-		expectedProfileLabelValue := fmt.Sprintf("%s-%s", profileNamespacedName.Namespace, profileNamespacedName.Name)
+		expectedProfileLabelValue := agentprofile.NodeLabelValue(profileNamespacedName)
 
 		profileLabelValue, profileLabelExists := agentPod.Labels[agentprofile.ProfileLabelKey]
 
@@ -371,7 +507,7 @@ func (r *Reconciler) cleanupPodsForProfilesThatNoLongerApply(ctx context.Context
 					Name:      agentPod.Name,
 				},
 			}
-			if err = r.client.Delete(ctx, &toDelete); err != nil {
+			if err := r.client.Delete(ctx, &toDelete); err != nil && !errors.IsNotFound(err) {
 				return err
 			}
 		}
@@ -387,49 +523,74 @@ func (r *Reconciler) cleanupPodsForProfilesThatNoLongerApply(ctx context.Context
 // - a profile is deleted
 func (r *Reconciler) cleanupExtraneousDaemonSets(ctx context.Context, logger logr.Logger, dda *datadoghqv2alpha1.DatadogAgent, newStatus *datadoghqv2alpha1.DatadogAgentStatus,
 	providerList map[string]struct{}, profiles []v1alpha1.DatadogAgentProfile) error {
-	matchLabels := client.MatchingLabels{
-		apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
-		kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+	extendedDaemonSets, daemonSets, err := r.listExtraneousDaemonSets(ctx, dda, providerList, profiles)
+	if err != nil {
+		return err
+	}
+
+	for i := range extendedDaemonSets {
+		if err = r.deleteV2ExtendedDaemonSet(logger, dda, &extendedDaemonSets[i], newStatus); err != nil {
+			return err
+		}
+	}
+	for i := range daemonSets {
+		if err = r.deleteV2DaemonSet(logger, dda, &daemonSets[i], newStatus); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// listExtraneousDaemonSets returns the Agent EDSs and DSs that don't match any of the profiles and providers.
+func (r *Reconciler) listExtraneousDaemonSets(ctx context.Context, dda *datadoghqv2alpha1.DatadogAgent,
+	providerList map[string]struct{}, profiles []v1alpha1.DatadogAgentProfile) ([]edsv1alpha1.ExtendedDaemonSet, []appsv1.DaemonSet, error) {
+	// Only the DaemonSets of this DatadogAgent are listed, the other DatadogAgents clean up their own
+	listOptions := []client.ListOption{
+		client.InNamespace(dda.Namespace),
+		client.MatchingLabels{
+			apicommon.AgentDeploymentNameLabelKey:      dda.Name,
+			apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
+			kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+		},
 	}
 
 	dsName := getDaemonSetNameFromDatadogAgent(dda)
-	validDaemonSetNames, validExtendedDaemonSetNames := r.getValidDaemonSetNames(dsName, providerList, profiles)
+	validDaemonSetNames, validExtendedDaemonSetNames := r.getValidDaemonSetNames(dda.Name, dsName, providerList, profiles)
 
+	var extendedDaemonSets []edsv1alpha1.ExtendedDaemonSet
 	// Only the default profile uses an EDS when profiles are enabled
 	// Multiple EDSs can be created with introspection
 	if r.options.ExtendedDaemonsetOptions.Enabled {
 		edsList := edsv1alpha1.ExtendedDaemonSetList{}
-		if err := r.client.List(ctx, &edsList, matchLabels); err != nil {
-			return err
+		if err := r.client.List(ctx, &edsList, listOptions...); err != nil {
+			return nil, nil, err
 		}
 
 		for _, eds := range edsList.Items {
 			if _, ok := validExtendedDaemonSetNames[eds.Name]; !ok {
-				if err := r.deleteV2ExtendedDaemonSet(logger, dda, &eds, newStatus); err != nil {
-					return err
-				}
+				extendedDaemonSets = append(extendedDaemonSets, eds)
 			}
 		}
 	}
 
 	daemonSetList := appsv1.DaemonSetList{}
-	if err := r.client.List(ctx, &daemonSetList, matchLabels); err != nil {
-		return err
+	if err := r.client.List(ctx, &daemonSetList, listOptions...); err != nil {
+		return nil, nil, err
 	}
 
+	var daemonSets []appsv1.DaemonSet
 	for _, daemonSet := range daemonSetList.Items {
 		if _, ok := validDaemonSetNames[daemonSet.Name]; !ok {
-			if err := r.deleteV2DaemonSet(logger, dda, &daemonSet, newStatus); err != nil {
-				return err
-			}
+			daemonSets = append(daemonSets, daemonSet)
 		}
 	}
 
-	return nil
+	return extendedDaemonSets, daemonSets, nil
 }
 
 // getValidDaemonSetNames generates a list of valid DS and EDS names
-func (r *Reconciler) getValidDaemonSetNames(dsName string, providerList map[string]struct{}, profiles []v1alpha1.DatadogAgentProfile) (map[string]struct{}, map[string]struct{}) {
+func (r *Reconciler) getValidDaemonSetNames(ddaName, dsName string, providerList map[string]struct{}, profiles []v1alpha1.DatadogAgentProfile) (map[string]struct{}, map[string]struct{}) {
 	validDaemonSetNames := map[string]struct{}{}
 	validExtendedDaemonSetNames := map[string]struct{}{}
 
@@ -450,7 +611,7 @@ func (r *Reconciler) getValidDaemonSetNames(dsName string, providerList map[stri
 				Namespace: profile.Namespace,
 				Name:      profile.Name,
 			}
-			dsProfileName := agentprofile.DaemonSetName(name)
+			dsProfileName := agentprofile.DaemonSetName(name, ddaName)
 
 			// The default profile can be a DS or an EDS and uses the DS/EDS name
 			if agentprofile.IsDefaultProfile(profile.Namespace, profile.Name) {
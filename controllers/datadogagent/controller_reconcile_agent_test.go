@@ -10,14 +10,18 @@ import (
 	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
 	apiutils "github.com/DataDog/datadog-operator/apis/utils"
 	"github.com/DataDog/datadog-operator/controllers/datadogagent/component/agent"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+	"github.com/DataDog/datadog-operator/pkg/agentprofile"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
 
 	edsdatadoghqv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -159,7 +163,7 @@ func Test_getValidDaemonSetNames(t *testing.T) {
 				},
 			}
 
-			validDSNames, validEDSNames := r.getValidDaemonSetNames(tt.dsName, tt.existingProviders, tt.existingProfiles)
+			validDSNames, validEDSNames := r.getValidDaemonSetNames("datadog", tt.dsName, tt.existingProviders, tt.existingProfiles)
 			assert.Equal(t, tt.wantDS, validDSNames)
 			assert.Equal(t, tt.wantEDS, validEDSNames)
 		})
@@ -269,6 +273,7 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 						Labels: map[string]string{
 							apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 						},
 					},
 				},
@@ -291,6 +296,7 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 							Labels: map[string]string{
 								apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 								kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+								apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 							},
 						},
 					},
@@ -313,6 +319,7 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 						Labels: map[string]string{
 							apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 						},
 					},
 				},
@@ -341,6 +348,7 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 							Labels: map[string]string{
 								apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 								kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+								apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 							},
 						},
 					},
@@ -349,16 +357,17 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 		},
 		{
 			name:        "no unused ds, introspection enabled, profiles enabled",
-			description: "DS `datadog-agent-with-profile-ns-1-profile-1-gke-cos` should not be deleted",
+			description: "DS `dda-foo-agent-with-profile-ns-1-profile-1-gke-cos` should not be deleted",
 			existingAgents: []client.Object{
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1-gke-cos",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1-gke-cos",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 							apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 						},
 					},
 				},
@@ -385,12 +394,13 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 				Items: []appsv1.DaemonSet{
 					{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:      "datadog-agent-with-profile-ns-1-profile-1-gke-cos",
+							Name:      "dda-foo-agent-with-profile-ns-1-profile-1-gke-cos",
 							Namespace: "ns-1",
 							Labels: map[string]string{
 								apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 								apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 								kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+								apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 							},
 							ResourceVersion: "999",
 						},
@@ -416,6 +426,7 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 							apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 							apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 						},
 					},
 				},
@@ -454,6 +465,7 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 								apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 								apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 								kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+								apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 							},
 							ResourceVersion: "999",
 						},
@@ -463,7 +475,7 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 		},
 		{
 			name:        "multiple unused ds, introspection enabled, profiles enabled",
-			description: "All DS except `datadog-agent-with-profile-ns-1-profile-1-gke-cos` should be deleted",
+			description: "All DS except `dda-foo-agent-with-profile-ns-1-profile-1-gke-cos` should be deleted",
 			existingAgents: []client.Object{
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
@@ -472,26 +484,29 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 						Labels: map[string]string{
 							apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 						}},
 				},
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 						},
 					},
 				},
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1-gke-cos",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1-gke-cos",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 							apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 						},
 					},
 				},
@@ -518,12 +533,13 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 				Items: []appsv1.DaemonSet{
 					{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:      "datadog-agent-with-profile-ns-1-profile-1-gke-cos",
+							Name:      "dda-foo-agent-with-profile-ns-1-profile-1-gke-cos",
 							Namespace: "ns-1",
 							Labels: map[string]string{
 								apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 								apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 								kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+								apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 							},
 							ResourceVersion: "999",
 						},
@@ -539,7 +555,7 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 		},
 		{
 			name:        "multiple unused eds, introspection enabled, profiles enabled",
-			description: "All but EDS `dda-foo-agent-gke-cos` and DS `datadog-agent-with-profile-ns-1-profile-1-gke-cos` should be deleted",
+			description: "All but EDS `dda-foo-agent-gke-cos` and DS `dda-foo-agent-with-profile-ns-1-profile-1-gke-cos` should be deleted",
 			existingAgents: []client.Object{
 				&edsdatadoghqv1alpha1.ExtendedDaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
@@ -548,27 +564,30 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 						Labels: map[string]string{
 							apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 						},
 					},
 				},
 				&edsdatadoghqv1alpha1.ExtendedDaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 						},
 					},
 				},
 				&edsdatadoghqv1alpha1.ExtendedDaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1-gke-cos",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1-gke-cos",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 							apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 						},
 					},
 				},
@@ -580,17 +599,19 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 							apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 							apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 						},
 					},
 				},
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1-gke-cos",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1-gke-cos",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 							apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 						},
 					},
 				},
@@ -617,12 +638,13 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 				Items: []appsv1.DaemonSet{
 					{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:      "datadog-agent-with-profile-ns-1-profile-1-gke-cos",
+							Name:      "dda-foo-agent-with-profile-ns-1-profile-1-gke-cos",
 							Namespace: "ns-1",
 							Labels: map[string]string{
 								apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 								apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 								kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+								apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 							},
 							ResourceVersion: "999",
 						},
@@ -643,6 +665,7 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 								apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 								apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 								kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+								apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 							},
 							ResourceVersion: "999",
 						},
@@ -661,6 +684,7 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 						Labels: map[string]string{
 							apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 						}},
 				},
 				&appsv1.DaemonSet{
@@ -671,26 +695,29 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 							apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 							apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 						}},
 				},
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 						},
 					},
 				},
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1-gke-cos",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1-gke-cos",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 							apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 						},
 					},
 				},
@@ -723,6 +750,7 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 								apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 								apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 								kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+								apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 							},
 							ResourceVersion: "999",
 						},
@@ -747,27 +775,30 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 						Labels: map[string]string{
 							apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 						},
 					},
 				},
 				&edsdatadoghqv1alpha1.ExtendedDaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 						},
 					},
 				},
 				&edsdatadoghqv1alpha1.ExtendedDaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1-gke-cos",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1-gke-cos",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 							apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 						},
 					},
 				},
@@ -779,17 +810,19 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 							apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 							apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 						},
 					},
 				},
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1-gke-cos",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1-gke-cos",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 							apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 						},
 					},
 				},
@@ -829,6 +862,7 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 								apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 								apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 								kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+								apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 							},
 							ResourceVersion: "999",
 						},
@@ -838,7 +872,7 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 		},
 		{
 			name:        "multiple unused ds, introspection disabled, profiles enabled",
-			description: "DS `datadog-agent-with-profile-ns-1-profile-1-gke-cos` should be deleted",
+			description: "DS `dda-foo-agent-with-profile-ns-1-profile-1-gke-cos` should be deleted",
 			existingAgents: []client.Object{
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
@@ -847,26 +881,29 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 						Labels: map[string]string{
 							apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 						}},
 				},
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 						},
 					},
 				},
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1-gke-cos",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1-gke-cos",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 							apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 						},
 					},
 				},
@@ -899,22 +936,24 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 				Items: []appsv1.DaemonSet{
 					{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:      "datadog-agent-with-profile-ns-1-profile-1",
+							Name:      "dda-foo-agent",
 							Namespace: "ns-1",
 							Labels: map[string]string{
 								apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 								kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+								apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 							},
 							ResourceVersion: "999",
 						},
 					},
 					{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:      "dda-foo-agent",
+							Name:      "dda-foo-agent-with-profile-ns-1-profile-1",
 							Namespace: "ns-1",
 							Labels: map[string]string{
 								apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 								kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+								apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 							},
 							ResourceVersion: "999",
 						},
@@ -930,7 +969,7 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 		},
 		{
 			name:        "multiple unused eds, introspection disabled, profiles enabled",
-			description: "All but EDS `dda-foo-agent` and DS `datadog-agent-with-profile-ns-1-profile-1` should be deleted",
+			description: "All but EDS `dda-foo-agent` and DS `dda-foo-agent-with-profile-ns-1-profile-1` should be deleted",
 			existingAgents: []client.Object{
 				&edsdatadoghqv1alpha1.ExtendedDaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
@@ -939,27 +978,30 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 						Labels: map[string]string{
 							apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 						},
 					},
 				},
 				&edsdatadoghqv1alpha1.ExtendedDaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 						},
 					},
 				},
 				&edsdatadoghqv1alpha1.ExtendedDaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1-gke-cos",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1-gke-cos",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 							apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 						},
 					},
 				},
@@ -971,27 +1013,30 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 							apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 							apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 						},
 					},
 				},
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1-gke-cos",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1-gke-cos",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 							apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 						},
 					},
 				},
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 							kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 						},
 					},
 				},
@@ -1024,11 +1069,12 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 				Items: []appsv1.DaemonSet{
 					{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:      "datadog-agent-with-profile-ns-1-profile-1",
+							Name:      "dda-foo-agent-with-profile-ns-1-profile-1",
 							Namespace: "ns-1",
 							Labels: map[string]string{
 								apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 								kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+								apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 							},
 							ResourceVersion: "999",
 						},
@@ -1048,6 +1094,7 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 							Labels: map[string]string{
 								apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
 								kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+								apicommon.AgentDeploymentNameLabelKey:      "dda-foo",
 							},
 							ResourceVersion: "999",
 						},
@@ -1067,7 +1114,7 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 				},
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							"foo": "bar",
@@ -1076,11 +1123,12 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 				},
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1-gke-cos",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1-gke-cos",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 							kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 						},
 					},
 				},
@@ -1113,7 +1161,14 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 				Items: []appsv1.DaemonSet{
 					{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:      "datadog-agent-with-profile-ns-1-profile-1",
+							Name:            "dda-foo-agent",
+							Namespace:       "ns-1",
+							ResourceVersion: "999",
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "dda-foo-agent-with-profile-ns-1-profile-1",
 							Namespace: "ns-1",
 							Labels: map[string]string{
 								"foo": "bar",
@@ -1123,22 +1178,16 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 					},
 					{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:      "datadog-agent-with-profile-ns-1-profile-1-gke-cos",
+							Name:      "dda-foo-agent-with-profile-ns-1-profile-1-gke-cos",
 							Namespace: "ns-1",
 							Labels: map[string]string{
 								apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 								kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+								apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 							},
 							ResourceVersion: "999",
 						},
 					},
-					{
-						ObjectMeta: metav1.ObjectMeta{
-							Name:            "dda-foo-agent",
-							Namespace:       "ns-1",
-							ResourceVersion: "999",
-						},
-					},
 				},
 			},
 			wantEDS: &edsdatadoghqv1alpha1.ExtendedDaemonSetList{
@@ -1160,7 +1209,7 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 				},
 				&edsdatadoghqv1alpha1.ExtendedDaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							"foo": "bar",
@@ -1169,11 +1218,12 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 				},
 				&edsdatadoghqv1alpha1.ExtendedDaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1-gke-cos",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1-gke-cos",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 							kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 						},
 					},
 				},
@@ -1189,17 +1239,18 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 				},
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1-gke-cos",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1-gke-cos",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 							kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+							apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 						},
 					},
 				},
 				&appsv1.DaemonSet{
 					ObjectMeta: metav1.ObjectMeta{
-						Name:      "datadog-agent-with-profile-ns-1-profile-1",
+						Name:      "dda-foo-agent-with-profile-ns-1-profile-1",
 						Namespace: "ns-1",
 						Labels: map[string]string{
 							apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
@@ -1235,7 +1286,7 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 				Items: []appsv1.DaemonSet{
 					{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:      "datadog-agent-with-profile-ns-1-profile-1",
+							Name:      "dda-foo-agent-with-profile-ns-1-profile-1",
 							Namespace: "ns-1",
 							Labels: map[string]string{
 								apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
@@ -1245,11 +1296,12 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 					},
 					{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:      "datadog-agent-with-profile-ns-1-profile-1-gke-cos",
+							Name:      "dda-foo-agent-with-profile-ns-1-profile-1-gke-cos",
 							Namespace: "ns-1",
 							Labels: map[string]string{
 								apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
 								kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+								apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 							},
 							ResourceVersion: "999",
 						},
@@ -1264,39 +1316,40 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 				Items: []edsdatadoghqv1alpha1.ExtendedDaemonSet{
 					{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:      "datadog-agent-with-profile-ns-1-profile-1",
-							Namespace: "ns-1",
-							Labels: map[string]string{
-								"foo": "bar",
-							},
+							Name:            "dda-foo-agent",
+							Namespace:       "ns-1",
 							ResourceVersion: "999",
 						},
 					},
 					{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:      "datadog-agent-with-profile-ns-1-profile-1-gke-cos",
+							Name:      "dda-foo-agent-gke-cos",
 							Namespace: "ns-1",
 							Labels: map[string]string{
 								apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
-								kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+								apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
 							},
 							ResourceVersion: "999",
 						},
 					},
 					{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:            "dda-foo-agent",
-							Namespace:       "ns-1",
+							Name:      "dda-foo-agent-with-profile-ns-1-profile-1",
+							Namespace: "ns-1",
+							Labels: map[string]string{
+								"foo": "bar",
+							},
 							ResourceVersion: "999",
 						},
 					},
 					{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:      "dda-foo-agent-gke-cos",
+							Name:      "dda-foo-agent-with-profile-ns-1-profile-1-gke-cos",
 							Namespace: "ns-1",
 							Labels: map[string]string{
 								apicommon.MD5AgentDeploymentProviderLabelKey: gkeCosProvider,
-								apicommon.AgentDeploymentComponentLabelKey:   apicommon.DefaultAgentResourceSuffix,
+								kubernetes.AppKubernetesManageByLabelKey:     "datadog-operator",
+								apicommon.AgentDeploymentNameLabelKey:        "dda-foo",
 							},
 							ResourceVersion: "999",
 						},
@@ -1417,6 +1470,86 @@ func Test_cleanupExtraneousDaemonSets(t *testing.T) {
 	}
 }
 
+func Test_cleanupExtraneousDaemonSetsWithSeveralDatadogAgents(t *testing.T) {
+	sch := runtime.NewScheme()
+	_ = scheme.AddToScheme(sch)
+	_ = edsdatadoghqv1alpha1.AddToScheme(sch)
+	ctx := context.Background()
+
+	newDatadogAgent := func(name string) *datadoghqv2alpha1.DatadogAgent {
+		return &datadoghqv2alpha1.DatadogAgent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "ns-1",
+			},
+		}
+	}
+	newProfile := func(name string) v1alpha1.DatadogAgentProfile {
+		return v1alpha1.DatadogAgentProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "ns-1",
+			},
+		}
+	}
+	newDaemonSet := func(name, ddaName string) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "ns-1",
+				Labels: map[string]string{
+					apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
+					kubernetes.AppKubernetesManageByLabelKey:   "datadog-operator",
+					apicommon.AgentDeploymentNameLabelKey:      ddaName,
+				},
+			},
+		}
+	}
+	defaultProfile := v1alpha1.DatadogAgentProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "default",
+		},
+	}
+
+	ddaFoo := newDatadogAgent("dda-foo")
+	ddaBar := newDatadogAgent("dda-bar")
+	profileFoo := newProfile("profile-foo")
+	profileBar := newProfile("profile-bar")
+	fooProfileDaemonSetName := agentprofile.DaemonSetName(types.NamespacedName{Namespace: profileFoo.Namespace, Name: profileFoo.Name}, ddaFoo.Name)
+	barProfileDaemonSetName := agentprofile.DaemonSetName(types.NamespacedName{Namespace: profileBar.Namespace, Name: profileBar.Name}, ddaBar.Name)
+
+	fakeClient := fake.NewClientBuilder().WithScheme(sch).WithObjects(
+		newDaemonSet("dda-foo-agent", ddaFoo.Name),
+		newDaemonSet(fooProfileDaemonSetName, ddaFoo.Name),
+		newDaemonSet("dda-foo-agent-stale", ddaFoo.Name),
+		newDaemonSet("dda-bar-agent", ddaBar.Name),
+		newDaemonSet(barProfileDaemonSetName, ddaBar.Name),
+	).Build()
+	logger := logf.Log.WithName("test_cleanupExtraneousDaemonSetsWithSeveralDatadogAgents")
+	r := &Reconciler{
+		client:   fakeClient,
+		log:      logger,
+		recorder: record.NewFakeRecorder(10),
+		options: ReconcilerOptions{
+			DatadogAgentProfileEnabled: true,
+		},
+	}
+
+	// Each DatadogAgent only deletes its own extraneous DaemonSets
+	err := r.cleanupExtraneousDaemonSets(ctx, logger, ddaFoo, &datadoghqv2alpha1.DatadogAgentStatus{}, map[string]struct{}{}, []v1alpha1.DatadogAgentProfile{profileFoo, defaultProfile})
+	require.NoError(t, err)
+	err = r.cleanupExtraneousDaemonSets(ctx, logger, ddaBar, &datadoghqv2alpha1.DatadogAgentStatus{}, map[string]struct{}{}, []v1alpha1.DatadogAgentProfile{profileBar, defaultProfile})
+	require.NoError(t, err)
+
+	dsList := &appsv1.DaemonSetList{}
+	require.NoError(t, fakeClient.List(ctx, dsList))
+	var names []string
+	for _, ds := range dsList.Items {
+		names = append(names, ds.Name)
+	}
+	assert.ElementsMatch(t, []string{"dda-foo-agent", fooProfileDaemonSetName, "dda-bar-agent", barProfileDaemonSetName}, names)
+}
+
 func Test_removeStaleStatus(t *testing.T) {
 	testCases := []struct {
 		name       string
@@ -1534,3 +1667,218 @@ func Test_removeStaleStatus(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateV2AgentUpdateStrategy(t *testing.T) {
+	newDDA := func(strategyType string) *datadoghqv2alpha1.DatadogAgent {
+		return &datadoghqv2alpha1.DatadogAgent{
+			Spec: datadoghqv2alpha1.DatadogAgentSpec{
+				Override: map[datadoghqv2alpha1.ComponentName]*datadoghqv2alpha1.DatadogAgentComponentOverride{
+					datadoghqv2alpha1.NodeAgentComponentName: {
+						UpdateStrategy: &datadoghqv2alpha1.UpdateStrategy{Type: strategyType},
+					},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name            string
+		dda             *datadoghqv2alpha1.DatadogAgent
+		profilesEnabled bool
+		wantErr         bool
+	}{
+		{
+			name:            "no override",
+			dda:             &datadoghqv2alpha1.DatadogAgent{},
+			profilesEnabled: true,
+		},
+		{
+			name:            "rolling update with profiles",
+			dda:             newDDA("RollingUpdate"),
+			profilesEnabled: true,
+		},
+		{
+			name:            "on delete without profiles",
+			dda:             newDDA("OnDelete"),
+			profilesEnabled: false,
+		},
+		{
+			name:            "on delete with profiles",
+			dda:             newDDA("OnDelete"),
+			profilesEnabled: true,
+			wantErr:         true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reconciler{
+				options: ReconcilerOptions{
+					DatadogAgentProfileEnabled: tt.profilesEnabled,
+				},
+			}
+			err := r.validateV2AgentUpdateStrategy(tt.dda)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func Test_featuresForProfile(t *testing.T) {
+	dda := &datadoghqv2alpha1.DatadogAgent{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo"},
+		Spec: datadoghqv2alpha1.DatadogAgentSpec{
+			Features: &datadoghqv2alpha1.DatadogFeatures{
+				NPM: &datadoghqv2alpha1.NPMFeatureConfig{Enabled: apiutils.NewBoolPointer(true)},
+			},
+		},
+	}
+	datadoghqv2alpha1.DefaultDatadogAgent(dda)
+	logger := logf.Log.WithName("Test_featuresForProfile")
+	features, requiredComponents, featureRequirements := feature.BuildFeaturesWithRequirements(dda, reconcilerOptionsToFeatureOptions(&ReconcilerOptions{}, logger))
+	assert.Contains(t, requiredComponents.Agent.Containers, common.SystemProbeContainerName)
+
+	hasFeature := func(features []feature.Feature, id feature.IDType) bool {
+		for _, feat := range features {
+			if feat.ID() == id {
+				return true
+			}
+		}
+		return false
+	}
+	assert.True(t, hasFeature(features, feature.NPMIDType))
+
+	// Without disabled features, the features are unchanged
+	profile := &v1alpha1.DatadogAgentProfile{}
+	profileFeatures, profileRequiredComponents := featuresForProfile(features, requiredComponents, featureRequirements, profile)
+	assert.Equal(t, features, profileFeatures)
+	assert.Equal(t, requiredComponents, profileRequiredComponents)
+
+	// NPM is turned off by the profile, system-probe is only required by NPM
+	profile = &v1alpha1.DatadogAgentProfile{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "edge"},
+		Spec: v1alpha1.DatadogAgentProfileSpec{
+			Config: &v1alpha1.Config{
+				Override: map[v1alpha1.ComponentName]*v1alpha1.Override{
+					v1alpha1.NodeAgentComponentName: {
+						Features: map[string]*v1alpha1.FeatureToggle{
+							feature.NPMIDType: {Enabled: apiutils.NewBoolPointer(false)},
+							// The default feature can't be turned off
+							string(feature.DefaultIDType): {Enabled: apiutils.NewBoolPointer(false)},
+						},
+					},
+				},
+			},
+		},
+	}
+	profileFeatures, profileRequiredComponents = featuresForProfile(features, requiredComponents, featureRequirements, profile)
+	assert.False(t, hasFeature(profileFeatures, feature.NPMIDType))
+	assert.True(t, hasFeature(profileFeatures, feature.DefaultIDType))
+	assert.Len(t, profileFeatures, len(features)-1)
+	assert.True(t, profileRequiredComponents.Agent.IsEnabled())
+	assert.Contains(t, profileRequiredComponents.Agent.Containers, common.CoreAgentContainerName)
+	assert.NotContains(t, profileRequiredComponents.Agent.Containers, common.SystemProbeContainerName)
+	// The other components are not impacted
+	assert.Equal(t, requiredComponents.ClusterAgent, profileRequiredComponents.ClusterAgent)
+	// The required components of the DatadogAgent are not modified
+	assert.Contains(t, requiredComponents.Agent.Containers, common.SystemProbeContainerName)
+}
+
+func Test_agentComponentOverridesWithSeveralDatadogAgents(t *testing.T) {
+	profileForDatadogAgent := func(name, ddaName string) v1alpha1.DatadogAgentProfile {
+		return v1alpha1.DatadogAgentProfile{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: name},
+			Spec: v1alpha1.DatadogAgentProfileSpec{
+				DatadogAgentRef: &v1alpha1.DatadogAgentReference{Name: ddaName},
+				ProfileAffinity: &v1alpha1.ProfileAffinity{
+					ProfileNodeAffinity: []corev1.NodeSelectorRequirement{
+						{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{name}},
+					},
+				},
+				Config: &v1alpha1.Config{
+					Override: map[v1alpha1.ComponentName]*v1alpha1.Override{
+						v1alpha1.NodeAgentComponentName: {PriorityClassName: apiutils.NewStringPointer(name)},
+					},
+				},
+			},
+		}
+	}
+	profiles := []v1alpha1.DatadogAgentProfile{
+		profileForDatadogAgent("profile-foo", "foo"),
+		profileForDatadogAgent("profile-other", "other"),
+	}
+	nodes := []corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{
+			"pool":                                "profile-foo",
+			agentprofile.ProfileLabelKey:          "bar-profile-foo",
+			"agent.datadoghq.com/profile.bar-foo": "bar-profile-foo",
+		}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{
+			"pool":                                  "profile-other",
+			agentprofile.ProfileLabelKey:            "bar-profile-other",
+			"agent.datadoghq.com/profile.bar-other": "bar-profile-other",
+		}}},
+	}
+	r := &Reconciler{
+		options: ReconcilerOptions{DatadogAgentProfileEnabled: true},
+	}
+
+	// defaultAffinity returns the affinity of the default DaemonSet of a DatadogAgent
+	defaultAffinity := func(ddaName string, profiles []v1alpha1.DatadogAgentProfile) *corev1.Affinity {
+		dda := &datadoghqv2alpha1.DatadogAgent{ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: ddaName}}
+		profilesToApply, _, err := agentprofile.ProfilesToApply(agentprofile.ProfilesForDatadogAgent(profiles, dda), nodes, logf.Log.WithName(t.Name()))
+		require.NoError(t, err)
+
+		defaultProfile := profilesToApply[len(profilesToApply)-1]
+		require.True(t, agentprofile.IsDefaultProfile(defaultProfile.Namespace, defaultProfile.Name))
+		overrides := r.agentComponentOverrides(dda, ddaName+"-agent", kubernetes.DefaultProvider, nil, &defaultProfile)
+		require.Len(t, overrides, 1)
+		return overrides[0].Affinity
+	}
+	antiAffinity := func(ddaName string) *corev1.PodAntiAffinity {
+		return &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+				{
+					LabelSelector: &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{Key: apicommon.AgentDeploymentComponentLabelKey, Operator: metav1.LabelSelectorOpIn, Values: []string{"agent"}},
+							{Key: apicommon.AgentDeploymentNameLabelKey, Operator: metav1.LabelSelectorOpIn, Values: []string{ddaName}},
+						},
+					},
+					TopologyKey: corev1.LabelHostname,
+				},
+			},
+		}
+	}
+
+	// The default DaemonSet of each DatadogAgent only excludes the nodes labeled with its own profile label, so it
+	// still runs on the nodes labeled with the profiles of the other DatadogAgent, and the Agent pods of the other
+	// DatadogAgent don't prevent its scheduling. Its affinity doesn't depend on the profiles, so that their changes
+	// don't restart its Agents.
+	for _, tt := range []struct {
+		ddaName          string
+		excludedLabelKey string
+	}{
+		{ddaName: "foo", excludedLabelKey: "agent.datadoghq.com/profile.bar-foo"},
+		{ddaName: "other", excludedLabelKey: "agent.datadoghq.com/profile.bar-other"},
+	} {
+		t.Run(tt.ddaName, func(t *testing.T) {
+			expected := &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{
+							{
+								MatchExpressions: []corev1.NodeSelectorRequirement{
+									{Key: tt.excludedLabelKey, Operator: corev1.NodeSelectorOpDoesNotExist},
+								},
+							},
+						},
+					},
+				},
+				PodAntiAffinity: antiAffinity(tt.ddaName),
+			}
+			assert.Equal(t, expected, defaultAffinity(tt.ddaName, profiles))
+			assert.Equal(t, expected, defaultAffinity(tt.ddaName, append(profiles, profileForDatadogAgent("profile-new", tt.ddaName))))
+			assert.Equal(t, expected, defaultAffinity(tt.ddaName, nil))
+		})
+	}
+}
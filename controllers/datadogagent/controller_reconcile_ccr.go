@@ -27,6 +27,46 @@ import (
 func (r *Reconciler) reconcileV2ClusterChecksRunner(logger logr.Logger, requiredComponents feature.RequiredComponents, features []feature.Feature, dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers, newStatus *datadoghqv2alpha1.DatadogAgentStatus) (reconcile.Result, error) {
 	var result reconcile.Result
 
+	deployment, disabledByOverride, err := r.buildV2ClusterChecksRunnerDeployment(logger, features, dda, resourcesManager)
+	if err != nil {
+		return result, err
+	}
+
+	deploymentLogger := logger.WithValues("component", datadoghqv2alpha1.ClusterChecksRunnerReconcileConditionType)
+
+	// The requiredComponents can change depending on if updates to features result in disabled components
+	ccrEnabled := requiredComponents.ClusterChecksRunner.IsEnabled()
+
+	// If the Cluster Agent is disabled, then CCR should be disabled too
+	if !isV2ComponentDeployed(dda, datadoghqv2alpha1.ClusterAgentComponentName, requiredComponents.ClusterAgent) {
+		return r.cleanupV2ClusterChecksRunner(deploymentLogger, dda, deployment, newStatus)
+	}
+
+	if disabledByOverride {
+		if ccrEnabled {
+			// The override supersedes what's set in requiredComponents; update status to reflect the conflict
+			datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(
+				newStatus,
+				metav1.NewTime(time.Now()),
+				datadoghqv2alpha1.OverrideReconcileConflictConditionType,
+				metav1.ConditionTrue,
+				"OverrideConflict",
+				"ClusterChecks component is set to disabled",
+				true,
+			)
+		}
+		// Delete CCR
+		return r.cleanupV2ClusterChecksRunner(deploymentLogger, dda, deployment, newStatus)
+	} else if !hasComponentOverride(dda, datadoghqv2alpha1.ClusterChecksRunnerComponentName) && !ccrEnabled {
+		return r.cleanupV2ClusterChecksRunner(deploymentLogger, dda, deployment, newStatus)
+	}
+
+	return r.createOrUpdateDeployment(deploymentLogger, dda, deployment, newStatus, updateStatusV2WithClusterChecksRunner)
+}
+
+// buildV2ClusterChecksRunnerDeployment returns the Cluster Checks Runner Deployment with the global settings, the features
+// and the override applied. It also returns true if the component is disabled by its override.
+func (r *Reconciler) buildV2ClusterChecksRunnerDeployment(logger logr.Logger, features []feature.Feature, dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers) (*appsv1.Deployment, bool, error) {
 	// Start by creating the Default Cluster-Agent deployment
 	deployment := componentccr.NewDefaultClusterChecksRunnerDeployment(dda)
 	podManagers := feature.NewPodTemplateManagers(&deployment.Spec.Template)
@@ -37,50 +77,24 @@ func (r *Reconciler) reconcileV2ClusterChecksRunner(logger logr.Logger, required
 	// Apply features changes on the Deployment.Spec.Template
 	for _, feat := range features {
 		if errFeat := feat.ManageClusterChecksRunner(podManagers); errFeat != nil {
-			return result, errFeat
-		}
-	}
-
-	deploymentLogger := logger.WithValues("component", datadoghqv2alpha1.ClusterChecksRunnerReconcileConditionType)
-
-	// The requiredComponents can change depending on if updates to features result in disabled components
-	ccrEnabled := requiredComponents.ClusterChecksRunner.IsEnabled()
-	dcaEnabled := requiredComponents.ClusterAgent.IsEnabled()
-
-	// If the Cluster Agent is disabled, then CCR should be disabled too
-	if dcaOverride, ok := dda.Spec.Override[datadoghqv2alpha1.ClusterAgentComponentName]; ok {
-		if apiutils.BoolValue(dcaOverride.Disabled) {
-			return r.cleanupV2ClusterChecksRunner(deploymentLogger, dda, deployment, newStatus)
+			return deployment, false, errFeat
 		}
-	} else if !dcaEnabled {
-		return r.cleanupV2ClusterChecksRunner(deploymentLogger, dda, deployment, newStatus)
 	}
 
 	// If Override is defined for the CCR component, apply the override on the PodTemplateSpec, it will cascade to container.
 	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.ClusterChecksRunnerComponentName]; ok {
 		if apiutils.BoolValue(componentOverride.Disabled) {
-			if ccrEnabled {
-				// The override supersedes what's set in requiredComponents; update status to reflect the conflict
-				datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(
-					newStatus,
-					metav1.NewTime(time.Now()),
-					datadoghqv2alpha1.OverrideReconcileConflictConditionType,
-					metav1.ConditionTrue,
-					"OverrideConflict",
-					"ClusterChecks component is set to disabled",
-					true,
-				)
-			}
-			// Delete CCR
-			return r.cleanupV2ClusterChecksRunner(deploymentLogger, dda, deployment, newStatus)
+			return deployment, true, nil
 		}
 		override.PodTemplateSpec(logger, podManagers, componentOverride, datadoghqv2alpha1.ClusterChecksRunnerComponentName, dda.Name)
 		override.Deployment(deployment, componentOverride)
-	} else if !ccrEnabled {
-		return r.cleanupV2ClusterChecksRunner(deploymentLogger, dda, deployment, newStatus)
 	}
 
-	return r.createOrUpdateDeployment(deploymentLogger, dda, deployment, newStatus, updateStatusV2WithClusterChecksRunner)
+	if err := r.addReferencedObjectsChecksum(podManagers, dda, datadoghqv2alpha1.ClusterChecksRunnerComponentName); err != nil {
+		return deployment, false, err
+	}
+
+	return deployment, false, nil
 }
 
 func updateStatusV2WithClusterChecksRunner(deployment *appsv1.Deployment, newStatus *datadoghqv2alpha1.DatadogAgentStatus, updateTime metav1.Time, status metav1.ConditionStatus, reason, message string) {
@@ -29,6 +29,44 @@ func (r *Reconciler) reconcileV2ClusterAgent(logger logr.Logger, requiredCompone
 	var result reconcile.Result
 	now := metav1.NewTime(time.Now())
 
+	deployment, disabledByOverride, err := r.buildV2ClusterAgentDeployment(logger, features, dda, resourcesManager)
+	if err != nil {
+		updateStatusV2WithClusterAgent(deployment, newStatus, now, metav1.ConditionFalse, "ClusterAgent feature error", err.Error())
+		return result, err
+	}
+
+	deploymentLogger := logger.WithValues("component", datadoghqv2alpha1.ClusterAgentComponentName)
+
+	// The requiredComponents can change depending on if updates to features result in disabled components
+	dcaEnabled := requiredComponents.ClusterAgent.IsEnabled()
+
+	if disabledByOverride {
+		if dcaEnabled {
+			// The override supersedes what's set in requiredComponents; update status to reflect the conflict
+			datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(
+				newStatus,
+				metav1.NewTime(time.Now()),
+				datadoghqv2alpha1.OverrideReconcileConflictConditionType,
+				metav1.ConditionTrue,
+				"OverrideConflict",
+				"ClusterAgent component is set to disabled",
+				true,
+			)
+		}
+		deleteStatusV2WithClusterAgent(newStatus)
+		return r.cleanupV2ClusterAgent(deploymentLogger, dda, deployment, resourcesManager, newStatus)
+	} else if !hasComponentOverride(dda, datadoghqv2alpha1.ClusterAgentComponentName) && !dcaEnabled {
+		// If the override is not defined, then disable based on dcaEnabled value
+		deleteStatusV2WithClusterAgent(newStatus)
+		return r.cleanupV2ClusterAgent(deploymentLogger, dda, deployment, resourcesManager, newStatus)
+	}
+
+	return r.createOrUpdateDeployment(deploymentLogger, dda, deployment, newStatus, updateStatusV2WithClusterAgent)
+}
+
+// buildV2ClusterAgentDeployment returns the Cluster Agent Deployment with the global settings, the features
+// and the override applied. It also returns true if the component is disabled by its override.
+func (r *Reconciler) buildV2ClusterAgentDeployment(logger logr.Logger, features []feature.Feature, dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers) (*appsv1.Deployment, bool, error) {
 	// Start by creating the Default Cluster-Agent deployment
 	deployment := componentdca.NewDefaultClusterAgentDeployment(dda)
 	podManagers := feature.NewPodTemplateManagers(&deployment.Spec.Template)
@@ -44,43 +82,23 @@ func (r *Reconciler) reconcileV2ClusterAgent(logger logr.Logger, requiredCompone
 		}
 	}
 	if len(featErrors) > 0 {
-		err := utilerrors.NewAggregate(featErrors)
-		updateStatusV2WithClusterAgent(deployment, newStatus, now, metav1.ConditionFalse, "ClusterAgent feature error", err.Error())
-		return result, err
+		return deployment, false, utilerrors.NewAggregate(featErrors)
 	}
 
-	deploymentLogger := logger.WithValues("component", datadoghqv2alpha1.ClusterAgentComponentName)
-
-	// The requiredComponents can change depending on if updates to features result in disabled components
-	dcaEnabled := requiredComponents.ClusterAgent.IsEnabled()
-
 	// If Override is defined for the clusterAgent component, apply the override on the PodTemplateSpec, it will cascade to container.
 	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.ClusterAgentComponentName]; ok {
 		if apiutils.BoolValue(componentOverride.Disabled) {
-			if dcaEnabled {
-				// The override supersedes what's set in requiredComponents; update status to reflect the conflict
-				datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(
-					newStatus,
-					metav1.NewTime(time.Now()),
-					datadoghqv2alpha1.OverrideReconcileConflictConditionType,
-					metav1.ConditionTrue,
-					"OverrideConflict",
-					"ClusterAgent component is set to disabled",
-					true,
-				)
-			}
-			deleteStatusV2WithClusterAgent(newStatus)
-			return r.cleanupV2ClusterAgent(deploymentLogger, dda, deployment, resourcesManager, newStatus)
+			return deployment, true, nil
 		}
 		override.PodTemplateSpec(logger, podManagers, componentOverride, datadoghqv2alpha1.ClusterAgentComponentName, dda.Name)
 		override.Deployment(deployment, componentOverride)
-	} else if !dcaEnabled {
-		// If the override is not defined, then disable based on dcaEnabled value
-		deleteStatusV2WithClusterAgent(newStatus)
-		return r.cleanupV2ClusterAgent(deploymentLogger, dda, deployment, resourcesManager, newStatus)
 	}
 
-	return r.createOrUpdateDeployment(deploymentLogger, dda, deployment, newStatus, updateStatusV2WithClusterAgent)
+	if err := r.addReferencedObjectsChecksum(podManagers, dda, datadoghqv2alpha1.ClusterAgentComponentName); err != nil {
+		return deployment, false, err
+	}
+
+	return deployment, false, nil
 }
 
 func updateStatusV2WithClusterAgent(dca *appsv1.Deployment, newStatus *datadoghqv2alpha1.DatadogAgentStatus, updateTime metav1.Time, status metav1.ConditionStatus, reason, message string) {
@@ -8,6 +8,7 @@ package datadogagent
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
@@ -19,6 +20,7 @@ import (
 	"github.com/DataDog/datadog-operator/controllers/datadogagent/override"
 	"github.com/DataDog/datadog-operator/pkg/agentprofile"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
 
 	"github.com/go-logr/logr"
@@ -78,13 +80,18 @@ func (r *Reconciler) internalReconcileV2(ctx context.Context, request reconcile.
 		return result, err
 	}
 
-	// TODO check if IsValideDatadogAgent function is needed for v2
-	/*
-		if err = datadoghqv2alpha1.IsValidDatadogAgent(&instance.Spec); err != nil {
-			reqLogger.V(1).Info("Invalid spec", "error", err)
-			return r.updateStatusIfNeeded(reqLogger, instance, &instance.Status, result, err)
-		}
-	*/
+	// Nothing is reconciled while the DatadogAgent is paused, except its deletion
+	if isPausedV2(instance) {
+		// The objects may drift while the DatadogAgent is paused, they are applied again when it is resumed
+		r.appliedStates.invalidate(request.NamespacedName)
+		return r.reconcilePausedV2(reqLogger, instance)
+	}
+
+	// The validating webhook is optional, so the spec is validated again here
+	if err = datadoghqv2alpha1.IsValidDatadogAgent(&instance.Spec); err != nil {
+		reqLogger.V(1).Info("Invalid spec", "error", err)
+		return r.updateStatusIfNeededV2(reqLogger, instance, instance.Status.DeepCopy(), result, err)
+	}
 
 	// Set default values for GlobalConfig and Features
 	instanceCopy := instance.DeepCopy()
@@ -98,40 +105,23 @@ func (r *Reconciler) reconcileInstanceV2(ctx context.Context, logger logr.Logger
 	newStatus := instance.Status.DeepCopy()
 	now := metav1.NewTime(time.Now())
 
-	features, requiredComponents := feature.BuildFeatures(instance, reconcilerOptionsToFeatureOptions(&r.options, logger))
+	if isDryRunV2(instance) {
+		r.appliedStates.invalidate(types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name})
+		datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(newStatus, now, datadoghqv2alpha1.PausedConditionType, metav1.ConditionFalse, "Resumed", "reconcile resumed", false)
+		return r.reconcileInstanceV2DryRun(ctx, logger, instance, newStatus)
+	}
+	resetPausedAndDryRunStatusV2(newStatus, now)
+
+	features, requiredComponents, featureRequirements := feature.BuildFeaturesWithRequirements(instance, reconcilerOptionsToFeatureOptions(&r.options, logger))
 	// update list of enabled features for metrics forwarder
 	r.updateMetricsForwardersFeatures(instance, features)
 
 	// -----------------------
 	// Manage dependencies
 	// -----------------------
-	storeOptions := &dependencies.StoreOptions{
-		SupportCilium: r.options.SupportCilium,
-		VersionInfo:   r.versionInfo,
-		PlatformInfo:  r.platformInfo,
-		Logger:        logger,
-		Scheme:        r.scheme,
-	}
-	depsStore := dependencies.NewStore(instance, storeOptions)
-	resourceManagers := feature.NewResourceManagers(depsStore)
-
-	var errs []error
-
-	// Set up dependencies required by enabled features
-	for _, feat := range features {
-		logger.V(1).Info("Dependency ManageDependencies", "featureID", feat.ID())
-		if featErr := feat.ManageDependencies(resourceManagers, requiredComponents); featErr != nil {
-			errs = append(errs, featErr)
-		}
-	}
-	if len(errs) > 0 {
-		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, errors.NewAggregate(errs))
-	}
-
-	// Examine user configuration to override any external dependencies (e.g. RBACs)
-	errs = override.Dependencies(logger, resourceManagers, instance)
-	if len(errs) > 0 {
-		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, errors.NewAggregate(errs))
+	depsStore, resourceManagers, err := r.buildV2DependenciesStore(logger, instance, features, requiredComponents)
+	if err != nil {
+		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
 	}
 
 	userSpecifiedClusterAgentToken := instance.Spec.Global.ClusterAgentToken != nil || instance.Spec.Global.ClusterAgentTokenSecret != nil
@@ -143,7 +133,7 @@ func (r *Reconciler) reconcileInstanceV2(ctx context.Context, logger logr.Logger
 	// Start reconcile Components
 	// -----------------------------
 
-	var err error
+	var errs []error
 
 	result, err = r.reconcileV2ClusterAgent(logger, requiredComponents, features, instance, resourceManagers, newStatus)
 	if utils.ShouldReturn(result, err) {
@@ -153,39 +143,20 @@ func (r *Reconciler) reconcileInstanceV2(ctx context.Context, logger logr.Logger
 		datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(newStatus, now, datadoghqv2alpha1.ClusterAgentReconcileConditionType, metav1.ConditionTrue, "reconcile_succeed", "reconcile succeed", false)
 	}
 
-	// Start with an "empty" profile and provider
-	// If profiles is disabled, reconcile the agent once using an empty profile
-	// If introspection is disabled, reconcile the agent once using the empty provider `LegacyProvider`
-	providerList := map[string]struct{}{kubernetes.LegacyProvider: {}}
-	profiles := []datadoghqv1alpha1.DatadogAgentProfile{{}}
-
-	if r.options.DatadogAgentProfileEnabled || r.options.IntrospectionEnabled {
-		// Get a node list for profiles and introspection
-		nodeList, e := r.getNodeList(ctx)
-		if e != nil {
-			return r.updateStatusIfNeededV2(logger, instance, newStatus, result, e)
-		}
-
-		if r.options.IntrospectionEnabled {
-			providerList = kubernetes.GetProviderListFromNodeList(nodeList, logger)
-		}
-
-		if r.options.DatadogAgentProfileEnabled {
-			profileList, profilesByNode, e := r.profilesToApply(ctx, logger, nodeList)
-			if e != nil {
-				return r.updateStatusIfNeededV2(logger, instance, newStatus, result, e)
-			}
-			profiles = profileList
-
-			if err = r.handleProfiles(ctx, profilesByNode, instance.Namespace); err != nil {
-				return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
-			}
+	providerList, profiles, profilesByNode, err := r.agentProvidersAndProfiles(ctx, logger, instance)
+	if err != nil {
+		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
+	}
+	if r.options.DatadogAgentProfileEnabled {
+		if err = r.handleProfiles(ctx, logger, instance, profiles, profilesByNode); err != nil {
+			return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
 		}
 	}
 
 	for _, profile := range profiles {
+		profileFeatures, profileRequiredComponents := featuresForProfile(features, requiredComponents, featureRequirements, &profile)
 		for provider := range providerList {
-			result, err = r.reconcileV2Agent(logger, requiredComponents, features, instance, resourceManagers, newStatus, provider, providerList, &profile)
+			result, err = r.reconcileV2Agent(logger, profileRequiredComponents, profileFeatures, instance, resourceManagers, newStatus, provider, providerList, &profile)
 			if utils.ShouldReturn(result, err) {
 				// If the agent reconcile failed, we should not continue with the other profiles
 				errs = append(errs, err)
@@ -212,21 +183,40 @@ func (r *Reconciler) reconcileInstanceV2(ctx context.Context, logger logr.Logger
 		datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(newStatus, now, datadoghqv2alpha1.ClusterChecksRunnerReconcileConditionType, metav1.ConditionTrue, "reconcile_succeed", "reconcile succeed", false)
 	}
 
-	// ------------------------------
-	// Create and update dependencies
-	// ------------------------------
-	errs = append(errs, depsStore.Apply(ctx, r.client)...)
-	if len(errs) > 0 {
-		logger.V(2).Info("Dependencies apply error", "errs", errs)
-		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, errors.NewAggregate(errs))
+	// Skip the apply and the cleanup of the dependencies if nothing changed since the last successful apply.
+	// The owned objects watch events invalidate the applied state to catch their drift.
+	ddaKey := types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}
+	appliedState, err := buildAppliedState(instance, depsStore)
+	if err != nil {
+		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
 	}
+	if r.appliedStates.isUnchanged(ddaKey, appliedState) {
+		logger.V(2).Info("Dependencies unchanged, skipping apply and cleanup")
+	} else {
+		// ------------------------------
+		// Create and update dependencies
+		// ------------------------------
+		applyErrs := depsStore.Apply(ctx, r.client)
+		updateApplyConflictStatus(newStatus, now, applyErrs)
+		errs = append(errs, applyErrs...)
+		if len(errs) > 0 {
+			logger.V(2).Info("Dependencies apply error", "errs", errs)
+			return r.updateStatusIfNeededV2(logger, instance, newStatus, result, errors.NewAggregate(errs))
+		}
 
-	// -----------------------------
-	// Cleanup unused dependencies
-	// -----------------------------
-	// Run it after the deployments reconcile
-	if errs = depsStore.Cleanup(ctx, r.client); len(errs) > 0 {
-		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, errors.NewAggregate(errs))
+		// -----------------------------
+		// Cleanup unused dependencies
+		// -----------------------------
+		// Run it after the deployments reconcile
+		if errs = depsStore.Cleanup(ctx, r.client); len(errs) > 0 {
+			return r.updateStatusIfNeededV2(logger, instance, newStatus, result, errors.NewAggregate(errs))
+		}
+		r.appliedStates.set(ddaKey, appliedState)
+	}
+
+	// Record which spec and features have been applied
+	if err = updateReconciledStatus(instance, newStatus, features, featureRequirements); err != nil {
+		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
 	}
 
 	// Always requeue
@@ -236,6 +226,100 @@ func (r *Reconciler) reconcileInstanceV2(ctx context.Context, logger logr.Logger
 	return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
 }
 
+// agentProvidersAndProfiles returns the providers and the profiles for which an Agent DaemonSet is deployed
+// for the DatadogAgent, and the profile applied on each node.
+func (r *Reconciler) agentProvidersAndProfiles(ctx context.Context, logger logr.Logger, dda metav1.Object) (map[string]struct{}, []datadoghqv1alpha1.DatadogAgentProfile, map[string]types.NamespacedName, error) {
+	// Start with an "empty" profile and provider
+	// If profiles is disabled, reconcile the agent once using an empty profile
+	// If introspection is disabled, reconcile the agent once using the empty provider `LegacyProvider`
+	providerList := map[string]struct{}{kubernetes.LegacyProvider: {}}
+	profiles := []datadoghqv1alpha1.DatadogAgentProfile{{}}
+	var profilesByNode map[string]types.NamespacedName
+
+	if r.options.DatadogAgentProfileEnabled || r.options.IntrospectionEnabled {
+		// Get a node list for profiles and introspection
+		nodeList, err := r.getNodeList(ctx)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		if r.options.IntrospectionEnabled {
+			providerList = kubernetes.GetProviderListFromNodeList(nodeList, logger)
+		}
+
+		if r.options.DatadogAgentProfileEnabled {
+			profiles, profilesByNode, err = r.profilesToApply(ctx, logger, nodeList, dda)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+		}
+	}
+
+	return providerList, profiles, profilesByNode, nil
+}
+
+// buildV2DependenciesStore returns a dependencies store filled with the dependencies of the enabled features
+// and the ones defined in the overrides.
+func (r *Reconciler) buildV2DependenciesStore(logger logr.Logger, instance *datadoghqv2alpha1.DatadogAgent, features []feature.Feature, requiredComponents feature.RequiredComponents) (*dependencies.Store, feature.ResourceManagers, error) {
+	storeOptions := &dependencies.StoreOptions{
+		SupportCilium: r.options.SupportCilium,
+		VersionInfo:   r.versionInfo,
+		PlatformInfo:  r.platformInfo,
+		Logger:        logger,
+		Scheme:        r.scheme,
+	}
+	depsStore := dependencies.NewStore(instance, storeOptions)
+	resourceManagers := feature.NewResourceManagers(depsStore)
+
+	var errs []error
+
+	// Set up dependencies required by enabled features
+	for _, feat := range features {
+		logger.V(1).Info("Dependency ManageDependencies", "featureID", feat.ID())
+		if featErr := feat.ManageDependencies(resourceManagers, requiredComponents); featErr != nil {
+			errs = append(errs, featErr)
+		}
+	}
+	if len(errs) > 0 {
+		return depsStore, resourceManagers, errors.NewAggregate(errs)
+	}
+
+	// Examine user configuration to override any external dependencies (e.g. RBACs)
+	errs = override.Dependencies(logger, resourceManagers, instance)
+	if len(errs) > 0 {
+		return depsStore, resourceManagers, errors.NewAggregate(errs)
+	}
+
+	// PodDisruptionBudgets are only created for the deployed components, the store cleanup removes the other ones
+	dcaDeployed := isV2ComponentDeployed(instance, datadoghqv2alpha1.ClusterAgentComponentName, requiredComponents.ClusterAgent)
+	if dcaDeployed {
+		errs = append(errs, override.PodDisruptionBudget(resourceManagers, instance, datadoghqv2alpha1.ClusterAgentComponentName))
+	}
+	if dcaDeployed && isV2ComponentDeployed(instance, datadoghqv2alpha1.ClusterChecksRunnerComponentName, requiredComponents.ClusterChecksRunner) {
+		errs = append(errs, override.PodDisruptionBudget(resourceManagers, instance, datadoghqv2alpha1.ClusterChecksRunnerComponentName))
+	}
+	if err := errors.NewAggregate(errs); err != nil {
+		return depsStore, resourceManagers, err
+	}
+
+	return depsStore, resourceManagers, nil
+}
+
+// updateApplyConflictStatus reports in the DependenciesApplyConflict condition the dependencies that could not be
+// applied because of fields owned by another field manager.
+func updateApplyConflictStatus(newStatus *datadoghqv2alpha1.DatadogAgentStatus, now metav1.Time, applyErrs []error) {
+	conflicts := dependencies.ApplyConflicts(applyErrs)
+	if len(conflicts) == 0 {
+		datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(newStatus, now, datadoghqv2alpha1.DependenciesApplyConflictConditionType, metav1.ConditionFalse, "ApplySucceeded", "dependencies applied without conflict", false)
+		return
+	}
+	messages := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		messages = append(messages, conflict.Error())
+	}
+	datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(newStatus, now, datadoghqv2alpha1.DependenciesApplyConflictConditionType, metav1.ConditionTrue, "ApplyConflict", strings.Join(messages, "; "), false)
+}
+
 func (r *Reconciler) updateStatusIfNeededV2(logger logr.Logger, agentdeployment *datadoghqv2alpha1.DatadogAgent, newStatus *datadoghqv2alpha1.DatadogAgentStatus, result reconcile.Result, currentError error) (reconcile.Result, error) {
 	now := metav1.NewTime(time.Now())
 	if currentError == nil {
@@ -307,6 +391,47 @@ func ensureAutoGeneratedTokenInStatus(instance *datadoghqv2alpha1.DatadogAgent,
 	newStatus.ClusterAgent.GeneratedToken = string(generatedToken)
 }
 
+// updateReconciledStatus sets the observed generation, the hash of the defaulted spec
+// and the enabled features once they have been successfully applied.
+func updateReconciledStatus(instance *datadoghqv2alpha1.DatadogAgent, newStatus *datadoghqv2alpha1.DatadogAgentStatus, features []feature.Feature, requirements map[feature.IDType]feature.RequiredComponents) error {
+	hash, err := comparison.GenerateMD5ForSpec(instance.Spec)
+	if err != nil {
+		return fmt.Errorf("unable to generate the spec MD5, %w", err)
+	}
+
+	newStatus.ObservedGeneration = instance.Generation
+	newStatus.SpecHash = hash
+	newStatus.Features = make([]datadoghqv2alpha1.FeatureStatus, 0, len(features))
+	for _, feat := range features {
+		newStatus.Features = append(newStatus.Features, getFeatureStatus(feat.ID(), requirements[feat.ID()]))
+	}
+
+	return nil
+}
+
+func getFeatureStatus(id feature.IDType, reqComponents feature.RequiredComponents) datadoghqv2alpha1.FeatureStatus {
+	status := datadoghqv2alpha1.FeatureStatus{ID: string(id)}
+	components := []struct {
+		name      datadoghqv2alpha1.ComponentName
+		component feature.RequiredComponent
+	}{
+		{datadoghqv2alpha1.NodeAgentComponentName, reqComponents.Agent},
+		{datadoghqv2alpha1.ClusterAgentComponentName, reqComponents.ClusterAgent},
+		{datadoghqv2alpha1.ClusterChecksRunnerComponentName, reqComponents.ClusterChecksRunner},
+	}
+	for _, c := range components {
+		if !c.component.IsConfigured() {
+			continue
+		}
+		status.RequiredComponents = append(status.RequiredComponents, datadoghqv2alpha1.RequiredComponentStatus{
+			Name:       c.name,
+			Required:   c.component.IsEnabled(),
+			Containers: c.component.Containers,
+		})
+	}
+	return status
+}
+
 func (r *Reconciler) updateMetricsForwardersFeatures(dda *datadoghqv2alpha1.DatadogAgent, features []feature.Feature) {
 	// todo: fix nil pointer metrics forwarder
 	// if r.forwarders != nil {
@@ -314,14 +439,15 @@ func (r *Reconciler) updateMetricsForwardersFeatures(dda *datadoghqv2alpha1.Data
 	// }
 }
 
-func (r *Reconciler) profilesToApply(ctx context.Context, logger logr.Logger, nodeList []corev1.Node) ([]datadoghqv1alpha1.DatadogAgentProfile, map[string]types.NamespacedName, error) {
+// profilesToApply returns the profiles to apply for the given DatadogAgent, among the ones that apply to it.
+func (r *Reconciler) profilesToApply(ctx context.Context, logger logr.Logger, nodeList []corev1.Node, dda metav1.Object) ([]datadoghqv1alpha1.DatadogAgentProfile, map[string]types.NamespacedName, error) {
 	profilesList := datadoghqv1alpha1.DatadogAgentProfileList{}
 	err := r.client.List(ctx, &profilesList)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return agentprofile.ProfilesToApply(profilesList.Items, nodeList, logger)
+	return agentprofile.ProfilesToApply(agentprofile.ProfilesForDatadogAgent(profilesList.Items, dda), nodeList, logger)
 }
 
 func (r *Reconciler) getNodeList(ctx context.Context) ([]corev1.Node, error) {
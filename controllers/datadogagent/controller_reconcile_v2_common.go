@@ -11,6 +11,8 @@ import (
 	"time"
 
 	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
@@ -360,3 +362,18 @@ func ensureSelectorInPodTemplateLabels(logger logr.Logger, selector *metav1.Labe
 
 	return labels
 }
+
+// hasComponentOverride returns true if an override is defined for the component.
+func hasComponentOverride(dda *datadoghqv2alpha1.DatadogAgent, name datadoghqv2alpha1.ComponentName) bool {
+	_, found := dda.Spec.Override[name]
+	return found
+}
+
+// isV2ComponentDeployed returns true if the component needs to be deployed.
+// When an override is defined for the component it supersedes the features requirements.
+func isV2ComponentDeployed(dda *datadoghqv2alpha1.DatadogAgent, name datadoghqv2alpha1.ComponentName, required feature.RequiredComponent) bool {
+	if componentOverride, found := dda.Spec.Override[name]; found {
+		return !apiutils.BoolValue(componentOverride.Disabled)
+	}
+	return required.IsEnabled()
+}
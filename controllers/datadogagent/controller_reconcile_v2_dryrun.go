@@ -0,0 +1,158 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+)
+
+const dryRunEventReason = "DryRun"
+
+// isPausedV2 returns true if the reconcile of the DatadogAgent is paused with the PausedAnnotationKey annotation.
+func isPausedV2(dda *datadoghqv2alpha1.DatadogAgent) bool {
+	return dda.Annotations[datadoghqv2alpha1.PausedAnnotationKey] == "true"
+}
+
+// isDryRunV2 returns true if the DatadogAgent is reconciled in dry-run mode with the DryRunAnnotationKey annotation.
+func isDryRunV2(dda *datadoghqv2alpha1.DatadogAgent) bool {
+	return dda.Annotations[datadoghqv2alpha1.DryRunAnnotationKey] == "true"
+}
+
+// reconcilePausedV2 only reports that the DatadogAgent is paused, nothing is created, updated or deleted.
+// There is no need to requeue: removing the annotation triggers a new reconcile.
+func (r *Reconciler) reconcilePausedV2(logger logr.Logger, instance *datadoghqv2alpha1.DatadogAgent) (reconcile.Result, error) {
+	logger.Info("Reconcile paused", "annotation", datadoghqv2alpha1.PausedAnnotationKey)
+
+	newStatus := instance.Status.DeepCopy()
+	datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(newStatus, metav1.NewTime(time.Now()), datadoghqv2alpha1.PausedConditionType, metav1.ConditionTrue,
+		"Paused", fmt.Sprintf("reconcile paused by the %s annotation", datadoghqv2alpha1.PausedAnnotationKey), false)
+	return r.updateStatusIfNeededV2(logger, instance, newStatus, reconcile.Result{}, nil)
+}
+
+// reconcileInstanceV2DryRun renders the dependencies and the workloads like reconcileInstanceV2, but instead of applying them
+// it records the planned creates, updates and deletes in the status and in events.
+func (r *Reconciler) reconcileInstanceV2DryRun(ctx context.Context, logger logr.Logger, instance *datadoghqv2alpha1.DatadogAgent, newStatus *datadoghqv2alpha1.DatadogAgentStatus) (reconcile.Result, error) {
+	result := reconcile.Result{RequeueAfter: defaultRequeuePeriod}
+
+	// The profiles are not applied on the nodes in dry-run mode
+	providerList, profiles, _, err := r.agentProvidersAndProfiles(ctx, logger, instance)
+	if err != nil {
+		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
+	}
+
+	rendered, err := r.renderV2(logger, instance, providerList, profiles)
+	if err != nil {
+		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
+	}
+
+	// Keep the generated token, otherwise a new one is planned on each reconcile
+	userSpecifiedClusterAgentToken := instance.Spec.Global.ClusterAgentToken != nil || instance.Spec.Global.ClusterAgentTokenSecret != nil
+	if !userSpecifiedClusterAgentToken {
+		ensureAutoGeneratedTokenInStatus(instance, newStatus, feature.NewResourceManagers(rendered.Store), logger)
+	}
+
+	changes, err := rendered.Plan(ctx, r.client)
+	if err != nil {
+		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
+	}
+
+	extendedDaemonSets, daemonSets, err := r.listExtraneousDaemonSets(ctx, instance, providerList, profiles)
+	if err != nil {
+		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
+	}
+	for i := range extendedDaemonSets {
+		changes = append(changes, PlannedChange{Action: PlannedDelete, Current: &extendedDaemonSets[i]})
+	}
+	for i := range daemonSets {
+		changes = append(changes, PlannedChange{Action: PlannedDelete, Current: &daemonSets[i]})
+	}
+
+	plannedChanges, err := r.plannedChangesStatus(changes)
+	if err != nil {
+		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
+	}
+	newStatus.PlannedChanges = plannedChanges
+
+	// Only record events when the plan changes, the dry-run is computed on every reconcile
+	if !apiequality.Semantic.DeepEqual(instance.Status.PlannedChanges, plannedChanges) {
+		r.recordPlannedChanges(instance, plannedChanges)
+	}
+
+	datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(newStatus, metav1.NewTime(time.Now()), datadoghqv2alpha1.DryRunConditionType, metav1.ConditionTrue,
+		"DryRun", fmt.Sprintf("%d change(s) planned, dry-run enabled by the %s annotation", len(plannedChanges), datadoghqv2alpha1.DryRunAnnotationKey), false)
+	return r.updateStatusIfNeededV2(logger, instance, newStatus, result, nil)
+}
+
+// resetPausedAndDryRunStatusV2 removes the result of a previous dry-run from the status, and the paused condition once the annotation is removed.
+func resetPausedAndDryRunStatusV2(newStatus *datadoghqv2alpha1.DatadogAgentStatus, now metav1.Time) {
+	newStatus.PlannedChanges = nil
+	datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(newStatus, now, datadoghqv2alpha1.DryRunConditionType, metav1.ConditionFalse, "DryRunDisabled", "dry-run disabled", false)
+	datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(newStatus, now, datadoghqv2alpha1.PausedConditionType, metav1.ConditionFalse, "Resumed", "reconcile resumed", false)
+}
+
+// plannedChangesStatus converts the planned changes to their status representation, sorted by kind, namespace and name.
+func (r *Reconciler) plannedChangesStatus(changes []PlannedChange) ([]datadoghqv2alpha1.PlannedChangeStatus, error) {
+	var errs []error
+	plannedChanges := make([]datadoghqv2alpha1.PlannedChangeStatus, 0, len(changes))
+	for _, change := range changes {
+		obj := change.Desired
+		if obj == nil {
+			obj = change.Current
+		}
+		gvk, err := apiutil.GVKForObject(obj, r.scheme)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to get the kind of %s: %w", obj.GetName(), err))
+			continue
+		}
+		plannedChanges = append(plannedChanges, datadoghqv2alpha1.PlannedChangeStatus{
+			Action:    string(change.Action),
+			Kind:      gvk.Kind,
+			Namespace: obj.GetNamespace(),
+			Name:      obj.GetName(),
+		})
+	}
+
+	sort.Slice(plannedChanges, func(i, j int) bool {
+		a, b := plannedChanges[i], plannedChanges[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return plannedChanges, errors.NewAggregate(errs)
+}
+
+func (r *Reconciler) recordPlannedChanges(dda client.Object, plannedChanges []datadoghqv2alpha1.PlannedChangeStatus) {
+	if len(plannedChanges) == 0 {
+		r.recorder.Event(dda, corev1.EventTypeNormal, dryRunEventReason, "Dry-run: no change planned")
+		return
+	}
+	for _, change := range plannedChanges {
+		name := change.Name
+		if change.Namespace != "" {
+			name = change.Namespace + "/" + name
+		}
+		r.recorder.Eventf(dda, corev1.EventTypeNormal, dryRunEventReason, "Dry-run: %s %s %s", change.Action, change.Kind, name)
+	}
+}
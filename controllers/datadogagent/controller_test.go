@@ -2849,7 +2849,7 @@ func TestReconcileDatadogAgent_Reconcile(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &Reconciler{
-				client:       tt.fields.client,
+				client:       testutils.NewApplyClient(tt.fields.client),
 				scheme:       tt.fields.scheme,
 				platformInfo: tt.fields.platformInfo,
 				recorder:     recorder,
@@ -2959,6 +2959,48 @@ func Test_LabelNodesWithProfiles(t *testing.T) {
 				"node-2": "ns-2-profile-2",
 			},
 		},
+		{
+			name: "Node with the label of another profile",
+			profilesByNode: map[string]types.NamespacedName{
+				"node-1": {
+					Name:      "profile-2",
+					Namespace: "ns-2",
+				},
+			},
+			nodes: []corev1.Node{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "node-1",
+						Labels: map[string]string{
+							"some-label":                 "value",
+							agentprofile.ProfileLabelKey: "ns-1-profile-1",
+						},
+					},
+				},
+			},
+			expectProfileLabel: map[string]string{
+				"node-1": "ns-2-profile-2",
+			},
+		},
+		{
+			name: "Profile with a long name",
+			profilesByNode: map[string]types.NamespacedName{
+				"node-1": {
+					Name:      "profile-with-a-very-long-name-that-does-not-fit-in-a-label-value",
+					Namespace: "ns-1",
+				},
+			},
+			nodes: []corev1.Node{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "node-1",
+					},
+				},
+			},
+			expectProfileLabel: map[string]string{
+				"node-1": "ns-1-profile-with-a-very-long-name-that-does-not-fit-i-5f3009e2",
+			},
+		},
 		{
 			name:           "No nodes match profiles",
 			profilesByNode: map[string]types.NamespacedName{},
@@ -2999,7 +3041,8 @@ func Test_LabelNodesWithProfiles(t *testing.T) {
 				err := client.Create(context.TODO(), &node)
 				require.NoError(t, err, "Error creating node")
 			}
-			err := r.labelNodesWithProfiles(context.TODO(), tt.profilesByNode)
+			dda := &metav1.ObjectMeta{Namespace: "ns-1", Name: "dda-foo"}
+			err := r.labelNodesWithProfiles(context.TODO(), dda, tt.profilesByNode)
 			require.NoErrorf(t, err, "Error labeling nodes. Error: %v", err)
 
 			gotNodes := &corev1.NodeList{}
@@ -3009,14 +3052,162 @@ func Test_LabelNodesWithProfiles(t *testing.T) {
 			for _, node := range gotNodes.Items {
 				if val, ok := tt.expectProfileLabel[node.Name]; ok && val != "" {
 					assert.Equal(t, node.Labels[agentprofile.ProfileLabelKey], val)
+					assert.Equal(t, node.Labels[agentprofile.DatadogAgentProfileLabelKey(dda)], val)
 				} else {
 					assert.NotContains(t, node.Labels, agentprofile.ProfileLabelKey)
+					assert.NotContains(t, node.Labels, agentprofile.DatadogAgentProfileLabelKey(dda))
 				}
 			}
 		})
 	}
 }
 
+func Test_handleProfiles(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(corev1.SchemeGroupVersion, &corev1.Node{})
+	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogAgentProfile{}, &datadoghqv1alpha1.DatadogAgentProfileList{})
+
+	profile := datadoghqv1alpha1.DatadogAgentProfile{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "profile-1"},
+		Spec: datadoghqv1alpha1.DatadogAgentProfileSpec{
+			RolloutPolicy: &datadoghqv1alpha1.ProfileRolloutPolicy{},
+		},
+	}
+	profileNamespacedName := types.NamespacedName{Namespace: "ns-1", Name: "profile-1"}
+	dda := &metav1.ObjectMeta{Namespace: "bar", Name: "foo"}
+	agentPodLabels := map[string]string{
+		apicommon.AgentDeploymentNameLabelKey:      "foo",
+		apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
+	}
+
+	var objects []client.Object
+	for _, nodeName := range []string{"node-1", "node-2"} {
+		objects = append(objects,
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "bar",
+					Name:      "agent-" + nodeName,
+					Labels:    agentPodLabels,
+				},
+				Spec: corev1.PodSpec{NodeName: nodeName},
+			},
+		)
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(objects...).Build()
+	r := &Reconciler{
+		client: c,
+		scheme: s,
+		log:    logf.Log.WithName(t.Name()),
+	}
+
+	profilesByNode := map[string]types.NamespacedName{
+		"node-1": profileNamespacedName,
+		"node-2": profileNamespacedName,
+	}
+	err := r.handleProfiles(context.TODO(), r.log, dda, []datadoghqv1alpha1.DatadogAgentProfile{profile}, profilesByNode)
+	require.NoError(t, err)
+
+	// Only one node moves to the profile, the Agent pod of the other node is kept
+	node := &corev1.Node{}
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "node-1"}, node))
+	assert.Equal(t, "ns-1-profile-1", node.Labels[agentprofile.ProfileLabelKey])
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "node-2"}, node))
+	assert.NotContains(t, node.Labels, agentprofile.ProfileLabelKey)
+
+	pod := &corev1.Pod{}
+	err = c.Get(context.TODO(), types.NamespacedName{Namespace: "bar", Name: "agent-node-1"}, pod)
+	assert.True(t, apierrors.IsNotFound(err))
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: "bar", Name: "agent-node-2"}, pod))
+
+	// The new Agent pod of the first node isn't ready yet, the other node keeps waiting
+	err = r.handleProfiles(context.TODO(), r.log, dda, []datadoghqv1alpha1.DatadogAgentProfile{profile}, profilesByNode)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "node-2"}, node))
+	assert.NotContains(t, node.Labels, agentprofile.ProfileLabelKey)
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: "bar", Name: "agent-node-2"}, pod))
+
+	// Once the new Agent pod of the first node is ready, the other node moves
+	require.NoError(t, c.Create(context.TODO(), &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "bar",
+			Name:      "agent-profile-node-1",
+			Labels: map[string]string{
+				apicommon.AgentDeploymentNameLabelKey:      "foo",
+				apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
+				agentprofile.ProfileLabelKey:               "ns-1-profile-1",
+			},
+		},
+		Spec: corev1.PodSpec{NodeName: "node-1"},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}))
+	err = r.handleProfiles(context.TODO(), r.log, dda, []datadoghqv1alpha1.DatadogAgentProfile{profile}, profilesByNode)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "node-2"}, node))
+	assert.Equal(t, "ns-1-profile-1", node.Labels[agentprofile.ProfileLabelKey])
+	err = c.Get(context.TODO(), types.NamespacedName{Namespace: "bar", Name: "agent-node-2"}, pod)
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func Test_handleProfilesWithOtherDatadogAgents(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(corev1.SchemeGroupVersion, &corev1.Node{})
+	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogAgentProfile{}, &datadoghqv1alpha1.DatadogAgentProfileList{})
+
+	// The profile of the node-2 applies to another DatadogAgent
+	otherProfile := &datadoghqv1alpha1.DatadogAgentProfile{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "other-profile"},
+		Spec: datadoghqv1alpha1.DatadogAgentProfileSpec{
+			DatadogAgentRef: &datadoghqv1alpha1.DatadogAgentReference{Namespace: "bar", Name: "other"},
+		},
+	}
+	dda := &metav1.ObjectMeta{Namespace: "bar", Name: "foo"}
+
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		otherProfile,
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{agentprofile.ProfileLabelKey: "ns-1-deleted-profile"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{agentprofile.ProfileLabelKey: "ns-1-other-profile"}}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "bar",
+				Name:      "other-agent-node-1",
+				Labels: map[string]string{
+					apicommon.AgentDeploymentNameLabelKey:      "other",
+					apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
+					agentprofile.ProfileLabelKey:               "ns-1-other-profile",
+				},
+			},
+			Spec: corev1.PodSpec{NodeName: "node-1"},
+		},
+	).Build()
+	r := &Reconciler{
+		client: c,
+		scheme: s,
+		log:    logf.Log.WithName(t.Name()),
+	}
+
+	defaultProfile := types.NamespacedName{Name: "default"}
+	profilesByNode := map[string]types.NamespacedName{
+		"node-1": defaultProfile,
+		"node-2": defaultProfile,
+	}
+	err := r.handleProfiles(context.TODO(), r.log, dda, nil, profilesByNode)
+	require.NoError(t, err)
+
+	// The label of a deleted profile is removed, the label of the other DatadogAgent profile is kept
+	node := &corev1.Node{}
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "node-1"}, node))
+	assert.NotContains(t, node.Labels, agentprofile.ProfileLabelKey)
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "node-2"}, node))
+	assert.Equal(t, "ns-1-other-profile", node.Labels[agentprofile.ProfileLabelKey])
+
+	// The Agent pods of the other DatadogAgent are not deleted
+	pod := &corev1.Pod{}
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: "bar", Name: "other-agent-node-1"}, pod))
+}
+
 func newRequest(ns, name string) reconcile.Request {
 	return reconcile.Request{
 		NamespacedName: types.NamespacedName{
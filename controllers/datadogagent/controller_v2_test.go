@@ -19,6 +19,7 @@ import (
 	v2alpha1test "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1/test"
 	apiutils "github.com/DataDog/datadog-operator/apis/utils"
 	componentagent "github.com/DataDog/datadog-operator/controllers/datadogagent/component/agent"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
 	testutils "github.com/DataDog/datadog-operator/controllers/datadogagent/testutils"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
 
@@ -99,6 +100,82 @@ func TestReconcileDatadogAgentV2_Reconcile(t *testing.T) {
 				return verifyDaemonsetContainers(c, resourcesNamespace, dsName, expectedContainers)
 			},
 		},
+		{
+			name: "DatadogAgent default, status reports the applied spec and features",
+			fields: fields{
+				client:   fake.NewFakeClient(),
+				scheme:   s,
+				recorder: recorder,
+			},
+			args: args{
+				request: newRequest(resourcesNamespace, resourcesName),
+				loadFunc: func(c client.Client) {
+					dda := v2alpha1test.NewInitializedDatadogAgentBuilder(resourcesNamespace, resourcesName).
+						Build()
+					_ = c.Create(context.TODO(), dda)
+				},
+			},
+			want:    reconcile.Result{RequeueAfter: defaultRequeueDuration},
+			wantErr: false,
+			wantFunc: func(c client.Client) error {
+				return verifyReconciledStatus(c, resourcesNamespace, resourcesName)
+			},
+		},
+		{
+			name: "DatadogAgent paused, nothing is created",
+			fields: fields{
+				client:   fake.NewFakeClient(),
+				scheme:   s,
+				recorder: recorder,
+			},
+			args: args{
+				request: newRequest(resourcesNamespace, resourcesName),
+				loadFunc: func(c client.Client) {
+					dda := v2alpha1test.NewInitializedDatadogAgentBuilder(resourcesNamespace, resourcesName).
+						Build()
+					dda.Annotations = map[string]string{v2alpha1.PausedAnnotationKey: "true"}
+					_ = c.Create(context.TODO(), dda)
+				},
+			},
+			want:    reconcile.Result{},
+			wantErr: false,
+			wantFunc: func(c client.Client) error {
+				if err := verifyNoDaemonSet(c, resourcesNamespace); err != nil {
+					return err
+				}
+				return verifyStatusCondition(c, resourcesNamespace, resourcesName, v2alpha1.PausedConditionType)
+			},
+		},
+		{
+			name: "DatadogAgent dry-run, planned changes are reported in the status",
+			fields: fields{
+				client:   fake.NewFakeClient(),
+				scheme:   s,
+				recorder: recorder,
+			},
+			args: args{
+				request: newRequest(resourcesNamespace, resourcesName),
+				loadFunc: func(c client.Client) {
+					dda := v2alpha1test.NewInitializedDatadogAgentBuilder(resourcesNamespace, resourcesName).
+						Build()
+					dda.Annotations = map[string]string{v2alpha1.DryRunAnnotationKey: "true"}
+					_ = c.Create(context.TODO(), dda)
+				},
+			},
+			want:    reconcile.Result{RequeueAfter: defaultRequeueDuration},
+			wantErr: false,
+			wantFunc: func(c client.Client) error {
+				if err := verifyNoDaemonSet(c, resourcesNamespace); err != nil {
+					return err
+				}
+				if err := verifyStatusCondition(c, resourcesNamespace, resourcesName, v2alpha1.DryRunConditionType); err != nil {
+					return err
+				}
+				return verifyPlannedChange(c, resourcesNamespace, resourcesName, v2alpha1.PlannedChangeStatus{
+					Action: "create", Kind: "DaemonSet", Namespace: resourcesNamespace, Name: dsName,
+				})
+			},
+		},
 		{
 			name: "DatadogAgent singleProcessContainer, create Daemonset with core, trace and process agents",
 			fields: fields{
@@ -373,7 +450,7 @@ func TestReconcileDatadogAgentV2_Reconcile(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &Reconciler{
-				client:       tt.fields.client,
+				client:       testutils.NewApplyClient(tt.fields.client),
 				scheme:       tt.fields.scheme,
 				platformInfo: tt.fields.platformInfo,
 				recorder:     recorder,
@@ -408,6 +485,27 @@ func TestReconcileDatadogAgentV2_Reconcile(t *testing.T) {
 	}
 }
 
+func Test_updateApplyConflictStatus(t *testing.T) {
+	now := metav1.NewTime(time.Now())
+	status := &v2alpha1.DatadogAgentStatus{}
+
+	// No condition is added while there is no conflict
+	updateApplyConflictStatus(status, now, nil)
+	assert.Empty(t, status.Conditions)
+
+	conflict := &dependencies.ApplyConflictError{Kind: kubernetes.ConfigMapKind, Namespace: "bar", Name: "foo", Err: fmt.Errorf("conflict with \"other-manager\": .data.data1")}
+	updateApplyConflictStatus(status, now, []error{fmt.Errorf("other error"), conflict})
+	assert.Len(t, status.Conditions, 1)
+	assert.Equal(t, v2alpha1.DependenciesApplyConflictConditionType, status.Conditions[0].Type)
+	assert.Equal(t, metav1.ConditionTrue, status.Conditions[0].Status)
+	assert.Equal(t, "ApplyConflict", status.Conditions[0].Reason)
+	assert.Equal(t, `conflict while applying configmaps bar/foo: conflict with "other-manager": .data.data1`, status.Conditions[0].Message)
+
+	updateApplyConflictStatus(status, now, nil)
+	assert.Len(t, status.Conditions, 1)
+	assert.Equal(t, metav1.ConditionFalse, status.Conditions[0].Status)
+}
+
 func Test_Introspection(t *testing.T) {
 	const resourcesName = "foo"
 	const resourcesNamespace = "bar"
@@ -497,7 +595,7 @@ func Test_Introspection(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &Reconciler{
-				client:       fake.NewClientBuilder().WithObjects(tt.nodes...).Build(),
+				client:       testutils.NewApplyClient(fake.NewClientBuilder().WithObjects(tt.nodes...).Build()),
 				scheme:       tt.fields.scheme,
 				platformInfo: tt.fields.platformInfo,
 				recorder:     recorder,
@@ -552,6 +650,71 @@ func verifyDaemonsetContainers(c client.Client, resourcesNamespace, dsName strin
 	}
 }
 
+func verifyReconciledStatus(c client.Client, resourcesNamespace, resourcesName string) error {
+	dda := &v2alpha1.DatadogAgent{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: resourcesNamespace, Name: resourcesName}, dda); err != nil {
+		return err
+	}
+	if dda.Status.ObservedGeneration != dda.Generation {
+		return fmt.Errorf("observedGeneration is %d, want %d", dda.Status.ObservedGeneration, dda.Generation)
+	}
+	if dda.Status.SpecHash == "" {
+		return fmt.Errorf("specHash should be set")
+	}
+	for _, feat := range dda.Status.Features {
+		if feat.ID != "default" {
+			continue
+		}
+		for _, component := range feat.RequiredComponents {
+			if component.Name == v2alpha1.NodeAgentComponentName && component.Required {
+				return nil
+			}
+		}
+		return fmt.Errorf("default feature should require the node agent, got: %v", feat.RequiredComponents)
+	}
+	return fmt.Errorf("default feature not found in status, got: %v", dda.Status.Features)
+}
+
+func verifyNoDaemonSet(c client.Client, resourcesNamespace string) error {
+	daemonSetList := appsv1.DaemonSetList{}
+	if err := c.List(context.TODO(), &daemonSetList, client.InNamespace(resourcesNamespace)); err != nil {
+		return err
+	}
+	if len(daemonSetList.Items) > 0 {
+		return fmt.Errorf("no DaemonSet should be created, got %d", len(daemonSetList.Items))
+	}
+	return nil
+}
+
+func verifyStatusCondition(c client.Client, resourcesNamespace, resourcesName, conditionType string) error {
+	dda := &v2alpha1.DatadogAgent{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: resourcesNamespace, Name: resourcesName}, dda); err != nil {
+		return err
+	}
+	for _, condition := range dda.Status.Conditions {
+		if condition.Type == conditionType {
+			if condition.Status != metav1.ConditionTrue {
+				return fmt.Errorf("condition %s is %s, want True", conditionType, condition.Status)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("condition %s not found in status, got: %v", conditionType, dda.Status.Conditions)
+}
+
+func verifyPlannedChange(c client.Client, resourcesNamespace, resourcesName string, expected v2alpha1.PlannedChangeStatus) error {
+	dda := &v2alpha1.DatadogAgent{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: resourcesNamespace, Name: resourcesName}, dda); err != nil {
+		return err
+	}
+	for _, change := range dda.Status.PlannedChanges {
+		if change == expected {
+			return nil
+		}
+	}
+	return fmt.Errorf("planned change %v not found in status, got: %v", expected, dda.Status.PlannedChanges)
+}
+
 func verifyDaemonsetNames(t *testing.T, c client.Client, resourcesNamespace, dsName string, expectedDSNames []string) error {
 	daemonSetList := appsv1.DaemonSetList{}
 	if err := c.List(context.TODO(), &daemonSetList, client.HasLabels{apicommon.MD5AgentDeploymentProviderLabelKey}); err != nil {
@@ -0,0 +1,115 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package dependencies
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+)
+
+// legacyFieldManagers are the field managers of the updates made by the operator before the store used server-side
+// apply. "manager" is the name of the operator binary, used by the api-server when a client sets no field manager.
+var legacyFieldManagers = map[string]struct{}{
+	"manager":    {},
+	FieldManager: {},
+}
+
+// upgradeManagedFields patches the object in the api-server to move the fields owned by the legacy updates of the
+// operator to its apply field manager. Without it, the first apply after an upgrade of the operator conflicts with the
+// fields the operator owns with updates. The object is patched once: the next applies find no legacy field manager.
+func (ds *Store) upgradeManagedFields(ctx context.Context, k8sClient client.Client, kind kubernetes.ObjectKind, current client.Object) error {
+	original, ok := current.DeepCopyObject().(client.Object)
+	if !ok {
+		return nil
+	}
+	upgraded, err := upgradeManagedFields(current)
+	if err != nil || !upgraded {
+		return err
+	}
+
+	ds.logger.V(1).Info("dependencies.store Upgrade managed fields", "obj.namespace", current.GetNamespace(), "obj.name", current.GetName(), "obj.kind", kind)
+	patch := client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})
+	if err := k8sClient.Patch(ctx, current, patch); err != nil {
+		return fmt.Errorf("unable to upgrade the managed fields of %s %s: %w", kind, buildID(current.GetNamespace(), current.GetName()), err)
+	}
+	return nil
+}
+
+// upgradeManagedFields merges the managed fields entries of the legacy field managers into the apply entry of
+// FieldManager, like csaupgrade.UpgradeManagedFields of client-go. It returns false if the object has no legacy entry.
+func upgradeManagedFields(obj metav1.Object) (bool, error) {
+	var legacyEntries, entries []metav1.ManagedFieldsEntry
+	applyIndex := -1
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Subresource != "" {
+			entries = append(entries, entry)
+			continue
+		}
+		if _, found := legacyFieldManagers[entry.Manager]; found && entry.Operation == metav1.ManagedFieldsOperationUpdate {
+			legacyEntries = append(legacyEntries, entry)
+			continue
+		}
+		if entry.Manager == FieldManager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			applyIndex = len(entries)
+		}
+		entries = append(entries, entry)
+	}
+	if len(legacyEntries) == 0 {
+		return false, nil
+	}
+
+	for _, entry := range legacyEntries {
+		if applyIndex == -1 {
+			entry.Manager = FieldManager
+			entry.Operation = metav1.ManagedFieldsOperationApply
+			applyIndex = len(entries)
+			entries = append(entries, entry)
+			continue
+		}
+		fields, err := unionFields(entries[applyIndex].FieldsV1, entry.FieldsV1)
+		if err != nil {
+			return false, fmt.Errorf("unable to merge the fields of the field manager %s: %w", entry.Manager, err)
+		}
+		entries[applyIndex].FieldsV1 = fields
+	}
+	obj.SetManagedFields(entries)
+
+	return true, nil
+}
+
+func unionFields(lhs, rhs *metav1.FieldsV1) (*metav1.FieldsV1, error) {
+	lhsSet, err := fieldSet(lhs)
+	if err != nil {
+		return nil, err
+	}
+	rhsSet, err := fieldSet(rhs)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := lhsSet.Union(rhsSet).ToJSON()
+	if err != nil {
+		return nil, err
+	}
+	return &metav1.FieldsV1{Raw: raw}, nil
+}
+
+func fieldSet(fields *metav1.FieldsV1) (*fieldpath.Set, error) {
+	set := &fieldpath.Set{}
+	if fields == nil || len(fields.Raw) == 0 {
+		return set, nil
+	}
+	if err := set.FromJSON(bytes.NewReader(fields.Raw)); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
@@ -0,0 +1,129 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package dependencies
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+)
+
+func managedFieldsEntry(manager string, operation metav1.ManagedFieldsOperationType, fields string) metav1.ManagedFieldsEntry {
+	return metav1.ManagedFieldsEntry{
+		Manager:    manager,
+		Operation:  operation,
+		APIVersion: "v1",
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(fields)},
+	}
+}
+
+func Test_upgradeManagedFields(t *testing.T) {
+	tests := []struct {
+		name          string
+		managedFields []metav1.ManagedFieldsEntry
+		wantUpgraded  bool
+		want          []metav1.ManagedFieldsEntry
+	}{
+		{
+			name: "no legacy field manager",
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry(FieldManager, metav1.ManagedFieldsOperationApply, `{"f:data":{"f:data1":{}}}`),
+				managedFieldsEntry("kubectl", metav1.ManagedFieldsOperationUpdate, `{"f:data":{"f:data2":{}}}`),
+			},
+			wantUpgraded: false,
+		},
+		{
+			name: "legacy update moved to a new apply entry",
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("manager", metav1.ManagedFieldsOperationUpdate, `{"f:data":{"f:data1":{}}}`),
+				managedFieldsEntry("kubectl", metav1.ManagedFieldsOperationUpdate, `{"f:data":{"f:data2":{}}}`),
+			},
+			wantUpgraded: true,
+			want: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("kubectl", metav1.ManagedFieldsOperationUpdate, `{"f:data":{"f:data2":{}}}`),
+				managedFieldsEntry(FieldManager, metav1.ManagedFieldsOperationApply, `{"f:data":{"f:data1":{}}}`),
+			},
+		},
+		{
+			name: "legacy updates merged into the apply entry",
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry(FieldManager, metav1.ManagedFieldsOperationApply, `{"f:data":{"f:data1":{}}}`),
+				managedFieldsEntry("manager", metav1.ManagedFieldsOperationUpdate, `{"f:data":{"f:data2":{}}}`),
+				managedFieldsEntry(FieldManager, metav1.ManagedFieldsOperationUpdate, `{"f:metadata":{"f:labels":{"f:foo":{}}}}`),
+			},
+			wantUpgraded: true,
+			want: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry(FieldManager, metav1.ManagedFieldsOperationApply, `{"f:data":{"f:data1":{},"f:data2":{}},"f:metadata":{"f:labels":{"f:foo":{}}}}`),
+			},
+		},
+		{
+			name: "status updates are kept",
+			managedFields: []metav1.ManagedFieldsEntry{
+				func() metav1.ManagedFieldsEntry {
+					entry := managedFieldsEntry("manager", metav1.ManagedFieldsOperationUpdate, `{"f:status":{}}`)
+					entry.Subresource = "status"
+					return entry
+				}(),
+			},
+			wantUpgraded: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{ManagedFields: tt.managedFields}}
+			upgraded, err := upgradeManagedFields(obj)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUpgraded, upgraded)
+			if tt.wantUpgraded {
+				assert.Equal(t, tt.want, obj.ManagedFields)
+			}
+		})
+	}
+}
+
+func TestStore_upgradeManagedFields(t *testing.T) {
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "bar",
+			Name:      "foo",
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("manager", metav1.ManagedFieldsOperationUpdate, `{"f:data":{"f:data1":{}}}`),
+			},
+		},
+		Data: map[string]string{
+			"data1": "value1",
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(existing).Build()
+	ds := &Store{logger: logf.Log.WithName(t.Name())}
+
+	current := &corev1.ConfigMap{}
+	require.NoError(t, k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(existing), current))
+	require.NoError(t, ds.upgradeManagedFields(context.TODO(), k8sClient, kubernetes.ConfigMapKind, current))
+
+	upgraded := &corev1.ConfigMap{}
+	require.NoError(t, k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(existing), upgraded))
+	assert.Equal(t, []metav1.ManagedFieldsEntry{
+		managedFieldsEntry(FieldManager, metav1.ManagedFieldsOperationApply, `{"f:data":{"f:data1":{}}}`),
+	}, upgraded.ManagedFields)
+	assert.Equal(t, existing.Data, upgraded.Data)
+
+	// The next applies don't patch the object again
+	resourceVersion := upgraded.ResourceVersion
+	require.NoError(t, ds.upgradeManagedFields(context.TODO(), k8sClient, kubernetes.ConfigMapKind, upgraded))
+	require.NoError(t, k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(existing), upgraded))
+	assert.Equal(t, resourceVersion, upgraded.ResourceVersion)
+}
@@ -7,7 +7,9 @@ package dependencies
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -18,7 +20,6 @@ import (
 	"github.com/DataDog/datadog-operator/pkg/equality"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
 
-	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -28,13 +29,36 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/version"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
 const (
 	// operatorStoreLabelKey used to identified which resource is managed by the store.
 	operatorStoreLabelKey = "operator.datadoghq.com/managed-by-store"
+
+	// FieldManager is the field manager used by the store to server-side apply the resources.
+	FieldManager = "datadog-operator"
 )
 
+// ApplyConflictError is returned by Apply when the server-side apply of a resource
+// conflicts with fields owned by another field manager.
+type ApplyConflictError struct {
+	Kind      kubernetes.ObjectKind
+	Namespace string
+	Name      string
+	Err       error
+}
+
+// Error implements the error interface
+func (e *ApplyConflictError) Error() string {
+	return fmt.Sprintf("conflict while applying %s %s: %v", e.Kind, buildID(e.Namespace, e.Name), e.Err)
+}
+
+// Unwrap returns the error returned by the api-server
+func (e *ApplyConflictError) Unwrap() error {
+	return e.Err
+}
+
 // StoreClient dependencies store client interface
 type StoreClient interface {
 	AddOrUpdate(kind kubernetes.ObjectKind, obj client.Object) error
@@ -197,14 +221,114 @@ func (ds *Store) Delete(kind kubernetes.ObjectKind, namespace string, name strin
 	return false
 }
 
-// Apply use to create/update resources in the api-server
+// Objects returns all the objects of the Store, sorted by kind, namespace and name.
+func (ds *Store) Objects() []client.Object {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	kinds := make([]string, 0, len(ds.deps))
+	for kind := range ds.deps {
+		kinds = append(kinds, string(kind))
+	}
+	sort.Strings(kinds)
+
+	var objs []client.Object
+	for _, kind := range kinds {
+		ids := make([]string, 0, len(ds.deps[kubernetes.ObjectKind(kind)]))
+		for id := range ds.deps[kubernetes.ObjectKind(kind)] {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			objs = append(objs, ds.deps[kubernetes.ObjectKind(kind)][id])
+		}
+	}
+	return objs
+}
+
+// PlannedObject is an object that needs to be created, updated or deleted in the api-server.
+type PlannedObject struct {
+	Kind kubernetes.ObjectKind
+	// Object is the object of the Store, it is nil when the object needs to be deleted.
+	Object client.Object
+	// Current is the object in the api-server, it is nil when the object needs to be created.
+	Current client.Object
+}
+
+// Apply use to create/update resources in the api-server.
+// The resources are server-side applied with the FieldManager field manager, so the fields
+// set by other controllers are preserved. Fields owned by another field manager are not
+// overwritten, an ApplyConflictError is returned for each resource in conflict.
+// The fields set by the updates of previous operator versions are moved to FieldManager
+// before the first apply of a resource, so they don't conflict.
 func (ds *Store) Apply(ctx context.Context, k8sClient client.Client) []error {
 	ds.mutex.RLock()
 	defer ds.mutex.RUnlock()
 
+	objsToCreate, objsToUpdate, errs := ds.planApply(ctx, k8sClient)
+
+	ds.logger.V(2).Info("dependencies.store objsToCreate", "nb", len(objsToCreate))
+	ds.logger.V(2).Info("dependencies.store objsToUpdate", "nb", len(objsToUpdate))
+	for _, planned := range objsToUpdate {
+		if err := ds.upgradeManagedFields(ctx, k8sClient, planned.Kind, planned.Current); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, planned := range append(objsToCreate, objsToUpdate...) {
+		if err := ds.apply(ctx, k8sClient, planned.Kind, planned.Object); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// ApplyConflicts returns the ApplyConflictErrors of errs.
+func ApplyConflicts(errs []error) []*ApplyConflictError {
+	var conflicts []*ApplyConflictError
+	for _, err := range errs {
+		var conflict *ApplyConflictError
+		if errors.As(err, &conflict) {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	return conflicts
+}
+
+func (ds *Store) apply(ctx context.Context, k8sClient client.Client, kind kubernetes.ObjectKind, obj client.Object) error {
+	// The apply patch is built from the serialized object, it must contain the apiVersion and the kind.
+	if obj.GetObjectKind().GroupVersionKind().Empty() {
+		gvk, err := apiutil.GVKForObject(obj, k8sClient.Scheme())
+		if err != nil {
+			return fmt.Errorf("unable to get the GroupVersionKind of %s %s: %w", kind, buildID(obj.GetNamespace(), obj.GetName()), err)
+		}
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+	}
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+
+	err := k8sClient.Patch(ctx, obj, client.Apply, client.FieldOwner(FieldManager))
+	if err == nil {
+		return nil
+	}
+	if apierrors.IsConflict(err) {
+		err = &ApplyConflictError{Kind: kind, Namespace: obj.GetNamespace(), Name: obj.GetName(), Err: err}
+	}
+	ds.logger.Error(err, "dependencies.store Apply", "obj.namespace", obj.GetNamespace(), "obj.name", obj.GetName(), "obj.kind", kind)
+	return err
+}
+
+// PlanApply returns the objects that Apply would create and update, without modifying the api-server.
+func (ds *Store) PlanApply(ctx context.Context, k8sClient client.Client) ([]PlannedObject, []PlannedObject, []error) {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	return ds.planApply(ctx, k8sClient)
+}
+
+func (ds *Store) planApply(ctx context.Context, k8sClient client.Client) ([]PlannedObject, []PlannedObject, []error) {
 	var errs []error
-	var objsToCreate []client.Object
-	var objsToUpdate []client.Object
+	var objsToCreate []PlannedObject
+	var objsToUpdate []PlannedObject
 	for kind := range ds.deps {
 		for objID, objStore := range ds.deps[kind] {
 			objNSName := buildObjectKey(objID)
@@ -212,56 +336,56 @@ func (ds *Store) Apply(ctx context.Context, k8sClient client.Client) []error {
 			err := k8sClient.Get(ctx, objNSName, objAPIServer)
 			if err != nil && apierrors.IsNotFound(err) {
 				ds.logger.V(2).Info("dependencies.store Add object to create", "obj.namespace", objStore.GetNamespace(), "obj.name", objStore.GetName(), "obj.kind", kind)
-				objsToCreate = append(objsToCreate, objStore)
+				objsToCreate = append(objsToCreate, PlannedObject{Kind: kind, Object: objStore})
 				continue
 			} else if err != nil {
 				errs = append(errs, err)
 				continue
 			}
 
-			// ServicesKind is a special case; the cluster IPs are immutable and resource version must be set.
-			if kind == kubernetes.ServicesKind {
-				objStore.(*v1.Service).Spec.ClusterIP = objAPIServer.(*v1.Service).Spec.ClusterIP
-				objStore.(*v1.Service).Spec.ClusterIPs = objAPIServer.(*v1.Service).Spec.ClusterIPs
-				objStore.SetResourceVersion(objAPIServer.GetResourceVersion())
-			}
-			// The APIServiceKind resource version must be set.
-			if kind == kubernetes.APIServiceKind {
-				objStore.SetResourceVersion(objAPIServer.GetResourceVersion())
-			}
-
 			if !equality.IsEqualObject(kind, objStore, objAPIServer) {
 				ds.logger.V(2).Info("dependencies.store Add object to update", "obj.namespace", objStore.GetNamespace(), "obj.name", objStore.GetName(), "obj.kind", kind)
-				objsToUpdate = append(objsToUpdate, objStore)
+				objsToUpdate = append(objsToUpdate, PlannedObject{Kind: kind, Object: objStore, Current: objAPIServer})
 				continue
 			}
 		}
 	}
+	return objsToCreate, objsToUpdate, errs
+}
 
-	ds.logger.V(2).Info("dependencies.store objsToCreate", "nb", len(objsToCreate))
-	for _, obj := range objsToCreate {
-		if err := k8sClient.Create(ctx, obj); err != nil {
-			ds.logger.Error(err, "dependencies.store Create", "obj.namespace", obj.GetNamespace(), "obj.name", obj.GetName())
-			errs = append(errs, err)
-		}
-	}
+// Cleanup use to cleanup resources that are not needed anymore
+func (ds *Store) Cleanup(ctx context.Context, k8sClient client.Client) []error {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
 
-	ds.logger.V(2).Info("dependencies.store objsToUpdate", "nb", len(objsToUpdate))
-	for _, obj := range objsToUpdate {
-		if err := k8sClient.Update(ctx, obj); err != nil {
-			ds.logger.Error(err, "dependencies.store Update", "obj.namespace", obj.GetNamespace(), "obj.name", obj.GetName())
-			errs = append(errs, err)
+	objsToDelete, errs := ds.planCleanup(ctx, k8sClient)
+
+	partialObjs := make([]client.Object, 0, len(objsToDelete))
+	for _, planned := range objsToDelete {
+		partialObj := &metav1.PartialObjectMetadata{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      planned.Current.GetName(),
+				Namespace: planned.Current.GetNamespace(),
+			},
 		}
+		partialObj.TypeMeta.SetGroupVersionKind(planned.Current.GetObjectKind().GroupVersionKind())
+		partialObjs = append(partialObjs, partialObj)
 	}
-	return errs
+
+	return append(errs, deleteObjects(ctx, k8sClient, partialObjs)...)
 }
 
-// Cleanup use to cleanup resources that are not needed anymore
-func (ds *Store) Cleanup(ctx context.Context, k8sClient client.Client) []error {
+// PlanCleanup returns the objects that Cleanup would delete, without modifying the api-server.
+func (ds *Store) PlanCleanup(ctx context.Context, k8sClient client.Client) ([]PlannedObject, []error) {
 	ds.mutex.RLock()
 	defer ds.mutex.RUnlock()
 
+	return ds.planCleanup(ctx, k8sClient)
+}
+
+func (ds *Store) planCleanup(ctx context.Context, k8sClient client.Client) ([]PlannedObject, []error) {
 	var errs []error
+	var objsToDelete []PlannedObject
 
 	requirementLabel, _ := labels.NewRequirement(operatorStoreLabelKey, selection.Exists, nil)
 	listOptions := &client.ListOptions{
@@ -274,15 +398,17 @@ func (ds *Store) Cleanup(ctx context.Context, k8sClient client.Client) []error {
 			continue
 		}
 
-		objsToDelete, err := ds.listObjectToDelete(objList, ds.deps[kind])
+		objs, err := ds.listObjectToDelete(objList, ds.deps[kind])
 		if err != nil {
 			errs = append(errs, err)
 			continue
 		}
-		errs = append(errs, deleteObjects(ctx, k8sClient, objsToDelete)...)
+		for _, obj := range objs {
+			objsToDelete = append(objsToDelete, PlannedObject{Kind: kind, Current: obj})
+		}
 	}
 
-	return errs
+	return objsToDelete, errs
 }
 
 // GetVersionInfo returns the Kubernetes version
@@ -364,14 +490,9 @@ func (ds *Store) listObjectToDelete(objList client.ObjectList, cacheObjects map[
 					},
 				}
 				if partOfValue == object.NewPartOfLabelValue(partialDDA).String() {
-					partialObj := &metav1.PartialObjectMetadata{
-						ObjectMeta: metav1.ObjectMeta{
-							Name:      objMeta.GetName(),
-							Namespace: objMeta.GetNamespace(),
-						},
+					if obj, ok := objAPIServer.(client.Object); ok {
+						objsToDelete = append(objsToDelete, obj)
 					}
-					partialObj.TypeMeta.SetGroupVersionKind(objAPIServer.GetObjectKind().GroupVersionKind())
-					objsToDelete = append(objsToDelete, partialObj)
 				}
 			}
 		}
@@ -7,6 +7,7 @@ package dependencies
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -18,6 +19,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
@@ -361,7 +363,7 @@ func TestStore_Apply(t *testing.T) {
 			},
 			args: args{
 				ctx:       context.TODO(),
-				k8sClient: fake.NewClientBuilder().Build(),
+				k8sClient: testutils.NewApplyClient(fake.NewClientBuilder().Build()),
 			},
 		},
 		{
@@ -375,7 +377,7 @@ func TestStore_Apply(t *testing.T) {
 			},
 			args: args{
 				ctx:       context.TODO(),
-				k8sClient: fake.NewClientBuilder().WithObjects(dummyConfigMap1.DeepCopy()).Build(),
+				k8sClient: testutils.NewApplyClient(fake.NewClientBuilder().WithObjects(dummyConfigMap1.DeepCopy()).Build()),
 			},
 		},
 	}
@@ -391,6 +393,61 @@ func TestStore_Apply(t *testing.T) {
 	}
 }
 
+// conflictClient rejects every patch with a conflict, as the api-server does when a server-side apply
+// modifies fields owned by another field manager.
+type conflictClient struct {
+	client.Client
+	patchOptions []client.PatchOption
+}
+
+func (c *conflictClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.patchOptions = opts
+	return errors.NewConflict(schema.GroupResource{Resource: "configmaps"}, obj.GetName(), fmt.Errorf("conflict with \"other-manager\": .data.data1"))
+}
+
+func TestStore_Apply_Conflict(t *testing.T) {
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "bar",
+			Name:      "foo",
+		},
+		Data: map[string]string{
+			"data1": "other-value",
+		},
+	}
+	k8sClient := &conflictClient{Client: fake.NewClientBuilder().WithObjects(existing).Build()}
+
+	ds := &Store{
+		deps: map[kubernetes.ObjectKind]map[string]client.Object{
+			kubernetes.ConfigMapKind: {
+				"bar/foo": &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "bar",
+						Name:      "foo",
+					},
+					Data: map[string]string{
+						"data1": "value1",
+					},
+				},
+			},
+		},
+		logger: logf.Log.WithName(t.Name()),
+	}
+	errs := ds.Apply(context.TODO(), k8sClient)
+	assert.Len(t, errs, 1)
+
+	var conflictErr *ApplyConflictError
+	assert.ErrorAs(t, errs[0], &conflictErr)
+	assert.Equal(t, kubernetes.ConfigMapKind, conflictErr.Kind)
+	assert.Equal(t, "bar", conflictErr.Namespace)
+	assert.Equal(t, "foo", conflictErr.Name)
+	assert.True(t, errors.IsConflict(errs[0]))
+	assert.Equal(t, []*ApplyConflictError{conflictErr}, ApplyConflicts(append(errs, fmt.Errorf("other error"))))
+
+	// The conflicting fields are not forced
+	assert.Equal(t, []client.PatchOption{client.FieldOwner(FieldManager)}, k8sClient.patchOptions)
+}
+
 func TestStore_Cleanup(t *testing.T) {
 	dummyConfigMap1 := &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
@@ -7,6 +7,7 @@ package enabledefault
 
 import (
 	"fmt"
+	"strings"
 
 	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
 	commonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
@@ -21,10 +22,12 @@ import (
 	"github.com/DataDog/datadog-operator/controllers/datadogagent/object"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes/rbac"
 	"github.com/DataDog/datadog-operator/pkg/version"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/errors"
 )
@@ -67,6 +70,7 @@ type defaultFeature struct {
 	clusterChecksRunner     clusterChecksRunnerConfig
 	logger                  logr.Logger
 	disableNonResourceRules bool
+	secretBackend           *v2alpha1.SecretBackendConfig
 
 	customConfigAnnotationKey   string
 	customConfigAnnotationValue string
@@ -124,6 +128,8 @@ func (f *defaultFeature) Configure(dda *v2alpha1.DatadogAgent) feature.RequiredC
 			f.disableNonResourceRules = true
 		}
 
+		f.secretBackend = dda.Spec.Global.SecretBackend
+
 		if dda.Spec.Global.Credentials != nil {
 			creds := dda.Spec.Global.Credentials
 
@@ -283,6 +289,10 @@ func (f *defaultFeature) ManageDependencies(managers feature.ResourceManagers, c
 		return err
 	}
 
+	if err := f.secretBackendDependencies(managers, components); err != nil {
+		errs = append(errs, err)
+	}
+
 	if components.Agent.IsEnabled() {
 		if err := f.agentDependencies(managers, components.Agent); err != nil {
 			errs = append(errs, err)
@@ -379,6 +389,67 @@ func (f *defaultFeature) clusterChecksRunnerDependencies(managers feature.Resour
 	return errors.NewAggregate(errs)
 }
 
+// secretBackendDependencies creates the Roles allowing the components to read the Secrets
+// listed in the secret backend configuration.
+func (f *defaultFeature) secretBackendDependencies(managers feature.ResourceManagers, components feature.RequiredComponents) error {
+	if f.secretBackend == nil {
+		return nil
+	}
+
+	serviceAccounts := []string{}
+	if components.Agent.IsEnabled() {
+		serviceAccounts = append(serviceAccounts, f.agent.serviceAccountName)
+	}
+	if components.ClusterAgent.IsEnabled() {
+		serviceAccounts = append(serviceAccounts, f.clusterAgent.serviceAccountName)
+	}
+	if components.ClusterChecksRunner.IsEnabled() {
+		serviceAccounts = append(serviceAccounts, f.clusterChecksRunner.serviceAccountName)
+	}
+
+	var errs []error
+	roleName := getSecretBackendRoleName(f.owner)
+	for _, roleConfig := range f.secretBackend.Roles {
+		if len(roleConfig.Secrets) == 0 {
+			continue
+		}
+		namespace := f.owner.GetNamespace()
+		if roleConfig.Namespace != nil && *roleConfig.Namespace != "" {
+			namespace = *roleConfig.Namespace
+		}
+
+		obj, _ := managers.Store().GetOrCreate(kubernetes.RolesKind, namespace, roleName)
+		role, ok := obj.(*rbacv1.Role)
+		if !ok {
+			errs = append(errs, fmt.Errorf("unable to get from the store the Role %s/%s", namespace, roleName))
+			continue
+		}
+		role.Rules = append(role.Rules, rbacv1.PolicyRule{
+			APIGroups:     []string{rbac.CoreAPIGroup},
+			Resources:     []string{rbac.SecretsResource},
+			ResourceNames: roleConfig.Secrets,
+			Verbs:         []string{rbac.GetVerb},
+		})
+		if err := managers.Store().AddOrUpdate(kubernetes.RolesKind, role); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		roleRef := rbacv1.RoleRef{
+			APIGroup: rbac.RbacAPIGroup,
+			Kind:     rbac.RoleKind,
+			Name:     roleName,
+		}
+		for _, saName := range serviceAccounts {
+			if err := managers.RBACManager().AddRoleBinding(namespace, roleName, f.owner.GetNamespace(), saName, roleRef); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.NewAggregate(errs)
+}
+
 // ManageClusterAgent allows a feature to configure the ClusterAgent's corev1.PodTemplateSpec
 // It should do nothing if the feature doesn't need to configure it.
 func (f *defaultFeature) ManageClusterAgent(managers feature.PodTemplateManagers) error {
@@ -435,6 +506,25 @@ func (f *defaultFeature) addDefaultCommonEnvs(managers feature.PodTemplateManage
 		appKeyEnvVar := component.BuildEnvVarFromSource(apicommon.DDAppKey, component.BuildEnvVarFromSecret(f.credentialsInfo.appKey.SecretName, f.credentialsInfo.appKey.SecretKey))
 		managers.EnvVar().AddEnvVar(appKeyEnvVar)
 	}
+
+	if f.secretBackend != nil {
+		if f.secretBackend.Command != nil && *f.secretBackend.Command != "" {
+			managers.EnvVar().AddEnvVar(&corev1.EnvVar{
+				Name:  apicommon.DDSecretBackendCommand,
+				Value: *f.secretBackend.Command,
+			})
+		}
+		if len(f.secretBackend.Args) > 0 {
+			managers.EnvVar().AddEnvVar(&corev1.EnvVar{
+				Name:  apicommon.DDSecretBackendArgs,
+				Value: strings.Join(f.secretBackend.Args, " "),
+			})
+		}
+	}
+}
+
+func getSecretBackendRoleName(dda metav1.Object) string {
+	return fmt.Sprintf("%s-secret-backend", dda.GetName())
 }
 
 func buildInstallInfoConfigMap(dda metav1.Object) *corev1.ConfigMap {
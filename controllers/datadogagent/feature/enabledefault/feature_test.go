@@ -0,0 +1,153 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package enabledefault
+
+import (
+	"testing"
+
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
+	apicommonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature/fake"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature/test"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_defaultFeature_SecretBackend(t *testing.T) {
+	newDDA := func(secretBackend *v2alpha1.SecretBackendConfig) *v2alpha1.DatadogAgent {
+		return &v2alpha1.DatadogAgent{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo"},
+			Spec: v2alpha1.DatadogAgentSpec{
+				Global: &v2alpha1.GlobalConfig{
+					SecretBackend: secretBackend,
+				},
+			},
+		}
+	}
+
+	requiredComponents := feature.RequiredComponents{
+		Agent:               feature.RequiredComponent{IsRequired: apiutils.NewBoolPointer(true)},
+		ClusterAgent:        feature.RequiredComponent{IsRequired: apiutils.NewBoolPointer(true)},
+		ClusterChecksRunner: feature.RequiredComponent{IsRequired: apiutils.NewBoolPointer(true)},
+	}
+
+	wantSecretBackendEnvVars := func(want []*corev1.EnvVar) *test.ComponentTest {
+		return test.NewDefaultComponentTest().WithWantFunc(
+			func(t testing.TB, mgrInterface feature.PodTemplateManagers) {
+				mgr := mgrInterface.(*fake.PodTemplateManagers)
+				var got []*corev1.EnvVar
+				for _, envVar := range mgr.EnvVarMgr.EnvVarsByC[apicommonv1.AllContainers] {
+					if envVar.Name == apicommon.DDSecretBackendCommand || envVar.Name == apicommon.DDSecretBackendArgs {
+						got = append(got, envVar)
+					}
+				}
+				assert.Equal(t, want, got)
+			},
+		)
+	}
+
+	secretBackendEnvVars := []*corev1.EnvVar{
+		{
+			Name:  apicommon.DDSecretBackendCommand,
+			Value: "/readsecret_multiple_providers.sh",
+		},
+		{
+			Name:  apicommon.DDSecretBackendArgs,
+			Value: "--timeout 30",
+		},
+	}
+
+	tests := test.FeatureTestSuite{
+		{
+			Name:               "v2alpha1 secret backend not configured",
+			DDAv2:              newDDA(nil),
+			WantConfigure:      true,
+			RequiredComponents: requiredComponents,
+			WantDependenciesFunc: func(t testing.TB, store dependencies.StoreClient) {
+				_, found := store.Get(kubernetes.RolesKind, "bar", "foo-secret-backend")
+				assert.False(t, found)
+			},
+			Agent:               wantSecretBackendEnvVars(nil),
+			ClusterAgent:        wantSecretBackendEnvVars(nil),
+			ClusterChecksRunner: wantSecretBackendEnvVars(nil),
+		},
+		{
+			Name: "v2alpha1 secret backend command and args",
+			DDAv2: newDDA(&v2alpha1.SecretBackendConfig{
+				Command: apiutils.NewStringPointer("/readsecret_multiple_providers.sh"),
+				Args:    []string{"--timeout", "30"},
+			}),
+			WantConfigure:      true,
+			RequiredComponents: requiredComponents,
+			WantDependenciesFunc: func(t testing.TB, store dependencies.StoreClient) {
+				_, found := store.Get(kubernetes.RolesKind, "bar", "foo-secret-backend")
+				assert.False(t, found)
+			},
+			Agent:               wantSecretBackendEnvVars(secretBackendEnvVars),
+			ClusterAgent:        wantSecretBackendEnvVars(secretBackendEnvVars),
+			ClusterChecksRunner: wantSecretBackendEnvVars(secretBackendEnvVars),
+		},
+		{
+			Name: "v2alpha1 secret backend roles",
+			DDAv2: newDDA(&v2alpha1.SecretBackendConfig{
+				Command: apiutils.NewStringPointer("/readsecret_multiple_providers.sh"),
+				Args:    []string{"--timeout", "30"},
+				Roles: []v2alpha1.SecretBackendRolesConfig{
+					{
+						Secrets: []string{"secret-1", "secret-2"},
+					},
+					{
+						Namespace: apiutils.NewStringPointer("other"),
+						Secrets:   []string{"secret-3"},
+					},
+				},
+			}),
+			WantConfigure:      true,
+			RequiredComponents: requiredComponents,
+			WantDependenciesFunc: func(t testing.TB, store dependencies.StoreClient) {
+				obj, found := store.Get(kubernetes.RolesKind, "bar", "foo-secret-backend")
+				assert.True(t, found)
+				role := obj.(*rbacv1.Role)
+				assert.Equal(t, []rbacv1.PolicyRule{
+					{
+						APIGroups:     []string{""},
+						Resources:     []string{"secrets"},
+						ResourceNames: []string{"secret-1", "secret-2"},
+						Verbs:         []string{"get"},
+					},
+				}, role.Rules)
+
+				obj, found = store.Get(kubernetes.RolesKind, "other", "foo-secret-backend")
+				assert.True(t, found)
+				role = obj.(*rbacv1.Role)
+				assert.Equal(t, []string{"secret-3"}, role.Rules[0].ResourceNames)
+
+				obj, found = store.Get(kubernetes.RoleBindingKind, "other", "foo-secret-backend")
+				assert.True(t, found)
+				roleBinding := obj.(*rbacv1.RoleBinding)
+				assert.Equal(t, "foo-secret-backend", roleBinding.RoleRef.Name)
+				assert.ElementsMatch(t, []rbacv1.Subject{
+					{Kind: "ServiceAccount", Namespace: "bar", Name: "foo-agent"},
+					{Kind: "ServiceAccount", Namespace: "bar", Name: "foo-cluster-agent"},
+					{Kind: "ServiceAccount", Namespace: "bar", Name: "foo-cluster-checks-runner"},
+				}, roleBinding.Subjects)
+			},
+			Agent:               wantSecretBackendEnvVars(secretBackendEnvVars),
+			ClusterAgent:        wantSecretBackendEnvVars(secretBackendEnvVars),
+			ClusterChecksRunner: wantSecretBackendEnvVars(secretBackendEnvVars),
+		},
+	}
+
+	tests.Run(t, buildDefaultFeature)
+}
@@ -33,11 +33,19 @@ func Register(id IDType, buildFunc BuildFunc) error {
 
 // BuildFeatures use to build a list features depending of the v2alpha1.DatadogAgent instance
 func BuildFeatures(dda *v2alpha1.DatadogAgent, options *Options) ([]Feature, RequiredComponents) {
+	output, requiredComponents, _ := BuildFeaturesWithRequirements(dda, options)
+	return output, requiredComponents
+}
+
+// BuildFeaturesWithRequirements is like BuildFeatures, but also returns the RequiredComponents
+// requested by each configured feature, indexed by feature ID.
+func BuildFeaturesWithRequirements(dda *v2alpha1.DatadogAgent, options *Options) ([]Feature, RequiredComponents, map[IDType]RequiredComponents) {
 	builderMutex.RLock()
 	defer builderMutex.RUnlock()
 
 	var output []Feature
 	var requiredComponents RequiredComponents
+	requirements := map[IDType]RequiredComponents{}
 
 	// to always return in feature in the same order we need to sort the map keys
 	sortedkeys := make([]IDType, 0, len(featureBuilders))
@@ -54,6 +62,7 @@ func BuildFeatures(dda *v2alpha1.DatadogAgent, options *Options) ([]Feature, Req
 		// only add feature to the output if one of the components is configured (but not necessarily required)
 		if reqComponents.IsConfigured() {
 			output = append(output, feat)
+			requirements[feat.ID()] = reqComponents
 		}
 		requiredComponents.Merge(&reqComponents)
 	}
@@ -67,9 +76,9 @@ func BuildFeatures(dda *v2alpha1.DatadogAgent, options *Options) ([]Feature, Req
 		!requiredComponents.Agent.IsPrivileged() {
 
 		requiredComponents.Agent.Containers = []common.AgentContainerName{common.UnprivilegedSingleAgentContainerName}
-		return output, requiredComponents
+		return output, requiredComponents, requirements
 	}
-	return output, requiredComponents
+	return output, requiredComponents, requirements
 }
 
 // BuildFeaturesV1 use to build a list features depending of the v1alpha1.DatadogAgent instance
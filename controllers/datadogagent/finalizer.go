@@ -18,6 +18,8 @@ import (
 	"github.com/DataDog/datadog-operator/pkg/controller/utils"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -96,6 +98,7 @@ func (r *Reconciler) finalizeDadV2(reqLogger logr.Logger, obj client.Object) err
 	if r.options.OperatorMetricsEnabled {
 		r.forwarders.Unregister(dda)
 	}
+	r.appliedStates.invalidate(types.NamespacedName{Namespace: dda.Namespace, Name: dda.Name})
 
 	// To delete the resources associated with the DatadogAgent that we need to
 	// delete, we figure out its dependencies, store them in the dependencies
@@ -134,7 +137,7 @@ func (r *Reconciler) finalizeDadV2(reqLogger logr.Logger, obj client.Object) err
 		return fmt.Errorf("error deleting dependencies while finalizing the DatadogAgent: %v", deleteErrs)
 	}
 
-	if err := r.profilesCleanup(); err != nil {
+	if err := r.profilesCleanup(dda); err != nil {
 		return err
 	}
 
@@ -157,23 +160,38 @@ func (r *Reconciler) addFinalizer(reqLogger logr.Logger, dda client.Object) erro
 
 // profilesCleanup performs the cleanups required for the profiles feature. The
 // only thing that we need to do is to ensure that no nodes are left with the
-// profile label.
-func (r *Reconciler) profilesCleanup() error {
+// profile labels of the DatadogAgent. The profile label of the nodes labeled
+// with a profile of another DatadogAgent is kept.
+func (r *Reconciler) profilesCleanup(dda metav1.Object) error {
 	nodeList := corev1.NodeList{}
 	if err := r.client.List(context.TODO(), &nodeList); err != nil {
 		return err
 	}
 
+	var nodesOfOtherDatadogAgents map[string]bool
+	if r.options.DatadogAgentProfileEnabled {
+		profiles := datadoghqv1alpha1.DatadogAgentProfileList{}
+		if err := r.client.List(context.TODO(), &profiles); err != nil {
+			return err
+		}
+		nodesOfOtherDatadogAgents = agentprofile.NodesWithProfileOfOtherDatadogAgents(nodeList.Items, profiles.Items, dda)
+	}
+	ddaProfileLabelKey := agentprofile.DatadogAgentProfileLabelKey(dda)
+
 	for _, node := range nodeList.Items {
-		if _, profileLabelExists := node.Labels[agentprofile.ProfileLabelKey]; !profileLabelExists {
+		_, profileLabelExists := node.Labels[agentprofile.ProfileLabelKey]
+		removeProfileLabel := profileLabelExists && !nodesOfOtherDatadogAgents[node.Name]
+		_, ddaProfileLabelExists := node.Labels[ddaProfileLabelKey]
+		if !removeProfileLabel && !ddaProfileLabelExists {
 			continue
 		}
 
-		newLabels := make(map[string]string, len(node.Labels)-1)
+		newLabels := make(map[string]string, len(node.Labels))
 		for label, value := range node.Labels {
-			if label != agentprofile.ProfileLabelKey {
-				newLabels[label] = value
+			if label == ddaProfileLabelKey || (removeProfileLabel && label == agentprofile.ProfileLabelKey) {
+				continue
 			}
+			newLabels[label] = value
 		}
 
 		patch := corev1.Node{
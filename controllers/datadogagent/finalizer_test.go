@@ -202,6 +202,35 @@ func Test_handleFinalizer_V2(t *testing.T) {
 	}
 }
 
+func Test_profilesCleanupWithOtherDatadogAgents(t *testing.T) {
+	dda := &metav1.ObjectMeta{Namespace: "bar", Name: "foo"}
+	otherProfile := &datadoghqv1alpha1.DatadogAgentProfile{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "other-profile"},
+		Spec: datadoghqv1alpha1.DatadogAgentProfileSpec{
+			DatadogAgentRef: &datadoghqv1alpha1.DatadogAgentReference{Name: "other"},
+		},
+	}
+	nodes := []*corev1.Node{
+		// The labels should be deleted
+		testutils.NewNode("node-1", map[string]string{agentprofile.ProfileLabelKey: "bar-profile", "agent.datadoghq.com/profile.bar-foo": "bar-profile"}),
+		// The label of the other DatadogAgent should be kept
+		testutils.NewNode("node-2", map[string]string{agentprofile.ProfileLabelKey: "bar-other-profile", "agent.datadoghq.com/profile.bar-other": "bar-other-profile"}),
+	}
+
+	reconciler := reconcilerV2ForFinalizerTest([]client.Object{otherProfile, nodes[0], nodes[1]})
+	reconciler.options.DatadogAgentProfileEnabled = true
+	assert.NoError(t, reconciler.profilesCleanup(dda))
+
+	currentNode := &corev1.Node{}
+	assert.NoError(t, reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "node-1"}, currentNode))
+	assert.NotContains(t, currentNode.Labels, agentprofile.ProfileLabelKey)
+	assert.NotContains(t, currentNode.Labels, "agent.datadoghq.com/profile.bar-foo")
+	currentNode = &corev1.Node{}
+	assert.NoError(t, reconciler.client.Get(context.TODO(), types.NamespacedName{Name: "node-2"}, currentNode))
+	assert.Equal(t, "bar-other-profile", currentNode.Labels[agentprofile.ProfileLabelKey])
+	assert.Equal(t, "bar-other-profile", currentNode.Labels["agent.datadoghq.com/profile.bar-other"])
+}
+
 func reconcilerV1ForFinalizerTest(initialKubeObjects []client.Object) Reconciler {
 	reconcilerScheme := scheme.Scheme
 	reconcilerScheme.AddKnownTypes(rbacv1.SchemeGroupVersion, &rbacv1.ClusterRoleBinding{}, &rbacv1.ClusterRole{})
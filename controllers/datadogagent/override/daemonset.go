@@ -16,6 +16,10 @@ func DaemonSet(daemonSet *v1.DaemonSet, override *v2alpha1.DatadogAgentComponent
 	if override.Name != nil && *override.Name != "" {
 		daemonSet.Name = *override.Name
 	}
+
+	if override.UpdateStrategy != nil {
+		daemonSet.Spec.UpdateStrategy = daemonSetUpdateStrategy(override.UpdateStrategy)
+	}
 }
 
 // ExtendedDaemonSet overrides an ExtendedDaemonSet according to the given override options
@@ -24,3 +28,19 @@ func ExtendedDaemonSet(eds *edsv1alpha1.ExtendedDaemonSet, override *v2alpha1.Da
 		eds.Name = *override.Name
 	}
 }
+
+func daemonSetUpdateStrategy(strategy *v2alpha1.UpdateStrategy) v1.DaemonSetUpdateStrategy {
+	updateStrategy := v1.DaemonSetUpdateStrategy{
+		Type: v1.DaemonSetUpdateStrategyType(strategy.Type),
+	}
+	if updateStrategy.Type == "" {
+		updateStrategy.Type = v1.RollingUpdateDaemonSetStrategyType
+	}
+	if updateStrategy.Type == v1.RollingUpdateDaemonSetStrategyType && strategy.RollingUpdate != nil {
+		updateStrategy.RollingUpdate = &v1.RollingUpdateDaemonSet{
+			MaxUnavailable: strategy.RollingUpdate.MaxUnavailable,
+			MaxSurge:       strategy.RollingUpdate.MaxSurge,
+		}
+	}
+	return updateStrategy
+}
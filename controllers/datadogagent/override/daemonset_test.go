@@ -0,0 +1,73 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package override
+
+import (
+	"testing"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestDaemonSet(t *testing.T) {
+	maxUnavailable := intstr.FromString("10%")
+	maxSurge := intstr.FromInt(1)
+	defaultStrategy := v1.DaemonSetUpdateStrategy{
+		RollingUpdate: &v1.RollingUpdateDaemonSet{MaxUnavailable: &maxUnavailable},
+	}
+
+	tests := []struct {
+		name     string
+		override v2alpha1.DatadogAgentComponentOverride
+		wantName string
+		want     v1.DaemonSetUpdateStrategy
+	}{
+		{
+			name:     "empty override",
+			override: v2alpha1.DatadogAgentComponentOverride{},
+			wantName: "current-name",
+			want:     defaultStrategy,
+		},
+		{
+			name: "name and rolling update",
+			override: v2alpha1.DatadogAgentComponentOverride{
+				Name: apiutils.NewStringPointer("new-name"),
+				UpdateStrategy: &v2alpha1.UpdateStrategy{
+					Type:          "RollingUpdate",
+					RollingUpdate: &v2alpha1.RollingUpdate{MaxSurge: &maxSurge},
+				},
+			},
+			wantName: "new-name",
+			want: v1.DaemonSetUpdateStrategy{
+				Type:          v1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &v1.RollingUpdateDaemonSet{MaxSurge: &maxSurge},
+			},
+		},
+		{
+			name: "on delete",
+			override: v2alpha1.DatadogAgentComponentOverride{
+				UpdateStrategy: &v2alpha1.UpdateStrategy{Type: "OnDelete"},
+			},
+			wantName: "current-name",
+			want:     v1.DaemonSetUpdateStrategy{Type: v1.OnDeleteDaemonSetStrategyType},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			daemonSet := v1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "current-name"},
+				Spec:       v1.DaemonSetSpec{UpdateStrategy: defaultStrategy},
+			}
+			DaemonSet(&daemonSet, &tt.override)
+			assert.Equal(t, tt.wantName, daemonSet.Name)
+			assert.Equal(t, tt.want, daemonSet.Spec.UpdateStrategy)
+		})
+	}
+}
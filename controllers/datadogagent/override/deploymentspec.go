@@ -19,4 +19,24 @@ func Deployment(deployment *v1.Deployment, override *v2alpha1.DatadogAgentCompon
 	if override.Name != nil {
 		deployment.Name = *override.Name
 	}
+
+	if override.UpdateStrategy != nil {
+		deployment.Spec.Strategy = deploymentStrategy(override.UpdateStrategy)
+	}
+}
+
+func deploymentStrategy(strategy *v2alpha1.UpdateStrategy) v1.DeploymentStrategy {
+	deploymentStrategy := v1.DeploymentStrategy{
+		Type: v1.DeploymentStrategyType(strategy.Type),
+	}
+	if deploymentStrategy.Type == "" {
+		deploymentStrategy.Type = v1.RollingUpdateDeploymentStrategyType
+	}
+	if deploymentStrategy.Type == v1.RollingUpdateDeploymentStrategyType && strategy.RollingUpdate != nil {
+		deploymentStrategy.RollingUpdate = &v1.RollingUpdateDeployment{
+			MaxUnavailable: strategy.RollingUpdate.MaxUnavailable,
+			MaxSurge:       strategy.RollingUpdate.MaxSurge,
+		}
+	}
+	return deploymentStrategy
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func TestDeployment(t *testing.T) {
@@ -35,3 +36,42 @@ func TestDeployment(t *testing.T) {
 	assert.Equal(t, "new-name", deployment.Name)
 	assert.Equal(t, int32(2), *deployment.Spec.Replicas)
 }
+
+func TestDeploymentUpdateStrategy(t *testing.T) {
+	maxUnavailable := intstr.FromInt(0)
+	maxSurge := intstr.FromString("25%")
+
+	tests := []struct {
+		name     string
+		strategy *v2alpha1.UpdateStrategy
+		want     v1.DeploymentStrategy
+	}{
+		{
+			name:     "no update strategy",
+			strategy: nil,
+			want:     v1.DeploymentStrategy{},
+		},
+		{
+			name: "rolling update",
+			strategy: &v2alpha1.UpdateStrategy{
+				RollingUpdate: &v2alpha1.RollingUpdate{MaxUnavailable: &maxUnavailable, MaxSurge: &maxSurge},
+			},
+			want: v1.DeploymentStrategy{
+				Type:          v1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &v1.RollingUpdateDeployment{MaxUnavailable: &maxUnavailable, MaxSurge: &maxSurge},
+			},
+		},
+		{
+			name:     "recreate",
+			strategy: &v2alpha1.UpdateStrategy{Type: "Recreate"},
+			want:     v1.DeploymentStrategy{Type: v1.RecreateDeploymentStrategyType},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := v1.Deployment{}
+			Deployment(&deployment, &v2alpha1.DatadogAgentComponentOverride{UpdateStrategy: tt.strategy})
+			assert.Equal(t, tt.want, deployment.Spec.Strategy)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package override
+
+import (
+	"fmt"
+
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/component"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+)
+
+// PodDisruptionBudget adds to the store the PodDisruptionBudget defined in the override of a component.
+// Only the Cluster Agent and the Cluster Checks Runner support it.
+func PodDisruptionBudget(manager feature.ResourceManagers, dda *v2alpha1.DatadogAgent, componentName v2alpha1.ComponentName) error {
+	override, found := dda.Spec.Override[componentName]
+	if !found || override.PodDisruptionBudget == nil {
+		return nil
+	}
+
+	var name, componentKind string
+	switch componentName {
+	case v2alpha1.ClusterAgentComponentName:
+		name = component.GetClusterAgentName(dda)
+		componentKind = apicommon.DefaultClusterAgentResourceSuffix
+	case v2alpha1.ClusterChecksRunnerComponentName:
+		name = component.GetClusterChecksRunnerName(dda)
+		componentKind = apicommon.DefaultClusterChecksRunnerResourceSuffix
+	default:
+		return fmt.Errorf("podDisruptionBudget is not supported by the component %s", componentName)
+	}
+	if override.Name != nil && *override.Name != "" {
+		name = *override.Name
+	}
+
+	metadata := metav1.ObjectMeta{
+		Name:      name,
+		Namespace: dda.Namespace,
+		Labels:    component.GetDefaultLabels(dda, componentKind, name, ""),
+	}
+	selector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			apicommon.AgentDeploymentNameLabelKey:      dda.Name,
+			apicommon.AgentDeploymentComponentLabelKey: componentKind,
+		},
+	}
+	config := override.PodDisruptionBudget
+
+	platformInfo := manager.Store().GetPlatformInfo()
+	pdb := platformInfo.CreatePDBObject()
+	switch obj := pdb.(type) {
+	case *policyv1.PodDisruptionBudget:
+		obj.ObjectMeta = metadata
+		obj.Spec = policyv1.PodDisruptionBudgetSpec{
+			Selector:       selector,
+			MinAvailable:   config.MinAvailable,
+			MaxUnavailable: config.MaxUnavailable,
+		}
+	case *policyv1beta1.PodDisruptionBudget:
+		obj.ObjectMeta = metadata
+		obj.Spec = policyv1beta1.PodDisruptionBudgetSpec{
+			Selector:       selector,
+			MinAvailable:   config.MinAvailable,
+			MaxUnavailable: config.MaxUnavailable,
+		}
+	}
+
+	return manager.Store().AddOrUpdate(kubernetes.PodDisruptionBudgetsKind, pdb)
+}
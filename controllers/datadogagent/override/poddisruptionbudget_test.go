@@ -0,0 +1,121 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package override
+
+import (
+	"testing"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+
+	"github.com/stretchr/testify/assert"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestPodDisruptionBudget(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	testScheme.AddKnownTypes(v2alpha1.GroupVersion, &v2alpha1.DatadogAgent{})
+
+	minAvailable := intstr.FromInt(1)
+	maxUnavailable := intstr.FromString("50%")
+	v1PlatformInfo := kubernetes.NewPlatformInfoFromVersionMaps(nil, map[string]string{"PodDisruptionBudget": "policy/v1"}, nil)
+	v1beta1PlatformInfo := kubernetes.NewPlatformInfoFromVersionMaps(nil, map[string]string{"PodDisruptionBudget": "policy/v1beta1"}, nil)
+
+	newDDA := func(componentName v2alpha1.ComponentName, override *v2alpha1.DatadogAgentComponentOverride) *v2alpha1.DatadogAgent {
+		return &v2alpha1.DatadogAgent{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo"},
+			Spec: v2alpha1.DatadogAgentSpec{
+				Override: map[v2alpha1.ComponentName]*v2alpha1.DatadogAgentComponentOverride{componentName: override},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		dda           *v2alpha1.DatadogAgent
+		componentName v2alpha1.ComponentName
+		platformInfo  kubernetes.PlatformInfo
+		wantErr       bool
+		check         func(t *testing.T, store *dependencies.Store)
+	}{
+		{
+			name:          "no podDisruptionBudget",
+			dda:           newDDA(v2alpha1.ClusterAgentComponentName, &v2alpha1.DatadogAgentComponentOverride{}),
+			componentName: v2alpha1.ClusterAgentComponentName,
+			platformInfo:  v1PlatformInfo,
+			check: func(t *testing.T, store *dependencies.Store) {
+				_, found := store.Get(kubernetes.PodDisruptionBudgetsKind, "bar", "foo-cluster-agent")
+				assert.False(t, found)
+			},
+		},
+		{
+			name: "cluster agent policy/v1",
+			dda: newDDA(v2alpha1.ClusterAgentComponentName, &v2alpha1.DatadogAgentComponentOverride{
+				PodDisruptionBudget: &v2alpha1.PodDisruptionBudgetConfig{MinAvailable: &minAvailable},
+			}),
+			componentName: v2alpha1.ClusterAgentComponentName,
+			platformInfo:  v1PlatformInfo,
+			check: func(t *testing.T, store *dependencies.Store) {
+				obj, found := store.Get(kubernetes.PodDisruptionBudgetsKind, "bar", "foo-cluster-agent")
+				assert.True(t, found)
+				pdb, ok := obj.(*policyv1.PodDisruptionBudget)
+				assert.True(t, ok)
+				assert.Equal(t, &minAvailable, pdb.Spec.MinAvailable)
+				assert.Nil(t, pdb.Spec.MaxUnavailable)
+				assert.Equal(t, "cluster-agent", pdb.Spec.Selector.MatchLabels["agent.datadoghq.com/component"])
+				assert.Equal(t, "foo", pdb.Spec.Selector.MatchLabels["agent.datadoghq.com/name"])
+			},
+		},
+		{
+			name: "cluster checks runner policy/v1beta1 with a custom name",
+			dda: newDDA(v2alpha1.ClusterChecksRunnerComponentName, &v2alpha1.DatadogAgentComponentOverride{
+				Name:                apiutils.NewStringPointer("ccr"),
+				PodDisruptionBudget: &v2alpha1.PodDisruptionBudgetConfig{MaxUnavailable: &maxUnavailable},
+			}),
+			componentName: v2alpha1.ClusterChecksRunnerComponentName,
+			platformInfo:  v1beta1PlatformInfo,
+			check: func(t *testing.T, store *dependencies.Store) {
+				obj, found := store.Get(kubernetes.PodDisruptionBudgetsKind, "bar", "ccr")
+				assert.True(t, found)
+				pdb, ok := obj.(*policyv1beta1.PodDisruptionBudget)
+				assert.True(t, ok)
+				assert.Equal(t, &maxUnavailable, pdb.Spec.MaxUnavailable)
+				assert.Equal(t, "cluster-checks-runner", pdb.Spec.Selector.MatchLabels["agent.datadoghq.com/component"])
+			},
+		},
+		{
+			name: "node agent not supported",
+			dda: newDDA(v2alpha1.NodeAgentComponentName, &v2alpha1.DatadogAgentComponentOverride{
+				PodDisruptionBudget: &v2alpha1.PodDisruptionBudgetConfig{MinAvailable: &minAvailable},
+			}),
+			componentName: v2alpha1.NodeAgentComponentName,
+			platformInfo:  v1PlatformInfo,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := dependencies.NewStore(tt.dda, &dependencies.StoreOptions{
+				Scheme:       testScheme,
+				PlatformInfo: tt.platformInfo,
+			})
+			manager := feature.NewResourceManagers(store)
+
+			err := PodDisruptionBudget(manager, tt.dda, tt.componentName)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if tt.check != nil {
+				tt.check(t, store)
+			}
+		})
+	}
+}
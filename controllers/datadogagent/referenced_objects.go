@@ -0,0 +1,117 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+)
+
+// ReferencesObject returns true if the Secret or the ConfigMap is referenced in the DatadogAgent spec,
+// either by the credentials or by the custom configurations of the overrides.
+func ReferencesObject(dda *datadoghqv2alpha1.DatadogAgent, obj client.Object) bool {
+	if obj.GetNamespace() != dda.Namespace {
+		return false
+	}
+	for _, componentName := range []datadoghqv2alpha1.ComponentName{
+		datadoghqv2alpha1.NodeAgentComponentName,
+		datadoghqv2alpha1.ClusterAgentComponentName,
+		datadoghqv2alpha1.ClusterChecksRunnerComponentName,
+	} {
+		for _, ref := range referencedObjects(dda, componentName) {
+			if reflect.TypeOf(ref) == reflect.TypeOf(obj) && ref.GetName() == obj.GetName() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// referencedObjects returns the Secrets and the ConfigMaps, not managed by the operator, that are used by a component.
+func referencedObjects(dda *datadoghqv2alpha1.DatadogAgent, componentName datadoghqv2alpha1.ComponentName) []client.Object {
+	var objs []client.Object
+	objectMeta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Namespace: dda.Namespace, Name: name}
+	}
+
+	if dda.Spec.Global != nil && dda.Spec.Global.Credentials != nil {
+		creds := dda.Spec.Global.Credentials
+		if creds.APISecret != nil && creds.APISecret.SecretName != "" {
+			objs = append(objs, &corev1.Secret{ObjectMeta: objectMeta(creds.APISecret.SecretName)})
+		}
+		if creds.AppSecret != nil && creds.AppSecret.SecretName != "" {
+			objs = append(objs, &corev1.Secret{ObjectMeta: objectMeta(creds.AppSecret.SecretName)})
+		}
+	}
+
+	componentOverride, found := dda.Spec.Override[componentName]
+	if !found || componentOverride == nil {
+		return objs
+	}
+	for _, customConfig := range componentOverride.CustomConfigurations {
+		if customConfig.ConfigMap != nil && customConfig.ConfigMap.Name != "" {
+			objs = append(objs, &corev1.ConfigMap{ObjectMeta: objectMeta(customConfig.ConfigMap.Name)})
+		}
+	}
+	for _, multiCustomConfig := range []*datadoghqv2alpha1.MultiCustomConfig{componentOverride.ExtraConfd, componentOverride.ExtraChecksd} {
+		if multiCustomConfig != nil && multiCustomConfig.ConfigMap != nil && multiCustomConfig.ConfigMap.Name != "" {
+			objs = append(objs, &corev1.ConfigMap{ObjectMeta: objectMeta(multiCustomConfig.ConfigMap.Name)})
+		}
+	}
+	return objs
+}
+
+// referencedObjectsChecksum returns the checksum of the content of the Secrets and the ConfigMaps used by a component.
+// The objects that don't exist are ignored, the pods can't start until they are created.
+func (r *Reconciler) referencedObjectsChecksum(dda *datadoghqv2alpha1.DatadogAgent, componentName datadoghqv2alpha1.ComponentName) (string, error) {
+	objs := referencedObjects(dda, componentName)
+	// The render of a DatadogAgent without a cluster doesn't have a client
+	if len(objs) == 0 || r.client == nil {
+		return "", nil
+	}
+
+	contents := map[string]interface{}{}
+	for _, obj := range objs {
+		if err := r.client.Get(context.TODO(), client.ObjectKeyFromObject(obj), obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return "", fmt.Errorf("unable to get %s: %w", obj.GetName(), err)
+		}
+		switch o := obj.(type) {
+		case *corev1.Secret:
+			contents["Secret/"+o.Name] = o.Data
+		case *corev1.ConfigMap:
+			contents["ConfigMap/"+o.Name] = []interface{}{o.Data, o.BinaryData}
+		}
+	}
+	if len(contents) == 0 {
+		return "", nil
+	}
+	return comparison.GenerateMD5ForSpec(contents)
+}
+
+// addReferencedObjectsChecksum adds to the pod template the checksum of the Secrets and the ConfigMaps used by the component,
+// so the pods are rolled out when their content changes.
+func (r *Reconciler) addReferencedObjectsChecksum(podManagers feature.PodTemplateManagers, dda *datadoghqv2alpha1.DatadogAgent, componentName datadoghqv2alpha1.ComponentName) error {
+	checksum, err := r.referencedObjectsChecksum(dda, componentName)
+	if err != nil || checksum == "" {
+		return err
+	}
+	podManagers.Annotation().AddAnnotation(apicommon.MD5ReferencedObjectsChecksumAnnotationKey, checksum)
+	return nil
+}
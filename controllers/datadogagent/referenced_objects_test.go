@@ -0,0 +1,99 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"testing"
+
+	apicommonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testReferencingDDA() *datadoghqv2alpha1.DatadogAgent {
+	return &datadoghqv2alpha1.DatadogAgent{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo"},
+		Spec: datadoghqv2alpha1.DatadogAgentSpec{
+			Global: &datadoghqv2alpha1.GlobalConfig{
+				Credentials: &datadoghqv2alpha1.DatadogCredentials{
+					APISecret: &apicommonv1.SecretConfig{SecretName: "api-secret", KeyName: "api_key"},
+				},
+			},
+			Override: map[datadoghqv2alpha1.ComponentName]*datadoghqv2alpha1.DatadogAgentComponentOverride{
+				datadoghqv2alpha1.NodeAgentComponentName: {
+					ExtraConfd: &datadoghqv2alpha1.MultiCustomConfig{
+						ConfigMap: &apicommonv1.ConfigMapConfig{Name: "confd"},
+					},
+				},
+				datadoghqv2alpha1.ClusterAgentComponentName: {
+					CustomConfigurations: map[datadoghqv2alpha1.AgentConfigFileName]datadoghqv2alpha1.CustomConfig{
+						datadoghqv2alpha1.ClusterAgentConfigFile: {
+							ConfigMap: &apicommonv1.ConfigMapConfig{Name: "dca-config"},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestReferencesObject(t *testing.T) {
+	dda := testReferencingDDA()
+
+	assert.True(t, ReferencesObject(dda, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "api-secret"}}))
+	assert.True(t, ReferencesObject(dda, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "confd"}}))
+	assert.True(t, ReferencesObject(dda, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "dca-config"}}))
+	assert.False(t, ReferencesObject(dda, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "api-secret"}}))
+	assert.False(t, ReferencesObject(dda, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "api-secret"}}))
+	assert.False(t, ReferencesObject(dda, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "other"}}))
+}
+
+func Test_referencedObjectsChecksum(t *testing.T) {
+	dda := testReferencingDDA()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "api-secret"},
+		Data:       map[string][]byte{"api_key": []byte("key-1")},
+	}
+	confd := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "confd"},
+		Data:       map[string]string{"check.yaml": "instances: []"},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret, confd).Build()
+	r := &Reconciler{client: k8sClient}
+
+	agentChecksum, err := r.referencedObjectsChecksum(dda, datadoghqv2alpha1.NodeAgentComponentName)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, agentChecksum)
+
+	// The ConfigMap of the Cluster Agent doesn't exist, only the Secret is taken into account
+	dcaChecksum, err := r.referencedObjectsChecksum(dda, datadoghqv2alpha1.ClusterAgentComponentName)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, dcaChecksum)
+	assert.NotEqual(t, agentChecksum, dcaChecksum)
+
+	// The checksum changes when the content of a referenced object changes
+	secret.Data["api_key"] = []byte("key-2")
+	assert.NoError(t, k8sClient.Update(context.TODO(), secret))
+	newAgentChecksum, err := r.referencedObjectsChecksum(dda, datadoghqv2alpha1.NodeAgentComponentName)
+	assert.NoError(t, err)
+	assert.NotEqual(t, agentChecksum, newAgentChecksum)
+
+	newDCAChecksum, err := r.referencedObjectsChecksum(dda, datadoghqv2alpha1.ClusterAgentComponentName)
+	assert.NoError(t, err)
+	assert.NotEqual(t, dcaChecksum, newDCAChecksum)
+
+	// No client, no checksum
+	r = &Reconciler{}
+	checksum, err := r.referencedObjectsChecksum(dda, datadoghqv2alpha1.NodeAgentComponentName)
+	assert.NoError(t, err)
+	assert.Empty(t, checksum)
+}
@@ -0,0 +1,315 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/version"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	componentagent "github.com/DataDog/datadog-operator/controllers/datadogagent/component/agent"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+
+	edsv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
+)
+
+// RenderOptions provides the information needed to render the resources of a DatadogAgent
+// without access to a Kubernetes cluster.
+type RenderOptions struct {
+	ExtendedDaemonsetOptions        componentagent.ExtendedDaemonsetOptions
+	SupportCilium                   bool
+	ProcessChecksInCoreAgentEnabled bool
+
+	VersionInfo  *version.Info
+	PlatformInfo kubernetes.PlatformInfo
+	Scheme       *runtime.Scheme
+	Logger       logr.Logger
+	// Client is optional, when it is set the Secrets and the ConfigMaps referenced by the DatadogAgent are read
+	// to compute the checksum annotation of the pod templates.
+	Client client.Client
+}
+
+// RenderedResources contains the resources that the reconciler manages for a DatadogAgent.
+type RenderedResources struct {
+	Store              *dependencies.Store
+	Deployments        []*appsv1.Deployment
+	DaemonSets         []*appsv1.DaemonSet
+	ExtendedDaemonSets []*edsv1alpha1.ExtendedDaemonSet
+	// Disabled contains the workloads that are not deployed, the reconciler deletes them if they exist.
+	Disabled []client.Object
+}
+
+// Objects returns the dependencies followed by the workloads.
+func (rr *RenderedResources) Objects() []client.Object {
+	objs := rr.Store.Objects()
+	for _, deployment := range rr.Deployments {
+		objs = append(objs, deployment)
+	}
+	for _, ds := range rr.DaemonSets {
+		objs = append(objs, ds)
+	}
+	for _, eds := range rr.ExtendedDaemonSets {
+		objs = append(objs, eds)
+	}
+	return objs
+}
+
+// Render returns the resources that the reconciler creates for a DatadogAgent.
+// It goes through the same steps as the reconciler: defaulting, features, overrides and dependencies,
+// but it doesn't write anything in a cluster, and it only reads the referenced Secrets and ConfigMaps when
+// a client is provided. Profiles and introspection require the list of nodes, so they are not taken into account.
+func Render(dda *datadoghqv2alpha1.DatadogAgent, options RenderOptions) (*RenderedResources, error) {
+	if err := datadoghqv2alpha1.IsValidDatadogAgent(&dda.Spec); err != nil {
+		return nil, err
+	}
+
+	r := &Reconciler{
+		options: ReconcilerOptions{
+			ExtendedDaemonsetOptions:        options.ExtendedDaemonsetOptions,
+			SupportCilium:                   options.SupportCilium,
+			V2Enabled:                       true,
+			ProcessChecksInCoreAgentEnabled: options.ProcessChecksInCoreAgentEnabled,
+		},
+		client:       options.Client,
+		versionInfo:  options.VersionInfo,
+		platformInfo: options.PlatformInfo,
+		scheme:       options.Scheme,
+		log:          options.Logger,
+	}
+
+	instance := dda.DeepCopy()
+	datadoghqv2alpha1.DefaultDatadogAgent(instance)
+
+	providerList := map[string]struct{}{kubernetes.LegacyProvider: {}}
+	profiles := []datadoghqv1alpha1.DatadogAgentProfile{{}}
+	return r.renderV2(r.log, instance, providerList, profiles)
+}
+
+// renderV2 builds the dependencies store and the workloads of a defaulted DatadogAgent,
+// with one Agent DaemonSet per profile and provider.
+func (r *Reconciler) renderV2(logger logr.Logger, instance *datadoghqv2alpha1.DatadogAgent,
+	providerList map[string]struct{}, profiles []datadoghqv1alpha1.DatadogAgentProfile) (*RenderedResources, error) {
+	features, requiredComponents, featureRequirements := feature.BuildFeaturesWithRequirements(instance, reconcilerOptionsToFeatureOptions(&r.options, logger))
+
+	depsStore, resourceManagers, err := r.buildV2DependenciesStore(logger, instance, features, requiredComponents)
+	if err != nil {
+		return nil, err
+	}
+	rendered := &RenderedResources{Store: depsStore}
+
+	dca, dcaDisabledByOverride, err := r.buildV2ClusterAgentDeployment(logger, features, instance, resourceManagers)
+	if err != nil {
+		return nil, err
+	}
+	dcaDeployed := !dcaDisabledByOverride && isV2ComponentDeployed(instance, datadoghqv2alpha1.ClusterAgentComponentName, requiredComponents.ClusterAgent)
+	if dcaDeployed {
+		rendered.Deployments = append(rendered.Deployments, dca)
+	} else {
+		rendered.Disabled = append(rendered.Disabled, dca)
+	}
+
+	for i := range profiles {
+		profile := &profiles[i]
+		profileFeatures, profileRequiredComponents := featuresForProfile(features, requiredComponents, featureRequirements, profile)
+		for provider := range providerList {
+			if r.useV2ExtendedDaemonSet(profile) {
+				eds, disabledByOverride, edsErr := r.buildV2AgentExtendedDaemonSet(logger, profileRequiredComponents, profileFeatures, instance, resourceManagers, provider, providerList, profile)
+				if edsErr != nil {
+					return nil, edsErr
+				}
+				if disabledByOverride {
+					rendered.Disabled = append(rendered.Disabled, eds)
+				} else {
+					rendered.ExtendedDaemonSets = append(rendered.ExtendedDaemonSets, eds)
+				}
+				continue
+			}
+
+			ds, disabledByOverride, dsErr := r.buildV2AgentDaemonSet(logger, profileRequiredComponents, profileFeatures, instance, resourceManagers, provider, providerList, profile)
+			if dsErr != nil {
+				return nil, dsErr
+			}
+			if disabledByOverride {
+				rendered.Disabled = append(rendered.Disabled, ds)
+			} else {
+				rendered.DaemonSets = append(rendered.DaemonSets, ds)
+			}
+		}
+	}
+
+	ccr, ccrDisabledByOverride, err := r.buildV2ClusterChecksRunnerDeployment(logger, features, instance, resourceManagers)
+	if err != nil {
+		return nil, err
+	}
+	if dcaDeployed && !ccrDisabledByOverride && isV2ComponentDeployed(instance, datadoghqv2alpha1.ClusterChecksRunnerComponentName, requiredComponents.ClusterChecksRunner) {
+		rendered.Deployments = append(rendered.Deployments, ccr)
+	} else {
+		rendered.Disabled = append(rendered.Disabled, ccr)
+	}
+
+	// Set the owner reference and the spec hash annotation like the reconciler does before creating the workloads.
+	var workloads []client.Object
+	for _, deployment := range rendered.Deployments {
+		workloads = append(workloads, deployment)
+		if _, err = comparison.SetMD5DatadogAgentGenerationAnnotation(&deployment.ObjectMeta, deployment.Spec); err != nil {
+			return nil, err
+		}
+	}
+	for _, ds := range rendered.DaemonSets {
+		workloads = append(workloads, ds)
+		if _, err = comparison.SetMD5DatadogAgentGenerationAnnotation(&ds.ObjectMeta, ds.Spec); err != nil {
+			return nil, err
+		}
+	}
+	for _, eds := range rendered.ExtendedDaemonSets {
+		workloads = append(workloads, eds)
+		if _, err = comparison.SetMD5DatadogAgentGenerationAnnotation(&eds.ObjectMeta, eds.Spec); err != nil {
+			return nil, err
+		}
+	}
+	for _, obj := range workloads {
+		if err = controllerutil.SetControllerReference(instance, obj, r.scheme); err != nil {
+			return nil, fmt.Errorf("unable to set the owner reference of %s, %w", obj.GetName(), err)
+		}
+	}
+
+	return rendered, nil
+}
+
+// PlannedAction is an action that the reconciler performs on an object.
+type PlannedAction string
+
+const (
+	// PlannedCreate the object is created
+	PlannedCreate PlannedAction = "create"
+	// PlannedUpdate the object is updated
+	PlannedUpdate PlannedAction = "update"
+	// PlannedDelete the object is deleted
+	PlannedDelete PlannedAction = "delete"
+)
+
+// PlannedChange is a change that the reconciler would apply in the cluster.
+type PlannedChange struct {
+	Action PlannedAction
+	// Desired is the rendered object, it is nil when the object is deleted.
+	Desired client.Object
+	// Current is the object in the cluster, it is nil when the object is created.
+	Current client.Object
+}
+
+// Plan returns the changes that the reconciler would apply to go from the current state of the cluster
+// to the rendered resources. The dependencies are compared like the dependencies store does, and the
+// workloads with their spec hash annotation.
+func (rr *RenderedResources) Plan(ctx context.Context, k8sClient client.Client) ([]PlannedChange, error) {
+	var changes []PlannedChange
+	logger := rr.Store.Logger()
+
+	toCreate, toUpdate, errs := rr.Store.PlanApply(ctx, k8sClient)
+	toDelete, cleanupErrs := rr.Store.PlanCleanup(ctx, k8sClient)
+	errs = append(errs, cleanupErrs...)
+	for _, planned := range toCreate {
+		changes = append(changes, PlannedChange{Action: PlannedCreate, Desired: planned.Object})
+	}
+	for _, planned := range toUpdate {
+		changes = append(changes, PlannedChange{Action: PlannedUpdate, Desired: planned.Object, Current: planned.Current})
+	}
+	for _, planned := range toDelete {
+		changes = append(changes, PlannedChange{Action: PlannedDelete, Current: planned.Current})
+	}
+
+	for _, deployment := range rr.Deployments {
+		current := &appsv1.Deployment{}
+		change, err := planWorkload(ctx, logger, k8sClient, deployment, current, func() (client.Object, string, error) {
+			update := deployment.DeepCopy()
+			update.Spec.Replicas = getReplicas(current.Spec.Replicas, update.Spec.Replicas)
+			hash, err := comparison.SetMD5DatadogAgentGenerationAnnotation(&update.ObjectMeta, update.Spec)
+			return update, hash, err
+		})
+		errs = appendPlannedChange(&changes, change, err, errs)
+	}
+	for _, ds := range rr.DaemonSets {
+		current := &appsv1.DaemonSet{}
+		change, err := planWorkload(ctx, logger, k8sClient, ds, current, func() (client.Object, string, error) {
+			update := ds.DeepCopy()
+			update.Spec.Selector = current.Spec.Selector
+			update.Spec.Template.Labels = ensureSelectorInPodTemplateLabels(logger, update.Spec.Selector, update.Spec.Template.Labels)
+			hash, err := comparison.SetMD5DatadogAgentGenerationAnnotation(&update.ObjectMeta, update.Spec)
+			return update, hash, err
+		})
+		errs = appendPlannedChange(&changes, change, err, errs)
+	}
+	for _, eds := range rr.ExtendedDaemonSets {
+		current := &edsv1alpha1.ExtendedDaemonSet{}
+		change, err := planWorkload(ctx, logger, k8sClient, eds, current, func() (client.Object, string, error) {
+			update := eds.DeepCopy()
+			hash, err := comparison.SetMD5DatadogAgentGenerationAnnotation(&update.ObjectMeta, update.Spec)
+			return update, hash, err
+		})
+		errs = appendPlannedChange(&changes, change, err, errs)
+	}
+	for _, obj := range rr.Disabled {
+		current := obj.DeepCopyObject().(client.Object)
+		if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, current); err != nil {
+			if !apierrors.IsNotFound(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		changes = append(changes, PlannedChange{Action: PlannedDelete, Current: current})
+	}
+
+	return changes, errors.NewAggregate(errs)
+}
+
+// planWorkload compares a rendered workload with its current version in the cluster.
+// newUpdate returns the object that the reconciler would send to update the workload, and its spec hash,
+// once the current object is known.
+func planWorkload(ctx context.Context, logger logr.Logger, k8sClient client.Client, desired, current client.Object, newUpdate func() (client.Object, string, error)) (*PlannedChange, error) {
+	if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: desired.GetNamespace(), Name: desired.GetName()}, current); err != nil {
+		if apierrors.IsNotFound(err) {
+			return &PlannedChange{Action: PlannedCreate, Desired: desired}, nil
+		}
+		return nil, err
+	}
+
+	update, hash, err := newUpdate()
+	if err != nil {
+		return nil, err
+	}
+	if comparison.IsSameSpecMD5Hash(hash, current.GetAnnotations()) {
+		return nil, nil
+	}
+
+	// Like the reconciler, keep the labels and annotations that are set on the current object
+	update.SetAnnotations(mergeAnnotationsLabels(logger, current.GetAnnotations(), update.GetAnnotations(), ""))
+	update.SetLabels(mergeAnnotationsLabels(logger, current.GetLabels(), update.GetLabels(), ""))
+
+	return &PlannedChange{Action: PlannedUpdate, Desired: update, Current: current}, nil
+}
+
+func appendPlannedChange(changes *[]PlannedChange, change *PlannedChange, err error, errs []error) []error {
+	if err != nil {
+		return append(errs, err)
+	}
+	if change != nil {
+		*changes = append(*changes, *change)
+	}
+	return errs
+}
@@ -0,0 +1,157 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"testing"
+
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
+	apicommonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	v2alpha1test "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1/test"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	testutils "github.com/DataDog/datadog-operator/controllers/datadogagent/testutils"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+
+	assert "github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/version"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestRender(t *testing.T) {
+	const resourcesName = "foo"
+	const resourcesNamespace = "bar"
+
+	options := RenderOptions{
+		VersionInfo:  &version.Info{GitVersion: "v1.28.0"},
+		PlatformInfo: kubernetes.NewPlatformInfoFromVersionMaps(nil, map[string]string{"PodDisruptionBudget": "policy/v1"}, nil),
+		Scheme:       testutils.TestScheme(true),
+		Logger:       logf.Log.WithName(t.Name()),
+	}
+
+	tests := []struct {
+		name            string
+		dda             *v2alpha1.DatadogAgent
+		wantDaemonSets  []string
+		wantDeployments []string
+		wantErr         bool
+	}{
+		{
+			name:            "default DatadogAgent",
+			dda:             v2alpha1test.NewInitializedDatadogAgentBuilder(resourcesNamespace, resourcesName).Build(),
+			wantDaemonSets:  []string{"foo-agent"},
+			wantDeployments: []string{"foo-cluster-agent"},
+		},
+		{
+			name: "cluster checks runner enabled",
+			dda: v2alpha1test.NewInitializedDatadogAgentBuilder(resourcesNamespace, resourcesName).
+				WithClusterChecksEnabled(true).
+				WithClusterChecksUseCLCEnabled(true).
+				Build(),
+			wantDaemonSets:  []string{"foo-agent"},
+			wantDeployments: []string{"foo-cluster-agent", "foo-cluster-checks-runner"},
+		},
+		{
+			name: "cluster agent disabled by override",
+			dda: v2alpha1test.NewInitializedDatadogAgentBuilder(resourcesNamespace, resourcesName).
+				WithClusterChecksEnabled(true).
+				WithClusterChecksUseCLCEnabled(true).
+				WithComponentOverride(v2alpha1.ClusterAgentComponentName, v2alpha1.DatadogAgentComponentOverride{
+					Disabled: apiutils.NewBoolPointer(true),
+				}).
+				Build(),
+			wantDaemonSets: []string{"foo-agent"},
+		},
+		{
+			name: "invalid DatadogAgent",
+			dda: v2alpha1test.NewInitializedDatadogAgentBuilder(resourcesNamespace, resourcesName).
+				WithAPMEnabled(true).
+				WithAPMSingleStepInstrumentationEnabled(true, []string{"foo"}, []string{"bar"}, nil).
+				Build(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := Render(tt.dda, options)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			var daemonSets, deployments []string
+			for _, ds := range rendered.DaemonSets {
+				daemonSets = append(daemonSets, ds.Name)
+				assert.NotEmpty(t, ds.Annotations[apicommon.MD5AgentDeploymentAnnotationKey])
+				assert.Len(t, ds.OwnerReferences, 1)
+			}
+			for _, deployment := range rendered.Deployments {
+				deployments = append(deployments, deployment.Name)
+			}
+			assert.Equal(t, tt.wantDaemonSets, daemonSets)
+			assert.Equal(t, tt.wantDeployments, deployments)
+
+			// The dependencies are rendered too, e.g. the RBAC of the components
+			_, found := rendered.Store.Get(kubernetes.ServiceAccountsKind, resourcesNamespace, "foo-agent")
+			assert.True(t, found)
+			assert.Len(t, rendered.Objects(), len(rendered.Store.Objects())+len(daemonSets)+len(deployments))
+
+			// Rendering doesn't modify the DatadogAgent
+			assert.Nil(t, tt.dda.Spec.Global.Registry)
+		})
+	}
+}
+
+func TestRenderedResources_Plan(t *testing.T) {
+	options := RenderOptions{
+		VersionInfo:  &version.Info{GitVersion: "v1.28.0"},
+		PlatformInfo: kubernetes.NewPlatformInfoFromVersionMaps(nil, map[string]string{"PodDisruptionBudget": "policy/v1"}, nil),
+		Scheme:       testutils.TestScheme(true),
+		Logger:       logf.Log.WithName(t.Name()),
+	}
+	dda := v2alpha1test.NewInitializedDatadogAgentBuilder("bar", "foo").Build()
+	// Otherwise a new Cluster Agent token is generated on each render
+	dda.Spec.Global.ClusterAgentToken = apiutils.NewStringPointer("0123456789abcdef0123456789abcdef")
+
+	rendered, err := Render(dda, options)
+	assert.NoError(t, err)
+
+	// Nothing exists: every object is created
+	c := fake.NewClientBuilder().WithScheme(options.Scheme).Build()
+	changes, err := rendered.Plan(context.TODO(), c)
+	assert.NoError(t, err)
+	assert.Len(t, changes, len(rendered.Objects()))
+	for _, change := range changes {
+		assert.Equal(t, PlannedCreate, change.Action)
+		assert.Nil(t, change.Current)
+	}
+
+	// Everything is up to date: nothing to do
+	for _, obj := range rendered.Objects() {
+		assert.NoError(t, c.Create(context.TODO(), obj.DeepCopyObject().(client.Object)))
+	}
+	changes, err = rendered.Plan(context.TODO(), c)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	// The Agent image changes: only the DaemonSet is updated
+	dda.Spec.Override = map[v2alpha1.ComponentName]*v2alpha1.DatadogAgentComponentOverride{
+		v2alpha1.NodeAgentComponentName: {Image: &apicommonv1.AgentImageConfig{Name: "agent", Tag: "7.99.0"}},
+	}
+	rendered, err = Render(dda, options)
+	assert.NoError(t, err)
+	changes, err = rendered.Plan(context.TODO(), c)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, PlannedUpdate, changes[0].Action)
+	assert.Equal(t, "foo-agent", changes[0].Desired.GetName())
+	assert.NotNil(t, changes[0].Current)
+}
@@ -6,6 +6,8 @@
 package testutils_test
 
 import (
+	"context"
+
 	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
 	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
 	edsdatadoghqv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
@@ -14,9 +16,12 @@ import (
 	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // TestScheme return a runtime.Scheme for testing purposes
@@ -48,3 +53,34 @@ func TestScheme(isV2 bool) *runtime.Scheme {
 
 	return s
 }
+
+// NewApplyClient returns a client.Client that handles the server-side apply patches, which are not supported
+// by the controller-runtime fake client. The applied object replaces the existing one: the field ownership
+// and the conflicts are not emulated.
+func NewApplyClient(c client.Client) client.Client {
+	return &applyClient{Client: c}
+}
+
+type applyClient struct {
+	client.Client
+}
+
+// Patch handles the server-side apply patches with a Create or an Update
+func (c *applyClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+
+	current, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+	if err := c.Client.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		if apierrors.IsNotFound(err) {
+			return c.Client.Create(ctx, obj)
+		}
+		return err
+	}
+	obj.SetResourceVersion(current.GetResourceVersion())
+	return c.Client.Update(ctx, obj)
+}
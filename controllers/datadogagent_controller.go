@@ -7,24 +7,26 @@ package controllers
 
 import (
 	"context"
-	"reflect"
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -33,6 +35,7 @@ import (
 	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
 	"github.com/DataDog/datadog-operator/controllers/datadogagent"
 	"github.com/DataDog/datadog-operator/controllers/datadogagent/object"
+	"github.com/DataDog/datadog-operator/pkg/agentprofile"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
 	edsdatadoghqv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
@@ -184,17 +187,26 @@ func (r *DatadogAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 // SetupWithManager creates a new DatadogAgent controller.
 func (r *DatadogAgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	builder := ctrl.NewControllerManagedBy(mgr).
-		Owns(&corev1.Secret{}).
-		Owns(&corev1.ConfigMap{}).
-		Owns(&appsv1.DaemonSet{}).
-		Owns(&appsv1.Deployment{}).
-		Owns(&rbacv1.Role{}).
-		Owns(&rbacv1.RoleBinding{}).
-		Owns(&corev1.ServiceAccount{}).
+	builder := ctrl.NewControllerManagedBy(mgr)
+	ownedObjects := []client.Object{
+		&corev1.Secret{},
+		&corev1.ConfigMap{},
+		&appsv1.DaemonSet{},
+		&appsv1.Deployment{},
+		&rbacv1.Role{},
+		&rbacv1.RoleBinding{},
+		&corev1.ServiceAccount{},
 		// We let PlatformInfo supply PDB object based on the current API version
-		Owns(r.PlatformInfo.CreatePDBObject()).
-		Owns(&networkingv1.NetworkPolicy{})
+		r.PlatformInfo.CreatePDBObject(),
+		&networkingv1.NetworkPolicy{},
+	}
+	if r.Options.V2Enabled {
+		// The Services are watched to catch their drift when the dependencies apply is skipped
+		ownedObjects = append(ownedObjects, &corev1.Service{})
+	}
+	for _, obj := range ownedObjects {
+		builder = r.owns(builder, obj)
+	}
 
 	if r.Options.DatadogAgentProfileEnabled {
 		builder.Watches(
@@ -203,24 +215,31 @@ func (r *DatadogAgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		)
 	}
 
-	// Watch nodes and reconcile all DatadogAgents for node creation, node deletion, and node label change events
+	// Watch nodes and reconcile all DatadogAgents for node creation, node deletion, and node label, annotation, taint and allocatable resources change events
 	if r.Options.V2Enabled {
 		builder.Watches(
 			&source.Kind{Type: &corev1.Node{}},
 			handler.EnqueueRequestsFromMapFunc(r.enqueueRequestsForAllDDAs()),
-			ctrlbuilder.WithPredicates(r.enqueueIfNodeLabelsChange()),
+			ctrlbuilder.WithPredicates(r.enqueueIfNodeChanges()),
 		)
+
+		// Watch the Secrets and ConfigMaps referenced by the DatadogAgents to roll the pods when they change
+		builder.Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.enqueueRequestsForReferencingDDAs()))
+		builder.Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.enqueueRequestsForReferencingDDAs()))
 	}
 
 	// DatadogAgent is namespaced whereas ClusterRole and ClusterRoleBinding are
 	// cluster-scoped. That means that DatadogAgent cannot be their owner, and
 	// we cannot use .Owns().
-	handlerEnqueue := handler.EnqueueRequestsFromMapFunc(enqueueIfOwnedByDatadogAgent)
+	var handlerEnqueue handler.EventHandler = handler.EnqueueRequestsFromMapFunc(enqueueIfOwnedByDatadogAgent)
+	if r.Options.V2Enabled {
+		handlerEnqueue = &invalidateAppliedStateHandler{EventHandler: handlerEnqueue, invalidate: r.invalidateAppliedState}
+	}
 	builder.Watches(&source.Kind{Type: &rbacv1.ClusterRole{}}, handlerEnqueue)
 	builder.Watches(&source.Kind{Type: &rbacv1.ClusterRoleBinding{}}, handlerEnqueue)
 
 	if r.Options.ExtendedDaemonsetOptions.Enabled {
-		builder = builder.Owns(&edsdatadoghqv1alpha1.ExtendedDaemonSet{})
+		builder = r.owns(builder, &edsdatadoghqv1alpha1.ExtendedDaemonSet{})
 	}
 
 	if r.Options.SupportCilium {
@@ -230,7 +249,7 @@ func (r *DatadogAgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			Version: "v2",
 			Kind:    "CiliumNetworkPolicy",
 		})
-		builder = builder.Owns(policy)
+		builder = r.owns(builder, policy)
 	}
 
 	var metricForwarder datadog.MetricForwardersManager
@@ -278,10 +297,16 @@ func enqueueIfOwnedByDatadogAgent(obj client.Object) []reconcile.Request {
 	return []reconcile.Request{{NamespacedName: owner}}
 }
 
-func (r *DatadogAgentReconciler) enqueueIfNodeLabelsChange() predicate.Funcs {
+func (r *DatadogAgentReconciler) enqueueIfNodeChanges() predicate.Funcs {
 	return predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			return !reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+			// The profiles select the nodes by their labels, annotations, taints and allocatable resources
+			if r.Options.DatadogAgentProfileEnabled {
+				return nodeChangedForProfiles(r.Client, e)
+			}
+			oldNode, oldOk := e.ObjectOld.(*corev1.Node)
+			newNode, newOk := e.ObjectNew.(*corev1.Node)
+			return !oldOk || !newOk || agentprofile.NodeChanged(oldNode, newNode, nil)
 		},
 		CreateFunc: func(e event.CreateEvent) bool {
 			return true
@@ -316,3 +341,92 @@ func (r *DatadogAgentReconciler) enqueueRequestsForAllDDAs() handler.MapFunc {
 		return requests
 	}
 }
+
+// enqueueRequestsForReferencingDDAs returns the DatadogAgents that reference the Secret or the ConfigMap.
+func (r *DatadogAgentReconciler) enqueueRequestsForReferencingDDAs() handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		var requests []reconcile.Request
+
+		ddaList := datadoghqv2alpha1.DatadogAgentList{}
+		if err := r.List(context.Background(), &ddaList, client.InNamespace(obj.GetNamespace())); err != nil {
+			return requests
+		}
+
+		for i := range ddaList.Items {
+			dda := &ddaList.Items[i]
+			if datadogagent.ReferencesObject(dda, obj) {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: dda.Namespace, Name: dda.Name}})
+			}
+		}
+
+		return requests
+	}
+}
+
+// owns watches the objects owned by the DatadogAgents. With v2alpha1, their events also invalidate
+// the applied state of the owner, so their drift is fixed even when nothing changed in the DatadogAgent.
+func (r *DatadogAgentReconciler) owns(builder *ctrlbuilder.Builder, obj client.Object) *ctrlbuilder.Builder {
+	if !r.Options.V2Enabled {
+		return builder.Owns(obj)
+	}
+	return builder.Watches(&source.Kind{Type: obj}, &invalidateAppliedStateHandler{
+		EventHandler: &handler.EnqueueRequestForOwner{OwnerType: &datadoghqv2alpha1.DatadogAgent{}, IsController: true},
+		invalidate:   r.invalidateAppliedState,
+	})
+}
+
+// invalidateAppliedState invalidates the applied state of the DatadogAgent owning the object.
+func (r *DatadogAgentReconciler) invalidateAppliedState(obj client.Object) {
+	if r.internal == nil {
+		return
+	}
+	if ref := metav1.GetControllerOf(obj); ref != nil {
+		if ref.Kind == "DatadogAgent" {
+			r.internal.InvalidateAppliedState(types.NamespacedName{Namespace: obj.GetNamespace(), Name: ref.Name})
+		}
+		return
+	}
+	// Cluster level objects can't be owned by a DatadogAgent
+	for _, request := range enqueueIfOwnedByDatadogAgent(obj) {
+		r.internal.InvalidateAppliedState(request.NamespacedName)
+	}
+}
+
+// invalidateAppliedStateHandler invalidates the applied state of the DatadogAgent owning an object
+// before enqueuing it. The updates that don't change the generation of an object, like the status
+// updates, are ignored unless the object doesn't track its generation.
+type invalidateAppliedStateHandler struct {
+	handler.EventHandler
+	invalidate func(obj client.Object)
+}
+
+// InjectFunc forwards the dependencies injection, like the scheme and the RESTMapper, to the wrapped handler.
+func (h *invalidateAppliedStateHandler) InjectFunc(f inject.Func) error {
+	return f(h.EventHandler)
+}
+
+// Create implements handler.EventHandler
+func (h *invalidateAppliedStateHandler) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	h.invalidate(evt.Object)
+	h.EventHandler.Create(evt, q)
+}
+
+// Update implements handler.EventHandler
+func (h *invalidateAppliedStateHandler) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	if evt.ObjectNew.GetGeneration() == 0 || evt.ObjectOld.GetGeneration() != evt.ObjectNew.GetGeneration() {
+		h.invalidate(evt.ObjectNew)
+	}
+	h.EventHandler.Update(evt, q)
+}
+
+// Delete implements handler.EventHandler
+func (h *invalidateAppliedStateHandler) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	h.invalidate(evt.Object)
+	h.EventHandler.Delete(evt, q)
+}
+
+// Generic implements handler.EventHandler
+func (h *invalidateAppliedStateHandler) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	h.invalidate(evt.Object)
+	h.EventHandler.Generic(evt, q)
+}
@@ -75,7 +75,7 @@ var _ = Describe("V2 Controller - DatadogAgentProfile", func() {
 
 		expectedDaemonSets := map[types.NamespacedName]daemonSetExpectations{
 			defaultDaemonSetNamespacedName(namespace, &agent): {
-				affinity: affinityForDefaultProfile(),
+				affinity: affinityForDefaultProfile(&agent),
 				containerResources: map[common.AgentContainerName]v1.ResourceRequirements{
 					common.CoreAgentContainerName:    {},
 					common.TraceAgentContainerName:   {},
@@ -144,12 +144,12 @@ var _ = Describe("V2 Controller - DatadogAgentProfile", func() {
 			Name: agentprofile.DaemonSetName(types.NamespacedName{
 				Namespace: profile.Namespace,
 				Name:      profile.Name,
-			}),
+			}, agent.Name),
 		}
 
 		expectedDaemonSets := map[types.NamespacedName]daemonSetExpectations{
 			defaultDaemonSetNamespacedName(namespace, &agent): {
-				affinity: affinityForDefaultProfile(),
+				affinity: affinityForDefaultProfile(&agent),
 				containerResources: map[common.AgentContainerName]v1.ResourceRequirements{
 					common.CoreAgentContainerName:    {},
 					common.TraceAgentContainerName:   {},
@@ -173,7 +173,7 @@ var _ = Describe("V2 Controller - DatadogAgentProfile", func() {
 							},
 						},
 					},
-					PodAntiAffinity: podAntiAffinityForAgents(),
+					PodAntiAffinity: podAntiAffinityForAgents(agent.Name),
 				},
 				containerResources: map[common.AgentContainerName]v1.ResourceRequirements{
 					common.CoreAgentContainerName: {
@@ -252,12 +252,12 @@ var _ = Describe("V2 Controller - DatadogAgentProfile", func() {
 			Name: agentprofile.DaemonSetName(types.NamespacedName{
 				Namespace: profile.Namespace,
 				Name:      profile.Name,
-			}),
+			}, agent.Name),
 		}
 
 		expectedDaemonSets := map[types.NamespacedName]daemonSetExpectations{
 			defaultDaemonSetNamespacedName(namespace, &agent): {
-				affinity: affinityForDefaultProfile(),
+				affinity: affinityForDefaultProfile(&agent),
 				containerResources: map[common.AgentContainerName]v1.ResourceRequirements{
 					common.CoreAgentContainerName:    {},
 					common.TraceAgentContainerName:   {},
@@ -281,7 +281,7 @@ var _ = Describe("V2 Controller - DatadogAgentProfile", func() {
 							},
 						},
 					},
-					PodAntiAffinity: podAntiAffinityForAgents(),
+					PodAntiAffinity: podAntiAffinityForAgents(agent.Name),
 				},
 				containerResources: map[common.AgentContainerName]v1.ResourceRequirements{
 					common.CoreAgentContainerName: {
@@ -365,12 +365,12 @@ var _ = Describe("V2 Controller - DatadogAgentProfile", func() {
 			Name: agentprofile.DaemonSetName(types.NamespacedName{
 				Namespace: profile.Namespace,
 				Name:      profile.Name,
-			}),
+			}, agent.Name),
 		}
 
 		expectedDaemonSets := map[types.NamespacedName]daemonSetExpectations{
 			defaultDaemonSetNamespacedName(namespace, &agent): {
-				affinity: affinityForDefaultProfile(),
+				affinity: affinityForDefaultProfile(&agent),
 				containerResources: map[common.AgentContainerName]v1.ResourceRequirements{
 					common.CoreAgentContainerName:    {},
 					common.TraceAgentContainerName:   {},
@@ -394,7 +394,7 @@ var _ = Describe("V2 Controller - DatadogAgentProfile", func() {
 							},
 						},
 					},
-					PodAntiAffinity: podAntiAffinityForAgents(),
+					PodAntiAffinity: podAntiAffinityForAgents(agent.Name),
 				},
 				containerResources: map[common.AgentContainerName]v1.ResourceRequirements{
 					common.CoreAgentContainerName: {
@@ -516,7 +516,7 @@ var _ = Describe("V2 Controller - DatadogAgentProfile", func() {
 			Name: agentprofile.DaemonSetName(types.NamespacedName{
 				Namespace: profiles[0].Namespace,
 				Name:      profiles[0].Name,
-			}),
+			}, agent.Name),
 		}
 
 		profile2DaemonSetName := types.NamespacedName{
@@ -524,12 +524,12 @@ var _ = Describe("V2 Controller - DatadogAgentProfile", func() {
 			Name: agentprofile.DaemonSetName(types.NamespacedName{
 				Namespace: profiles[1].Namespace,
 				Name:      profiles[1].Name,
-			}),
+			}, agent.Name),
 		}
 
 		expectedDaemonSets := map[types.NamespacedName]daemonSetExpectations{
 			defaultDaemonSetNamespacedName(namespace, &agent): {
-				affinity: affinityForDefaultProfile(),
+				affinity: affinityForDefaultProfile(&agent),
 				containerResources: map[common.AgentContainerName]v1.ResourceRequirements{
 					common.CoreAgentContainerName:    {},
 					common.TraceAgentContainerName:   {},
@@ -553,7 +553,7 @@ var _ = Describe("V2 Controller - DatadogAgentProfile", func() {
 							},
 						},
 					},
-					PodAntiAffinity: podAntiAffinityForAgents(),
+					PodAntiAffinity: podAntiAffinityForAgents(agent.Name),
 				},
 				containerResources: map[common.AgentContainerName]v1.ResourceRequirements{
 					common.CoreAgentContainerName: {
@@ -585,7 +585,7 @@ var _ = Describe("V2 Controller - DatadogAgentProfile", func() {
 							},
 						},
 					},
-					PodAntiAffinity: podAntiAffinityForAgents(),
+					PodAntiAffinity: podAntiAffinityForAgents(agent.Name),
 				},
 				containerResources: map[common.AgentContainerName]v1.ResourceRequirements{
 					common.CoreAgentContainerName: {
@@ -709,12 +709,12 @@ var _ = Describe("V2 Controller - DatadogAgentProfile", func() {
 			Name: agentprofile.DaemonSetName(types.NamespacedName{
 				Namespace: profiles[0].Namespace,
 				Name:      profiles[0].Name,
-			}),
+			}, agent.Name),
 		}
 
 		expectedDaemonSets := map[types.NamespacedName]daemonSetExpectations{
 			defaultDaemonSetNamespacedName(namespace, &agent): {
-				affinity: affinityForDefaultProfile(),
+				affinity: affinityForDefaultProfile(&agent),
 				containerResources: map[common.AgentContainerName]v1.ResourceRequirements{
 					common.CoreAgentContainerName:    {},
 					common.TraceAgentContainerName:   {},
@@ -738,7 +738,7 @@ var _ = Describe("V2 Controller - DatadogAgentProfile", func() {
 							},
 						},
 					},
-					PodAntiAffinity: podAntiAffinityForAgents(),
+					PodAntiAffinity: podAntiAffinityForAgents(agent.Name),
 				},
 				containerResources: map[common.AgentContainerName]v1.ResourceRequirements{
 					common.CoreAgentContainerName: {
@@ -841,12 +841,12 @@ var _ = Describe("V2 Controller - DatadogAgentProfile", func() {
 			Name: agentprofile.DaemonSetName(types.NamespacedName{
 				Namespace: profile.Namespace,
 				Name:      profile.Name,
-			}),
+			}, agent.Name),
 		}
 
 		expectedDaemonSets := map[types.NamespacedName]daemonSetExpectations{
 			defaultDaemonSetNamespacedName(namespace, &agent): {
-				affinity: affinityForDefaultProfile(),
+				affinity: affinityForDefaultProfile(&agent),
 				containerResources: map[common.AgentContainerName]v1.ResourceRequirements{
 					common.CoreAgentContainerName: {
 						Limits: map[v1.ResourceName]resource.Quantity{
@@ -879,7 +879,7 @@ var _ = Describe("V2 Controller - DatadogAgentProfile", func() {
 							},
 						},
 					},
-					PodAntiAffinity: podAntiAffinityForAgents(),
+					PodAntiAffinity: podAntiAffinityForAgents(agent.Name),
 				},
 				containerResources: map[common.AgentContainerName]v1.ResourceRequirements{
 					common.CoreAgentContainerName: {
@@ -969,12 +969,12 @@ var _ = Describe("V2 Controller - DatadogAgentProfile", func() {
 			Name: agentprofile.DaemonSetName(types.NamespacedName{
 				Namespace: profile.Namespace,
 				Name:      profile.Name,
-			}),
+			}, agent.Name),
 		}
 
 		expectedDaemonSets := map[types.NamespacedName]daemonSetExpectations{
 			defaultDaemonSetNamespacedName(namespace, &agent): {
-				affinity: affinityForDefaultProfile(),
+				affinity: affinityForDefaultProfile(&agent),
 				containerResources: map[common.AgentContainerName]v1.ResourceRequirements{
 					common.CoreAgentContainerName:    {},
 					common.TraceAgentContainerName:   {},
@@ -1003,7 +1003,7 @@ var _ = Describe("V2 Controller - DatadogAgentProfile", func() {
 							},
 						},
 					},
-					PodAntiAffinity: podAntiAffinityForAgents(),
+					PodAntiAffinity: podAntiAffinityForAgents(agent.Name),
 				},
 				containerResources: map[common.AgentContainerName]v1.ResourceRequirements{
 					common.CoreAgentContainerName: {
@@ -1176,7 +1176,7 @@ func sortAffinityRequirements(affinity *v1.Affinity) {
 	})
 }
 
-func affinityForDefaultProfile() *v1.Affinity {
+func affinityForDefaultProfile(agent *v2alpha1.DatadogAgent) *v1.Affinity {
 	return &v1.Affinity{
 		NodeAffinity: &v1.NodeAffinity{
 			RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
@@ -1184,7 +1184,7 @@ func affinityForDefaultProfile() *v1.Affinity {
 					{
 						MatchExpressions: []v1.NodeSelectorRequirement{
 							{
-								Key:      agentprofile.ProfileLabelKey,
+								Key:      agentprofile.DatadogAgentProfileLabelKey(agent),
 								Operator: v1.NodeSelectorOpDoesNotExist,
 							},
 						},
@@ -1192,11 +1192,11 @@ func affinityForDefaultProfile() *v1.Affinity {
 				},
 			},
 		},
-		PodAntiAffinity: podAntiAffinityForAgents(),
+		PodAntiAffinity: podAntiAffinityForAgents(agent.Name),
 	}
 }
 
-func podAntiAffinityForAgents() *v1.PodAntiAffinity {
+func podAntiAffinityForAgents(agentName string) *v1.PodAntiAffinity {
 	return &v1.PodAntiAffinity{
 		RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{
 			{
@@ -1207,6 +1207,11 @@ func podAntiAffinityForAgents() *v1.PodAntiAffinity {
 							Operator: metav1.LabelSelectorOpIn,
 							Values:   []string{"agent"},
 						},
+						{
+							Key:      apicommon.AgentDeploymentNameLabelKey,
+							Operator: metav1.LabelSelectorOpIn,
+							Values:   []string{agentName},
+						},
 					},
 				},
 				TopologyKey: v1.LabelHostname,
@@ -9,10 +9,31 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/pkg/agentprofile"
+)
+
+const (
+	// Reasons of the DatadogAgentProfile conditions
+	profileValidReason          = "Valid"
+	profileInvalidReason        = "Invalid"
+	profileAppliedReason        = "Applied"
+	profileConflictReason       = "Conflict"
+	profileNoConflictReason     = "NoConflict"
+	profileNoDatadogAgentReason = "NoDatadogAgent"
+	profileNotAppliedMessage    = "The profile is not applied"
 )
 
 // Reconciler reconciles a DatadogAgentProfile object
@@ -36,11 +57,6 @@ func NewReconciler(client client.Client, scheme *runtime.Scheme, log logr.Logger
 }
 
 func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (ctrl.Result, error) {
-	// _ = log.FromContext(ctx)
-
-	// // TODO(user): your logic here
-
-	// return ctrl.Result{}, nil
 	return r.internalReconcile(ctx, req)
 }
 
@@ -50,5 +66,122 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 
 	var result reconcile.Result
 
+	instance := &datadoghqv1alpha1.DatadogAgentProfile{}
+	if err := r.client.Get(ctx, req.NamespacedName, instance); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			return result, nil
+		}
+		return result, err
+	}
+
+	// The status of a profile depends on the other profiles, since they can conflict with it
+	profileList := datadoghqv1alpha1.DatadogAgentProfileList{}
+	if err := r.client.List(ctx, &profileList); err != nil {
+		return result, err
+	}
+	nodeList := corev1.NodeList{}
+	if err := r.client.List(ctx, &nodeList); err != nil {
+		return result, err
+	}
+	// Only the profiles that apply to the same DatadogAgent can conflict
+	ddaList := datadoghqv2alpha1.DatadogAgentList{}
+	if err := r.client.List(ctx, &ddaList); err != nil {
+		return result, err
+	}
+	ddas := make([]metav1.Object, 0, len(ddaList.Items))
+	for i := range ddaList.Items {
+		ddas = append(ddas, &ddaList.Items[i])
+	}
+
+	statuses, err := agentprofile.ProfilesStatusForDatadogAgents(profileList.Items, ddas, nodeList.Items, reqLogger)
+	if err != nil {
+		return result, err
+	}
+
+	newStatus := instance.Status.DeepCopy()
+	updateStatus(newStatus, req.NamespacedName, statuses[req.NamespacedName], instance.Generation)
+
+	if apiequality.Semantic.DeepEqual(&instance.Status, newStatus) {
+		return result, nil
+	}
+	instance.Status = *newStatus
+	if err := r.client.Status().Update(ctx, instance); err != nil {
+		if apierrors.IsConflict(err) {
+			reqLogger.V(1).Info("unable to update DatadogAgentProfile status due to update conflict")
+			return reconcile.Result{Requeue: true}, nil
+		}
+		return result, err
+	}
+
 	return result, nil
 }
+
+// updateStatus sets the conditions and the fields of the profile status from the result of the profiles evaluation.
+func updateStatus(status *datadoghqv1alpha1.DatadogAgentProfileStatus, profile types.NamespacedName, profileStatus agentprofile.ProfileStatus, generation int64) {
+	status.MatchedNodes = int32(profileStatus.MatchedNodes)
+	status.MigratedNodes = int32(profileStatus.MigratedNodes)
+	status.PendingNodes = int32(profileStatus.PendingNodes)
+	// The DaemonSets are only generated for the profiles that are applied, one per DatadogAgent
+	status.DaemonSetNames = nil
+	if profileStatus.Applied {
+		status.DaemonSetNames = profileStatus.DaemonSetNames
+	}
+	status.ConflictingProfile = ""
+	if profileStatus.ConflictingProfile != nil {
+		status.ConflictingProfile = profileStatus.ConflictingProfile.String()
+	}
+
+	validCondition := metav1.Condition{
+		Type:               datadoghqv1alpha1.DatadogAgentProfileValidConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             profileValidReason,
+		Message:            "The profile spec is valid",
+		ObservedGeneration: generation,
+	}
+	if profileStatus.ValidationError != nil {
+		validCondition.Status = metav1.ConditionFalse
+		validCondition.Reason = profileInvalidReason
+		validCondition.Message = profileStatus.ValidationError.Error()
+	}
+
+	conflictCondition := metav1.Condition{
+		Type:               datadoghqv1alpha1.DatadogAgentProfileConflictConditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             profileNoConflictReason,
+		Message:            "The profile doesn't conflict with other profiles",
+		ObservedGeneration: generation,
+	}
+	if profileStatus.ConflictingProfile != nil {
+		conflictCondition.Status = metav1.ConditionTrue
+		conflictCondition.Reason = profileConflictReason
+		conflictCondition.Message = "The profile conflicts with " + status.ConflictingProfile + " on some of the nodes it matches"
+	}
+
+	appliedCondition := metav1.Condition{
+		Type:               datadoghqv1alpha1.DatadogAgentProfileAppliedConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             profileAppliedReason,
+		Message:            "The profile is applied",
+		ObservedGeneration: generation,
+	}
+	if !profileStatus.Applied {
+		appliedCondition.Status = metav1.ConditionFalse
+		appliedCondition.Message = profileNotAppliedMessage
+		switch {
+		case profileStatus.ValidationError != nil:
+			appliedCondition.Reason = profileInvalidReason
+			appliedCondition.Message = profileNotAppliedMessage + " because its spec is invalid"
+		case profileStatus.ConflictingProfile != nil:
+			appliedCondition.Reason = profileConflictReason
+			appliedCondition.Message = profileNotAppliedMessage + " because it conflicts with " + status.ConflictingProfile
+		case profileStatus.NoDatadogAgent:
+			appliedCondition.Reason = profileNoDatadogAgentReason
+			appliedCondition.Message = profileNotAppliedMessage + " because it doesn't apply to any DatadogAgent"
+		}
+	}
+
+	meta.SetStatusCondition(&status.Conditions, validCondition)
+	meta.SetStatusCondition(&status.Conditions, appliedCondition)
+	meta.SetStatusCondition(&status.Conditions, conflictCondition)
+}
@@ -0,0 +1,196 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadoghq
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	common "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+)
+
+func testProfile(name string, creationTime time.Time, os string) *datadoghqv1alpha1.DatadogAgentProfile {
+	return &datadoghqv1alpha1.DatadogAgentProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "default",
+			Name:              name,
+			Generation:        1,
+			CreationTimestamp: metav1.NewTime(creationTime),
+		},
+		Spec: datadoghqv1alpha1.DatadogAgentProfileSpec{
+			ProfileAffinity: &datadoghqv1alpha1.ProfileAffinity{
+				ProfileNodeAffinity: []corev1.NodeSelectorRequirement{
+					{
+						Key:      "os",
+						Operator: corev1.NodeSelectorOpIn,
+						Values:   []string{os},
+					},
+				},
+			},
+			Config: &datadoghqv1alpha1.Config{
+				Override: map[datadoghqv1alpha1.ComponentName]*datadoghqv1alpha1.Override{
+					datadoghqv1alpha1.NodeAgentComponentName: {
+						Containers: map[common.AgentContainerName]*datadoghqv1alpha1.Container{
+							common.CoreAgentContainerName: {
+								Resources: &corev1.ResourceRequirements{
+									Requests: corev1.ResourceList{
+										corev1.ResourceCPU: resource.MustParse("100m"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, datadoghqv1alpha1.AddToScheme(s))
+	require.NoError(t, datadoghqv2alpha1.AddToScheme(s))
+
+	now := time.Now()
+	linuxProfile := testProfile("linux", now, "linux")
+	conflictingProfile := testProfile("linux-new", now.Add(time.Minute), "linux")
+	invalidProfile := testProfile("invalid", now, "windows")
+	invalidProfile.Spec.Config = nil
+
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		linuxProfile,
+		conflictingProfile,
+		invalidProfile,
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"os": "linux"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: map[string]string{"os": "linux"}}},
+		&datadoghqv2alpha1.DatadogAgent{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "datadog"}},
+		&datadoghqv2alpha1.DatadogAgent{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"}},
+	).Build()
+
+	r, err := NewReconciler(c, s, logf.Log.WithName(t.Name()))
+	require.NoError(t, err)
+
+	getStatus := func(name string) datadoghqv1alpha1.DatadogAgentProfileStatus {
+		nsName := types.NamespacedName{Namespace: "default", Name: name}
+		_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: nsName})
+		require.NoError(t, err)
+
+		profile := &datadoghqv1alpha1.DatadogAgentProfile{}
+		require.NoError(t, c.Get(context.TODO(), nsName, profile))
+		return profile.Status
+	}
+
+	status := getStatus("linux")
+	assert.Equal(t, int32(2), status.MatchedNodes)
+	assert.Equal(t, int32(0), status.MigratedNodes)
+	assert.Equal(t, int32(2), status.PendingNodes)
+	// The profile applies to both DatadogAgents, each one has its own DaemonSet
+	assert.Equal(t, []string{"datadog-agent-with-profile-default-linux", "other-agent-with-profile-default-linux"}, status.DaemonSetNames)
+	assert.Empty(t, status.ConflictingProfile)
+	assert.True(t, meta.IsStatusConditionTrue(status.Conditions, datadoghqv1alpha1.DatadogAgentProfileValidConditionType))
+	assert.True(t, meta.IsStatusConditionTrue(status.Conditions, datadoghqv1alpha1.DatadogAgentProfileAppliedConditionType))
+	assert.True(t, meta.IsStatusConditionFalse(status.Conditions, datadoghqv1alpha1.DatadogAgentProfileConflictConditionType))
+
+	status = getStatus("linux-new")
+	assert.Equal(t, int32(2), status.MatchedNodes)
+	assert.Empty(t, status.DaemonSetNames)
+	assert.Equal(t, "default/linux", status.ConflictingProfile)
+	assert.True(t, meta.IsStatusConditionTrue(status.Conditions, datadoghqv1alpha1.DatadogAgentProfileValidConditionType))
+	assert.True(t, meta.IsStatusConditionFalse(status.Conditions, datadoghqv1alpha1.DatadogAgentProfileAppliedConditionType))
+	assert.True(t, meta.IsStatusConditionTrue(status.Conditions, datadoghqv1alpha1.DatadogAgentProfileConflictConditionType))
+	assert.Equal(t, profileConflictReason, meta.FindStatusCondition(status.Conditions, datadoghqv1alpha1.DatadogAgentProfileAppliedConditionType).Reason)
+
+	status = getStatus("invalid")
+	assert.Equal(t, int32(0), status.MatchedNodes)
+	assert.Empty(t, status.DaemonSetNames)
+	assert.True(t, meta.IsStatusConditionFalse(status.Conditions, datadoghqv1alpha1.DatadogAgentProfileValidConditionType))
+	assert.Equal(t, "config must be defined", meta.FindStatusCondition(status.Conditions, datadoghqv1alpha1.DatadogAgentProfileValidConditionType).Message)
+	assert.True(t, meta.IsStatusConditionFalse(status.Conditions, datadoghqv1alpha1.DatadogAgentProfileAppliedConditionType))
+	assert.Equal(t, profileInvalidReason, meta.FindStatusCondition(status.Conditions, datadoghqv1alpha1.DatadogAgentProfileAppliedConditionType).Reason)
+
+	// Once the older profile is deleted, the conflict is resolved
+	require.NoError(t, c.Delete(context.TODO(), linuxProfile))
+	status = getStatus("linux-new")
+	assert.Empty(t, status.ConflictingProfile)
+	assert.Equal(t, []string{"datadog-agent-with-profile-default-linux-new", "other-agent-with-profile-default-linux-new"}, status.DaemonSetNames)
+	assert.True(t, meta.IsStatusConditionTrue(status.Conditions, datadoghqv1alpha1.DatadogAgentProfileAppliedConditionType))
+	assert.True(t, meta.IsStatusConditionFalse(status.Conditions, datadoghqv1alpha1.DatadogAgentProfileConflictConditionType))
+
+	// Reconciling a deleted profile is a no-op
+	_, err = r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "linux"}})
+	assert.NoError(t, err)
+}
+
+func TestReconciler_ReconcileWithDatadogAgentTargets(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, datadoghqv1alpha1.AddToScheme(s))
+	require.NoError(t, datadoghqv2alpha1.AddToScheme(s))
+
+	now := time.Now()
+	// The profiles target the same nodes, but not the same DatadogAgent
+	profileA := testProfile("linux-a", now, "linux")
+	profileA.Spec.DatadogAgentRef = &datadoghqv1alpha1.DatadogAgentReference{Name: "agent-a"}
+	profileB := testProfile("linux-b", now.Add(time.Minute), "linux")
+	profileB.Spec.DatadogAgentSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"team": "b"}}
+	orphanProfile := testProfile("orphan", now, "linux")
+	orphanProfile.Spec.DatadogAgentRef = &datadoghqv1alpha1.DatadogAgentReference{Name: "agent-a", Namespace: "other"}
+
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		profileA,
+		profileB,
+		orphanProfile,
+		&datadoghqv2alpha1.DatadogAgent{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "agent-a"}},
+		&datadoghqv2alpha1.DatadogAgent{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "agent-b", Labels: map[string]string{"team": "b"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"os": "linux"}}},
+	).Build()
+
+	r, err := NewReconciler(c, s, logf.Log.WithName(t.Name()))
+	require.NoError(t, err)
+
+	getStatus := func(name string) datadoghqv1alpha1.DatadogAgentProfileStatus {
+		nsName := types.NamespacedName{Namespace: "default", Name: name}
+		_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: nsName})
+		require.NoError(t, err)
+
+		profile := &datadoghqv1alpha1.DatadogAgentProfile{}
+		require.NoError(t, c.Get(context.TODO(), nsName, profile))
+		return profile.Status
+	}
+
+	for _, name := range []string{"linux-a", "linux-b"} {
+		status := getStatus(name)
+		assert.Equal(t, int32(1), status.MatchedNodes, name)
+		assert.Empty(t, status.ConflictingProfile, name)
+		assert.True(t, meta.IsStatusConditionTrue(status.Conditions, datadoghqv1alpha1.DatadogAgentProfileAppliedConditionType), name)
+	}
+
+	status := getStatus("orphan")
+	assert.Empty(t, status.DaemonSetNames)
+	assert.True(t, meta.IsStatusConditionTrue(status.Conditions, datadoghqv1alpha1.DatadogAgentProfileValidConditionType))
+	assert.True(t, meta.IsStatusConditionFalse(status.Conditions, datadoghqv1alpha1.DatadogAgentProfileAppliedConditionType))
+	assert.Equal(t, profileNoDatadogAgentReason, meta.FindStatusCondition(status.Conditions, datadoghqv1alpha1.DatadogAgentProfileAppliedConditionType).Reason)
+}
@@ -9,13 +9,23 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
 	dap "github.com/DataDog/datadog-operator/controllers/datadogagentprofile"
+	"github.com/DataDog/datadog-operator/pkg/agentprofile"
 )
 
 // DatadogAgentProfileReconciler reconciles a DatadogAgentProfile object.
@@ -45,7 +55,24 @@ func (r *DatadogAgentProfileReconciler) SetupWithManager(mgr ctrl.Manager) error
 	r.internal = internal
 
 	builder := ctrl.NewControllerManagedBy(mgr).
-		For(&datadoghqv1alpha1.DatadogAgentProfile{})
+		For(&datadoghqv1alpha1.DatadogAgentProfile{}).
+		// The status of a profile depends on the other profiles and on the nodes it matches
+		Watches(
+			&source.Kind{Type: &datadoghqv1alpha1.DatadogAgentProfile{}},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueRequestsForAllProfiles()),
+			ctrlbuilder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(
+			&source.Kind{Type: &corev1.Node{}},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueRequestsForAllProfiles()),
+			ctrlbuilder.WithPredicates(nodeChangedPredicate(r.Client)),
+		).
+		// The profiles select the DatadogAgents they apply to by name or by labels
+		Watches(
+			&source.Kind{Type: &datadoghqv2alpha1.DatadogAgent{}},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueRequestsForAllProfiles()),
+			ctrlbuilder.WithPredicates(predicate.LabelChangedPredicate{}),
+		)
 
 	err = builder.Complete(r)
 	if err != nil {
@@ -54,3 +81,45 @@ func (r *DatadogAgentProfileReconciler) SetupWithManager(mgr ctrl.Manager) error
 
 	return nil
 }
+
+func (r *DatadogAgentProfileReconciler) enqueueRequestsForAllProfiles() handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		var requests []reconcile.Request
+
+		profileList := datadoghqv1alpha1.DatadogAgentProfileList{}
+		if err := r.Client.List(context.Background(), &profileList); err != nil {
+			return requests
+		}
+
+		for _, profile := range profileList.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: profile.Namespace, Name: profile.Name}})
+		}
+
+		return requests
+	}
+}
+
+// nodeChangedPredicate filters out the node updates that don't change the node fields used by the profiles.
+func nodeChangedPredicate(c client.Client) predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return nodeChangedForProfiles(c, e)
+		},
+	}
+}
+
+// nodeChangedForProfiles returns true if a node update changes the node fields that the profiles select the nodes by.
+// Only the annotations referenced by the profiles are compared, since the nodes annotations change often.
+func nodeChangedForProfiles(c client.Client, e event.UpdateEvent) bool {
+	oldNode, oldOk := e.ObjectOld.(*corev1.Node)
+	newNode, newOk := e.ObjectNew.(*corev1.Node)
+	if !oldOk || !newOk {
+		return true
+	}
+
+	profileList := datadoghqv1alpha1.DatadogAgentProfileList{}
+	if err := c.List(context.Background(), &profileList); err != nil {
+		return true
+	}
+	return agentprofile.NodeChanged(oldNode, newNode, agentprofile.NodeAnnotationKeys(profileList.Items))
+}
@@ -0,0 +1,52 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+func Test_nodeChangedForProfiles(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = datadoghqv1alpha1.AddToScheme(s)
+
+	profile := &datadoghqv1alpha1.DatadogAgentProfile{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "edge"},
+		Spec: datadoghqv1alpha1.DatadogAgentProfileSpec{
+			ProfileAffinity: &datadoghqv1alpha1.ProfileAffinity{
+				ProfileNodeAnnotations: []corev1.NodeSelectorRequirement{
+					{Key: "example.com/location", Operator: corev1.NodeSelectorOpIn, Values: []string{"edge"}},
+				},
+			},
+		},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "node-1",
+			Annotations: map[string]string{"example.com/location": "cloud", "node.alpha.kubernetes.io/ttl": "0"},
+		},
+	}
+	otherAnnotation := node.DeepCopy()
+	otherAnnotation.Annotations["node.alpha.kubernetes.io/ttl"] = "15"
+	profileAnnotation := node.DeepCopy()
+	profileAnnotation.Annotations["example.com/location"] = "edge"
+
+	withProfile := fake.NewClientBuilder().WithScheme(s).WithObjects(profile).Build()
+	withoutProfile := fake.NewClientBuilder().WithScheme(s).Build()
+
+	assert.False(t, nodeChangedForProfiles(withProfile, event.UpdateEvent{ObjectOld: node, ObjectNew: otherAnnotation}))
+	assert.True(t, nodeChangedForProfiles(withProfile, event.UpdateEvent{ObjectOld: node, ObjectNew: profileAnnotation}))
+	assert.False(t, nodeChangedForProfiles(withoutProfile, event.UpdateEvent{ObjectOld: node, ObjectNew: profileAnnotation}))
+}
@@ -0,0 +1,380 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogdowntime
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilserrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/controllers/finalizer"
+	"github.com/DataDog/datadog-operator/controllers/utils"
+	ctrutils "github.com/DataDog/datadog-operator/pkg/controller/utils"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/condition"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+const (
+	defaultRequeuePeriod     = 60 * time.Second
+	defaultErrRequeuePeriod  = 5 * time.Second
+	defaultForceSyncPeriod   = 60 * time.Minute
+	datadogDowntimeKind      = "DatadogDowntime"
+	datadogDowntimeFinalizer = "finalizer.downtime.datadoghq.com"
+)
+
+// Reconciler reconciles a DatadogDowntime object
+type Reconciler struct {
+	client        client.Client
+	datadogClient *datadogV2.DowntimesApi
+	datadogAuth   context.Context
+	versionInfo   *version.Info
+	log           logr.Logger
+	recorder      record.EventRecorder
+}
+
+// NewReconciler returns a new Reconciler object
+func NewReconciler(client client.Client, ddClient datadogclient.DatadogDowntimeClient, versionInfo *version.Info, log logr.Logger, recorder record.EventRecorder) *Reconciler {
+	return &Reconciler{
+		client:        client,
+		datadogClient: ddClient.Client,
+		datadogAuth:   ddClient.Auth,
+		versionInfo:   versionInfo,
+		log:           log,
+		recorder:      recorder,
+	}
+}
+
+var _ reconcile.Reconciler = (*Reconciler)(nil)
+
+// Reconcile is similar to reconciler.Reconcile interface, but taking a context
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return r.internalReconcile(ctx, req)
+}
+
+// Reconcile loop for DatadogDowntime
+func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	logger := r.log.WithValues("datadogdowntime", req.NamespacedName)
+	logger.Info("Reconciling DatadogDowntime")
+	now := metav1.NewTime(time.Now())
+
+	// Get instance
+	instance := &v1alpha1.DatadogDowntime{}
+	var result ctrl.Result
+	var err error
+	if err = r.client.Get(ctx, req.NamespacedName, instance); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: defaultErrRequeuePeriod}, err
+	}
+
+	final := finalizer.NewFinalizer(
+		logger,
+		r.client,
+		r.deleteResource(logger, instance),
+		defaultRequeuePeriod,
+		defaultErrRequeuePeriod,
+	)
+	if result, err = final.HandleFinalizer(ctx, instance, instance.Status.ID, datadogDowntimeFinalizer); ctrutils.ShouldReturn(result, err) {
+		return result, err
+	}
+	if !instance.GetDeletionTimestamp().IsZero() {
+		// The downtimes are canceled, nothing left to sync
+		return result, nil
+	}
+
+	status := instance.Status.DeepCopy()
+
+	// Validate the DatadogDowntime spec
+	if err = v1alpha1.IsValidDatadogDowntime(&instance.Spec); err != nil {
+		logger.Error(err, "invalid DatadogDowntime spec")
+		updateErrStatus(status, now, v1alpha1.DatadogDowntimeSyncStatusValidateError, "ValidatingDowntime", err)
+		return r.updateStatusIfNeeded(logger, instance, status, result)
+	}
+
+	// The downtime applies to the monitors of the selected DatadogMonitors, one downtime per monitor
+	var monitorIDs []int
+	var skippedMonitors []string
+	selectsDatadogMonitors := instance.Spec.MonitorSelector != nil && instance.Spec.MonitorSelector.DatadogMonitorSelector != nil
+	if selectsDatadogMonitors {
+		if monitorIDs, skippedMonitors, err = r.selectedMonitorIDs(ctx, instance); err != nil {
+			logger.Error(err, "error selecting DatadogMonitors")
+			updateErrStatus(status, now, v1alpha1.DatadogDowntimeSyncStatusSelectError, "SelectingMonitors", err)
+			result.RequeueAfter = defaultErrRequeuePeriod
+			return r.updateStatusIfNeeded(logger, instance, status, result)
+		}
+	}
+
+	instanceSpecHash, err := comparison.GenerateMD5ForSpec(&instance.Spec)
+	if err != nil {
+		logger.Error(err, "error generating hash")
+		updateErrStatus(status, now, v1alpha1.DatadogDowntimeSyncStatusSyncError, "GeneratingDowntimeSpecHash", err)
+		return r.updateStatusIfNeeded(logger, instance, status, result)
+	}
+
+	// Update the downtimes when the spec has changed, and periodically to ensure parity with the API
+	forceSync := status.LastForceSyncTime == nil || (defaultForceSyncPeriod-now.Sub(status.LastForceSyncTime.Time)) <= 0
+	shouldUpdate := instanceSpecHash != status.CurrentHash || forceSync
+
+	var errs []error
+	var active bool
+	if !selectsDatadogMonitors {
+		var downtimeErr error
+		status.ID, active, downtimeErr = r.syncDowntime(logger, instance, status, now, status.ID, 0, shouldUpdate)
+		errs = append(errs, downtimeErr)
+	} else if status.ID != "" {
+		// The downtime was replaced by the downtimes of the selected monitors
+		if downtimeErr := r.cancel(logger, status.ID); downtimeErr != nil {
+			errs = append(errs, downtimeErr)
+		} else {
+			status.ID = ""
+		}
+	}
+	monitorsActive, monitorsErr := r.syncMonitorDowntimes(logger, instance, status, now, monitorIDs, shouldUpdate)
+	errs = append(errs, monitorsErr)
+	if len(skippedMonitors) > 0 {
+		errs = append(errs, fmt.Errorf("the monitors of the DatadogMonitors %s are created with the credentials of a Secret, they can't be downtimed with the credentials of the operator", strings.Join(skippedMonitors, ", ")))
+	}
+
+	if err = utilserrors.NewAggregate(errs); err != nil {
+		logger.Error(err, "error syncing downtime")
+		updateErrStatus(status, now, v1alpha1.DatadogDowntimeSyncStatusSyncError, "SyncingDowntime", err)
+		result.RequeueAfter = defaultErrRequeuePeriod
+		// Retry when the rate limits of the Datadog API endpoints are reset
+		if retryAfter, throttled := rateLimitRetryAfter(errs); throttled {
+			result.RequeueAfter = retryAfter
+		}
+		return r.updateStatusIfNeeded(logger, instance, status, result)
+	}
+
+	meta.RemoveStatusCondition(&status.Conditions, string(condition.DatadogConditionTypeError))
+	status.Active = active || monitorsActive
+	status.SyncStatus = v1alpha1.DatadogDowntimeSyncStatusOK
+	status.CurrentHash = instanceSpecHash
+	if forceSync {
+		status.LastForceSyncTime = &now
+	}
+
+	// Requeue to keep the active state of the downtime up to date
+	result.RequeueAfter = defaultRequeuePeriod
+
+	return r.updateStatusIfNeeded(logger, instance, status, result)
+}
+
+// selectedMonitorIDs returns the sorted IDs of the monitors of the DatadogMonitors selected by a DatadogDowntime.
+// The DatadogMonitors whose monitor isn't created yet are ignored. The DatadogMonitors whose monitor was created with
+// the credentials of a Secret are skipped, and their names are returned: the downtimes are created with the
+// credentials of the operator, which may belong to another organization.
+func (r *Reconciler) selectedMonitorIDs(ctx context.Context, instance *v1alpha1.DatadogDowntime) ([]int, []string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(instance.Spec.MonitorSelector.DatadogMonitorSelector)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	monitorList := &v1alpha1.DatadogMonitorList{}
+	if err = r.client.List(ctx, monitorList, client.InNamespace(instance.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, nil, err
+	}
+
+	var monitorIDs []int
+	var skipped []string
+	for _, monitor := range monitorList.Items {
+		if monitor.Status.ID == 0 {
+			continue
+		}
+		if monitor.Status.Credentials != nil && monitor.Status.Credentials.SecretName != "" {
+			skipped = append(skipped, monitor.Name)
+			continue
+		}
+		monitorIDs = append(monitorIDs, monitor.Status.ID)
+	}
+	sort.Ints(monitorIDs)
+	sort.Strings(skipped)
+
+	return monitorIDs, skipped, nil
+}
+
+// syncMonitorDowntimes creates, updates or refreshes the downtimes of the selected monitors, and cancels the
+// downtimes of the monitors that aren't selected anymore. It returns true if one of the downtimes is active.
+func (r *Reconciler) syncMonitorDowntimes(logger logr.Logger, instance *v1alpha1.DatadogDowntime, status *v1alpha1.DatadogDowntimeStatus, now metav1.Time, monitorIDs []int, shouldUpdate bool) (bool, error) {
+	selected := make(map[int]bool, len(monitorIDs))
+	for _, monitorID := range monitorIDs {
+		selected[monitorID] = true
+	}
+
+	var downtimes []v1alpha1.DatadogDowntimeMonitorDowntime
+	var errs []error
+	existing := make(map[int]string, len(status.MonitorDowntimes))
+	for _, downtime := range status.MonitorDowntimes {
+		if selected[downtime.MonitorID] {
+			existing[downtime.MonitorID] = downtime.ID
+			continue
+		}
+		if err := r.cancel(logger, downtime.ID); err != nil {
+			// Keep the downtime to retry later
+			errs = append(errs, err)
+			downtimes = append(downtimes, downtime)
+		}
+	}
+
+	active := false
+	for _, monitorID := range monitorIDs {
+		downtimeID, downtimeActive, err := r.syncDowntime(logger, instance, status, now, existing[monitorID], monitorID, shouldUpdate)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if downtimeID != "" {
+			downtimes = append(downtimes, v1alpha1.DatadogDowntimeMonitorDowntime{MonitorID: monitorID, ID: downtimeID})
+		}
+		active = active || downtimeActive
+	}
+
+	sort.Slice(downtimes, func(i, j int) bool {
+		return downtimes[i].MonitorID < downtimes[j].MonitorID
+	})
+	status.MonitorDowntimes = downtimes
+
+	return active, utilserrors.NewAggregate(errs)
+}
+
+// syncDowntime creates a downtime in Datadog if it doesn't exist yet, updates it if shouldUpdate is true,
+// or gets it to refresh its active state. It returns the ID of the downtime and whether it is active.
+func (r *Reconciler) syncDowntime(logger logr.Logger, instance *v1alpha1.DatadogDowntime, status *v1alpha1.DatadogDowntimeStatus, now metav1.Time, downtimeID string, monitorID int, shouldUpdate bool) (string, bool, error) {
+	if downtimeID != "" && !shouldUpdate {
+		downtime, err := getDowntime(r.datadogAuth, r.datadogClient, downtimeID)
+		if err == nil && !isCanceled(downtime) {
+			return downtimeID, isActive(downtime), nil
+		}
+		if err != nil && !strings.Contains(err.Error(), ctrutils.NotFoundString) {
+			return downtimeID, false, err
+		}
+		// The downtime was deleted or canceled in Datadog, create it again
+		logger.Info("Downtime not found or canceled in Datadog, creating it again", "Downtime ID", downtimeID)
+		downtimeID = ""
+	}
+
+	if downtimeID == "" {
+		downtime, err := createDowntime(r.datadogAuth, r.datadogClient, buildDowntime(instance, monitorID))
+		if err != nil {
+			return "", false, err
+		}
+		condition.UpdateStatusConditions(&status.Conditions, now, condition.DatadogConditionTypeCreated, metav1.ConditionTrue, "CreatingDowntime", "DatadogDowntime Created")
+		logger.Info("Created a new downtime", "Downtime ID", downtime.GetId(), "Monitor ID", monitorID)
+		r.recordEvent(instance, buildEventInfo(instance.Name, instance.Namespace, datadog.CreationEvent))
+		return downtime.GetId(), isActive(downtime), nil
+	}
+
+	downtime, err := updateDowntime(r.datadogAuth, r.datadogClient, downtimeID, buildDowntime(instance, monitorID))
+	if err != nil {
+		if strings.Contains(err.Error(), ctrutils.NotFoundString) {
+			// The downtime was deleted in Datadog, create it during the next reconcile
+			return "", false, err
+		}
+		return downtimeID, false, err
+	}
+	condition.UpdateStatusConditions(&status.Conditions, now, condition.DatadogConditionTypeUpdated, metav1.ConditionTrue, "UpdatingDowntime", "DatadogDowntime Updated")
+	logger.V(1).Info("Updated downtime", "Downtime ID", downtimeID, "Monitor ID", monitorID)
+	r.recordEvent(instance, buildEventInfo(instance.Name, instance.Namespace, datadog.UpdateEvent))
+	return downtimeID, isActive(downtime), nil
+}
+
+// cancel cancels a downtime in Datadog, a downtime that doesn't exist anymore is considered canceled.
+func (r *Reconciler) cancel(logger logr.Logger, downtimeID string) error {
+	if err := cancelDowntime(r.datadogAuth, r.datadogClient, downtimeID); err != nil && !strings.Contains(err.Error(), ctrutils.NotFoundString) {
+		logger.Error(err, "error canceling downtime", "Downtime ID", downtimeID)
+		return err
+	}
+	logger.Info("Canceled downtime", "Downtime ID", downtimeID)
+	return nil
+}
+
+func updateErrStatus(status *v1alpha1.DatadogDowntimeStatus, now metav1.Time, syncStatus v1alpha1.DatadogDowntimeSyncStatus, reason string, err error) {
+	condition.UpdateFailureStatusConditions(&status.Conditions, now, condition.DatadogConditionTypeError, reason, err)
+	status.SyncStatus = syncStatus
+}
+
+// rateLimitRetryAfter returns the longest time until the rate limits reached by errs are reset, and false if none was reached.
+func rateLimitRetryAfter(errs []error) (time.Duration, bool) {
+	aggregate := utilserrors.NewAggregate(errs)
+	if aggregate == nil {
+		return 0, false
+	}
+
+	var longest time.Duration
+	throttled := false
+	for _, err := range utilserrors.Flatten(aggregate).Errors() {
+		if retryAfter, isRateLimit := datadogclient.RetryAfter(err); isRateLimit {
+			throttled = true
+			if retryAfter > longest {
+				longest = retryAfter
+			}
+		}
+	}
+	return longest, throttled
+}
+
+func (r *Reconciler) updateStatusIfNeeded(logger logr.Logger, instance *v1alpha1.DatadogDowntime, status *v1alpha1.DatadogDowntimeStatus, result ctrl.Result) (ctrl.Result, error) {
+	if !apiequality.Semantic.DeepEqual(&instance.Status, status) {
+		instance.Status = *status
+		if err := r.client.Status().Update(context.TODO(), instance); err != nil {
+			if apierrors.IsConflict(err) {
+				logger.Error(err, "unable to update DatadogDowntime status due to update conflict")
+				return ctrl.Result{RequeueAfter: defaultErrRequeuePeriod}, nil
+			}
+			logger.Error(err, "unable to update DatadogDowntime status")
+			return ctrl.Result{RequeueAfter: defaultRequeuePeriod}, err
+		}
+	}
+	return result, nil
+}
+
+func (r *Reconciler) deleteResource(logger logr.Logger, instance *v1alpha1.DatadogDowntime) finalizer.ResourceDeleteFunc {
+	return func(ctx context.Context, k8sObj client.Object, datadogID string) error {
+		var errs []error
+		if instance.Status.ID != "" {
+			errs = append(errs, r.cancel(logger, instance.Status.ID))
+		}
+		for _, downtime := range instance.Status.MonitorDowntimes {
+			errs = append(errs, r.cancel(logger, downtime.ID))
+		}
+		if err := utilserrors.NewAggregate(errs); err != nil {
+			return err
+		}
+		r.recordEvent(instance, buildEventInfo(k8sObj.GetName(), k8sObj.GetNamespace(), datadog.DeletionEvent))
+		return nil
+	}
+}
+
+// buildEventInfo creates a new EventInfo instance.
+func buildEventInfo(name, ns string, eventType datadog.EventType) utils.EventInfo {
+	return utils.BuildEventInfo(name, ns, datadogDowntimeKind, eventType)
+}
+
+// recordEvent wraps the manager event recorder.
+func (r *Reconciler) recordEvent(downtime runtime.Object, info utils.EventInfo) {
+	r.recorder.Event(downtime, corev1.EventTypeNormal, info.GetReason(), info.GetMessage())
+}
@@ -0,0 +1,433 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogdowntime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	datadogapi "github.com/DataDog/datadog-api-client-go/v2/api/datadog"
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/condition"
+)
+
+const (
+	resourceNamespace = "default"
+	resourceName      = "downtime"
+)
+
+// fakeDowntimesAPI is a minimal Datadog downtimes API keeping the downtimes in memory.
+type fakeDowntimesAPI struct {
+	mutex     sync.Mutex
+	nextID    int
+	downtimes map[string]datadogV2.DowntimeResponseData
+	canceled  []string
+	fail      bool
+}
+
+func newFakeDowntimesAPI() *fakeDowntimesAPI {
+	return &fakeDowntimesAPI{nextID: 100, downtimes: map[string]datadogV2.DowntimeResponseData{}}
+}
+
+// fakeDowntimeID returns the UUID of the nth downtime created by the fake API.
+func fakeDowntimeID(n int) string {
+	return fmt.Sprintf("00000000-0000-0000-0000-%012d", n)
+}
+
+func (f *fakeDowntimesAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if f.fail {
+		http.Error(w, "invalid data", http.StatusBadRequest)
+		return
+	}
+
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/v2/downtime"), "/")
+
+	switch r.Method {
+	case http.MethodPost:
+		request := datadogV2.DowntimeCreateRequest{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		id = fakeDowntimeID(f.nextID)
+		f.nextID++
+		attributes := request.Data.Attributes
+		downtime := f.save(id, attributes.Scope, attributes.GetMessage(), attributes.MonitorIdentifier)
+		_ = json.NewEncoder(w).Encode(datadogV2.DowntimeResponse{Data: &downtime})
+	case http.MethodPatch:
+		if _, found := f.downtimes[id]; !found {
+			http.Error(w, "{}", http.StatusNotFound)
+			return
+		}
+		request := datadogV2.DowntimeUpdateRequest{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		attributes := request.Data.Attributes
+		downtime := f.save(id, attributes.GetScope(), attributes.GetMessage(), attributes.GetMonitorIdentifier())
+		_ = json.NewEncoder(w).Encode(datadogV2.DowntimeResponse{Data: &downtime})
+	case http.MethodGet:
+		downtime, found := f.downtimes[id]
+		if !found {
+			http.Error(w, "{}", http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(datadogV2.DowntimeResponse{Data: &downtime})
+	case http.MethodDelete:
+		if downtime, found := f.downtimes[id]; found {
+			downtime.Attributes.SetStatus(datadogV2.DOWNTIMESTATUS_CANCELED)
+			f.downtimes[id] = downtime
+		}
+		f.canceled = append(f.canceled, id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (f *fakeDowntimesAPI) save(id, scope, message string, monitorIdentifier datadogV2.DowntimeMonitorIdentifier) datadogV2.DowntimeResponseData {
+	attributes := datadogV2.NewDowntimeResponseAttributes()
+	attributes.SetScope(scope)
+	attributes.SetMessage(message)
+	attributes.SetMonitorIdentifier(monitorIdentifier)
+	attributes.SetStatus(datadogV2.DOWNTIMESTATUS_ACTIVE)
+
+	downtime := datadogV2.NewDowntimeResponseData()
+	downtime.SetId(id)
+	downtime.SetAttributes(*attributes)
+	f.downtimes[id] = *downtime
+	return *downtime
+}
+
+func (f *fakeDowntimesAPI) monitorIDs() []int64 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var ids []int64
+	for _, downtime := range f.downtimes {
+		if monitor := downtime.Attributes.MonitorIdentifier.DowntimeMonitorIdentifierId; monitor != nil && !isCanceled(downtime) {
+			ids = append(ids, monitor.MonitorId)
+		}
+	}
+	return ids
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(v1alpha1.GroupVersion, &v1alpha1.DatadogDowntime{}, &v1alpha1.DatadogDowntimeList{}, &v1alpha1.DatadogMonitor{}, &v1alpha1.DatadogMonitorList{})
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: resourceNamespace, Name: resourceName}}
+
+	datadogMonitor := func(name string, id int, labels map[string]string) *v1alpha1.DatadogMonitor {
+		return &v1alpha1.DatadogMonitor{
+			ObjectMeta: metav1.ObjectMeta{Namespace: resourceNamespace, Name: name, Labels: labels},
+			Status:     v1alpha1.DatadogMonitorStatus{ID: id},
+		}
+	}
+	downtimeSelectingMonitors := func() *v1alpha1.DatadogDowntime {
+		downtime := defaultDowntime()
+		downtime.Spec.MonitorSelector = &v1alpha1.DatadogDowntimeMonitorSelector{
+			DatadogMonitorSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "foo"}},
+		}
+		return downtime
+	}
+
+	tests := []struct {
+		name           string
+		objects        []client.Object
+		setup          func(api *fakeDowntimesAPI)
+		fail           bool
+		expectedResult ctrl.Result
+		check          func(t *testing.T, c client.Client, api *fakeDowntimesAPI)
+	}{
+		{
+			name:           "DatadogDowntime not found",
+			expectedResult: ctrl.Result{},
+		},
+		{
+			name:           "Create downtime",
+			objects:        []client.Object{defaultDowntime()},
+			expectedResult: ctrl.Result{RequeueAfter: defaultRequeuePeriod},
+			check: func(t *testing.T, c client.Client, api *fakeDowntimesAPI) {
+				downtime := getDowntimeObject(t, c)
+				assert.Equal(t, fakeDowntimeID(100), downtime.Status.ID)
+				assert.True(t, downtime.Status.Active)
+				assert.Equal(t, v1alpha1.DatadogDowntimeSyncStatusOK, downtime.Status.SyncStatus)
+				assert.NotEmpty(t, downtime.Status.CurrentHash)
+				assert.Contains(t, downtime.Finalizers, datadogDowntimeFinalizer)
+				attributes := api.downtimes[fakeDowntimeID(100)].Attributes
+				assert.Equal(t, "env:staging", attributes.GetScope())
+				assert.Equal(t, "maintenance", attributes.GetMessage())
+			},
+		},
+		{
+			name: "Downtime unchanged, its state is refreshed",
+			objects: []client.Object{func() *v1alpha1.DatadogDowntime {
+				downtime := defaultDowntime()
+				downtime.Status.ID = fakeDowntimeID(42)
+				downtime.Status.CurrentHash = "unknown"
+				return downtime
+			}()},
+			expectedResult: ctrl.Result{RequeueAfter: defaultRequeuePeriod},
+			check: func(t *testing.T, c client.Client, api *fakeDowntimesAPI) {
+				// The downtime didn't exist anymore in Datadog, it was created again
+				downtime := getDowntimeObject(t, c)
+				assert.Equal(t, fakeDowntimeID(100), downtime.Status.ID)
+				assert.Len(t, api.downtimes, 1)
+			},
+		},
+		{
+			name: "Downtime canceled in Datadog is created again",
+			objects: []client.Object{func() *v1alpha1.DatadogDowntime {
+				downtime := defaultDowntime()
+				hash, _ := comparison.GenerateMD5ForSpec(&downtime.Spec)
+				now := metav1.Now()
+				downtime.Status.ID = fakeDowntimeID(42)
+				downtime.Status.CurrentHash = hash
+				downtime.Status.LastForceSyncTime = &now
+				return downtime
+			}()},
+			setup: func(api *fakeDowntimesAPI) {
+				monitorIdentifier := datadogV2.DowntimeMonitorIdentifierTagsAsDowntimeMonitorIdentifier(datadogV2.NewDowntimeMonitorIdentifierTags([]string{"*"}))
+				downtime := api.save(fakeDowntimeID(42), "env:staging", "maintenance", monitorIdentifier)
+				downtime.Attributes.SetStatus(datadogV2.DOWNTIMESTATUS_CANCELED)
+				api.downtimes[fakeDowntimeID(42)] = downtime
+			},
+			expectedResult: ctrl.Result{RequeueAfter: defaultRequeuePeriod},
+			check: func(t *testing.T, c client.Client, api *fakeDowntimesAPI) {
+				downtime := getDowntimeObject(t, c)
+				assert.Equal(t, fakeDowntimeID(100), downtime.Status.ID)
+				assert.True(t, downtime.Status.Active)
+			},
+		},
+		{
+			name: "Invalid spec",
+			objects: []client.Object{func() *v1alpha1.DatadogDowntime {
+				downtime := defaultDowntime()
+				downtime.Spec.MonitorSelector = &v1alpha1.DatadogDowntimeMonitorSelector{ID: 1, Tags: []string{"team:foo"}}
+				return downtime
+			}()},
+			expectedResult: ctrl.Result{},
+			check: func(t *testing.T, c client.Client, api *fakeDowntimesAPI) {
+				downtime := getDowntimeObject(t, c)
+				assert.Equal(t, v1alpha1.DatadogDowntimeSyncStatusValidateError, downtime.Status.SyncStatus)
+				assert.Equal(t, string(condition.DatadogConditionTypeError), downtime.Status.Conditions[0].Type)
+				assert.Empty(t, api.downtimes)
+			},
+		},
+		{
+			name:           "Error creating downtime",
+			objects:        []client.Object{defaultDowntime()},
+			fail:           true,
+			expectedResult: ctrl.Result{RequeueAfter: defaultErrRequeuePeriod},
+			check: func(t *testing.T, c client.Client, api *fakeDowntimesAPI) {
+				downtime := getDowntimeObject(t, c)
+				assert.Empty(t, downtime.Status.ID)
+				assert.Equal(t, v1alpha1.DatadogDowntimeSyncStatusSyncError, downtime.Status.SyncStatus)
+			},
+		},
+		{
+			name: "One downtime per selected DatadogMonitor",
+			objects: []client.Object{
+				downtimeSelectingMonitors(),
+				datadogMonitor("monitor-a", 12, map[string]string{"team": "foo"}),
+				datadogMonitor("monitor-b", 34, map[string]string{"team": "foo"}),
+				datadogMonitor("monitor-c", 56, map[string]string{"team": "bar"}),
+				datadogMonitor("monitor-d", 0, map[string]string{"team": "foo"}),
+			},
+			expectedResult: ctrl.Result{RequeueAfter: defaultRequeuePeriod},
+			check: func(t *testing.T, c client.Client, api *fakeDowntimesAPI) {
+				downtime := getDowntimeObject(t, c)
+				assert.Empty(t, downtime.Status.ID)
+				assert.Equal(t, []v1alpha1.DatadogDowntimeMonitorDowntime{
+					{MonitorID: 12, ID: fakeDowntimeID(100)},
+					{MonitorID: 34, ID: fakeDowntimeID(101)},
+				}, downtime.Status.MonitorDowntimes)
+				assert.True(t, downtime.Status.Active)
+				assert.ElementsMatch(t, []int64{12, 34}, api.monitorIDs())
+			},
+		},
+		{
+			name: "DatadogMonitor created with the credentials of a Secret is skipped",
+			objects: []client.Object{
+				downtimeSelectingMonitors(),
+				datadogMonitor("monitor-a", 12, map[string]string{"team": "foo"}),
+				func() *v1alpha1.DatadogMonitor {
+					monitor := datadogMonitor("monitor-b", 34, map[string]string{"team": "foo"})
+					monitor.Status.Credentials = &v1alpha1.DatadogCredentialsStatus{SecretName: "team-credentials"}
+					return monitor
+				}(),
+			},
+			expectedResult: ctrl.Result{RequeueAfter: defaultErrRequeuePeriod},
+			check: func(t *testing.T, c client.Client, api *fakeDowntimesAPI) {
+				downtime := getDowntimeObject(t, c)
+				// The other DatadogMonitors are still downtimed
+				assert.Equal(t, []v1alpha1.DatadogDowntimeMonitorDowntime{{MonitorID: 12, ID: fakeDowntimeID(100)}}, downtime.Status.MonitorDowntimes)
+				assert.ElementsMatch(t, []int64{12}, api.monitorIDs())
+				assert.Equal(t, v1alpha1.DatadogDowntimeSyncStatusSyncError, downtime.Status.SyncStatus)
+				errorCondition := meta.FindStatusCondition(downtime.Status.Conditions, string(condition.DatadogConditionTypeError))
+				require.NotNil(t, errorCondition)
+				assert.Contains(t, errorCondition.Message, "the monitors of the DatadogMonitors monitor-b are created with the credentials of a Secret")
+			},
+		},
+		{
+			name: "Downtime of a DatadogMonitor that isn't selected anymore is canceled",
+			objects: []client.Object{
+				func() *v1alpha1.DatadogDowntime {
+					downtime := downtimeSelectingMonitors()
+					downtime.Status.MonitorDowntimes = []v1alpha1.DatadogDowntimeMonitorDowntime{{MonitorID: 78, ID: fakeDowntimeID(42)}}
+					return downtime
+				}(),
+				datadogMonitor("monitor-a", 12, map[string]string{"team": "foo"}),
+			},
+			expectedResult: ctrl.Result{RequeueAfter: defaultRequeuePeriod},
+			check: func(t *testing.T, c client.Client, api *fakeDowntimesAPI) {
+				downtime := getDowntimeObject(t, c)
+				assert.Equal(t, []v1alpha1.DatadogDowntimeMonitorDowntime{{MonitorID: 12, ID: fakeDowntimeID(100)}}, downtime.Status.MonitorDowntimes)
+				assert.Equal(t, []string{fakeDowntimeID(42)}, api.canceled)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newFakeDowntimesAPI()
+			api.fail = tt.fail
+			if tt.setup != nil {
+				tt.setup(api)
+			}
+			httpServer := httptest.NewServer(api)
+			defer httpServer.Close()
+
+			testConfig := datadogapi.NewConfiguration()
+			testConfig.HTTPClient = httpServer.Client()
+			apiClient := datadogapi.NewAPIClient(testConfig)
+			ddClient := datadogV2.NewDowntimesApi(apiClient)
+
+			k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(tt.objects...).Build()
+			r := &Reconciler{
+				client:        k8sClient,
+				datadogClient: ddClient,
+				datadogAuth:   setupTestAuth(httpServer.URL),
+				recorder:      record.NewFakeRecorder(10),
+				log:           zap.New(zap.UseDevMode(true)),
+				versionInfo:   &version.Info{},
+			}
+
+			// The first reconcile adds the finalizer
+			_, err := r.Reconcile(context.TODO(), request)
+			require.NoError(t, err)
+			res, err := r.Reconcile(context.TODO(), request)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, res)
+
+			if tt.check != nil {
+				tt.check(t, k8sClient, api)
+			}
+		})
+	}
+}
+
+func TestReconciler_ReconcileDeletion(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(v1alpha1.GroupVersion, &v1alpha1.DatadogDowntime{}, &v1alpha1.DatadogDowntimeList{})
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: resourceNamespace, Name: resourceName}}
+
+	api := newFakeDowntimesAPI()
+	httpServer := httptest.NewServer(api)
+	defer httpServer.Close()
+
+	testConfig := datadogapi.NewConfiguration()
+	testConfig.HTTPClient = httpServer.Client()
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(defaultDowntime()).Build()
+	r := &Reconciler{
+		client:        k8sClient,
+		datadogClient: datadogV2.NewDowntimesApi(datadogapi.NewAPIClient(testConfig)),
+		datadogAuth:   setupTestAuth(httpServer.URL),
+		recorder:      record.NewFakeRecorder(10),
+		log:           zap.New(zap.UseDevMode(true)),
+		versionInfo:   &version.Info{},
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := r.Reconcile(context.TODO(), request)
+		require.NoError(t, err)
+	}
+	require.Len(t, api.downtimes, 1)
+
+	// The downtime is canceled in Datadog before the DatadogDowntime is deleted
+	require.NoError(t, k8sClient.Delete(context.TODO(), getDowntimeObject(t, k8sClient)))
+	_, err := r.Reconcile(context.TODO(), request)
+	require.NoError(t, err)
+	assert.True(t, isCanceled(api.downtimes[fakeDowntimeID(100)]))
+	assert.Equal(t, []string{fakeDowntimeID(100)}, api.canceled)
+	assert.Error(t, k8sClient.Get(context.TODO(), request.NamespacedName, &v1alpha1.DatadogDowntime{}))
+}
+
+func getDowntimeObject(t *testing.T, c client.Client) *v1alpha1.DatadogDowntime {
+	downtime := &v1alpha1.DatadogDowntime{}
+	require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: resourceNamespace, Name: resourceName}, downtime))
+	return downtime
+}
+
+func defaultDowntime() *v1alpha1.DatadogDowntime {
+	return &v1alpha1.DatadogDowntime{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "DatadogDowntime",
+			APIVersion: fmt.Sprintf("%s/%s", v1alpha1.GroupVersion.Group, v1alpha1.GroupVersion.Version),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: resourceNamespace,
+			Name:      resourceName,
+		},
+		Spec: v1alpha1.DatadogDowntimeSpec{
+			Scope:   []string{"env:staging"},
+			Message: "maintenance",
+		},
+	}
+}
+
+func setupTestAuth(apiURL string) context.Context {
+	testAuth := context.WithValue(
+		context.Background(),
+		datadogapi.ContextAPIKeys,
+		map[string]datadogapi.APIKey{
+			"apiKeyAuth": {
+				Key: "DUMMY_API_KEY",
+			},
+			"appKeyAuth": {
+				Key: "DUMMY_APP_KEY",
+			},
+		},
+	)
+	parsedAPIURL, _ := url.Parse(apiURL)
+	testAuth = context.WithValue(testAuth, datadogapi.ContextServerIndex, 1)
+	testAuth = context.WithValue(testAuth, datadogapi.ContextServerVariables, map[string]string{
+		"name":     parsedAPIURL.Host,
+		"protocol": parsedAPIURL.Scheme,
+	})
+
+	return testAuth
+}
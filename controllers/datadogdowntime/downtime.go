@@ -0,0 +1,209 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogdowntime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+const (
+	// allScope is the scope of a downtime applying to all the sources and monitors.
+	allScope        = "*"
+	defaultTimezone = "UTC"
+
+	// recurrenceStartLayout is the layout of the start of a recurrence, a local time of the timezone of the schedule.
+	recurrenceStartLayout = "2006-01-02T15:04"
+	// rruleUntilLayout is the layout of the UNTIL part of a recurrence rule, a local time like the start.
+	rruleUntilLayout = "20060102T150405"
+)
+
+var recurrenceFrequencies = map[v1alpha1.DatadogDowntimeRecurrenceType]string{
+	v1alpha1.DatadogDowntimeRecurrenceTypeDays:   "DAILY",
+	v1alpha1.DatadogDowntimeRecurrenceTypeWeeks:  "WEEKLY",
+	v1alpha1.DatadogDowntimeRecurrenceTypeMonths: "MONTHLY",
+	v1alpha1.DatadogDowntimeRecurrenceTypeYears:  "YEARLY",
+}
+
+// buildDowntime returns the attributes of the Datadog downtime of a DatadogDowntime. When monitorID isn't 0,
+// the downtime only applies to this monitor.
+func buildDowntime(crdDowntime *v1alpha1.DatadogDowntime, monitorID int) datadogV2.DowntimeCreateRequestAttributes {
+	spec := crdDowntime.Spec
+
+	// The downtime applies to the sources matching all the scopes
+	scope := allScope
+	if len(spec.Scope) > 0 {
+		scope = strings.Join(spec.Scope, " AND ")
+	}
+
+	if spec.MonitorSelector != nil && spec.MonitorSelector.ID != 0 {
+		monitorID = int(spec.MonitorSelector.ID)
+	}
+	var monitorIdentifier datadogV2.DowntimeMonitorIdentifier
+	if monitorID != 0 {
+		monitorIdentifier = datadogV2.DowntimeMonitorIdentifierIdAsDowntimeMonitorIdentifier(datadogV2.NewDowntimeMonitorIdentifierId(int64(monitorID)))
+	} else if spec.MonitorSelector != nil && len(spec.MonitorSelector.Tags) > 0 {
+		monitorIdentifier = datadogV2.DowntimeMonitorIdentifierTagsAsDowntimeMonitorIdentifier(datadogV2.NewDowntimeMonitorIdentifierTags(spec.MonitorSelector.Tags))
+	} else {
+		monitorIdentifier = datadogV2.DowntimeMonitorIdentifierTagsAsDowntimeMonitorIdentifier(datadogV2.NewDowntimeMonitorIdentifierTags([]string{allScope}))
+	}
+
+	downtime := datadogV2.NewDowntimeCreateRequestAttributes(monitorIdentifier, scope)
+	downtime.SetMessage(spec.Message)
+
+	timezone := spec.Schedule.Timezone
+	if timezone == "" {
+		timezone = defaultTimezone
+	}
+	downtime.SetDisplayTimezone(timezone)
+	downtime.SetSchedule(buildSchedule(spec.Schedule, timezone))
+
+	return *downtime
+}
+
+// buildSchedule returns a one-time schedule, or a recurring schedule made of one RRULE-based recurrence.
+func buildSchedule(schedule v1alpha1.DatadogDowntimeSchedule, timezone string) datadogV2.DowntimeScheduleCreateRequest {
+	if schedule.Recurrence == nil {
+		oneTime := datadogV2.NewDowntimeScheduleOneTimeCreateUpdateRequest()
+		if schedule.Start != nil {
+			oneTime.SetStart(schedule.Start.UTC())
+		}
+		if schedule.End != nil {
+			oneTime.SetEnd(schedule.End.UTC())
+		} else {
+			oneTime.SetEndNil()
+		}
+		return datadogV2.DowntimeScheduleOneTimeCreateUpdateRequestAsDowntimeScheduleCreateRequest(oneTime)
+	}
+
+	// The timezone is validated with the spec
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	// The validation of the spec ensures a recurring downtime has a start and an end
+	recurrence := datadogV2.NewDowntimeScheduleRecurrenceCreateUpdateRequest(
+		formatDuration(schedule.End.Sub(schedule.Start.Time)),
+		buildRRule(schedule.Recurrence, location),
+	)
+	recurrence.SetStart(schedule.Start.In(location).Format(recurrenceStartLayout))
+
+	recurrences := datadogV2.NewDowntimeScheduleRecurrencesCreateRequest([]datadogV2.DowntimeScheduleRecurrenceCreateUpdateRequest{*recurrence})
+	recurrences.SetTimezone(timezone)
+	return datadogV2.DowntimeScheduleRecurrencesCreateRequestAsDowntimeScheduleCreateRequest(recurrences)
+}
+
+// buildRRule returns the recurrence rule of a recurrence: the rule of the rrule type, or the rule equivalent to
+// the period, week days and end of the other types.
+func buildRRule(crdRecurrence *v1alpha1.DatadogDowntimeRecurrence, location *time.Location) string {
+	if crdRecurrence.Type == v1alpha1.DatadogDowntimeRecurrenceTypeRRule {
+		return crdRecurrence.RRule
+	}
+
+	parts := []string{
+		"FREQ=" + recurrenceFrequencies[crdRecurrence.Type],
+		fmt.Sprintf("INTERVAL=%d", crdRecurrence.Period),
+	}
+	if len(crdRecurrence.WeekDays) > 0 {
+		days := make([]string, 0, len(crdRecurrence.WeekDays))
+		for _, day := range crdRecurrence.WeekDays {
+			days = append(days, strings.ToUpper(day[:2]))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if crdRecurrence.UntilDate != nil {
+		parts = append(parts, "UNTIL="+crdRecurrence.UntilDate.In(location).Format(rruleUntilLayout))
+	}
+	if crdRecurrence.UntilOccurrences != nil {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", *crdRecurrence.UntilOccurrences))
+	}
+	return strings.Join(parts, ";")
+}
+
+// formatDuration returns a duration in the format of the API, in minutes, or hours when it is a whole number of hours.
+func formatDuration(duration time.Duration) string {
+	if duration%time.Hour == 0 {
+		return fmt.Sprintf("%dh", int64(duration/time.Hour))
+	}
+	return fmt.Sprintf("%dm", int64(duration/time.Minute))
+}
+
+// buildDowntimeUpdate returns the update request of a downtime from the attributes built for its creation.
+func buildDowntimeUpdate(downtimeID string, downtime datadogV2.DowntimeCreateRequestAttributes) datadogV2.DowntimeUpdateRequest {
+	attributes := datadogV2.NewDowntimeUpdateRequestAttributes()
+	attributes.SetScope(downtime.Scope)
+	attributes.SetMonitorIdentifier(downtime.MonitorIdentifier)
+	attributes.SetMessage(downtime.GetMessage())
+	attributes.SetDisplayTimezone(downtime.GetDisplayTimezone())
+
+	schedule := downtime.GetSchedule()
+	if recurrences := schedule.DowntimeScheduleRecurrencesCreateRequest; recurrences != nil {
+		update := datadogV2.NewDowntimeScheduleRecurrencesUpdateRequest()
+		update.SetRecurrences(recurrences.Recurrences)
+		update.SetTimezone(recurrences.GetTimezone())
+		attributes.SetSchedule(datadogV2.DowntimeScheduleRecurrencesUpdateRequestAsDowntimeScheduleUpdateRequest(update))
+	} else {
+		attributes.SetSchedule(datadogV2.DowntimeScheduleOneTimeCreateUpdateRequestAsDowntimeScheduleUpdateRequest(schedule.DowntimeScheduleOneTimeCreateUpdateRequest))
+	}
+
+	return *datadogV2.NewDowntimeUpdateRequest(*datadogV2.NewDowntimeUpdateRequestData(*attributes, downtimeID, datadogV2.DOWNTIMERESOURCETYPE_DOWNTIME))
+}
+
+// isActive returns true if the downtime, or the current occurrence of a recurring downtime, is in effect.
+func isActive(downtime datadogV2.DowntimeResponseData) bool {
+	attributes := downtime.GetAttributes()
+	return attributes.GetStatus() == datadogV2.DOWNTIMESTATUS_ACTIVE
+}
+
+// isCanceled returns true if the downtime was canceled, in Datadog or by the operator.
+func isCanceled(downtime datadogV2.DowntimeResponseData) bool {
+	attributes := downtime.GetAttributes()
+	return attributes.GetStatus() == datadogV2.DOWNTIMESTATUS_CANCELED
+}
+
+func createDowntime(auth context.Context, client *datadogV2.DowntimesApi, downtime datadogV2.DowntimeCreateRequestAttributes) (datadogV2.DowntimeResponseData, error) {
+	body := datadogV2.NewDowntimeCreateRequest(*datadogV2.NewDowntimeCreateRequestData(downtime, datadogV2.DOWNTIMERESOURCETYPE_DOWNTIME))
+	created, _, err := client.CreateDowntime(auth, *body)
+	if err != nil {
+		return datadogV2.DowntimeResponseData{}, datadogclient.TranslateClientError(err, "error creating downtime")
+	}
+
+	return created.GetData(), nil
+}
+
+func getDowntime(auth context.Context, client *datadogV2.DowntimesApi, downtimeID string) (datadogV2.DowntimeResponseData, error) {
+	downtime, _, err := client.GetDowntime(auth, downtimeID)
+	if err != nil {
+		return datadogV2.DowntimeResponseData{}, datadogclient.TranslateClientError(err, "error getting downtime")
+	}
+
+	return downtime.GetData(), nil
+}
+
+func updateDowntime(auth context.Context, client *datadogV2.DowntimesApi, downtimeID string, downtime datadogV2.DowntimeCreateRequestAttributes) (datadogV2.DowntimeResponseData, error) {
+	updated, _, err := client.UpdateDowntime(auth, downtimeID, buildDowntimeUpdate(downtimeID, downtime))
+	if err != nil {
+		return datadogV2.DowntimeResponseData{}, datadogclient.TranslateClientError(err, "error updating downtime")
+	}
+
+	return updated.GetData(), nil
+}
+
+func cancelDowntime(auth context.Context, client *datadogV2.DowntimesApi, downtimeID string) error {
+	if _, err := client.CancelDowntime(auth, downtimeID); err != nil {
+		return datadogclient.TranslateClientError(err, "error canceling downtime")
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogdowntime
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+func Test_buildDowntime(t *testing.T) {
+	start := metav1.NewTime(time.Date(2023, 6, 3, 1, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(2 * time.Hour))
+	untilDate := metav1.NewTime(time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC))
+	occurrences := int32(10)
+
+	tests := []struct {
+		name      string
+		spec      v1alpha1.DatadogDowntimeSpec
+		monitorID int
+		expected  string
+	}{
+		{
+			name: "Default downtime of all the monitors starting now",
+			spec: v1alpha1.DatadogDowntimeSpec{},
+			expected: `{
+				"display_timezone": "UTC",
+				"message": "",
+				"monitor_identifier": {"monitor_tags": ["*"]},
+				"schedule": {"end": null},
+				"scope": "*"
+			}`,
+		},
+		{
+			name: "One-off downtime of a monitor",
+			spec: v1alpha1.DatadogDowntimeSpec{
+				Scope:           []string{"env:staging", "service:example"},
+				MonitorSelector: &v1alpha1.DatadogDowntimeMonitorSelector{ID: 1234},
+				Message:         "maintenance",
+				Schedule:        v1alpha1.DatadogDowntimeSchedule{Start: &start, End: &end},
+			},
+			expected: `{
+				"display_timezone": "UTC",
+				"message": "maintenance",
+				"monitor_identifier": {"monitor_id": 1234},
+				"schedule": {"start": "2023-06-03T01:00:00Z", "end": "2023-06-03T03:00:00Z"},
+				"scope": "env:staging AND service:example"
+			}`,
+		},
+		{
+			name: "Downtime of the monitor of a DatadogMonitor",
+			spec: v1alpha1.DatadogDowntimeSpec{
+				MonitorSelector: &v1alpha1.DatadogDowntimeMonitorSelector{
+					DatadogMonitorSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "foo"}},
+				},
+			},
+			monitorID: 12,
+			expected: `{
+				"display_timezone": "UTC",
+				"message": "",
+				"monitor_identifier": {"monitor_id": 12},
+				"schedule": {"end": null},
+				"scope": "*"
+			}`,
+		},
+		{
+			name: "Weekly downtime in a timezone",
+			spec: v1alpha1.DatadogDowntimeSpec{
+				MonitorSelector: &v1alpha1.DatadogDowntimeMonitorSelector{Tags: []string{"team:example"}},
+				Schedule: v1alpha1.DatadogDowntimeSchedule{
+					Start:    &start,
+					End:      &end,
+					Timezone: "Europe/Paris",
+					Recurrence: &v1alpha1.DatadogDowntimeRecurrence{
+						Type:             v1alpha1.DatadogDowntimeRecurrenceTypeWeeks,
+						Period:           1,
+						WeekDays:         []string{"Sat", "Sun"},
+						UntilOccurrences: &occurrences,
+					},
+				},
+			},
+			expected: `{
+				"display_timezone": "Europe/Paris",
+				"message": "",
+				"monitor_identifier": {"monitor_tags": ["team:example"]},
+				"schedule": {
+					"recurrences": [{"duration": "2h", "rrule": "FREQ=WEEKLY;INTERVAL=1;BYDAY=SA,SU;COUNT=10", "start": "2023-06-03T03:00"}],
+					"timezone": "Europe/Paris"
+				},
+				"scope": "*"
+			}`,
+		},
+		{
+			name: "Downtime every 3 days until a date",
+			spec: v1alpha1.DatadogDowntimeSpec{
+				Schedule: v1alpha1.DatadogDowntimeSchedule{
+					Start: &start,
+					End:   &metav1.Time{Time: start.Add(90 * time.Minute)},
+					Recurrence: &v1alpha1.DatadogDowntimeRecurrence{
+						Type:      v1alpha1.DatadogDowntimeRecurrenceTypeDays,
+						Period:    3,
+						UntilDate: &untilDate,
+					},
+				},
+			},
+			expected: `{
+				"display_timezone": "UTC",
+				"message": "",
+				"monitor_identifier": {"monitor_tags": ["*"]},
+				"schedule": {
+					"recurrences": [{"duration": "90m", "rrule": "FREQ=DAILY;INTERVAL=3;UNTIL=20231231T000000", "start": "2023-06-03T01:00"}],
+					"timezone": "UTC"
+				},
+				"scope": "*"
+			}`,
+		},
+		{
+			name: "Downtime with a recurrence rule",
+			spec: v1alpha1.DatadogDowntimeSpec{
+				Schedule: v1alpha1.DatadogDowntimeSchedule{
+					Start: &start,
+					End:   &end,
+					Recurrence: &v1alpha1.DatadogDowntimeRecurrence{
+						Type:  v1alpha1.DatadogDowntimeRecurrenceTypeRRule,
+						RRule: "FREQ=MONTHLY;BYMONTHDAY=1",
+					},
+				},
+			},
+			expected: `{
+				"display_timezone": "UTC",
+				"message": "",
+				"monitor_identifier": {"monitor_tags": ["*"]},
+				"schedule": {
+					"recurrences": [{"duration": "2h", "rrule": "FREQ=MONTHLY;BYMONTHDAY=1", "start": "2023-06-03T01:00"}],
+					"timezone": "UTC"
+				},
+				"scope": "*"
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			downtime := buildDowntime(&v1alpha1.DatadogDowntime{Spec: tt.spec}, tt.monitorID)
+			actual, err := json.Marshal(downtime)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(actual))
+		})
+	}
+}
+
+func Test_buildDowntimeUpdate(t *testing.T) {
+	start := metav1.NewTime(time.Date(2023, 6, 3, 1, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(2 * time.Hour))
+
+	crdDowntime := &v1alpha1.DatadogDowntime{
+		Spec: v1alpha1.DatadogDowntimeSpec{
+			Scope:   []string{"env:staging"},
+			Message: "maintenance",
+			Schedule: v1alpha1.DatadogDowntimeSchedule{
+				Start: &start,
+				End:   &end,
+				Recurrence: &v1alpha1.DatadogDowntimeRecurrence{
+					Type:  v1alpha1.DatadogDowntimeRecurrenceTypeRRule,
+					RRule: "FREQ=MONTHLY;BYMONTHDAY=1",
+				},
+			},
+		},
+	}
+
+	update := buildDowntimeUpdate("00000000-0000-0000-0000-000000000001", buildDowntime(crdDowntime, 0))
+	actual, err := json.Marshal(update)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"data": {
+			"attributes": {
+				"display_timezone": "UTC",
+				"message": "maintenance",
+				"monitor_identifier": {"monitor_tags": ["*"]},
+				"schedule": {
+					"recurrences": [{"duration": "2h", "rrule": "FREQ=MONTHLY;BYMONTHDAY=1", "start": "2023-06-03T01:00"}],
+					"timezone": "UTC"
+				},
+				"scope": "env:staging"
+			},
+			"id": "00000000-0000-0000-0000-000000000001",
+			"type": "downtime"
+		}
+	}`, string(actual))
+}
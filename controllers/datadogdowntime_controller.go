@@ -0,0 +1,90 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogdowntime"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+// DatadogDowntimeReconciler reconciles a DatadogDowntime object.
+type DatadogDowntimeReconciler struct {
+	Client      client.Client
+	DDClient    datadogclient.DatadogDowntimeClient
+	VersionInfo *version.Info
+	Log         logr.Logger
+	Scheme      *runtime.Scheme
+	Recorder    record.EventRecorder
+	internal    *datadogdowntime.Reconciler
+}
+
+// +kubebuilder:rbac:groups=datadoghq.com,resources=datadogdowntimes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=datadoghq.com,resources=datadogdowntimes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=datadoghq.com,resources=datadogdowntimes/finalizers,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile loop for DatadogDowntime.
+func (r *DatadogDowntimeReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return r.internal.Reconcile(ctx, req)
+}
+
+// SetupWithManager creates a new DatadogDowntime controller.
+func (r *DatadogDowntimeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.internal = datadogdowntime.NewReconciler(r.Client, r.DDClient, r.VersionInfo, r.Log, r.Recorder)
+
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&datadoghqv1alpha1.DatadogDowntime{}).
+		// The downtimes can apply to the monitors of DatadogMonitors selected by labels
+		Watches(
+			&source.Kind{Type: &datadoghqv1alpha1.DatadogMonitor{}},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueRequestsForMonitorSelectors()),
+			ctrlbuilder.WithPredicates(predicate.Or(predicate.LabelChangedPredicate{}, monitorIDChangedPredicate())),
+		)
+
+	err := builder.Complete(r)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// enqueueRequestsForMonitorSelectors returns the DatadogDowntimes of the namespace of a DatadogMonitor that select DatadogMonitors by labels.
+// They are all enqueued, so that a DatadogMonitor that doesn't match a selector anymore is removed from its downtimes.
+func (r *DatadogDowntimeReconciler) enqueueRequestsForMonitorSelectors() handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		var requests []reconcile.Request
+
+		downtimeList := datadoghqv1alpha1.DatadogDowntimeList{}
+		if err := r.Client.List(context.Background(), &downtimeList, client.InNamespace(obj.GetNamespace())); err != nil {
+			return requests
+		}
+
+		for _, downtime := range downtimeList.Items {
+			if downtime.Spec.MonitorSelector != nil && downtime.Spec.MonitorSelector.DatadogMonitorSelector != nil {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: downtime.Namespace, Name: downtime.Name}})
+			}
+		}
+
+		return requests
+	}
+}
+
+var _ reconcile.Reconciler = (*DatadogDowntimeReconciler)(nil)
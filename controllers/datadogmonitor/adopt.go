@@ -0,0 +1,194 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	datadogV1 "github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/controllers/utils"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/condition"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+// adoptedMonitorID returns the ID of the existing monitor to adopt, defined by spec.adoptID or by the
+// adopt-id annotation. It returns 0 when the DatadogMonitor doesn't adopt a monitor.
+func adoptedMonitorID(dm *datadoghqv1alpha1.DatadogMonitor) (int, error) {
+	if dm.Spec.AdoptID != 0 {
+		return int(dm.Spec.AdoptID), nil
+	}
+
+	value, found := dm.GetAnnotations()[datadoghqv1alpha1.DatadogMonitorAdoptIDAnnotationKey]
+	if !found || value == "" {
+		return 0, nil
+	}
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("annotation %s must be a positive monitor ID, got %q", datadoghqv1alpha1.DatadogMonitorAdoptIDAnnotationKey, value)
+	}
+
+	return id, nil
+}
+
+// adoptionPending returns true while the DatadogMonitor keeps the monitor it adopted unmodified, until spec.adoptID
+// and the adopt-id annotation are removed.
+func adoptionPending(dm *datadoghqv1alpha1.DatadogMonitor, adoptID int) bool {
+	return adoptID != 0 && adoptID == dm.Status.ID
+}
+
+// adopt takes ownership of an existing monitor instead of creating a new one. The monitor isn't modified while the
+// adoption is pending: the differences with the spec are reported in the Adopted condition.
+func (r *Reconciler) adopt(ctx context.Context, ddClient datadogclient.DatadogMonitorClient, logger logr.Logger, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, status *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time, monitorID int, instanceSpecHash string) error {
+	adopter, err := r.otherAdopter(ctx, datadogMonitor, monitorID)
+	if err != nil {
+		return err
+	}
+	if adopter != "" {
+		return fmt.Errorf("monitor %d is already adopted by DatadogMonitor %s", monitorID, adopter)
+	}
+
+	m, err := getMonitor(ddClient.Auth, ddClient.Client, monitorID)
+	if err != nil {
+		status.MonitorStateSyncStatus = datadoghqv1alpha1.MonitorStateSyncStatusGetError
+		return err
+	}
+	event := buildEventInfo(datadogMonitor.Name, datadogMonitor.Namespace, datadog.AdoptionEvent)
+	r.recordEvent(datadogMonitor, event)
+
+	status.ID = monitorID
+	creator := m.GetCreator()
+	status.Creator = creator.GetEmail()
+	createdTime := metav1.NewTime(m.GetCreated())
+	status.Created = &createdTime
+	status.Primary = true
+	status.MonitorStateSyncStatus = ""
+
+	differences := reportAdoptionDifferences(logger, datadogMonitor, m, status, now, instanceSpecHash)
+	logger.Info("Adopted an existing monitor", "Monitor Namespace", datadogMonitor.Namespace, "Monitor Name", datadogMonitor.Name, "Monitor ID", monitorID, "Differences", differences)
+
+	return nil
+}
+
+// reportAdoptionDifferences sets the Adopted condition with the differences between the adopted monitor and the spec.
+// The status hash is only set when there is no difference, so that the monitor is updated once the adoption is
+// no longer pending.
+func reportAdoptionDifferences(logger logr.Logger, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, m datadogV1.Monitor, status *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time, instanceSpecHash string) []string {
+	var msg string
+	differences := monitorDifferences(logger, datadogMonitor, m)
+	if len(differences) == 0 {
+		status.CurrentHash = instanceSpecHash
+		msg = fmt.Sprintf("DatadogMonitor adopted monitor %d, it matches the spec", status.ID)
+	} else {
+		status.CurrentHash = ""
+		msg = fmt.Sprintf("DatadogMonitor adopted monitor %d, differences with the spec overwritten once adoptID is removed: %s", status.ID, strings.Join(differences, ", "))
+	}
+
+	// Set Adopted Condition
+	condition.UpdateDatadogMonitorConditions(status, now, datadoghqv1alpha1.DatadogMonitorConditionTypeAdopted, corev1.ConditionTrue, msg)
+
+	return differences
+}
+
+// otherAdopter returns the name of another DatadogMonitor that manages or adopts the given monitor. When several
+// DatadogMonitors adopt the same monitor, the oldest one takes precedence.
+func (r *Reconciler) otherAdopter(ctx context.Context, dm *datadoghqv1alpha1.DatadogMonitor, monitorID int) (string, error) {
+	datadogMonitors := &datadoghqv1alpha1.DatadogMonitorList{}
+	if err := r.client.List(ctx, datadogMonitors); err != nil {
+		return "", err
+	}
+
+	for i := range datadogMonitors.Items {
+		other := &datadogMonitors.Items[i]
+		if other.Namespace == dm.Namespace && other.Name == dm.Name {
+			continue
+		}
+		if other.Status.ID == monitorID {
+			return other.Namespace + "/" + other.Name, nil
+		}
+		if otherID, err := adoptedMonitorID(other); err != nil || otherID != monitorID || other.Status.ID != 0 {
+			continue
+		}
+		if adoptsFirst(other, dm) {
+			return other.Namespace + "/" + other.Name, nil
+		}
+	}
+
+	return "", nil
+}
+
+// adoptsFirst returns true if dm takes precedence over other to adopt a monitor.
+func adoptsFirst(dm, other *datadoghqv1alpha1.DatadogMonitor) bool {
+	if !dm.CreationTimestamp.Equal(&other.CreationTimestamp) {
+		return dm.CreationTimestamp.Before(&other.CreationTimestamp)
+	}
+	if dm.Namespace != other.Namespace {
+		return dm.Namespace < other.Namespace
+	}
+	return dm.Name < other.Name
+}
+
+// monitorDifferences returns the sorted list of the fields of a monitor that differ from the DatadogMonitor spec.
+// Only the options defined by the spec are compared.
+func monitorDifferences(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor, m datadogV1.Monitor) []string {
+	expected, _ := buildMonitor(logger, dm)
+	differences := []string{}
+
+	if expected.GetName() != m.GetName() {
+		differences = append(differences, "name")
+	}
+	if expected.GetMessage() != m.GetMessage() {
+		differences = append(differences, "message")
+	}
+	if expected.GetPriority() != m.GetPriority() {
+		differences = append(differences, "priority")
+	}
+	if expected.GetQuery() != m.GetQuery() {
+		differences = append(differences, "query")
+	}
+	if expected.GetType() != m.GetType() {
+		differences = append(differences, "type")
+	}
+	if !utils.SameStrings(expected.GetTags(), m.GetTags()) {
+		differences = append(differences, "tags")
+	}
+	if !utils.SameStrings(dm.Spec.RestrictedRoles, m.GetRestrictedRoles()) {
+		differences = append(differences, "restrictedRoles")
+	}
+
+	expectedOptions := optionsAsMap(expected.GetOptions())
+	actualOptions := optionsAsMap(m.GetOptions())
+	for name, value := range expectedOptions {
+		if !reflect.DeepEqual(value, actualOptions[name]) {
+			differences = append(differences, "options."+name)
+		}
+	}
+
+	sort.Strings(differences)
+	return differences
+}
+
+func optionsAsMap(options datadogV1.MonitorOptions) map[string]interface{} {
+	result := map[string]interface{}{}
+	data, err := json.Marshal(options)
+	if err != nil {
+		return result
+	}
+	_ = json.Unmarshal(data, &result)
+
+	return result
+}
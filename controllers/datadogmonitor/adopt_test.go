@@ -0,0 +1,263 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	datadogapi "github.com/DataDog/datadog-api-client-go/v2/api/datadog"
+	datadogV1 "github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+func Test_adoptedMonitorID(t *testing.T) {
+	tests := []struct {
+		name        string
+		adoptID     int64
+		annotations map[string]string
+		want        int
+		wantErr     string
+	}{
+		{
+			name: "no monitor to adopt",
+			want: 0,
+		},
+		{
+			name:    "spec.adoptID",
+			adoptID: 1234,
+			want:    1234,
+		},
+		{
+			name:        "annotation",
+			annotations: map[string]string{datadoghqv1alpha1.DatadogMonitorAdoptIDAnnotationKey: "5678"},
+			want:        5678,
+		},
+		{
+			name:        "spec.adoptID takes precedence over the annotation",
+			adoptID:     1234,
+			annotations: map[string]string{datadoghqv1alpha1.DatadogMonitorAdoptIDAnnotationKey: "5678"},
+			want:        1234,
+		},
+		{
+			name:        "invalid annotation",
+			annotations: map[string]string{datadoghqv1alpha1.DatadogMonitorAdoptIDAnnotationKey: "my-monitor"},
+			wantErr:     "annotation monitor.datadoghq.com/adopt-id must be a positive monitor ID, got \"my-monitor\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dm := genericDatadogMonitor()
+			dm.Spec.AdoptID = tt.adoptID
+			dm.Annotations = tt.annotations
+
+			id, err := adoptedMonitorID(dm)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, id)
+		})
+	}
+}
+
+func Test_monitorDifferences(t *testing.T) {
+	logger := logf.Log.WithName("Test_monitorDifferences")
+
+	tests := []struct {
+		name   string
+		modify func(m *datadogV1.Monitor)
+		want   []string
+	}{
+		{
+			name:   "monitor matching the spec",
+			modify: func(m *datadogV1.Monitor) {},
+			want:   []string{},
+		},
+		{
+			name: "tags in another order",
+			modify: func(m *datadogV1.Monitor) {
+				m.SetTags([]string{"team:foo", "env:prod"})
+			},
+			want: []string{},
+		},
+		{
+			name: "monitor modified in Datadog",
+			modify: func(m *datadogV1.Monitor) {
+				m.SetName("renamed monitor")
+				m.SetQuery("avg(last_10m):avg:system.disk.in_use{*} by {host} > 0.5")
+				m.SetTags([]string{"env:prod"})
+				options := m.GetOptions()
+				options.SetNotifyNoData(false)
+				options.SetRenotifyInterval(30)
+				m.SetOptions(options)
+			},
+			want: []string{"name", "options.notify_no_data", "query", "tags"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dm := genericDatadogMonitor()
+			dm.Spec.Tags = []string{"env:prod", "team:foo"}
+			notifyNoData := true
+			dm.Spec.Options.NotifyNoData = &notifyNoData
+
+			m, _ := buildMonitor(logger, dm)
+			tt.modify(m)
+
+			assert.Equal(t, tt.want, monitorDifferences(logger, dm, *m))
+		})
+	}
+}
+
+func TestReconciler_ReconcileAdoption(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogMonitor{}, &datadoghqv1alpha1.DatadogMonitorList{})
+	logger := logf.Log.WithName("TestReconciler_ReconcileAdoption")
+
+	// The existing monitor was created in the UI with another name
+	existing := genericDatadogMonitor()
+	existing.Spec.Name = "monitor created in the UI"
+	existing.Spec.Tags = []string{"generated:kubernetes"}
+	remote, _ := buildMonitor(logger, existing)
+	remote.SetId(1234)
+	jsonMonitor, _ := remote.MarshalJSON()
+
+	var mutex sync.Mutex
+	var calls []string
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		mutex.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/monitor/1234" {
+			_, _ = w.Write(jsonMonitor)
+			return
+		}
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer httpServer.Close()
+
+	testConfig := datadogapi.NewConfiguration()
+	testConfig.HTTPClient = httpServer.Client()
+
+	dm := genericDatadogMonitor()
+	dm.Spec.AdoptID = 1234
+	r := &Reconciler{
+		client:        fake.NewClientBuilder().WithScheme(s).WithObjects(dm).Build(),
+		datadogClient: datadogV1.NewMonitorsApi(datadogapi.NewAPIClient(testConfig)),
+		datadogAuth:   setupTestAuth(httpServer.URL),
+		scheme:        s,
+		recorder:      record.NewFakeRecorder(10),
+		log:           logger,
+	}
+	request := newRequest(resourcesNamespace, resourcesName)
+	key := types.NamespacedName{Namespace: resourcesNamespace, Name: resourcesName}
+
+	// Adopt the monitor: the differences are reported and nothing is written to Datadog
+	for i := 0; i < 3 && dm.Status.ID == 0; i++ {
+		_, err := r.Reconcile(context.TODO(), request)
+		require.NoError(t, err)
+		require.NoError(t, r.client.Get(context.TODO(), key, dm))
+	}
+	assert.Equal(t, 1234, dm.Status.ID)
+	assert.True(t, dm.Status.Primary)
+	assert.Empty(t, dm.Status.CurrentHash)
+	adopted := findCondition(dm.Status.Conditions, datadoghqv1alpha1.DatadogMonitorConditionTypeAdopted)
+	require.NotNil(t, adopted)
+	assert.Equal(t, corev1.ConditionTrue, adopted.Status)
+	assert.Equal(t, "DatadogMonitor adopted monitor 1234, differences with the spec overwritten once adoptID is removed: name", adopted.Message)
+	assert.Equal(t, []string{"GET /api/v1/monitor/1234"}, calls)
+
+	// The monitor isn't modified while adoptID is set, even when the spec changes
+	dm.Spec.Message = "another message"
+	require.NoError(t, r.client.Update(context.TODO(), dm))
+	_, err := r.Reconcile(context.TODO(), request)
+	require.NoError(t, err)
+	require.NoError(t, r.client.Get(context.TODO(), key, dm))
+	assert.Empty(t, dm.Status.CurrentHash)
+	adopted = findCondition(dm.Status.Conditions, datadoghqv1alpha1.DatadogMonitorConditionTypeAdopted)
+	require.NotNil(t, adopted)
+	assert.Equal(t, "DatadogMonitor adopted monitor 1234, differences with the spec overwritten once adoptID is removed: message, name", adopted.Message)
+	assert.Equal(t, []string{"GET /api/v1/monitor/1234", "GET /api/v1/monitor/1234"}, calls)
+
+	// Removing adoptID confirms the adoption: the differences are overwritten
+	dm.Spec.AdoptID = 0
+	require.NoError(t, r.client.Update(context.TODO(), dm))
+	_, err = r.Reconcile(context.TODO(), request)
+	require.NoError(t, err)
+	require.NoError(t, r.client.Get(context.TODO(), key, dm))
+	assert.NotEmpty(t, dm.Status.CurrentHash)
+	assert.Contains(t, calls, "PUT /api/v1/monitor/1234")
+	assert.NotContains(t, calls, "POST /api/v1/monitor")
+}
+
+func TestReconciler_adoptAlreadyAdoptedMonitor(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogMonitor{}, &datadoghqv1alpha1.DatadogMonitorList{})
+
+	older := genericDatadogMonitor()
+	older.Name = "older"
+	older.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	older.Spec.AdoptID = 1234
+	newer := genericDatadogMonitor()
+	newer.Name = "newer"
+	newer.CreationTimestamp = metav1.NewTime(time.Now())
+	newer.Annotations = map[string]string{datadoghqv1alpha1.DatadogMonitorAdoptIDAnnotationKey: "1234"}
+	other := genericDatadogMonitor()
+	other.Name = "other"
+
+	r := &Reconciler{
+		client: fake.NewClientBuilder().WithScheme(s).WithObjects(older, newer, other).Build(),
+		scheme: s,
+		log:    logf.Log.WithName(t.Name()),
+	}
+
+	// The oldest DatadogMonitor adopts the monitor
+	adopter, err := r.otherAdopter(context.TODO(), older, 1234)
+	require.NoError(t, err)
+	assert.Empty(t, adopter)
+	adopter, err = r.otherAdopter(context.TODO(), newer, 1234)
+	require.NoError(t, err)
+	assert.Equal(t, resourcesNamespace+"/older", adopter)
+
+	// Once adopted, the monitor can't be adopted by another DatadogMonitor
+	other.Status.ID = 1234
+	require.NoError(t, r.client.Status().Update(context.TODO(), other))
+	adopter, err = r.otherAdopter(context.TODO(), older, 1234)
+	require.NoError(t, err)
+	assert.Equal(t, resourcesNamespace+"/other", adopter)
+
+	err = r.adopt(context.TODO(), datadogclient.DatadogMonitorClient{}, r.log, older, older.Status.DeepCopy(), metav1.Now(), 1234, "")
+	assert.EqualError(t, err, "monitor 1234 is already adopted by DatadogMonitor "+resourcesNamespace+"/other")
+}
+
+func findCondition(conditions []datadoghqv1alpha1.DatadogMonitorCondition, conditionType datadoghqv1alpha1.DatadogMonitorConditionType) *datadoghqv1alpha1.DatadogMonitorCondition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+// monitorReferenceRegexp matches the references to other DatadogMonitors in the query of a composite monitor:
+// ${name} refers to a DatadogMonitor of the same namespace, ${namespace/name} to a DatadogMonitor of any namespace.
+var monitorReferenceRegexp = regexp.MustCompile(`\$\{\s*([^{}/\s]+)(?:/([^{}/\s]+))?\s*\}`)
+
+// ReferencedMonitors returns the DatadogMonitors referenced in the query of a DatadogMonitor.
+func ReferencedMonitors(dm *datadoghqv1alpha1.DatadogMonitor) []types.NamespacedName {
+	var references []types.NamespacedName
+	found := map[types.NamespacedName]bool{}
+	for _, match := range monitorReferenceRegexp.FindAllStringSubmatch(dm.Spec.Query, -1) {
+		reference := monitorReference(dm.Namespace, match)
+		if !found[reference] {
+			found[reference] = true
+			references = append(references, reference)
+		}
+	}
+	return references
+}
+
+// monitorReference returns the DatadogMonitor referenced by a match of monitorReferenceRegexp.
+func monitorReference(namespace string, match []string) types.NamespacedName {
+	if match[2] == "" {
+		return types.NamespacedName{Namespace: namespace, Name: match[1]}
+	}
+	return types.NamespacedName{Namespace: match[1], Name: match[2]}
+}
+
+// resolveQuery returns the query of a DatadogMonitor with the references to other DatadogMonitors
+// replaced by the IDs of their monitors. It returns an error if a referenced monitor doesn't exist yet.
+func (r *Reconciler) resolveQuery(ctx context.Context, dm *datadoghqv1alpha1.DatadogMonitor) (string, error) {
+	references := ReferencedMonitors(dm)
+	if len(references) == 0 {
+		return dm.Spec.Query, nil
+	}
+	if dm.Spec.Type != datadoghqv1alpha1.DatadogMonitorTypeComposite {
+		return "", fmt.Errorf("only the query of a composite monitor can refer to other DatadogMonitors")
+	}
+
+	ids := make(map[types.NamespacedName]int, len(references))
+	for _, reference := range references {
+		if reference.Namespace == dm.Namespace && reference.Name == dm.Name {
+			return "", fmt.Errorf("a composite monitor can't refer to itself")
+		}
+		referenced := &datadoghqv1alpha1.DatadogMonitor{}
+		if err := r.client.Get(ctx, reference, referenced); err != nil {
+			if apierrors.IsNotFound(err) {
+				return "", fmt.Errorf("waiting for the referenced DatadogMonitor %s to be created", reference)
+			}
+			return "", err
+		}
+		if referenced.Status.ID == 0 {
+			return "", fmt.Errorf("waiting for the monitor of the referenced DatadogMonitor %s to be created", reference)
+		}
+		ids[reference] = referenced.Status.ID
+	}
+
+	return monitorReferenceRegexp.ReplaceAllStringFunc(dm.Spec.Query, func(s string) string {
+		return strconv.Itoa(ids[monitorReference(dm.Namespace, monitorReferenceRegexp.FindStringSubmatch(s))])
+	}), nil
+}
+
+// withResolvedQuery returns a copy of a DatadogMonitor with the given query, to send to the Datadog API.
+func withResolvedQuery(dm *datadoghqv1alpha1.DatadogMonitor, query string) *datadoghqv1alpha1.DatadogMonitor {
+	if query == dm.Spec.Query {
+		return dm
+	}
+	resolved := dm.DeepCopy()
+	resolved.Spec.Query = query
+	return resolved
+}
@@ -0,0 +1,176 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+)
+
+func TestReferencedMonitors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []types.NamespacedName
+	}{
+		{
+			name:  "no reference",
+			query: "123 && 456",
+		},
+		{
+			name:  "references in the same namespace and in another namespace",
+			query: "${monitor-a} && ( ${other/monitor-b} || ${ monitor-a } )",
+			want: []types.NamespacedName{
+				{Namespace: resourcesNamespace, Name: "monitor-a"},
+				{Namespace: "other", Name: "monitor-b"},
+			},
+		},
+		{
+			name:  "metric query braces aren't references",
+			query: "avg(last_10m):avg:system.disk.in_use{*} by {host} > 0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dm := genericDatadogMonitor()
+			dm.Spec.Query = tt.query
+			assert.Equal(t, tt.want, ReferencedMonitors(dm))
+		})
+	}
+}
+
+func TestReconciler_resolveQuery(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogMonitor{}, &datadoghqv1alpha1.DatadogMonitorList{})
+
+	referencedMonitor := func(namespace, name string, id int) *datadoghqv1alpha1.DatadogMonitor {
+		return &datadoghqv1alpha1.DatadogMonitor{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Status:     datadoghqv1alpha1.DatadogMonitorStatus{ID: id},
+		}
+	}
+	compositeMonitor := func(query string) *datadoghqv1alpha1.DatadogMonitor {
+		dm := genericDatadogMonitor()
+		dm.Spec.Type = datadoghqv1alpha1.DatadogMonitorTypeComposite
+		dm.Spec.Query = query
+		return dm
+	}
+
+	tests := []struct {
+		name      string
+		objects   []client.Object
+		monitor   *datadoghqv1alpha1.DatadogMonitor
+		wantQuery string
+		wantErr   string
+	}{
+		{
+			name:      "composite without reference",
+			monitor:   compositeMonitor("123 && 456"),
+			wantQuery: "123 && 456",
+		},
+		{
+			name: "references resolved to the monitor IDs",
+			objects: []client.Object{
+				referencedMonitor(resourcesNamespace, "monitor-a", 123),
+				referencedMonitor("other", "monitor-b", 456),
+			},
+			monitor:   compositeMonitor("${monitor-a} && !${other/monitor-b} || ${monitor-a}"),
+			wantQuery: "123 && !456 || 123",
+		},
+		{
+			name:    "referenced DatadogMonitor not found",
+			objects: []client.Object{referencedMonitor(resourcesNamespace, "monitor-a", 123)},
+			monitor: compositeMonitor("${monitor-a} && ${other/monitor-b}"),
+			wantErr: "waiting for the referenced DatadogMonitor other/monitor-b to be created",
+		},
+		{
+			name:    "monitor of the referenced DatadogMonitor not created yet",
+			objects: []client.Object{referencedMonitor(resourcesNamespace, "monitor-a", 0)},
+			monitor: compositeMonitor("${monitor-a} && 456"),
+			wantErr: "waiting for the monitor of the referenced DatadogMonitor bar/monitor-a to be created",
+		},
+		{
+			name:    "reference to itself",
+			monitor: compositeMonitor("${" + resourcesName + "} && 456"),
+			wantErr: "a composite monitor can't refer to itself",
+		},
+		{
+			name:    "reference in a monitor that isn't a composite",
+			objects: []client.Object{referencedMonitor(resourcesNamespace, "monitor-a", 123)},
+			monitor: func() *datadoghqv1alpha1.DatadogMonitor {
+				dm := genericDatadogMonitor()
+				dm.Spec.Query = "${monitor-a}"
+				return dm
+			}(),
+			wantErr: "only the query of a composite monitor can refer to other DatadogMonitors",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reconciler{
+				client: fake.NewClientBuilder().WithScheme(s).WithObjects(tt.objects...).Build(),
+			}
+
+			query, err := r.resolveQuery(context.TODO(), tt.monitor)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantQuery, query)
+		})
+	}
+}
+
+func TestReconciler_resolveQueryRecreatedMonitor(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogMonitor{}, &datadoghqv1alpha1.DatadogMonitorList{})
+
+	referenced := &datadoghqv1alpha1.DatadogMonitor{
+		ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: "monitor-a"},
+		Status:     datadoghqv1alpha1.DatadogMonitorStatus{ID: 123},
+	}
+	r := &Reconciler{
+		client: fake.NewClientBuilder().WithScheme(s).WithObjects(referenced).Build(),
+	}
+	composite := genericDatadogMonitor()
+	composite.Spec.Type = datadoghqv1alpha1.DatadogMonitorTypeComposite
+	composite.Spec.Query = "${monitor-a} && 456"
+
+	query, err := r.resolveQuery(context.TODO(), composite)
+	require.NoError(t, err)
+	assert.Equal(t, "123 && 456", query)
+	hash, err := comparison.GenerateMD5ForSpec(&withResolvedQuery(composite, query).Spec)
+	require.NoError(t, err)
+
+	// The referenced monitor is recreated with a new ID
+	require.NoError(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: resourcesNamespace, Name: "monitor-a"}, referenced))
+	referenced.Status.ID = 789
+	require.NoError(t, r.client.Status().Update(context.TODO(), referenced))
+
+	query, err = r.resolveQuery(context.TODO(), composite)
+	require.NoError(t, err)
+	assert.Equal(t, "789 && 456", query)
+	newHash, err := comparison.GenerateMD5ForSpec(&withResolvedQuery(composite, query).Spec)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, newHash, "the composite monitor must be updated")
+
+	// The DatadogMonitor itself isn't modified
+	assert.Equal(t, "${monitor-a} && 456", composite.Spec.Query)
+}
@@ -54,6 +54,7 @@ var supportedMonitorTypes = map[string]bool{
 	string(datadogV1.MONITORTYPE_SLO_ALERT):             true,
 	string(datadogV1.MONITORTYPE_EVENT_V2_ALERT):        true,
 	string(datadogV1.MONITORTYPE_AUDIT_ALERT):           true,
+	string(datadogV1.MONITORTYPE_COMPOSITE):             true,
 }
 
 const requiredTag = "generated:kubernetes"
@@ -63,22 +64,27 @@ type Reconciler struct {
 	client        client.Client
 	datadogClient *datadogV1.MonitorsApi
 	datadogAuth   context.Context
+	clients       *datadogclient.ClientCache
 	versionInfo   *version.Info
 	log           logr.Logger
 	scheme        *runtime.Scheme
 	recorder      record.EventRecorder
+
+	defaultDriftPolicy datadoghqv1alpha1.DriftPolicy
 }
 
 // NewReconciler returns a new Reconciler object
-func NewReconciler(client client.Client, ddClient datadogclient.DatadogMonitorClient, versionInfo *version.Info, scheme *runtime.Scheme, log logr.Logger, recorder record.EventRecorder) (*Reconciler, error) {
+func NewReconciler(client client.Client, ddClient datadogclient.DatadogMonitorClient, clients *datadogclient.ClientCache, versionInfo *version.Info, scheme *runtime.Scheme, log logr.Logger, recorder record.EventRecorder, defaultDriftPolicy datadoghqv1alpha1.DriftPolicy) (*Reconciler, error) {
 	return &Reconciler{
-		client:        client,
-		datadogClient: ddClient.Client,
-		datadogAuth:   ddClient.Auth,
-		versionInfo:   versionInfo,
-		scheme:        scheme,
-		log:           log,
-		recorder:      recorder,
+		client:             client,
+		datadogClient:      ddClient.Client,
+		datadogAuth:        ddClient.Auth,
+		clients:            clients,
+		versionInfo:        versionInfo,
+		scheme:             scheme,
+		log:                log,
+		recorder:           recorder,
+		defaultDriftPolicy: defaultDriftPolicy,
 	}, nil
 }
 
@@ -122,7 +128,34 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 		return r.updateStatusIfNeeded(logger, instance, now, newStatus, err, result)
 	}
 
-	instanceSpecHash, err := comparison.GenerateMD5ForSpec(&instance.Spec)
+	// Get the Datadog client of the credentials of the DatadogMonitor
+	ddClient, credentials, err := r.datadogClientFor(ctx, logger, instance)
+	if err != nil {
+		logger.Error(err, "error getting the Datadog credentials")
+		result.RequeueAfter = defaultErrRequeuePeriod
+
+		return r.updateStatusIfNeeded(logger, instance, now, newStatus, err, result)
+	}
+
+	// An existing monitor is adopted instead of creating a new one
+	adoptID, err := adoptedMonitorID(instance)
+	if err != nil {
+		logger.Error(err, "invalid monitor ID to adopt")
+
+		return r.updateStatusIfNeeded(logger, instance, now, newStatus, err, result)
+	}
+
+	// Replace the references of a composite monitor to other DatadogMonitors with their monitor IDs
+	query, err := r.resolveQuery(ctx, instance)
+	if err != nil {
+		logger.Error(err, "error resolving the monitor query")
+		result.RequeueAfter = defaultErrRequeuePeriod
+
+		return r.updateStatusIfNeeded(logger, instance, now, newStatus, err, result)
+	}
+
+	// The hash includes the resolved query, so that the monitor is updated when a referenced monitor gets a new ID
+	instanceSpecHash, err := comparison.GenerateMD5ForSpec(&withResolvedQuery(instance, query).Spec)
 	if err != nil {
 		logger.Error(err, "error generating hash")
 
@@ -139,26 +172,40 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 		shouldCreate = true
 	} else {
 		var m datadogV1.Monitor
-		if instanceSpecHash != statusSpecHash {
+		if adoptionPending(instance, adoptID) {
+			// The adopted monitor isn't modified until adoptID is removed, its differences with the spec are reported
+			m, err = r.get(ddClient, instance, newStatus)
+			if err != nil {
+				logger.Error(err, "error getting monitor", "Monitor ID", instance.Status.ID)
+				if strings.Contains(err.Error(), ctrutils.NotFoundString) {
+					shouldCreate = true
+				}
+			} else {
+				reportAdoptionDifferences(logger, withResolvedQuery(instance, query), m, newStatus, now, instanceSpecHash)
+				updateMonitorState(m, now, newStatus)
+			}
+		} else if instanceSpecHash != statusSpecHash {
 			// Custom resource manifest has changed, need to update the API
 			logger.V(1).Info("DatadogMonitor manifest has changed")
 			shouldUpdate = true
+			// The update overwrites the fields modified outside Kubernetes
+			newStatus.DriftedFields = nil
 		} else if instance.Status.MonitorLastForceSyncTime == nil || (defaultForceSyncPeriod-now.Sub(instance.Status.MonitorLastForceSyncTime.Time)) <= 0 {
-			// Periodically force a sync with the API monitor to ensure parity
+			// Periodically check that the API monitor wasn't modified outside Kubernetes, and handle the drift according to the drift policy
 			// Get monitor to make sure it exists before trying any updates. If it doesn't, set shouldCreate
-			m, err = r.get(instance, newStatus)
+			m, err = r.get(ddClient, instance, newStatus)
 			if err != nil {
 				logger.Error(err, "error getting monitor", "Monitor ID", instance.Status.ID)
 				if strings.Contains(err.Error(), ctrutils.NotFoundString) {
 					shouldCreate = true
 				}
 			} else {
-				shouldUpdate = true
+				shouldUpdate = r.checkDrift(logger, withResolvedQuery(instance, query), m, newStatus, now)
 			}
 		} else if instance.Status.MonitorStateLastUpdateTime == nil || (defaultRequeuePeriod-now.Sub(instance.Status.MonitorStateLastUpdateTime.Time)) <= 0 {
 			// If other conditions aren't met, and we have passed the defaultRequeuePeriod, then update monitor state
 			// Get monitor to make sure it exists before trying any updates. If it doesn't, set shouldCreate
-			m, err = r.get(instance, newStatus)
+			m, err = r.get(ddClient, instance, newStatus)
 			if err != nil {
 				logger.Error(err, "error getting monitor", "Monitor ID", instance.Status.ID)
 				if strings.Contains(err.Error(), ctrutils.NotFoundString) {
@@ -172,15 +219,22 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 	// Create and update actions
 	if shouldCreate {
 		if isSupportedMonitorType(instance.Spec.Type) {
-			logger.V(1).Info("Creating monitor in Datadog")
 			// Make sure required tags are present
 			if !apiutils.BoolValue(instance.Spec.ControllerOptions.DisableRequiredTags) {
 				if result, err = r.checkRequiredTags(logger, instance); err != nil || result.Requeue {
 					return r.updateStatusIfNeeded(logger, instance, now, newStatus, err, result)
 				}
 			}
-			if err = r.create(logger, instance, newStatus, now, instanceSpecHash); err != nil {
-				logger.Error(err, "error creating monitor")
+			if adoptID != 0 && instance.Status.ID == 0 {
+				logger.V(1).Info("Adopting monitor in Datadog", "Monitor ID", adoptID)
+				if err = r.adopt(ctx, ddClient, logger, withResolvedQuery(instance, query), newStatus, now, adoptID, instanceSpecHash); err != nil {
+					logger.Error(err, "error adopting monitor", "Monitor ID", adoptID)
+				}
+			} else {
+				logger.V(1).Info("Creating monitor in Datadog")
+				if err = r.create(ddClient, logger, withResolvedQuery(instance, query), newStatus, now, instanceSpecHash); err != nil {
+					logger.Error(err, "error creating monitor")
+				}
 			}
 		} else {
 			err = fmt.Errorf("monitor type %v not supported", instance.Spec.Type)
@@ -194,11 +248,22 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 				return r.updateStatusIfNeeded(logger, instance, now, newStatus, err, result)
 			}
 		}
-		if err = r.update(logger, instance, newStatus, now, instanceSpecHash); err != nil {
+		if err = r.update(ddClient, logger, withResolvedQuery(instance, query), newStatus, now, instanceSpecHash); err != nil {
 			logger.Error(err, "error updating monitor", "Monitor ID", instance.Status.ID)
 		}
 	}
 
+	// Record the credentials of the monitor, which are used to delete it. The monitors created before the credentials
+	// were recorded are managed with the current credentials.
+	if err == nil && newStatus.ID != 0 && (shouldCreate || newStatus.Credentials == nil) {
+		newStatus.Credentials = credentials
+	}
+
+	// Retry when the rate limit of the Datadog API endpoint is reset
+	if retryAfter, throttled := datadogclient.RetryAfter(err); throttled {
+		result.RequeueAfter = retryAfter
+	}
+
 	// If reconcile was successful, requeue with period defaultRequeuePeriod
 	if !result.Requeue && result.RequeueAfter == 0 {
 		result.RequeueAfter = defaultRequeuePeriod
@@ -208,14 +273,14 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 	return r.updateStatusIfNeeded(logger, instance, now, newStatus, err, result)
 }
 
-func (r *Reconciler) create(logger logr.Logger, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, status *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time, instanceSpecHash string) error {
+func (r *Reconciler) create(ddClient datadogclient.DatadogMonitorClient, logger logr.Logger, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, status *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time, instanceSpecHash string) error {
 	// Validate monitor in Datadog
-	if err := validateMonitor(r.datadogAuth, logger, r.datadogClient, datadogMonitor); err != nil {
+	if err := validateMonitor(ddClient.Auth, logger, ddClient.Client, datadogMonitor); err != nil {
 		return err
 	}
 
 	// Create monitor in Datadog
-	m, err := createMonitor(r.datadogAuth, logger, r.datadogClient, datadogMonitor)
+	m, err := createMonitor(ddClient.Auth, logger, ddClient.Client, datadogMonitor)
 	if err != nil {
 		return err
 	}
@@ -239,15 +304,15 @@ func (r *Reconciler) create(logger logr.Logger, datadogMonitor *datadoghqv1alpha
 	return nil
 }
 
-func (r *Reconciler) update(logger logr.Logger, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, status *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time, instanceSpecHash string) error {
+func (r *Reconciler) update(ddClient datadogclient.DatadogMonitorClient, logger logr.Logger, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, status *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time, instanceSpecHash string) error {
 	// Validate monitor in Datadog
-	if err := validateMonitor(r.datadogAuth, logger, r.datadogClient, datadogMonitor); err != nil {
+	if err := validateMonitor(ddClient.Auth, logger, ddClient.Client, datadogMonitor); err != nil {
 		status.MonitorStateSyncStatus = datadoghqv1alpha1.MonitorStateSyncStatusValidateError
 		return err
 	}
 
 	// Update monitor in Datadog
-	if _, err := updateMonitor(r.datadogAuth, logger, r.datadogClient, datadogMonitor); err != nil {
+	if _, err := updateMonitor(ddClient.Auth, logger, ddClient.Client, datadogMonitor); err != nil {
 		status.MonitorStateSyncStatus = datadoghqv1alpha1.MonitorStateSyncStatusUpdateError
 		return err
 	}
@@ -265,9 +330,9 @@ func (r *Reconciler) update(logger logr.Logger, datadogMonitor *datadoghqv1alpha
 	return nil
 }
 
-func (r *Reconciler) get(datadogMonitor *datadoghqv1alpha1.DatadogMonitor, status *datadoghqv1alpha1.DatadogMonitorStatus) (datadogV1.Monitor, error) {
+func (r *Reconciler) get(ddClient datadogclient.DatadogMonitorClient, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, status *datadoghqv1alpha1.DatadogMonitorStatus) (datadogV1.Monitor, error) {
 	// Get monitor from Datadog and update resource status if needed
-	m, err := getMonitor(r.datadogAuth, r.datadogClient, datadogMonitor.Status.ID)
+	m, err := getMonitor(ddClient.Auth, ddClient.Client, datadogMonitor.Status.ID)
 	if err != nil {
 		status.MonitorStateSyncStatus = datadoghqv1alpha1.MonitorStateSyncStatusGetError
 		return m, err
@@ -303,6 +368,10 @@ func (r *Reconciler) updateStatusIfNeeded(logger logr.Logger, datadogMonitor *da
 		// not an issue, but if a monitor has many groups and is "flapping", then it can cause a flood of updates to
 		// the Status.TriggeredState and put pressure on the controller. As a safeguard against this, the maximum number
 		// of groups stored in Status.TriggeredState should be conservative.
+		// A throttled reconcile keeps its result though, so that it is retried when the rate limit is reset.
+		if _, throttled := datadogclient.RetryAfter(currentErr); throttled {
+			return result, nil
+		}
 		return ctrl.Result{RequeueAfter: defaultRequeuePeriod}, nil
 	}
 
@@ -11,8 +11,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,7 +31,9 @@ import (
 	datadogapi "github.com/DataDog/datadog-api-client-go/v2/api/datadog"
 	datadogV1 "github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/config"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
 )
 
 const (
@@ -399,7 +403,7 @@ func TestReconcileDatadogMonitor_Reconcile(t *testing.T) {
 			},
 		},
 		{
-			name: "DatadogMonitor of unsupported type (composite)",
+			name: "DatadogMonitor composite waiting for a referenced DatadogMonitor",
 			args: args{
 				request: newRequest(resourcesNamespace, resourcesName),
 				firstAction: func(c client.Client) {
@@ -413,7 +417,7 @@ func TestReconcileDatadogMonitor_Reconcile(t *testing.T) {
 							Name:      resourcesName,
 						},
 						Spec: datadoghqv1alpha1.DatadogMonitorSpec{
-							Query:   "avg(last_10m):avg:system.disk.in_use{*} by {host} > 0.1",
+							Query:   "${other-monitor} && ${other-namespace/another-monitor}",
 							Type:    datadoghqv1alpha1.DatadogMonitorTypeComposite,
 							Name:    "test monitor",
 							Message: "something is wrong",
@@ -430,6 +434,8 @@ func TestReconcileDatadogMonitor_Reconcile(t *testing.T) {
 					return err
 				}
 				assert.Equal(t, dm.Status.Conditions[0].Type, datadoghqv1alpha1.DatadogMonitorConditionTypeError)
+				assert.Contains(t, dm.Status.Conditions[0].Message, "waiting for the referenced DatadogMonitor bar/other-monitor")
+				assert.Equal(t, 0, dm.Status.ID)
 				return nil
 			},
 		},
@@ -501,6 +507,48 @@ func TestReconcileDatadogMonitor_Reconcile(t *testing.T) {
 	}
 }
 
+func TestReconcileDatadogMonitor_ReconcileRateLimit(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogMonitor{}, &datadoghqv1alpha1.DatadogMonitorList{})
+	logger := logf.Log.WithName("TestReconcileDatadogMonitor_ReconcileRateLimit")
+
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "42")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer httpServer.Close()
+	t.Setenv(config.DDURLEnvVar, httpServer.URL)
+
+	ddClient, err := datadogclient.InitDatadogMonitorClient(logger, config.Creds{APIKey: "rate-limited-api-key", AppKey: "app-key"})
+	require.NoError(t, err)
+
+	dm := genericDatadogMonitor()
+	r := &Reconciler{
+		client:        fake.NewClientBuilder().WithScheme(s).WithObjects(dm).Build(),
+		datadogClient: ddClient.Client,
+		datadogAuth:   ddClient.Auth,
+		scheme:        s,
+		recorder:      record.NewFakeRecorder(10),
+		log:           logger,
+	}
+
+	// The first reconciles add the finalizer and the required tags, the next one is throttled
+	var result reconcile.Result
+	for i := 0; i < 5 && findCondition(dm.Status.Conditions, datadoghqv1alpha1.DatadogMonitorConditionTypeError) == nil; i++ {
+		result, err = r.Reconcile(context.TODO(), newRequest(resourcesNamespace, resourcesName))
+		require.NoError(t, err)
+		require.NoError(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: resourcesNamespace, Name: resourcesName}, dm))
+	}
+
+	errorCondition := findCondition(dm.Status.Conditions, datadoghqv1alpha1.DatadogMonitorConditionTypeError)
+	require.NotNil(t, errorCondition)
+	assert.Contains(t, errorCondition.Message, "rate limit of the Datadog API endpoint POST /api/v1/monitor")
+	// The monitor is requeued when the rate limit is reset
+	assert.Equal(t, 42*time.Second, result.RequeueAfter)
+	assert.Equal(t, 0, dm.Status.ID)
+}
+
 func newRequest(ns, name string) reconcile.Request {
 	return reconcile.Request{
 		NamespacedName: types.NamespacedName{
@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/controllers/utils"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+// datadogClientFor returns the Datadog client of the credentials of a DatadogMonitor, or the client of the operator
+// credentials when neither the DatadogMonitor nor its namespace defines credentials. It also returns the status
+// recording these credentials. It returns an error when they differ from the recorded credentials the monitor was
+// created with, so that the monitor isn't duplicated with the new credentials.
+func (r *Reconciler) datadogClientFor(ctx context.Context, logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor) (datadogclient.DatadogMonitorClient, *datadoghqv1alpha1.DatadogCredentialsStatus, error) {
+	secret, creds, err := utils.GetDatadogCredentials(ctx, r.client, dm.Namespace, dm.Spec.Credentials)
+	if err != nil {
+		return datadogclient.DatadogMonitorClient{}, nil, err
+	}
+	if err = utils.CheckCredentialsUnchanged(dm.Status.Credentials, utils.CredentialsStatus(secret)); err != nil {
+		return datadogclient.DatadogMonitorClient{}, nil, err
+	}
+	if secret == nil {
+		return datadogclient.DatadogMonitorClient{Client: r.datadogClient, Auth: r.datadogAuth}, utils.CredentialsStatus(nil), nil
+	}
+
+	ddClient, err := r.clients.MonitorClient(logger, *secret, creds)
+	return ddClient, utils.CredentialsStatus(secret), err
+}
+
+// recordedDatadogClient returns the Datadog client of the credentials the monitor of a DatadogMonitor was created with.
+// The monitors created before the credentials were recorded use the current credentials of the DatadogMonitor.
+func (r *Reconciler) recordedDatadogClient(ctx context.Context, logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor) (datadogclient.DatadogMonitorClient, error) {
+	if dm.Status.Credentials == nil {
+		ddClient, _, err := r.datadogClientFor(ctx, logger, dm)
+		return ddClient, err
+	}
+
+	secret, creds, err := utils.GetRecordedDatadogCredentials(ctx, r.client, dm.Namespace, *dm.Status.Credentials)
+	if err != nil {
+		return datadogclient.DatadogMonitorClient{}, err
+	}
+	if secret == nil {
+		return datadogclient.DatadogMonitorClient{Client: r.datadogClient, Auth: r.datadogAuth}, nil
+	}
+
+	return r.clients.MonitorClient(logger, *secret, creds)
+}
+
+// handleDeletionCredentialsError reports that the monitor of a deleted DatadogMonitor can't be finalized because its
+// credentials can't be read, for example because their Secret was deleted first. The deletion is retried until
+// utils.CredentialsDeletionTimeout expires: the DatadogMonitor is then released, and the monitor is left in Datadog.
+// It returns nil once the DatadogMonitor can be released.
+func (r *Reconciler) handleDeletionCredentialsError(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor, err error) error {
+	event := buildEventInfo(dm.Name, dm.Namespace, datadog.DeletionEvent)
+	if utils.CredentialsDeletionExpired(dm, time.Now()) {
+		logger.Error(err, "failed to finalize monitor, the monitor is left in Datadog", "Monitor ID", fmt.Sprint(dm.Status.ID))
+		r.recorder.Event(dm, corev1.EventTypeWarning, event.GetReason(), fmt.Sprintf("%s: monitor %d left in Datadog, unable to get the Datadog credentials for %s: %v", event.GetMessage(), dm.Status.ID, utils.CredentialsDeletionTimeout, err))
+		return nil
+	}
+
+	logger.Error(err, "failed to finalize monitor, unable to get the Datadog credentials", "Monitor ID", fmt.Sprint(dm.Status.ID))
+	r.recorder.Event(dm, corev1.EventTypeWarning, event.GetReason(), fmt.Sprintf("%s: unable to get the Datadog credentials to finalize monitor %d, retrying: %v", event.GetMessage(), dm.Status.ID, err))
+	return err
+}
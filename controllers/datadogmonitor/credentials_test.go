@@ -0,0 +1,296 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	datadogapi "github.com/DataDog/datadog-api-client-go/v2/api/datadog"
+	datadogV1 "github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/controllers/utils"
+	"github.com/DataDog/datadog-operator/pkg/config"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+func TestReconciler_ReconcileCredentials(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogMonitor{}, &datadoghqv1alpha1.DatadogMonitorList{})
+	logger := logf.Log.WithName("TestReconciler_ReconcileCredentials")
+
+	remote, _ := buildMonitor(logger, genericDatadogMonitor())
+	remote.SetId(1234)
+	jsonMonitor, _ := remote.MarshalJSON()
+
+	var mutex sync.Mutex
+	var apiKeys []string
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		apiKeys = append(apiKeys, r.Header.Get("DD-API-KEY"))
+		mutex.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(jsonMonitor)
+	}))
+	defer httpServer.Close()
+	// The clients of the credentials Secrets use the API URL of the operator
+	t.Setenv(config.DDURLEnvVar, httpServer.URL)
+
+	testConfig := datadogapi.NewConfiguration()
+	testConfig.HTTPClient = httpServer.Client()
+
+	tests := []struct {
+		name        string
+		credentials *datadoghqv1alpha1.DatadogCredentialsReference
+		secrets     []*corev1.Secret
+		wantAPIKey  string
+		wantSecret  string
+		wantErr     string
+	}{
+		{
+			name:       "operator credentials",
+			wantAPIKey: "DUMMY_API_KEY",
+		},
+		{
+			name:        "credentials of the DatadogMonitor",
+			credentials: &datadoghqv1alpha1.DatadogCredentialsReference{SecretName: "team-credentials"},
+			secrets: []*corev1.Secret{
+				credentialsSecret("team-credentials", nil, "team-api-key"),
+				credentialsSecret("namespace-credentials", map[string]string{datadoghqv1alpha1.DatadogCredentialsDefaultLabelKey: "true"}, "namespace-api-key"),
+			},
+			wantAPIKey: "team-api-key",
+			wantSecret: "team-credentials",
+		},
+		{
+			name: "default credentials of the namespace",
+			secrets: []*corev1.Secret{
+				credentialsSecret("namespace-credentials", map[string]string{datadoghqv1alpha1.DatadogCredentialsDefaultLabelKey: "true"}, "namespace-api-key"),
+			},
+			wantAPIKey: "namespace-api-key",
+			wantSecret: "namespace-credentials",
+		},
+		{
+			name:        "missing credentials Secret",
+			credentials: &datadoghqv1alpha1.DatadogCredentialsReference{SecretName: "team-credentials"},
+			wantErr:     "unable to get the credentials Secret bar/team-credentials",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiKeys = nil
+			dm := genericDatadogMonitor()
+			dm.Spec.Credentials = tt.credentials
+			fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(dm)
+			for _, secret := range tt.secrets {
+				fakeClient = fakeClient.WithObjects(secret)
+			}
+			r := &Reconciler{
+				client:        fakeClient.Build(),
+				datadogClient: datadogV1.NewMonitorsApi(datadogapi.NewAPIClient(testConfig)),
+				datadogAuth:   setupTestAuth(httpServer.URL),
+				clients:       datadogclient.NewClientCache(),
+				scheme:        s,
+				recorder:      record.NewFakeRecorder(10),
+				log:           logger,
+			}
+			key := types.NamespacedName{Namespace: resourcesNamespace, Name: resourcesName}
+
+			// The first reconciles add the finalizer and the required tags, the next one creates the monitor
+			for i := 0; i < 5 && dm.Status.ID == 0; i++ {
+				_, err := r.Reconcile(context.TODO(), newRequest(resourcesNamespace, resourcesName))
+				require.NoError(t, err)
+				require.NoError(t, r.client.Get(context.TODO(), key, dm))
+			}
+			if tt.wantErr != "" {
+				errorCondition := findCondition(dm.Status.Conditions, datadoghqv1alpha1.DatadogMonitorConditionTypeError)
+				require.NotNil(t, errorCondition)
+				assert.Contains(t, errorCondition.Message, tt.wantErr)
+				assert.Empty(t, apiKeys)
+				return
+			}
+
+			assert.Equal(t, 1234, dm.Status.ID)
+			// The credentials are recorded to delete the monitor
+			assert.Equal(t, &datadoghqv1alpha1.DatadogCredentialsStatus{SecretName: tt.wantSecret}, dm.Status.Credentials)
+			require.NotEmpty(t, apiKeys)
+			for _, apiKey := range apiKeys {
+				assert.Equal(t, tt.wantAPIKey, apiKey)
+			}
+		})
+	}
+}
+
+func TestReconciler_ReconcileChangedCredentials(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogMonitor{}, &datadoghqv1alpha1.DatadogMonitorList{})
+	logger := logf.Log.WithName("TestReconciler_ReconcileChangedCredentials")
+
+	var requests []string
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.Header.Get("DD-API-KEY"))
+	}))
+	defer httpServer.Close()
+	t.Setenv(config.DDURLEnvVar, httpServer.URL)
+
+	testConfig := datadogapi.NewConfiguration()
+	testConfig.HTTPClient = httpServer.Client()
+
+	dm := genericDatadogMonitor()
+	dm.Finalizers = []string{datadogMonitorFinalizer}
+	dm.Spec.Credentials = &datadoghqv1alpha1.DatadogCredentialsReference{SecretName: "other-credentials"}
+	dm.Status.ID = 1234
+	dm.Status.Primary = true
+	dm.Status.Credentials = &datadoghqv1alpha1.DatadogCredentialsStatus{SecretName: "team-credentials"}
+	r := &Reconciler{
+		client: fake.NewClientBuilder().WithScheme(s).WithObjects(
+			dm,
+			credentialsSecret("team-credentials", nil, "team-api-key"),
+			credentialsSecret("other-credentials", nil, "other-api-key"),
+		).Build(),
+		datadogClient: datadogV1.NewMonitorsApi(datadogapi.NewAPIClient(testConfig)),
+		datadogAuth:   setupTestAuth(httpServer.URL),
+		clients:       datadogclient.NewClientCache(),
+		scheme:        s,
+		recorder:      record.NewFakeRecorder(10),
+		log:           logger,
+	}
+
+	_, err := r.Reconcile(context.TODO(), newRequest(resourcesNamespace, resourcesName))
+	require.NoError(t, err)
+	require.NoError(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: resourcesNamespace, Name: resourcesName}, dm))
+
+	// The monitor isn't recreated with the new credentials, which may belong to another organization
+	assert.Empty(t, requests)
+	assert.Equal(t, 1234, dm.Status.ID)
+	assert.Equal(t, &datadoghqv1alpha1.DatadogCredentialsStatus{SecretName: "team-credentials"}, dm.Status.Credentials)
+	errorCondition := findCondition(dm.Status.Conditions, datadoghqv1alpha1.DatadogMonitorConditionTypeError)
+	require.NotNil(t, errorCondition)
+	assert.Contains(t, errorCondition.Message, "the Datadog credentials changed from the Secret team-credentials to the Secret other-credentials")
+}
+
+func TestReconciler_finalizeWithRecordedCredentials(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogMonitor{}, &datadoghqv1alpha1.DatadogMonitorList{})
+	logger := logf.Log.WithName("TestReconciler_finalizeWithRecordedCredentials")
+
+	var mutex sync.Mutex
+	var requests []string
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests = append(requests, r.Method+" "+r.Header.Get("DD-API-KEY"))
+		mutex.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"deleted_monitor_id": 1234}`))
+	}))
+	defer httpServer.Close()
+	t.Setenv(config.DDURLEnvVar, httpServer.URL)
+
+	testConfig := datadogapi.NewConfiguration()
+	testConfig.HTTPClient = httpServer.Client()
+
+	tests := []struct {
+		name          string
+		secrets       []*corev1.Secret
+		deletedSince  time.Duration
+		wantRequests  []string
+		wantErr       bool
+		wantFinalizer bool
+		wantEvent     string
+	}{
+		{
+			name: "the monitor is deleted with the recorded credentials",
+			secrets: []*corev1.Secret{
+				credentialsSecret("namespace-credentials", nil, "namespace-api-key"),
+				credentialsSecret("other-credentials", map[string]string{datadoghqv1alpha1.DatadogCredentialsDefaultLabelKey: "true"}, "other-api-key"),
+			},
+			wantRequests: []string{"DELETE namespace-api-key"},
+			wantEvent:    "Normal Delete DatadogMonitor",
+		},
+		{
+			name: "the deletion is retried while the recorded Secret is missing",
+			secrets: []*corev1.Secret{
+				credentialsSecret("other-credentials", map[string]string{datadoghqv1alpha1.DatadogCredentialsDefaultLabelKey: "true"}, "other-api-key"),
+			},
+			deletedSince:  time.Minute,
+			wantErr:       true,
+			wantFinalizer: true,
+			wantEvent:     "Warning Delete DatadogMonitor bar/foo: unable to get the Datadog credentials to finalize monitor 1234, retrying",
+		},
+		{
+			name:         "the monitor is left in Datadog once the deletion timed out",
+			deletedSince: 2 * utils.CredentialsDeletionTimeout,
+			wantEvent:    "Warning Delete DatadogMonitor bar/foo: monitor 1234 left in Datadog",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = nil
+			dm := genericDatadogMonitor()
+			dm.Finalizers = []string{datadogMonitorFinalizer}
+			dm.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-tt.deletedSince)}
+			dm.Status.ID = 1234
+			dm.Status.Primary = true
+			dm.Status.Credentials = &datadoghqv1alpha1.DatadogCredentialsStatus{SecretName: "namespace-credentials"}
+			fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(dm)
+			for _, secret := range tt.secrets {
+				fakeClient = fakeClient.WithObjects(secret)
+			}
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{
+				client:        fakeClient.Build(),
+				datadogClient: datadogV1.NewMonitorsApi(datadogapi.NewAPIClient(testConfig)),
+				datadogAuth:   setupTestAuth(httpServer.URL),
+				clients:       datadogclient.NewClientCache(),
+				scheme:        s,
+				recorder:      recorder,
+				log:           logger,
+			}
+
+			_, err := r.handleFinalizer(logger, dm)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			// The credentials of the operator and the default credentials of the namespace are never used instead
+			assert.Equal(t, tt.wantRequests, requests)
+			assert.Equal(t, tt.wantFinalizer, controllerutil.ContainsFinalizer(dm, datadogMonitorFinalizer))
+			require.NotEmpty(t, recorder.Events)
+			assert.Contains(t, <-recorder.Events, tt.wantEvent)
+		})
+	}
+}
+
+func credentialsSecret(name string, labels map[string]string, apiKey string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: resourcesNamespace,
+			Name:      name,
+			Labels:    labels,
+		},
+		Data: map[string][]byte{
+			"api_key": []byte(apiKey),
+			"app_key": []byte("app-key"),
+		},
+	}
+}
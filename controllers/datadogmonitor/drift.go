@@ -0,0 +1,44 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	datadogV1 "github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
+)
+
+// checkDrift compares the monitor in Datadog with the monitor rendered from the spec, and reports the drifted fields
+// in the status and in an event. It returns true if the monitor must be updated to revert the drift.
+func (r *Reconciler) checkDrift(logger logr.Logger, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, m datadogV1.Monitor, status *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time) bool {
+	status.MonitorLastForceSyncTime = &now
+
+	policy := datadoghqv1alpha1.GetDriftPolicy(datadogMonitor.Spec.ControllerOptions.DriftPolicy, r.defaultDriftPolicy)
+	if policy == datadoghqv1alpha1.DriftPolicyIgnore {
+		status.DriftedFields = nil
+		return false
+	}
+
+	drifted := monitorDifferences(logger, datadogMonitor, m)
+	if len(drifted) == 0 {
+		status.DriftedFields = nil
+		return false
+	}
+
+	status.DriftedFields = drifted
+	logger.Info("Monitor modified outside Kubernetes", "Monitor ID", datadogMonitor.Status.ID, "Drifted fields", drifted, "Drift policy", policy)
+	event := buildEventInfo(datadogMonitor.Name, datadogMonitor.Namespace, datadog.DriftEvent)
+	r.recorder.Event(datadogMonitor, corev1.EventTypeWarning, event.GetReason(), fmt.Sprintf("%s: fields modified outside Kubernetes (drift policy %s): %s", event.GetMessage(), policy, strings.Join(drifted, ", ")))
+
+	return policy == datadoghqv1alpha1.DriftPolicyRevert
+}
@@ -0,0 +1,96 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+func TestReconciler_checkDrift(t *testing.T) {
+	logger := logf.Log.WithName("TestReconciler_checkDrift")
+
+	tests := []struct {
+		name          string
+		policy        datadoghqv1alpha1.DriftPolicy
+		defaultPolicy datadoghqv1alpha1.DriftPolicy
+		drift         bool
+		wantUpdate    bool
+		wantDrifted   []string
+		wantEvent     bool
+	}{
+		{
+			name:       "no drift",
+			wantUpdate: false,
+		},
+		{
+			name:        "drift reverted by default",
+			drift:       true,
+			wantUpdate:  true,
+			wantDrifted: []string{"message", "name"},
+			wantEvent:   true,
+		},
+		{
+			name:          "drift reported with the default policy of the operator",
+			defaultPolicy: datadoghqv1alpha1.DriftPolicyReport,
+			drift:         true,
+			wantUpdate:    false,
+			wantDrifted:   []string{"message", "name"},
+			wantEvent:     true,
+		},
+		{
+			name:          "drift reverted with the policy of the DatadogMonitor",
+			policy:        datadoghqv1alpha1.DriftPolicyRevert,
+			defaultPolicy: datadoghqv1alpha1.DriftPolicyReport,
+			drift:         true,
+			wantUpdate:    true,
+			wantDrifted:   []string{"message", "name"},
+			wantEvent:     true,
+		},
+		{
+			name:       "drift ignored",
+			policy:     datadoghqv1alpha1.DriftPolicyIgnore,
+			drift:      true,
+			wantUpdate: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{
+				recorder:           recorder,
+				log:                logger,
+				defaultDriftPolicy: tt.defaultPolicy,
+			}
+			dm := genericDatadogMonitor()
+			dm.Spec.ControllerOptions.DriftPolicy = tt.policy
+			m, _ := buildMonitor(logger, dm)
+			if tt.drift {
+				m.SetName("renamed in Datadog")
+				m.SetMessage("modified in Datadog")
+			}
+			status := &datadoghqv1alpha1.DatadogMonitorStatus{DriftedFields: []string{"query"}}
+			now := metav1.Now()
+
+			assert.Equal(t, tt.wantUpdate, r.checkDrift(logger, dm, *m, status, now))
+			assert.Equal(t, tt.wantDrifted, status.DriftedFields)
+			assert.Equal(t, &now, status.MonitorLastForceSyncTime)
+			if tt.wantEvent {
+				assert.Len(t, recorder.Events, 1)
+				assert.Contains(t, <-recorder.Events, "fields modified outside Kubernetes")
+			} else {
+				assert.Empty(t, recorder.Events)
+			}
+		})
+	}
+}
@@ -8,9 +8,11 @@ package datadogmonitor
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/DataDog/datadog-operator/pkg/controller/utils"
@@ -25,7 +27,9 @@ func (r *Reconciler) handleFinalizer(logger logr.Logger, dm *datadoghqv1alpha1.D
 	// Check if the DatadogMonitor instance is marked to be deleted, which is indicated by the deletion timestamp being set.
 	if dm.GetDeletionTimestamp() != nil {
 		if utils.ContainsString(dm.GetFinalizers(), datadogMonitorFinalizer) {
-			r.finalizeDatadogMonitor(logger, dm)
+			if err := r.finalizeDatadogMonitor(logger, dm); err != nil {
+				return ctrl.Result{RequeueAfter: defaultErrRequeuePeriod}, err
+			}
 
 			dm.SetFinalizers(utils.RemoveString(dm.GetFinalizers(), datadogMonitorFinalizer))
 			err := r.client.Update(context.TODO(), dm)
@@ -51,18 +55,54 @@ func (r *Reconciler) handleFinalizer(logger logr.Logger, dm *datadoghqv1alpha1.D
 	return ctrl.Result{}, nil
 }
 
-func (r *Reconciler) finalizeDatadogMonitor(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor) {
-	if dm.Status.Primary {
-		err := deleteMonitor(r.datadogAuth, r.datadogClient, dm.Status.ID)
-		if err != nil {
-			logger.Error(err, "failed to finalize monitor", "Monitor ID", fmt.Sprint(dm.Status.ID))
+// finalizeDatadogMonitor deletes or orphans the monitor of a deleted DatadogMonitor, with the credentials it was created
+// with. It returns an error while the DatadogMonitor must keep its finalizer: the finalization is retried until the
+// monitor is deleted or orphaned, or is not found in Datadog.
+func (r *Reconciler) finalizeDatadogMonitor(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor) error {
+	if !dm.Status.Primary {
+		return nil
+	}
+
+	ddClient, err := r.recordedDatadogClient(context.TODO(), logger, dm)
+	if err != nil {
+		return r.handleDeletionCredentialsError(logger, dm, err)
+	}
 
-			return
+	if dm.Spec.DeletionPolicy.IsOrphan() {
+		// Keep the monitor in Datadog, released from Kubernetes
+		err = orphanMonitor(ddClient.Auth, ddClient.Client, dm.Status.ID)
+		if err != nil && !strings.Contains(err.Error(), utils.NotFoundString) {
+			logger.Error(err, "failed to orphan monitor", "Monitor ID", fmt.Sprint(dm.Status.ID))
+			r.recordFinalizeError(dm, datadog.OrphanEvent, err)
+
+			return err
 		}
-		logger.Info("Successfully finalized DatadogMonitor", "Monitor ID", fmt.Sprint(dm.Status.ID))
-		event := buildEventInfo(dm.Name, dm.Namespace, datadog.DeletionEvent)
+		logger.Info("Successfully orphaned DatadogMonitor", "Monitor ID", fmt.Sprint(dm.Status.ID))
+		event := buildEventInfo(dm.Name, dm.Namespace, datadog.OrphanEvent)
 		r.recordEvent(dm, event)
+
+		return nil
 	}
+
+	err = deleteMonitor(ddClient.Auth, ddClient.Client, dm.Status.ID)
+	if err != nil && !strings.Contains(err.Error(), utils.NotFoundString) {
+		logger.Error(err, "failed to finalize monitor", "Monitor ID", fmt.Sprint(dm.Status.ID))
+		r.recordFinalizeError(dm, datadog.DeletionEvent, err)
+
+		return err
+	}
+	logger.Info("Successfully finalized DatadogMonitor", "Monitor ID", fmt.Sprint(dm.Status.ID))
+	event := buildEventInfo(dm.Name, dm.Namespace, datadog.DeletionEvent)
+	r.recordEvent(dm, event)
+
+	return nil
+}
+
+// recordFinalizeError reports that the monitor of a deleted DatadogMonitor couldn't be deleted or orphaned, so that the
+// DatadogMonitor keeps its finalizer.
+func (r *Reconciler) recordFinalizeError(dm *datadoghqv1alpha1.DatadogMonitor, eventType datadog.EventType, err error) {
+	event := buildEventInfo(dm.Name, dm.Namespace, eventType)
+	r.recorder.Event(dm, corev1.EventTypeWarning, event.GetReason(), fmt.Sprintf("%s: failed to finalize monitor %d, retrying: %v", event.GetMessage(), dm.Status.ID, err))
 }
 
 func (r *Reconciler) addFinalizer(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor) error {
@@ -7,6 +7,10 @@ package datadogmonitor
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -14,9 +18,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
+	datadogapi "github.com/DataDog/datadog-api-client-go/v2/api/datadog"
+	datadogV1 "github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils"
 )
@@ -85,3 +92,164 @@ func Test_handleFinalizer(t *testing.T) {
 		})
 	}
 }
+
+func Test_handleFinalizerDeletionPolicy(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogMonitor{})
+	metaNow := metav1.NewTime(time.Now())
+
+	remote, _ := buildMonitor(testLogger, genericDatadogMonitor())
+	remote.SetId(1234)
+	remote.SetTags([]string{"env:prod", "generated:kubernetes"})
+	jsonMonitor, _ := remote.MarshalJSON()
+
+	testCases := []struct {
+		name           string
+		deletionPolicy datadoghqv1alpha1.DeletionPolicy
+		wantRequests   []string
+		wantTags       []string
+	}{
+		{
+			name:         "default deletion policy deletes the monitor",
+			wantRequests: []string{"DELETE /api/v1/monitor/1234"},
+		},
+		{
+			name:           "orphan deletion policy keeps the monitor without the required tags",
+			deletionPolicy: datadoghqv1alpha1.DeletionPolicyOrphan,
+			wantRequests:   []string{"GET /api/v1/monitor/1234", "PUT /api/v1/monitor/1234"},
+			wantTags:       []string{"env:prod"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			var requests []string
+			var updateRequest datadogV1.MonitorUpdateRequest
+			httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				switch r.Method {
+				case http.MethodDelete:
+					_, _ = w.Write([]byte(`{"deleted_monitor_id": 1234}`))
+				case http.MethodPut:
+					body, _ := io.ReadAll(r.Body)
+					_ = json.Unmarshal(body, &updateRequest)
+					_, _ = w.Write(jsonMonitor)
+				default:
+					_, _ = w.Write(jsonMonitor)
+				}
+			}))
+			defer httpServer.Close()
+
+			testConfig := datadogapi.NewConfiguration()
+			testConfig.HTTPClient = httpServer.Client()
+
+			dm := genericDatadogMonitor()
+			dm.Spec.DeletionPolicy = test.deletionPolicy
+			dm.Finalizers = []string{datadogMonitorFinalizer}
+			dm.DeletionTimestamp = &metaNow
+			dm.Status.ID = 1234
+			dm.Status.Primary = true
+
+			r := &Reconciler{
+				client:        fake.NewClientBuilder().WithScheme(s).WithObjects(dm).Build(),
+				datadogClient: datadogV1.NewMonitorsApi(datadogapi.NewAPIClient(testConfig)),
+				datadogAuth:   setupTestAuth(httpServer.URL),
+				scheme:        s,
+				recorder:      record.NewFakeRecorder(10),
+				log:           testLogger,
+			}
+
+			_, err := r.handleFinalizer(testLogger, dm)
+			assert.NoError(t, err)
+			assert.False(t, utils.ContainsString(dm.GetFinalizers(), datadogMonitorFinalizer))
+			assert.Equal(t, test.wantRequests, requests)
+			assert.Equal(t, test.wantTags, updateRequest.Tags)
+		})
+	}
+}
+
+func Test_handleFinalizerFailure(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogMonitor{})
+	metaNow := metav1.NewTime(time.Now())
+
+	remote, _ := buildMonitor(testLogger, genericDatadogMonitor())
+	remote.SetId(1234)
+	jsonMonitor, _ := remote.MarshalJSON()
+
+	testCases := []struct {
+		name                 string
+		deletionPolicy       datadoghqv1alpha1.DeletionPolicy
+		statusCode           int
+		wantErr              bool
+		finalizerShouldExist bool
+		wantEvent            string
+	}{
+		{
+			name:                 "monitor deletion failure keeps the finalizer",
+			statusCode:           http.StatusInternalServerError,
+			wantErr:              true,
+			finalizerShouldExist: true,
+			wantEvent:            "Warning Delete DatadogMonitor bar/foo: failed to finalize monitor 1234, retrying",
+		},
+		{
+			name:                 "monitor orphaning failure keeps the finalizer",
+			deletionPolicy:       datadoghqv1alpha1.DeletionPolicyOrphan,
+			statusCode:           http.StatusInternalServerError,
+			wantErr:              true,
+			finalizerShouldExist: true,
+			wantEvent:            "Warning Orphan DatadogMonitor bar/foo: failed to finalize monitor 1234, retrying",
+		},
+		{
+			name:       "monitor already deleted in Datadog",
+			statusCode: http.StatusNotFound,
+			wantEvent:  "Normal Delete DatadogMonitor bar/foo",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.Method == http.MethodGet {
+					_, _ = w.Write(jsonMonitor)
+					return
+				}
+				w.WriteHeader(test.statusCode)
+				_, _ = w.Write([]byte(`{"errors": ["error"]}`))
+			}))
+			defer httpServer.Close()
+
+			testConfig := datadogapi.NewConfiguration()
+			testConfig.HTTPClient = httpServer.Client()
+
+			dm := genericDatadogMonitor()
+			dm.Spec.DeletionPolicy = test.deletionPolicy
+			dm.Finalizers = []string{datadogMonitorFinalizer}
+			dm.DeletionTimestamp = &metaNow
+			dm.Status.ID = 1234
+			dm.Status.Primary = true
+
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{
+				client:        fake.NewClientBuilder().WithScheme(s).WithObjects(dm).Build(),
+				datadogClient: datadogV1.NewMonitorsApi(datadogapi.NewAPIClient(testConfig)),
+				datadogAuth:   setupTestAuth(httpServer.URL),
+				scheme:        s,
+				recorder:      recorder,
+				log:           testLogger,
+			}
+
+			_, err := r.handleFinalizer(testLogger, dm)
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.finalizerShouldExist, utils.ContainsString(dm.GetFinalizers(), datadogMonitorFinalizer))
+			assert.Len(t, recorder.Events, 1)
+			assert.Contains(t, <-recorder.Events, test.wantEvent)
+		})
+	}
+}
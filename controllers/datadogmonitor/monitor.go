@@ -7,17 +7,15 @@ package datadogmonitor
 
 import (
 	"context"
-	"errors"
-	"fmt"
-	"net/url"
 	"sort"
 	"strconv"
 
 	"github.com/go-logr/logr"
 
-	datadogapi "github.com/DataDog/datadog-api-client-go/v2/api/datadog"
 	datadogV1 "github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/controllers/utils"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
 )
 
 func buildMonitor(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor) (*datadogV1.Monitor, *datadogV1.MonitorUpdateRequest) {
@@ -199,7 +197,7 @@ func getMonitor(auth context.Context, client *datadogV1.MonitorsApi, monitorID i
 	}
 	m, _, err := client.GetMonitor(auth, int64(monitorID), optionalParams)
 	if err != nil {
-		return datadogV1.Monitor{}, translateClientError(err, "error getting monitor")
+		return datadogV1.Monitor{}, datadogclient.TranslateClientError(err, "error getting monitor")
 	}
 
 	return m, nil
@@ -208,7 +206,7 @@ func getMonitor(auth context.Context, client *datadogV1.MonitorsApi, monitorID i
 func validateMonitor(auth context.Context, logger logr.Logger, client *datadogV1.MonitorsApi, dm *datadoghqv1alpha1.DatadogMonitor) error {
 	m, _ := buildMonitor(logger, dm)
 	if _, _, err := client.ValidateMonitor(auth, *m); err != nil {
-		return translateClientError(err, "error validating monitor")
+		return datadogclient.TranslateClientError(err, "error validating monitor")
 	}
 
 	return nil
@@ -218,7 +216,7 @@ func createMonitor(auth context.Context, logger logr.Logger, client *datadogV1.M
 	m, _ := buildMonitor(logger, dm)
 	mCreated, _, err := client.CreateMonitor(auth, *m)
 	if err != nil {
-		return datadogV1.Monitor{}, translateClientError(err, "error creating monitor")
+		return datadogV1.Monitor{}, datadogclient.TranslateClientError(err, "error creating monitor")
 	}
 
 	return mCreated, nil
@@ -229,7 +227,7 @@ func updateMonitor(auth context.Context, logger logr.Logger, client *datadogV1.M
 
 	mUpdated, _, err := client.UpdateMonitor(auth, int64(dm.Status.ID), *u)
 	if err != nil {
-		return datadogV1.Monitor{}, translateClientError(err, "error updating monitor")
+		return datadogV1.Monitor{}, datadogclient.TranslateClientError(err, "error updating monitor")
 	}
 
 	// TODO additional logic to handle downtimes (and silenced param if needed)
@@ -243,26 +241,24 @@ func deleteMonitor(auth context.Context, client *datadogV1.MonitorsApi, monitorI
 		Force: &force,
 	}
 	if _, _, err := client.DeleteMonitor(auth, int64(monitorID), optionalParams); err != nil {
-		return translateClientError(err, "error deleting monitor")
+		return datadogclient.TranslateClientError(err, "error deleting monitor")
 	}
 
 	return nil
 }
 
-func translateClientError(err error, msg string) error {
-	if msg == "" {
-		msg = "an error occurred"
-	}
-
-	var apiErr datadogapi.GenericOpenAPIError
-	var errURL *url.Error
-	if errors.As(err, &apiErr) {
-		return fmt.Errorf(msg+": %w: %s", err, apiErr.Body())
+// orphanMonitor removes the required tags from a monitor, so that it is no longer marked as managed by Kubernetes.
+// The other fields of the monitor are left untouched.
+func orphanMonitor(auth context.Context, client *datadogV1.MonitorsApi, monitorID int) error {
+	m, err := getMonitor(auth, client, monitorID)
+	if err != nil {
+		return err
 	}
 
-	if errors.As(err, &errURL) {
-		return fmt.Errorf(msg+" (url.Error): %s", errURL)
+	updateRequest := datadogV1.MonitorUpdateRequest{Tags: utils.RemoveRequiredTags(m.GetTags())}
+	if _, _, err = client.UpdateMonitor(auth, int64(monitorID), updateRequest); err != nil {
+		return datadogclient.TranslateClientError(err, "error orphaning monitor")
 	}
 
-	return fmt.Errorf(msg+": %w", err)
+	return nil
 }
@@ -9,11 +9,19 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
 	"github.com/DataDog/datadog-operator/controllers/datadogmonitor"
@@ -24,16 +32,19 @@ import (
 type DatadogMonitorReconciler struct {
 	Client      client.Client
 	DDClient    datadogclient.DatadogMonitorClient
+	DDClients   *datadogclient.ClientCache
 	VersionInfo *version.Info
 	Log         logr.Logger
 	Scheme      *runtime.Scheme
 	Recorder    record.EventRecorder
+	DriftPolicy datadoghqv1alpha1.DriftPolicy
 	internal    *datadogmonitor.Reconciler
 }
 
 // +kubebuilder:rbac:groups=datadoghq.com,resources=datadogmonitors,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=datadoghq.com,resources=datadogmonitors/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=datadoghq.com,resources=datadogmonitors/finalizers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 
 // Reconcile loop for DatadogMonitor.
 func (r *DatadogMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -42,14 +53,22 @@ func (r *DatadogMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 // SetupWithManager creates a new DatadogMonitor controller.
 func (r *DatadogMonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	internal, err := datadogmonitor.NewReconciler(r.Client, r.DDClient, r.VersionInfo, r.Scheme, r.Log, r.Recorder)
+	internal, err := datadogmonitor.NewReconciler(r.Client, r.DDClient, r.DDClients, r.VersionInfo, r.Scheme, r.Log, r.Recorder, r.DriftPolicy)
 	if err != nil {
 		return err
 	}
 	r.internal = internal
 
 	builder := ctrl.NewControllerManagedBy(mgr).
-		For(&datadoghqv1alpha1.DatadogMonitor{})
+		For(&datadoghqv1alpha1.DatadogMonitor{}).
+		// The query of a composite monitor refers to the IDs of the monitors of other DatadogMonitors
+		Watches(
+			&source.Kind{Type: &datadoghqv1alpha1.DatadogMonitor{}},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueRequestsForReferencingMonitors()),
+			ctrlbuilder.WithPredicates(monitorIDChangedPredicate()),
+		).
+		// The Datadog client of the credentials of a Secret is dropped when the Secret is deleted
+		Watches(&source.Kind{Type: &corev1.Secret{}}, removeDeletedCredentials(r.DDClients))
 
 	err = builder.Complete(r)
 	if err != nil {
@@ -58,3 +77,42 @@ func (r *DatadogMonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	return nil
 }
+
+// enqueueRequestsForReferencingMonitors returns the composite DatadogMonitors whose query refers to a DatadogMonitor.
+func (r *DatadogMonitorReconciler) enqueueRequestsForReferencingMonitors() handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		var requests []reconcile.Request
+
+		monitorList := datadoghqv1alpha1.DatadogMonitorList{}
+		if err := r.Client.List(context.Background(), &monitorList); err != nil {
+			return requests
+		}
+
+		key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+		for i := range monitorList.Items {
+			monitor := &monitorList.Items[i]
+			if monitor.Spec.Type != datadoghqv1alpha1.DatadogMonitorTypeComposite {
+				continue
+			}
+			for _, reference := range datadogmonitor.ReferencedMonitors(monitor) {
+				if reference == key {
+					requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: monitor.Namespace, Name: monitor.Name}})
+					break
+				}
+			}
+		}
+
+		return requests
+	}
+}
+
+// monitorIDChangedPredicate filters out the DatadogMonitor updates that don't change the ID of the monitor.
+func monitorIDChangedPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldMonitor, oldOk := e.ObjectOld.(*datadoghqv1alpha1.DatadogMonitor)
+			newMonitor, newOk := e.ObjectNew.(*datadoghqv1alpha1.DatadogMonitor)
+			return !oldOk || !newOk || oldMonitor.Status.ID != newMonitor.Status.ID
+		},
+	}
+}
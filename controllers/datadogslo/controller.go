@@ -48,19 +48,25 @@ type Reconciler struct {
 	client        client.Client
 	datadogClient *datadogV1.ServiceLevelObjectivesApi
 	datadogAuth   context.Context
+	clients       *datadogclient.ClientCache
 	versionInfo   *version.Info
 	log           logr.Logger
 	recorder      record.EventRecorder
+
+	defaultDriftPolicy v1alpha1.DriftPolicy
 }
 
-func NewReconciler(client client.Client, ddClient datadogclient.DatadogSLOClient, versionInfo *version.Info, log logr.Logger, recorder record.EventRecorder) *Reconciler {
+func NewReconciler(client client.Client, ddClient datadogclient.DatadogSLOClient, clients *datadogclient.ClientCache, versionInfo *version.Info, log logr.Logger, recorder record.EventRecorder, defaultDriftPolicy v1alpha1.DriftPolicy) *Reconciler {
 	return &Reconciler{
 		client:        client,
 		datadogClient: ddClient.Client,
 		datadogAuth:   ddClient.Auth,
+		clients:       clients,
 		versionInfo:   versionInfo,
 		log:           log,
 		recorder:      recorder,
+
+		defaultDriftPolicy: defaultDriftPolicy,
 	}
 }
 
@@ -108,6 +114,15 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 		return r.updateStatusIfNeeded(logger, instance, status, result)
 	}
 
+	// Get the Datadog client of the credentials of the SLO
+	ddClient, credentials, err := r.datadogClientFor(ctx, logger, instance)
+	if err != nil {
+		logger.Error(err, "error getting the Datadog credentials")
+		updateErrStatus(status, now, v1alpha1.DatadogSLOSyncStatusCredentialsError, "GettingCredentials", err)
+		result.RequeueAfter = defaultErrRequeuePeriod
+		return r.updateStatusIfNeeded(logger, instance, status, result)
+	}
+
 	instanceSpecHash, err := comparison.GenerateMD5ForSpec(&instance.Spec)
 	if err != nil {
 		logger.Error(err, "error generating hash")
@@ -123,17 +138,21 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 	} else {
 		if instanceSpecHash != statusSpecHash {
 			shouldUpdate = true
+			status.DriftedFields = nil
 		} else if instance.Status.LastForceSyncTime == nil || (defaultForceSyncPeriod-now.Sub(instance.Status.LastForceSyncTime.Time)) <= 0 {
-			// Periodically force a sync with the API SLO to ensure parity
+			// Periodically check the API SLO for drift, and revert it depending on the drift policy
 			// Get SLO to make sure it exists before trying any updates. If it doesn't, set shouldCreate
-			_, err = r.get(instance)
+			var slo *datadogV1.SLOResponseData
+			slo, err = r.get(ddClient, instance)
 			if err != nil {
 				logger.Error(err, "error getting SLO", "SLO ID", instance.Status.ID)
 				if strings.Contains(err.Error(), ctrutils.NotFoundString) {
 					shouldCreate = true
 				}
-			} else {
+			} else if slo == nil {
 				shouldUpdate = true
+			} else {
+				shouldUpdate = r.checkDrift(logger, instance, *slo, status)
 			}
 			status.LastForceSyncTime = &now
 		}
@@ -144,7 +163,7 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 		if result, err = r.checkRequiredTags(logger, instance); err != nil || result.Requeue {
 			return r.updateStatusIfNeeded(logger, instance, status, result)
 		}
-		err = r.create(logger, instance, status, now, instanceSpecHash)
+		err = r.create(ddClient, logger, instance, status, now, instanceSpecHash)
 		if err != nil {
 			result.RequeueAfter = defaultErrRequeuePeriod
 		}
@@ -153,12 +172,23 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 		if result, err = r.checkRequiredTags(logger, instance); err != nil || result.Requeue {
 			return r.updateStatusIfNeeded(logger, instance, status, result)
 		}
-		err = r.update(logger, instance, status, now, instanceSpecHash)
+		err = r.update(ddClient, logger, instance, status, now, instanceSpecHash)
 		if err != nil {
 			result.RequeueAfter = defaultErrRequeuePeriod
 		}
 	}
 
+	// Record the credentials of the SLO, which are used to delete it. The SLOs created before the credentials were
+	// recorded are managed with the current credentials.
+	if err == nil && status.ID != "" && (shouldCreate || status.Credentials == nil) {
+		status.Credentials = credentials
+	}
+
+	// Retry when the rate limit of the Datadog API endpoint is reset
+	if retryAfter, throttled := datadogclient.RetryAfter(err); throttled {
+		result.RequeueAfter = retryAfter
+	}
+
 	// If reconcile was successful, requeue with period defaultRequeuePeriod
 	if !result.Requeue && result.RequeueAfter == 0 {
 		result.RequeueAfter = defaultRequeuePeriod
@@ -235,11 +265,11 @@ func (r *Reconciler) updateStatusIfNeeded(logger logr.Logger, instance *v1alpha1
 	return result, nil
 }
 
-func (r *Reconciler) create(logger logr.Logger, instance *v1alpha1.DatadogSLO, status *v1alpha1.DatadogSLOStatus, now metav1.Time, hash string) error {
+func (r *Reconciler) create(ddClient datadogclient.DatadogSLOClient, logger logr.Logger, instance *v1alpha1.DatadogSLO, status *v1alpha1.DatadogSLOStatus, now metav1.Time, hash string) error {
 	logger.V(1).Info("SLO ID is not set; creating SLO in Datadog")
 
 	// Create SLO in Datadog
-	createdSLO, err := createSLO(r.datadogAuth, r.datadogClient, instance)
+	createdSLO, err := createSLO(ddClient.Auth, ddClient.Client, instance)
 	if err != nil {
 		logger.Error(err, "error creating SLO")
 		updateErrStatus(status, now, v1alpha1.DatadogSLOSyncStatusCreateError, "CreatingSLO", err)
@@ -263,12 +293,12 @@ func (r *Reconciler) create(logger logr.Logger, instance *v1alpha1.DatadogSLO, s
 	return nil
 }
 
-func (r *Reconciler) get(instance *v1alpha1.DatadogSLO) (*datadogV1.SLOResponseData, error) {
-	return getSLO(r.datadogAuth, r.datadogClient, instance.Status.ID)
+func (r *Reconciler) get(ddClient datadogclient.DatadogSLOClient, instance *v1alpha1.DatadogSLO) (*datadogV1.SLOResponseData, error) {
+	return getSLO(ddClient.Auth, ddClient.Client, instance.Status.ID)
 }
 
-func (r *Reconciler) update(logger logr.Logger, instance *v1alpha1.DatadogSLO, status *v1alpha1.DatadogSLOStatus, now metav1.Time, hash string) error {
-	if _, err := updateSLO(r.datadogAuth, r.datadogClient, instance); err != nil {
+func (r *Reconciler) update(ddClient datadogclient.DatadogSLOClient, logger logr.Logger, instance *v1alpha1.DatadogSLO, status *v1alpha1.DatadogSLOStatus, now metav1.Time, hash string) error {
+	if _, err := updateSLO(ddClient.Auth, ddClient.Client, instance); err != nil {
 		logger.Error(err, "error updating SLO", "SLO ID", instance.Status.ID)
 		updateErrStatus(status, now, v1alpha1.DatadogSLOSyncStatusUpdateError, "UpdatingSLO", err)
 		return err
@@ -288,7 +318,21 @@ func (r *Reconciler) deleteResource(logger logr.Logger, instance *v1alpha1.Datad
 	return func(ctx context.Context, k8sObj client.Object, datadogID string) error {
 		if datadogID != "" {
 			kind := k8sObj.GetObjectKind().GroupVersionKind().Kind
-			if err := deleteSLO(r.datadogAuth, r.datadogClient, datadogID); err != nil {
+			ddClient, err := r.recordedDatadogClient(ctx, logger, instance)
+			if err != nil {
+				return r.handleDeletionCredentialsError(logger, instance, datadogID, err)
+			}
+			if instance.Spec.DeletionPolicy.IsOrphan() {
+				// Keep the SLO in Datadog, released from Kubernetes
+				if err := orphanSLO(ddClient.Auth, ddClient.Client, datadogID); err != nil && !strings.Contains(err.Error(), ctrutils.NotFoundString) {
+					logger.Error(err, "error orphaning SLO", "kind", kind, "ID", datadogID)
+					return err
+				}
+				logger.Info("Successfully orphaned object", "kind", kind, "ID", datadogID)
+				r.recordEvent(instance, buildEventInfo(k8sObj.GetName(), k8sObj.GetNamespace(), datadog.OrphanEvent))
+				return nil
+			}
+			if err := deleteSLO(ddClient.Auth, ddClient.Client, datadogID); err != nil {
 				logger.Error(err, "error deleting SLO", "kind", kind, "ID", datadogID)
 				return err
 			}
@@ -16,6 +16,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -31,6 +33,8 @@ import (
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
 
 	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/controllers/utils"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
 )
 
 const (
@@ -155,6 +159,160 @@ func TestReconciler_Reconcile(t *testing.T) {
 	}
 }
 
+func TestReconciler_deleteResource(t *testing.T) {
+	testLogger := zap.New(zap.UseDevMode(true))
+	sloType := datadogV1.SLOTYPE_METRIC
+	remote := datadogV1.SLOResponse{
+		Data: &datadogV1.SLOResponseData{
+			Id:   ptrString("SLO123"),
+			Name: ptrString("Test"),
+			Query: &datadogV1.ServiceLevelObjectiveQuery{
+				Denominator: "sum:my.custom.count.metric{*}.as_count()",
+				Numerator:   "sum:my.custom.count.metric{type:good_events}.as_count()",
+			},
+			Tags:       []string{"generated:kubernetes", "team:a"},
+			Thresholds: []datadogV1.SLOThreshold{{Timeframe: "7d", Target: 99}},
+			Type:       &sloType,
+		},
+	}
+
+	tests := []struct {
+		name           string
+		deletionPolicy v1alpha1.DeletionPolicy
+		remoteNotFound bool
+		wantRequests   []string
+		wantTags       []string
+	}{
+		{
+			name:         "default deletion policy deletes the SLO",
+			wantRequests: []string{"DELETE /api/v1/slo/SLO123"},
+		},
+		{
+			name:           "orphan deletion policy keeps the SLO without the required tags",
+			deletionPolicy: v1alpha1.DeletionPolicyOrphan,
+			wantRequests:   []string{"GET /api/v1/slo/SLO123", "PUT /api/v1/slo/SLO123"},
+			wantTags:       []string{"team:a"},
+		},
+		{
+			name:           "orphan deletion policy succeeds when the SLO is already deleted",
+			deletionPolicy: v1alpha1.DeletionPolicyOrphan,
+			remoteNotFound: true,
+			wantRequests:   []string{"GET /api/v1/slo/SLO123"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			var orphaned datadogV1.ServiceLevelObjective
+			httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				if tt.remoteNotFound {
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"errors": ["Not found"]}`))
+					return
+				}
+				switch r.Method {
+				case http.MethodDelete:
+					_ = json.NewEncoder(w).Encode(datadogV1.SLODeleteResponse{Data: []string{"SLO123"}})
+				case http.MethodPut:
+					_ = json.NewDecoder(r.Body).Decode(&orphaned)
+					_ = json.NewEncoder(w).Encode(defaultDatadogSLOResponse())
+				default:
+					_ = json.NewEncoder(w).Encode(remote)
+				}
+			}))
+			defer httpServer.Close()
+
+			testConfig := datadogapi.NewConfiguration()
+			testConfig.HTTPClient = httpServer.Client()
+			instance := defaultSLO()
+			instance.Spec.DeletionPolicy = tt.deletionPolicy
+			r := &Reconciler{
+				client:        fake.NewClientBuilder().Build(),
+				datadogClient: datadogV1.NewServiceLevelObjectivesApi(datadogapi.NewAPIClient(testConfig)),
+				datadogAuth:   setupTestAuth(httpServer.URL),
+				recorder:      record.NewFakeRecorder(5),
+				log:           testLogger,
+			}
+
+			err := r.deleteResource(testLogger, instance)(context.TODO(), instance, "SLO123")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantRequests, requests)
+			assert.Equal(t, tt.wantTags, orphaned.Tags)
+			if tt.wantTags != nil {
+				// The other fields of the SLO are left untouched
+				assert.Equal(t, "Test", orphaned.Name)
+				assert.Equal(t, remote.Data.Thresholds, orphaned.Thresholds)
+			}
+		})
+	}
+}
+
+func TestReconciler_deleteResourceWithDeletedCredentials(t *testing.T) {
+	testLogger := zap.New(zap.UseDevMode(true))
+	// The default credentials of the namespace must not be used instead of the deleted Secret
+	defaultSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: resourceNamespace, Name: "default", Labels: map[string]string{v1alpha1.DatadogCredentialsDefaultLabelKey: "true"}},
+		Data:       map[string][]byte{"api_key": []byte("default-api-key"), "app_key": []byte("default-app-key")},
+	}
+
+	tests := []struct {
+		name         string
+		deletedSince time.Duration
+		wantErr      bool
+		wantEvent    string
+	}{
+		{
+			name:         "the deletion is retried",
+			deletedSince: time.Minute,
+			wantErr:      true,
+			wantEvent:    "Warning Delete DatadogSLO default/slo: unable to get the Datadog credentials to delete SLO SLO123, retrying",
+		},
+		{
+			name:         "the SLO is left in Datadog after the timeout",
+			deletedSince: 2 * utils.CredentialsDeletionTimeout,
+			wantEvent:    "Warning Delete DatadogSLO default/slo: SLO SLO123 left in Datadog",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+			}))
+			defer httpServer.Close()
+
+			testConfig := datadogapi.NewConfiguration()
+			testConfig.HTTPClient = httpServer.Client()
+			instance := defaultSLO()
+			instance.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-tt.deletedSince)}
+			instance.Status.Credentials = &v1alpha1.DatadogCredentialsStatus{SecretName: "team-credentials"}
+			recorder := record.NewFakeRecorder(5)
+			r := &Reconciler{
+				client:        fake.NewClientBuilder().WithObjects(defaultSecret).Build(),
+				datadogClient: datadogV1.NewServiceLevelObjectivesApi(datadogapi.NewAPIClient(testConfig)),
+				datadogAuth:   setupTestAuth(httpServer.URL),
+				clients:       datadogclient.NewClientCache(),
+				recorder:      recorder,
+				log:           testLogger,
+			}
+
+			err := r.deleteResource(testLogger, instance)(context.TODO(), instance, "SLO123")
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Empty(t, requests)
+			require.Len(t, recorder.Events, 1)
+			assert.Contains(t, <-recorder.Events, tt.wantEvent)
+		})
+	}
+}
+
 func defaultSLO() *v1alpha1.DatadogSLO {
 	return &v1alpha1.DatadogSLO{
 		TypeMeta: metav1.TypeMeta{
@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogslo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/controllers/utils"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+// datadogClientFor returns the Datadog client of the credentials of a DatadogSLO, or the client of the operator
+// credentials when neither the DatadogSLO nor its namespace defines credentials. It also returns the status recording
+// these credentials. It returns an error when they differ from the recorded credentials the SLO was created with, so
+// that the SLO isn't duplicated with the new credentials.
+func (r *Reconciler) datadogClientFor(ctx context.Context, logger logr.Logger, instance *v1alpha1.DatadogSLO) (datadogclient.DatadogSLOClient, *v1alpha1.DatadogCredentialsStatus, error) {
+	secret, creds, err := utils.GetDatadogCredentials(ctx, r.client, instance.Namespace, instance.Spec.Credentials)
+	if err != nil {
+		return datadogclient.DatadogSLOClient{}, nil, err
+	}
+	if err = utils.CheckCredentialsUnchanged(instance.Status.Credentials, utils.CredentialsStatus(secret)); err != nil {
+		return datadogclient.DatadogSLOClient{}, nil, err
+	}
+	if secret == nil {
+		return datadogclient.DatadogSLOClient{Client: r.datadogClient, Auth: r.datadogAuth}, utils.CredentialsStatus(nil), nil
+	}
+
+	ddClient, err := r.clients.SLOClient(logger, *secret, creds)
+	return ddClient, utils.CredentialsStatus(secret), err
+}
+
+// recordedDatadogClient returns the Datadog client of the credentials the SLO of a DatadogSLO was created with.
+// The SLOs created before the credentials were recorded use the current credentials of the DatadogSLO.
+func (r *Reconciler) recordedDatadogClient(ctx context.Context, logger logr.Logger, instance *v1alpha1.DatadogSLO) (datadogclient.DatadogSLOClient, error) {
+	if instance.Status.Credentials == nil {
+		ddClient, _, err := r.datadogClientFor(ctx, logger, instance)
+		return ddClient, err
+	}
+
+	secret, creds, err := utils.GetRecordedDatadogCredentials(ctx, r.client, instance.Namespace, *instance.Status.Credentials)
+	if err != nil {
+		return datadogclient.DatadogSLOClient{}, err
+	}
+	if secret == nil {
+		return datadogclient.DatadogSLOClient{Client: r.datadogClient, Auth: r.datadogAuth}, nil
+	}
+
+	return r.clients.SLOClient(logger, *secret, creds)
+}
+
+// handleDeletionCredentialsError reports that the SLO of a deleted DatadogSLO can't be finalized because its
+// credentials can't be read, for example because their Secret was deleted first. The deletion is retried until
+// utils.CredentialsDeletionTimeout expires: the DatadogSLO is then released, and the SLO is left in Datadog.
+// It returns nil once the DatadogSLO can be released.
+func (r *Reconciler) handleDeletionCredentialsError(logger logr.Logger, instance *v1alpha1.DatadogSLO, datadogID string, err error) error {
+	event := buildEventInfo(instance.Name, instance.Namespace, datadog.DeletionEvent)
+	if utils.CredentialsDeletionExpired(instance, time.Now()) {
+		logger.Error(err, "error deleting SLO, the SLO is left in Datadog", "ID", datadogID)
+		r.recorder.Event(instance, corev1.EventTypeWarning, event.GetReason(), fmt.Sprintf("%s: SLO %s left in Datadog, unable to get the Datadog credentials for %s: %v", event.GetMessage(), datadogID, utils.CredentialsDeletionTimeout, err))
+		return nil
+	}
+
+	logger.Error(err, "error getting the Datadog credentials", "ID", datadogID)
+	r.recorder.Event(instance, corev1.EventTypeWarning, event.GetReason(), fmt.Sprintf("%s: unable to get the Datadog credentials to delete SLO %s, retrying: %v", event.GetMessage(), datadogID, err))
+	return err
+}
@@ -0,0 +1,70 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogslo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	datadogapi "github.com/DataDog/datadog-api-client-go/v2/api/datadog"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+func TestReconciler_datadogClientFor(t *testing.T) {
+	logger := logf.Log.WithName("TestReconciler_datadogClientFor")
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: resourceNamespace, Name: "team-credentials"},
+		Data: map[string][]byte{
+			"api_key": []byte("team-api-key"),
+			"app_key": []byte("team-app-key"),
+			"site":    []byte("datadoghq.eu"),
+		},
+	}
+	r := &Reconciler{
+		client:      fake.NewClientBuilder().WithObjects(secret).Build(),
+		datadogAuth: setupTestAuth("https://api.datadoghq.com"),
+		clients:     datadogclient.NewClientCache(),
+		log:         logger,
+	}
+
+	// Credentials of the operator
+	crdSLO := defaultSLO()
+	ddClient, credentials, err := r.datadogClientFor(context.TODO(), logger, crdSLO)
+	require.NoError(t, err)
+	assert.Equal(t, r.datadogAuth, ddClient.Auth)
+	assert.Equal(t, &v1alpha1.DatadogCredentialsStatus{}, credentials)
+
+	// Credentials of the DatadogSLO
+	crdSLO.Spec.Credentials = &v1alpha1.DatadogCredentialsReference{SecretName: "team-credentials"}
+	ddClient, credentials, err = r.datadogClientFor(context.TODO(), logger, crdSLO)
+	require.NoError(t, err)
+	assert.Equal(t, &v1alpha1.DatadogCredentialsStatus{SecretName: "team-credentials"}, credentials)
+	assert.Equal(t, "team-api-key", ddClient.Auth.Value(datadogapi.ContextAPIKeys).(map[string]datadogapi.APIKey)["apiKeyAuth"].Key)
+	assert.Equal(t, map[string]string{"name": "api.datadoghq.eu", "protocol": "https"}, ddClient.Auth.Value(datadogapi.ContextServerVariables))
+
+	// Credentials different from the ones the SLO was created with
+	crdSLO.Status.ID = "SLO123"
+	crdSLO.Status.Credentials = &v1alpha1.DatadogCredentialsStatus{}
+	_, _, err = r.datadogClientFor(context.TODO(), logger, crdSLO)
+	assert.EqualError(t, err, "the Datadog credentials changed from the credentials of the operator to the Secret team-credentials since the Datadog object was created, recreate the resource to use the new credentials")
+	crdSLO.Status.Credentials = &v1alpha1.DatadogCredentialsStatus{SecretName: "team-credentials"}
+	_, _, err = r.datadogClientFor(context.TODO(), logger, crdSLO)
+	assert.NoError(t, err)
+
+	// Missing Secret
+	crdSLO.Spec.Credentials = &v1alpha1.DatadogCredentialsReference{SecretName: "other-credentials"}
+	_, _, err = r.datadogClientFor(context.TODO(), logger, crdSLO)
+	assert.Error(t, err)
+}
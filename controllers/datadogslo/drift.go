@@ -0,0 +1,46 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogslo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
+)
+
+// checkDrift compares the SLO in Datadog with the SLO rendered from the spec, and reports the drifted fields
+// in the status and in an event. It returns true if the SLO must be updated to revert the drift.
+func (r *Reconciler) checkDrift(logger logr.Logger, instance *v1alpha1.DatadogSLO, slo datadogV1.SLOResponseData, status *v1alpha1.DatadogSLOStatus) bool {
+	var policy v1alpha1.DriftPolicy
+	if instance.Spec.ControllerOptions != nil {
+		policy = instance.Spec.ControllerOptions.DriftPolicy
+	}
+	policy = v1alpha1.GetDriftPolicy(policy, r.defaultDriftPolicy)
+	if policy == v1alpha1.DriftPolicyIgnore {
+		status.DriftedFields = nil
+		return false
+	}
+
+	drifted := sloDifferences(instance, slo)
+	if len(drifted) == 0 {
+		status.DriftedFields = nil
+		return false
+	}
+
+	status.DriftedFields = drifted
+	logger.Info("SLO modified outside Kubernetes", "SLO ID", instance.Status.ID, "Drifted fields", drifted, "Drift policy", policy)
+	event := buildEventInfo(instance.Name, instance.Namespace, datadog.DriftEvent)
+	r.recorder.Event(instance, corev1.EventTypeWarning, event.GetReason(), fmt.Sprintf("%s: fields modified outside Kubernetes (drift policy %s): %s", event.GetMessage(), policy, strings.Join(drifted, ", ")))
+
+	return policy == v1alpha1.DriftPolicyRevert
+}
@@ -0,0 +1,169 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogslo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/tools/record"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+func Test_sloDifferences(t *testing.T) {
+	tests := []struct {
+		name   string
+		crdSLO func() *v1alpha1.DatadogSLO
+		modify func(slo *datadogV1.SLOResponseData)
+		want   []string
+	}{
+		{
+			name:   "SLO matching the spec",
+			crdSLO: defaultSLO,
+			modify: func(slo *datadogV1.SLOResponseData) {},
+			want:   []string{},
+		},
+		{
+			name:   "tags in another order and threshold for another timeframe",
+			crdSLO: defaultSLO,
+			modify: func(slo *datadogV1.SLOResponseData) {
+				slo.SetTags([]string{"team:foo", "env:prod"})
+				slo.SetThresholds(append(slo.GetThresholds(), datadogV1.SLOThreshold{Timeframe: datadogV1.SLOTIMEFRAME_SEVEN_DAYS, Target: 95}))
+			},
+			want: []string{},
+		},
+		{
+			name:   "metric SLO modified in Datadog",
+			crdSLO: defaultSLO,
+			modify: func(slo *datadogV1.SLOResponseData) {
+				slo.SetName("renamed SLO")
+				slo.SetDescription("modified in Datadog")
+				slo.SetTags([]string{"env:prod"})
+				slo.SetQuery(datadogV1.ServiceLevelObjectiveQuery{Numerator: "sum:other.metric{*}.as_count()", Denominator: "sum:my.custom.count.metric{*}.as_count()"})
+				slo.SetThresholds([]datadogV1.SLOThreshold{{Timeframe: datadogV1.SLOTIMEFRAME_THIRTY_DAYS, Target: 95, Warning: float64Ptr(99.5)}})
+			},
+			want: []string{"description", "name", "query", "tags", "targetThreshold", "warningThreshold"},
+		},
+		{
+			name: "monitor SLO modified in Datadog",
+			crdSLO: func() *v1alpha1.DatadogSLO {
+				crdSLO := defaultSLO()
+				crdSLO.Spec.Type = v1alpha1.DatadogSLOTypeMonitor
+				crdSLO.Spec.Query = nil
+				crdSLO.Spec.MonitorIDs = []int64{1, 2}
+				crdSLO.Spec.Groups = []string{"host:a"}
+				return crdSLO
+			},
+			modify: func(slo *datadogV1.SLOResponseData) {
+				slo.SetMonitorIds([]int64{2, 3})
+				slo.SetGroups([]string{"host:a", "host:b"})
+				slo.SetThresholds([]datadogV1.SLOThreshold{{Timeframe: datadogV1.SLOTIMEFRAME_NINETY_DAYS, Target: 99}})
+			},
+			want: []string{"groups", "monitorIDs", "timeframe"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crdSLO := tt.crdSLO()
+			crdSLO.Spec.Tags = []string{"env:prod", "team:foo"}
+			slo := renderedSLO(t, crdSLO)
+			tt.modify(&slo)
+
+			assert.Equal(t, tt.want, sloDifferences(crdSLO, slo))
+		})
+	}
+}
+
+func TestReconciler_checkDrift(t *testing.T) {
+	logger := logf.Log.WithName("TestReconciler_checkDrift")
+
+	tests := []struct {
+		name          string
+		policy        v1alpha1.DriftPolicy
+		defaultPolicy v1alpha1.DriftPolicy
+		drift         bool
+		wantUpdate    bool
+		wantDrifted   []string
+		wantEvent     bool
+	}{
+		{
+			name:       "no drift",
+			wantUpdate: false,
+		},
+		{
+			name:        "drift reverted by default",
+			drift:       true,
+			wantUpdate:  true,
+			wantDrifted: []string{"targetThreshold"},
+			wantEvent:   true,
+		},
+		{
+			name:        "drift reported",
+			policy:      v1alpha1.DriftPolicyReport,
+			drift:       true,
+			wantUpdate:  false,
+			wantDrifted: []string{"targetThreshold"},
+			wantEvent:   true,
+		},
+		{
+			name:          "drift ignored with the default policy of the operator",
+			defaultPolicy: v1alpha1.DriftPolicyIgnore,
+			drift:         true,
+			wantUpdate:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{
+				recorder:           recorder,
+				log:                logger,
+				defaultDriftPolicy: tt.defaultPolicy,
+			}
+			crdSLO := defaultSLO()
+			if tt.policy != "" {
+				crdSLO.Spec.ControllerOptions = &v1alpha1.DatadogSLOControllerOptions{DriftPolicy: tt.policy}
+			}
+			crdSLO.Spec.TargetThreshold = resource.MustParse("99.9")
+			if !tt.drift {
+				crdSLO.Spec.TargetThreshold = resource.MustParse("99")
+			}
+			status := &v1alpha1.DatadogSLOStatus{DriftedFields: []string{"name"}}
+
+			// The SLO in Datadog has a target of 99
+			slo := renderedSLO(t, defaultSLO())
+
+			assert.Equal(t, tt.wantUpdate, r.checkDrift(logger, crdSLO, slo, status))
+			assert.Equal(t, tt.wantDrifted, status.DriftedFields)
+			if tt.wantEvent {
+				assert.Len(t, recorder.Events, 1)
+				assert.Contains(t, <-recorder.Events, "fields modified outside Kubernetes")
+			} else {
+				assert.Empty(t, recorder.Events)
+			}
+		})
+	}
+}
+
+// renderedSLO returns the SLO returned by the API after an update with the DatadogSLO spec
+func renderedSLO(t *testing.T, crdSLO *v1alpha1.DatadogSLO) datadogV1.SLOResponseData {
+	_, slo := buildSLO(crdSLO)
+	data, err := json.Marshal(slo)
+	require.NoError(t, err)
+	result := datadogV1.SLOResponseData{}
+	require.NoError(t, json.Unmarshal(data, &result))
+
+	return result
+}
@@ -7,14 +7,14 @@ package datadogslo
 
 import (
 	"context"
-	"errors"
-	"fmt"
-	"net/url"
+	"reflect"
+	"sort"
 
-	datadogapi "github.com/DataDog/datadog-api-client-go/v2/api/datadog"
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
 
 	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/controllers/utils"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
 )
 
 func buildSLO(crdSLO *v1alpha1.DatadogSLO) (*datadogV1.ServiceLevelObjectiveRequest, *datadogV1.ServiceLevelObjective) {
@@ -85,11 +85,76 @@ func buildThreshold(sloSpec v1alpha1.DatadogSLOSpec) []datadogV1.SLOThreshold {
 	return []datadogV1.SLOThreshold{threshold}
 }
 
+// sloDifferences returns the sorted list of the fields of an SLO that differ from the DatadogSLO spec.
+func sloDifferences(crdSLO *v1alpha1.DatadogSLO, slo datadogV1.SLOResponseData) []string {
+	_, expected := buildSLO(crdSLO)
+	differences := []string{}
+
+	if expected.GetName() != slo.GetName() {
+		differences = append(differences, "name")
+	}
+	if expected.GetDescription() != slo.GetDescription() {
+		differences = append(differences, "description")
+	}
+	if expected.GetType() != slo.GetType() {
+		differences = append(differences, "type")
+	}
+	if !utils.SameStrings(expected.GetTags(), slo.GetTags()) {
+		differences = append(differences, "tags")
+	}
+	if expected.GetType() == datadogV1.SLOTYPE_METRIC && !reflect.DeepEqual(expected.GetQuery(), slo.GetQuery()) {
+		differences = append(differences, "query")
+	}
+	if expected.GetType() == datadogV1.SLOTYPE_MONITOR {
+		if !sameIDs(expected.GetMonitorIds(), slo.GetMonitorIds()) {
+			differences = append(differences, "monitorIDs")
+		}
+		if !utils.SameStrings(expected.GetGroups(), slo.GetGroups()) {
+			differences = append(differences, "groups")
+		}
+	}
+
+	// The spec defines a single threshold, the SLO can have other thresholds for other timeframes
+	expectedThreshold := expected.GetThresholds()[0]
+	var threshold *datadogV1.SLOThreshold
+	for _, t := range slo.GetThresholds() {
+		if t.Timeframe == expectedThreshold.Timeframe {
+			threshold = &t
+			break
+		}
+	}
+	if threshold == nil {
+		differences = append(differences, "timeframe")
+	} else {
+		if threshold.Target != expectedThreshold.Target {
+			differences = append(differences, "targetThreshold")
+		}
+		if threshold.GetWarning() != expectedThreshold.GetWarning() {
+			differences = append(differences, "warningThreshold")
+		}
+	}
+
+	sort.Strings(differences)
+	return differences
+}
+
+func sameIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]int64{}, a...)
+	sortedB := append([]int64{}, b...)
+	sort.Slice(sortedA, func(i, j int) bool { return sortedA[i] < sortedA[j] })
+	sort.Slice(sortedB, func(i, j int) bool { return sortedB[i] < sortedB[j] })
+
+	return reflect.DeepEqual(sortedA, sortedB)
+}
+
 func createSLO(auth context.Context, client *datadogV1.ServiceLevelObjectivesApi, crdSLO *v1alpha1.DatadogSLO) (datadogV1.ServiceLevelObjective, error) {
 	sloReq, _ := buildSLO(crdSLO)
 	slo, _, err := client.CreateSLO(auth, *sloReq)
 	if err != nil {
-		return datadogV1.ServiceLevelObjective{}, translateClientError(err, "error creating SLO")
+		return datadogV1.ServiceLevelObjective{}, datadogclient.TranslateClientError(err, "error creating SLO")
 	}
 
 	return slo.Data[0], nil
@@ -98,7 +163,7 @@ func createSLO(auth context.Context, client *datadogV1.ServiceLevelObjectivesApi
 func getSLO(auth context.Context, client *datadogV1.ServiceLevelObjectivesApi, sloId string) (*datadogV1.SLOResponseData, error) {
 	slo, _, err := client.GetSLO(auth, sloId, datadogV1.GetSLOOptionalParameters{})
 	if err != nil {
-		return &datadogV1.SLOResponseData{}, translateClientError(err, "error getting SLO")
+		return &datadogV1.SLOResponseData{}, datadogclient.TranslateClientError(err, "error getting SLO")
 	}
 
 	return slo.Data, nil
@@ -108,7 +173,7 @@ func updateSLO(auth context.Context, client *datadogV1.ServiceLevelObjectivesApi
 	_, slo := buildSLO(crdSLO)
 	sloListResponse, _, err := client.UpdateSLO(auth, crdSLO.Status.ID, *slo)
 	if err != nil {
-		return datadogV1.SLOListResponse{}, translateClientError(err, "error updating SLO")
+		return datadogV1.SLOListResponse{}, datadogclient.TranslateClientError(err, "error updating SLO")
 	}
 	return sloListResponse, nil
 }
@@ -119,25 +184,34 @@ func deleteSLO(auth context.Context, client *datadogV1.ServiceLevelObjectivesApi
 		Force: &force,
 	}
 	if _, _, err := client.DeleteSLO(auth, sloID, optionalParams); err != nil {
-		return translateClientError(err, "error deleting SLO")
+		return datadogclient.TranslateClientError(err, "error deleting SLO")
 	}
 	return nil
 }
 
-func translateClientError(err error, msg string) error {
-	if msg == "" {
-		msg = "an error occurred"
+// orphanSLO removes the required tags from an SLO, so that it is no longer marked as managed by Kubernetes.
+// The SLO is updated from its current definition in Datadog, so that its other fields are left untouched.
+func orphanSLO(auth context.Context, client *datadogV1.ServiceLevelObjectivesApi, sloID string) error {
+	slo, err := getSLO(auth, client, sloID)
+	if err != nil {
+		return err
 	}
 
-	var apiErr datadogapi.GenericOpenAPIError
-	var errURL *url.Error
-	if errors.As(err, &apiErr) {
-		return fmt.Errorf(msg+": %w: %s", err, apiErr.Body())
+	orphaned := datadogV1.ServiceLevelObjective{
+		Description:      slo.Description,
+		Groups:           slo.Groups,
+		MonitorIds:       slo.MonitorIds,
+		Name:             slo.GetName(),
+		Query:            slo.Query,
+		Tags:             utils.RemoveRequiredTags(slo.GetTags()),
+		TargetThreshold:  slo.TargetThreshold,
+		Thresholds:       slo.GetThresholds(),
+		Timeframe:        slo.Timeframe,
+		Type:             slo.GetType(),
+		WarningThreshold: slo.WarningThreshold,
 	}
-
-	if errors.As(err, &errURL) {
-		return fmt.Errorf(msg+" (url.Error): %s", errURL)
+	if _, _, err = client.UpdateSLO(auth, sloID, orphaned); err != nil {
+		return datadogclient.TranslateClientError(err, "error orphaning SLO")
 	}
-
-	return fmt.Errorf(msg+": %w", err)
+	return nil
 }
@@ -13,27 +13,32 @@ import (
 	"github.com/DataDog/datadog-operator/controllers/datadogslo"
 	"github.com/DataDog/datadog-operator/pkg/datadogclient"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 type DatadogSLOReconciler struct {
 	Client      client.Client
 	DDClient    datadogclient.DatadogSLOClient
+	DDClients   *datadogclient.ClientCache
 	VersionInfo *version.Info
 	Log         logr.Logger
 	Scheme      *runtime.Scheme
 	Recorder    record.EventRecorder
+	DriftPolicy v1alpha1.DriftPolicy
 	internal    *datadogslo.Reconciler
 }
 
 // +kubebuilder:rbac:groups=datadoghq.com,resources=datadogslos,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=datadoghq.com,resources=datadogslos/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=datadoghq.com,resources=datadogslos/finalizers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 
 // Reconcile loop for Datadog SLO
 func (r *DatadogSLOReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
@@ -41,10 +46,12 @@ func (r *DatadogSLOReconciler) Reconcile(ctx context.Context, req reconcile.Requ
 }
 
 func (r *DatadogSLOReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	r.internal = datadogslo.NewReconciler(r.Client, r.DDClient, r.VersionInfo, r.Log, r.Recorder)
+	r.internal = datadogslo.NewReconciler(r.Client, r.DDClient, r.DDClients, r.VersionInfo, r.Log, r.Recorder, r.DriftPolicy)
 
 	builder := ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.DatadogSLO{})
+		For(&v1alpha1.DatadogSLO{}).
+		// The Datadog client of the credentials of a Secret is dropped when the Secret is deleted
+		Watches(&source.Kind{Type: &corev1.Secret{}}, removeDeletedCredentials(r.DDClients))
 
 	err := builder.Complete(r)
 	if err != nil {
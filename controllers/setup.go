@@ -13,6 +13,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
 	"github.com/DataDog/datadog-operator/controllers/datadogagent"
 	componentagent "github.com/DataDog/datadog-operator/controllers/datadogagent/component/agent"
 	"github.com/DataDog/datadog-operator/pkg/config"
@@ -26,10 +27,11 @@ import (
 )
 
 const (
-	agentControllerName   = "DatadogAgent"
-	monitorControllerName = "DatadogMonitor"
-	sloControllerName     = "DatadogSLO"
-	profileControllerName = "DatadogAgentProfile"
+	agentControllerName    = "DatadogAgent"
+	monitorControllerName  = "DatadogMonitor"
+	sloControllerName      = "DatadogSLO"
+	profileControllerName  = "DatadogAgentProfile"
+	downtimeControllerName = "DatadogDowntime"
 )
 
 // SetupOptions defines options for setting up controllers to ease testing
@@ -40,11 +42,16 @@ type SetupOptions struct {
 	DatadogAgentEnabled             bool
 	DatadogMonitorEnabled           bool
 	DatadogSLOEnabled               bool
+	DatadogDowntimeEnabled          bool
 	OperatorMetricsEnabled          bool
 	V2APIEnabled                    bool
 	IntrospectionEnabled            bool
 	DatadogAgentProfileEnabled      bool
 	ProcessChecksInCoreAgentEnabled bool
+	DriftPolicy                     v1alpha1.DriftPolicy
+
+	// datadogClients is shared by the DatadogMonitor and DatadogSLO controllers
+	datadogClients *datadogclient.ClientCache
 }
 
 // ExtendedDaemonsetOptions defines ExtendedDaemonset options
@@ -65,10 +72,11 @@ type ExtendedDaemonsetOptions struct {
 type starterFunc func(logr.Logger, manager.Manager, *version.Info, kubernetes.PlatformInfo, SetupOptions) error
 
 var controllerStarters = map[string]starterFunc{
-	agentControllerName:   startDatadogAgent,
-	monitorControllerName: startDatadogMonitor,
-	sloControllerName:     startDatadogSLO,
-	profileControllerName: startDatadogAgentProfiles,
+	agentControllerName:    startDatadogAgent,
+	monitorControllerName:  startDatadogMonitor,
+	sloControllerName:      startDatadogSLO,
+	profileControllerName:  startDatadogAgentProfiles,
+	downtimeControllerName: startDatadogDowntime,
 }
 
 // SetupControllers starts all controllers (also used by e2e tests)
@@ -92,6 +100,8 @@ func SetupControllers(logger logr.Logger, mgr manager.Manager, options SetupOpti
 	}
 	platformInfo := kubernetes.NewPlatformInfo(versionInfo, groups, resources)
 
+	options.datadogClients = datadogclient.NewClientCache()
+
 	for controller, starter := range controllerStarters {
 		if err := starter(logger, mgr, versionInfo, platformInfo, options); err != nil {
 			logger.Error(err, "Couldn't start controller", "controller", controller)
@@ -164,10 +174,12 @@ func startDatadogMonitor(logger logr.Logger, mgr manager.Manager, vInfo *version
 	return (&DatadogMonitorReconciler{
 		Client:      mgr.GetClient(),
 		DDClient:    ddClient,
+		DDClients:   options.datadogClients,
 		VersionInfo: vInfo,
 		Log:         ctrl.Log.WithName("controllers").WithName(monitorControllerName),
 		Scheme:      mgr.GetScheme(),
 		Recorder:    mgr.GetEventRecorderFor(monitorControllerName),
+		DriftPolicy: options.DriftPolicy,
 	}).SetupWithManager(mgr)
 }
 
@@ -185,15 +197,38 @@ func startDatadogSLO(logger logr.Logger, mgr manager.Manager, info *version.Info
 	controller := &DatadogSLOReconciler{
 		Client:      mgr.GetClient(),
 		DDClient:    ddClient,
+		DDClients:   options.datadogClients,
 		VersionInfo: info,
 		Log:         ctrl.Log.WithName("controllers").WithName(sloControllerName),
 		Scheme:      mgr.GetScheme(),
 		Recorder:    mgr.GetEventRecorderFor(sloControllerName),
+		DriftPolicy: options.DriftPolicy,
 	}
 
 	return controller.SetupWithManager(mgr)
 }
 
+func startDatadogDowntime(logger logr.Logger, mgr manager.Manager, info *version.Info, pInfo kubernetes.PlatformInfo, options SetupOptions) error {
+	if !options.DatadogDowntimeEnabled {
+		logger.Info("Feature disabled, not starting the controller", "controller", downtimeControllerName)
+		return nil
+	}
+
+	ddClient, err := datadogclient.InitDatadogDowntimeClient(logger, options.Creds)
+	if err != nil {
+		return fmt.Errorf("unable to create Datadog API Client: %w", err)
+	}
+
+	return (&DatadogDowntimeReconciler{
+		Client:      mgr.GetClient(),
+		DDClient:    ddClient,
+		VersionInfo: info,
+		Log:         ctrl.Log.WithName("controllers").WithName(downtimeControllerName),
+		Scheme:      mgr.GetScheme(),
+		Recorder:    mgr.GetEventRecorderFor(downtimeControllerName),
+	}).SetupWithManager(mgr)
+}
+
 func startDatadogAgentProfiles(logger logr.Logger, mgr manager.Manager, vInfo *version.Info, pInfo kubernetes.PlatformInfo, options SetupOptions) error {
 	if !options.DatadogAgentProfileEnabled {
 		logger.Info("Feature disabled, not starting the controller", "controller", profileControllerName)
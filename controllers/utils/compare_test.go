@@ -0,0 +1,19 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSameStrings(t *testing.T) {
+	assert.True(t, SameStrings(nil, []string{}))
+	assert.True(t, SameStrings([]string{"a", "b"}, []string{"b", "a"}))
+	assert.False(t, SameStrings([]string{"a", "b"}, []string{"a"}))
+	assert.False(t, SameStrings([]string{"a", "a"}, []string{"a", "b"}))
+}
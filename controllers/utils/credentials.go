@@ -0,0 +1,118 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/config"
+)
+
+// CredentialsDeletionTimeout is how long the deletion of a resource waits for the credentials Secret it was created with
+// to be readable again, before releasing the resource and leaving it in Datadog.
+const CredentialsDeletionTimeout = 10 * time.Minute
+
+// GetDatadogCredentials returns the Datadog credentials of a resource, and the Secret holding them: the Secret referenced
+// by the resource, otherwise the Secret of its namespace labeled as the default credentials.
+// It returns a nil Secret when the resource uses the credentials of the operator.
+func GetDatadogCredentials(ctx context.Context, c client.Client, namespace string, ref *v1alpha1.DatadogCredentialsReference) (*types.NamespacedName, config.Creds, error) {
+	secret := &corev1.Secret{}
+	if ref != nil {
+		key := types.NamespacedName{Namespace: namespace, Name: ref.SecretName}
+		if err := c.Get(ctx, key, secret); err != nil {
+			return nil, config.Creds{}, fmt.Errorf("unable to get the credentials Secret %s: %w", key, err)
+		}
+	} else {
+		secretList := &corev1.SecretList{}
+		if err := c.List(ctx, secretList, client.InNamespace(namespace), client.MatchingLabels{v1alpha1.DatadogCredentialsDefaultLabelKey: "true"}); err != nil {
+			return nil, config.Creds{}, fmt.Errorf("unable to list the default credentials Secrets of namespace %s: %w", namespace, err)
+		}
+		switch len(secretList.Items) {
+		case 0:
+			return nil, config.Creds{}, nil
+		case 1:
+			secret = &secretList.Items[0]
+		default:
+			return nil, config.Creds{}, fmt.Errorf("several Secrets of namespace %s are labeled %s=true", namespace, v1alpha1.DatadogCredentialsDefaultLabelKey)
+		}
+	}
+
+	return credentialsFromSecret(secret)
+}
+
+// GetRecordedDatadogCredentials returns the Datadog credentials recorded in the status of a resource when it was created
+// in Datadog, and the Secret holding them. Unlike GetDatadogCredentials, it doesn't look for the default credentials of
+// the namespace: a missing Secret is an error, never a fallback to the credentials of the operator.
+// It returns a nil Secret when the resource was created with the credentials of the operator.
+func GetRecordedDatadogCredentials(ctx context.Context, c client.Client, namespace string, recorded v1alpha1.DatadogCredentialsStatus) (*types.NamespacedName, config.Creds, error) {
+	if recorded.SecretName == "" {
+		return nil, config.Creds{}, nil
+	}
+
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: namespace, Name: recorded.SecretName}
+	if err := c.Get(ctx, key, secret); err != nil {
+		return nil, config.Creds{}, fmt.Errorf("unable to get the credentials Secret %s: %w", key, err)
+	}
+
+	return credentialsFromSecret(secret)
+}
+
+// CredentialsStatus returns the status recording the credentials Secret returned by GetDatadogCredentials.
+func CredentialsStatus(secret *types.NamespacedName) *v1alpha1.DatadogCredentialsStatus {
+	if secret == nil {
+		return &v1alpha1.DatadogCredentialsStatus{}
+	}
+	return &v1alpha1.DatadogCredentialsStatus{SecretName: secret.Name}
+}
+
+// CheckCredentialsUnchanged returns an error when the credentials of a resource, as returned by CredentialsStatus, differ
+// from the credentials its Datadog object was created with, recorded in its status. The object isn't moved to the new
+// credentials, which may belong to another organization, so the resource must be recreated to use them.
+func CheckCredentialsUnchanged(recorded, current *v1alpha1.DatadogCredentialsStatus) error {
+	if recorded == nil || current == nil || recorded.SecretName == current.SecretName {
+		return nil
+	}
+	return fmt.Errorf("the Datadog credentials changed from %s to %s since the Datadog object was created, recreate the resource to use the new credentials",
+		describeCredentials(recorded), describeCredentials(current))
+}
+
+func describeCredentials(credentials *v1alpha1.DatadogCredentialsStatus) string {
+	if credentials.SecretName == "" {
+		return "the credentials of the operator"
+	}
+	return fmt.Sprintf("the Secret %s", credentials.SecretName)
+}
+
+// CredentialsDeletionExpired returns true once a resource has been waiting for its credentials to be deleted for longer
+// than CredentialsDeletionTimeout.
+func CredentialsDeletionExpired(obj metav1.Object, now time.Time) bool {
+	deletion := obj.GetDeletionTimestamp()
+	return deletion != nil && now.Sub(deletion.Time) > CredentialsDeletionTimeout
+}
+
+func credentialsFromSecret(secret *corev1.Secret) (*types.NamespacedName, config.Creds, error) {
+	key := types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}
+	creds := config.Creds{
+		APIKey: string(secret.Data[apicommon.DefaultAPIKeyKey]),
+		AppKey: string(secret.Data[apicommon.DefaultAPPKeyKey]),
+		Site:   string(secret.Data[v1alpha1.DatadogCredentialsSiteKey]),
+	}
+	if creds.APIKey == "" || creds.AppKey == "" {
+		return nil, config.Creds{}, fmt.Errorf("the credentials Secret %s must hold the %s and %s keys", key, apicommon.DefaultAPIKeyKey, apicommon.DefaultAPPKeyKey)
+	}
+
+	return &key, creds, nil
+}
@@ -0,0 +1,165 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/config"
+)
+
+func TestGetDatadogCredentials(t *testing.T) {
+	credentialsSecret := func(namespace, name string, isDefault bool, data map[string]string) *corev1.Secret {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Data:       map[string][]byte{},
+		}
+		if isDefault {
+			secret.Labels = map[string]string{v1alpha1.DatadogCredentialsDefaultLabelKey: "true"}
+		}
+		for key, value := range data {
+			secret.Data[key] = []byte(value)
+		}
+		return secret
+	}
+	teamKeys := map[string]string{"api_key": "team-api-key", "app_key": "team-app-key", "site": "datadoghq.eu"}
+	defaultKeys := map[string]string{"api_key": "default-api-key", "app_key": "default-app-key"}
+
+	tests := []struct {
+		name       string
+		objects    []client.Object
+		namespace  string
+		ref        *v1alpha1.DatadogCredentialsReference
+		wantSecret *types.NamespacedName
+		wantCreds  config.Creds
+		wantErr    string
+	}{
+		{
+			name:      "operator credentials",
+			objects:   []client.Object{credentialsSecret("other", "default", true, defaultKeys)},
+			namespace: "team",
+		},
+		{
+			name: "referenced Secret",
+			objects: []client.Object{
+				credentialsSecret("team", "team-credentials", false, teamKeys),
+				credentialsSecret("team", "default", true, defaultKeys),
+			},
+			namespace:  "team",
+			ref:        &v1alpha1.DatadogCredentialsReference{SecretName: "team-credentials"},
+			wantSecret: &types.NamespacedName{Namespace: "team", Name: "team-credentials"},
+			wantCreds:  config.Creds{APIKey: "team-api-key", AppKey: "team-app-key", Site: "datadoghq.eu"},
+		},
+		{
+			name:       "default Secret of the namespace",
+			objects:    []client.Object{credentialsSecret("team", "default", true, defaultKeys)},
+			namespace:  "team",
+			wantSecret: &types.NamespacedName{Namespace: "team", Name: "default"},
+			wantCreds:  config.Creds{APIKey: "default-api-key", AppKey: "default-app-key"},
+		},
+		{
+			name:      "missing referenced Secret",
+			namespace: "team",
+			ref:       &v1alpha1.DatadogCredentialsReference{SecretName: "team-credentials"},
+			wantErr:   "unable to get the credentials Secret team/team-credentials",
+		},
+		{
+			name: "several default Secrets",
+			objects: []client.Object{
+				credentialsSecret("team", "default", true, defaultKeys),
+				credentialsSecret("team", "other-default", true, defaultKeys),
+			},
+			namespace: "team",
+			wantErr:   "several Secrets of namespace team are labeled datadoghq.com/default-credentials=true",
+		},
+		{
+			name:      "Secret missing the app key",
+			objects:   []client.Object{credentialsSecret("team", "team-credentials", false, map[string]string{"api_key": "team-api-key"})},
+			namespace: "team",
+			ref:       &v1alpha1.DatadogCredentialsReference{SecretName: "team-credentials"},
+			wantErr:   "the credentials Secret team/team-credentials must hold the api_key and app_key keys",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithObjects(tt.objects...).Build()
+
+			secret, creds, err := GetDatadogCredentials(context.TODO(), c, tt.namespace, tt.ref)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSecret, secret)
+			assert.Equal(t, tt.wantCreds, creds)
+		})
+	}
+}
+
+func TestGetRecordedDatadogCredentials(t *testing.T) {
+	teamSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team", Name: "team-credentials"},
+		Data:       map[string][]byte{"api_key": []byte("team-api-key"), "app_key": []byte("team-app-key")},
+	}
+	defaultSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team", Name: "default", Labels: map[string]string{v1alpha1.DatadogCredentialsDefaultLabelKey: "true"}},
+		Data:       map[string][]byte{"api_key": []byte("default-api-key"), "app_key": []byte("default-app-key")},
+	}
+	c := fake.NewClientBuilder().WithObjects(teamSecret, defaultSecret).Build()
+
+	// The recorded Secret is used, even if the namespace has default credentials
+	secret, creds, err := GetRecordedDatadogCredentials(context.TODO(), c, "team", *CredentialsStatus(&types.NamespacedName{Namespace: "team", Name: "team-credentials"}))
+	require.NoError(t, err)
+	assert.Equal(t, &types.NamespacedName{Namespace: "team", Name: "team-credentials"}, secret)
+	assert.Equal(t, config.Creds{APIKey: "team-api-key", AppKey: "team-app-key"}, creds)
+
+	// The operator credentials are recorded as an empty Secret name
+	secret, _, err = GetRecordedDatadogCredentials(context.TODO(), c, "team", *CredentialsStatus(nil))
+	require.NoError(t, err)
+	assert.Nil(t, secret)
+
+	// A deleted Secret is an error, the default credentials of the namespace are not used instead
+	_, _, err = GetRecordedDatadogCredentials(context.TODO(), c, "team", v1alpha1.DatadogCredentialsStatus{SecretName: "deleted"})
+	require.Error(t, err)
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestCredentialsDeletionExpired(t *testing.T) {
+	now := time.Now()
+	obj := &metav1.ObjectMeta{}
+	assert.False(t, CredentialsDeletionExpired(obj, now))
+
+	obj.DeletionTimestamp = &metav1.Time{Time: now.Add(-time.Minute)}
+	assert.False(t, CredentialsDeletionExpired(obj, now))
+
+	obj.DeletionTimestamp = &metav1.Time{Time: now.Add(-CredentialsDeletionTimeout - time.Second)}
+	assert.True(t, CredentialsDeletionExpired(obj, now))
+}
+
+func TestCheckCredentialsUnchanged(t *testing.T) {
+	operator := &v1alpha1.DatadogCredentialsStatus{}
+	team := &v1alpha1.DatadogCredentialsStatus{SecretName: "team"}
+
+	assert.NoError(t, CheckCredentialsUnchanged(nil, team))
+	assert.NoError(t, CheckCredentialsUnchanged(team, &v1alpha1.DatadogCredentialsStatus{SecretName: "team"}))
+	assert.NoError(t, CheckCredentialsUnchanged(operator, &v1alpha1.DatadogCredentialsStatus{}))
+	assert.EqualError(t, CheckCredentialsUnchanged(operator, team), "the Datadog credentials changed from the credentials of the operator to the Secret team since the Datadog object was created, recreate the resource to use the new credentials")
+	assert.EqualError(t, CheckCredentialsUnchanged(team, operator), "the Datadog credentials changed from the Secret team to the credentials of the operator since the Datadog object was created, recreate the resource to use the new credentials")
+}
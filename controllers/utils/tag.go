@@ -28,3 +28,22 @@ func GetTagsToAdd(tags []string) []string {
 	}
 	return tagsToAdd
 }
+
+// RemoveRequiredTags returns the tags without the required tags, which mark a Datadog resource as managed by Kubernetes.
+// It never returns nil, so that the tags are always sent in API update requests.
+func RemoveRequiredTags(tags []string) []string {
+	tagsToKeep := []string{}
+	for _, t := range tags {
+		required := false
+		for _, rT := range getRequiredTags() {
+			if t == rT {
+				required = true
+				break
+			}
+		}
+		if !required {
+			tagsToKeep = append(tagsToKeep, t)
+		}
+	}
+	return tagsToKeep
+}
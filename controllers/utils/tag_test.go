@@ -0,0 +1,18 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveRequiredTags(t *testing.T) {
+	assert.Equal(t, []string{"env:prod", "team:a"}, RemoveRequiredTags([]string{"env:prod", "generated:kubernetes", "team:a"}))
+	assert.Equal(t, []string{}, RemoveRequiredTags([]string{"generated:kubernetes"}))
+	assert.Equal(t, []string{}, RemoveRequiredTags(nil))
+}
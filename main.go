@@ -123,6 +123,7 @@ type options struct {
 	datadogAgentEnabled                    bool
 	datadogMonitorEnabled                  bool
 	datadogSLOEnabled                      bool
+	datadogDowntimeEnabled                 bool
 	operatorMetricsEnabled                 bool
 	webhookEnabled                         bool
 	v2APIEnabled                           bool
@@ -130,6 +131,7 @@ type options struct {
 	introspectionEnabled                   bool
 	datadogAgentProfileEnabled             bool
 	processChecksInCoreAgentEnabled        bool
+	driftPolicy                            string
 
 	// Secret Backend options
 	secretBackendCommand string
@@ -158,13 +160,15 @@ func (opts *options) Parse() {
 	flag.BoolVar(&opts.datadogAgentEnabled, "datadogAgentEnabled", true, "Enable the DatadogAgent controller")
 	flag.BoolVar(&opts.datadogMonitorEnabled, "datadogMonitorEnabled", false, "Enable the DatadogMonitor controller")
 	flag.BoolVar(&opts.datadogSLOEnabled, "datadogSLOEnabled", false, "Enable the DatadogSLO controller")
+	flag.BoolVar(&opts.datadogDowntimeEnabled, "datadogDowntimeEnabled", false, "Enable the DatadogDowntime controller")
 	flag.BoolVar(&opts.operatorMetricsEnabled, "operatorMetricsEnabled", true, "Enable sending operator metrics to Datadog")
 	flag.BoolVar(&opts.v2APIEnabled, "v2APIEnabled", true, "Enable the v2 api")
-	flag.BoolVar(&opts.webhookEnabled, "webhookEnabled", false, "Enable CRD conversion webhook.")
+	flag.BoolVar(&opts.webhookEnabled, "webhookEnabled", false, "Enable CRD conversion and DatadogAgent validating webhooks.")
 	flag.IntVar(&opts.maximumGoroutines, "maximumGoroutines", defaultMaximumGoroutines, "Override health check threshold for maximum number of goroutines.")
 	flag.BoolVar(&opts.introspectionEnabled, "introspectionEnabled", false, "Enable introspection (beta)")
 	flag.BoolVar(&opts.datadogAgentProfileEnabled, "datadogAgentProfileEnabled", false, "Enable DatadogAgentProfile controller (beta)")
 	flag.BoolVar(&opts.processChecksInCoreAgentEnabled, "processChecksInCoreAgentEnabled", false, "Enable running process checks in the core agent (beta)")
+	flag.StringVar(&opts.driftPolicy, "driftPolicy", string(datadoghqv1alpha1.DriftPolicyRevert), "Default drift policy of the DatadogMonitor and DatadogSLO controllers ('revert', 'report' or 'ignore')")
 
 	// ExtendedDaemonset configuration
 	flag.BoolVar(&opts.supportExtendedDaemonset, "supportExtendedDaemonset", false, "Support usage of Datadog ExtendedDaemonset CRD.")
@@ -216,6 +220,10 @@ func run(opts *options) error {
 			"See the migration page for instructions on migrating to v2alpha1: https://docs.datadoghq.com/containers/guide/datadogoperator_migration/")
 	}
 
+	if !datadoghqv1alpha1.DriftPolicy(opts.driftPolicy).IsValid() {
+		return setupErrorf(setupLog, fmt.Errorf("invalid drift policy %q", opts.driftPolicy), "The 'driftPolicy' flag must be one of: revert, report or ignore")
+	}
+
 	if opts.profilingEnabled {
 		setupLog.Info("Starting datadog profiler")
 		if err := profiler.Start(
@@ -248,7 +256,7 @@ func run(opts *options) error {
 		LeaseDuration:              &opts.leaderElectionLeaseDuration,
 		RenewDeadline:              &renewDeadline,
 		RetryPeriod:                &retryPeriod,
-		NewCache:                   cache.BuilderWithOptions(cacheOptions()),
+		NewCache:                   cache.BuilderWithOptions(cacheOptions(opts.datadogAgentProfileEnabled)),
 	}))
 	if err != nil {
 		return setupErrorf(setupLog, err, "Unable to start manager")
@@ -282,11 +290,13 @@ func run(opts *options) error {
 		DatadogAgentEnabled:             opts.datadogAgentEnabled,
 		DatadogMonitorEnabled:           opts.datadogMonitorEnabled,
 		DatadogSLOEnabled:               opts.datadogSLOEnabled,
+		DatadogDowntimeEnabled:          opts.datadogDowntimeEnabled,
 		OperatorMetricsEnabled:          opts.operatorMetricsEnabled,
 		V2APIEnabled:                    opts.v2APIEnabled,
 		IntrospectionEnabled:            opts.introspectionEnabled,
 		DatadogAgentProfileEnabled:      opts.datadogAgentProfileEnabled,
 		ProcessChecksInCoreAgentEnabled: opts.processChecksInCoreAgentEnabled,
+		DriftPolicy:                     datadoghqv1alpha1.DriftPolicy(opts.driftPolicy),
 	}
 
 	if err = controllers.SetupControllers(setupLog, mgr, options); err != nil {
@@ -312,13 +322,16 @@ func run(opts *options) error {
 // This function is used to configure the cache used by the manager. It is very
 // important to reduce memory usage.
 // For the profiles feature we need to list the agent pods, but we're only
-// interested in the node name and the labels. This function removes all the
-// rest of fields to reduce memory usage.
+// interested in the node name, the labels, and the conditions and the deletion
+// timestamp to know if they're available during the profile rollouts. This
+// function removes all the rest of fields to reduce memory usage.
 // Also for the profiles feature, we need to list the nodes, but we're only
-// interested in the node name and the labels.
+// interested in the node name, and the labels, annotations, taints and
+// allocatable resources that the profiles select the nodes by. The annotations
+// are only stored when the profiles are enabled.
 // Note that if in the future we need to list or get pods or nodes and use other
 // fields we'll need to modify this function.
-func cacheOptions() cache.Options {
+func cacheOptions(profilesEnabled bool) cache.Options {
 	return cache.Options{
 		SelectorsByObject: cache.SelectorsByObject{
 			// Store pods only if they are node agent pods.
@@ -329,34 +342,50 @@ func cacheOptions() cache.Options {
 			},
 		},
 		TransformByObject: map[client.Object]toolscache.TransformFunc{
-			// Store only the node name and the labels of the pod.
+			// Store only the node name, the labels and the availability of the pod.
 			&corev1.Pod{}: func(obj interface{}) (interface{}, error) {
 				pod := obj.(*corev1.Pod)
 
 				newPod := &corev1.Pod{
 					TypeMeta: pod.TypeMeta,
 					ObjectMeta: v1.ObjectMeta{
-						Namespace: pod.Namespace,
-						Name:      pod.Name,
-						Labels:    pod.Labels,
+						Namespace:         pod.Namespace,
+						Name:              pod.Name,
+						Labels:            pod.Labels,
+						DeletionTimestamp: pod.DeletionTimestamp,
 					},
 					Spec: corev1.PodSpec{
 						NodeName: pod.Spec.NodeName,
 					},
+					Status: corev1.PodStatus{
+						Conditions: pod.Status.Conditions,
+					},
 				}
 
 				return newPod, nil
 			},
 
-			// Store only the node name and its labels.
+			// Store only the node name and the fields the profiles select the nodes by.
 			&corev1.Node{}: func(obj interface{}) (interface{}, error) {
 				node := obj.(*corev1.Node)
 
+				var annotations map[string]string
+				if profilesEnabled {
+					annotations = node.Annotations
+				}
+
 				newNode := &corev1.Node{
 					TypeMeta: node.TypeMeta,
 					ObjectMeta: v1.ObjectMeta{
-						Name:   node.Name,
-						Labels: node.Labels,
+						Name:        node.Name,
+						Labels:      node.Labels,
+						Annotations: annotations,
+					},
+					Spec: corev1.NodeSpec{
+						Taints: node.Spec.Taints,
+					},
+					Status: corev1.NodeStatus{
+						Allocatable: node.Status.Allocatable,
 					},
 				}
 
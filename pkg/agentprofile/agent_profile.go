@@ -7,13 +7,17 @@ package agentprofile
 
 import (
 	"fmt"
+	"hash/fnv"
 	"sort"
+	"strings"
 
 	v1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
 	"github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
@@ -23,69 +27,153 @@ import (
 )
 
 const (
-	ProfileLabelKey     = "agent.datadoghq.com/profile"
-	defaultProfileName  = "default"
-	daemonSetNamePrefix = "datadog-agent-with-profile-"
+	ProfileLabelKey    = "agent.datadoghq.com/profile"
+	defaultProfileName = "default"
+	daemonSetNameInfix = "-agent-with-profile-"
+	// mergedProfileNameSeparator separates the name of the profile with
+	// precedence from the hash of the names of the merged profiles.
+	mergedProfileNameSeparator = "-merged-"
+	// mergedProfileHashLength is the length of the hash that ends the name of
+	// the merged profiles.
+	mergedProfileHashLength = 8
 )
 
+// ProfileStatus is the result of the evaluation of a profile by ProfilesToApply.
+type ProfileStatus struct {
+	// ValidationError is the error returned by the validation of the profile
+	// spec. It is nil when the profile is valid.
+	ValidationError error
+	// Applied is true when the profile is applied. A profile is not applied
+	// when it is invalid or when it conflicts with another profile.
+	Applied bool
+	// ConflictingProfile is the profile that takes precedence on some of the
+	// nodes that match the profile. It is nil when there are no conflicts.
+	ConflictingProfile *types.NamespacedName
+	// MatchedNodes is the number of nodes that match the profile affinity.
+	MatchedNodes int
+	// MigratedNodes is the number of nodes on which the profile is applied and
+	// that already have the profile label.
+	MigratedNodes int
+	// PendingNodes is the number of nodes on which the profile is applied but
+	// that don't have the profile label yet.
+	PendingNodes int
+	// NoDatadogAgent is true when the profile doesn't apply to any of the
+	// DatadogAgents, because of its datadogAgentRef or datadogAgentSelector.
+	NoDatadogAgent bool
+	// DaemonSetNames are the names of the DaemonSets generated for the
+	// profile, one per DatadogAgent on which it is applied.
+	DaemonSetNames []string
+}
+
 // ProfilesToApply given a list of profiles, returns the ones that should be
 // applied in the cluster.
 // - If there are no profiles, it returns the default profile.
 // - If there are no conflicting profiles, it returns all the profiles plus the default one.
 // - If there are conflicting profiles, it returns a subset that does not
-// conflict plus the default one. When there are conflicting profiles, the
-// oldest one is the one that takes precedence. When two profiles share an
+// conflict plus the default one. When there are conflicting profiles, the one
+// with the highest priority takes precedence. When two profiles share the same
+// priority, the oldest one takes precedence, and when they also share an
 // identical creation timestamp, the profile whose name is alphabetically first
 // is considered to have priority.
+// - If a conflicting profile uses the merge conflict mode and its overrides
+// don't overlap with the ones of the profiles with precedence, it returns an
+// additional merged profile for each set of profiles that apply to the same
+// nodes. The merged profiles target these nodes through the profile label and
+// the other profiles exclude them.
 // This function also returns a map that maps each node name to the profile that
 // should be applied to it.
 func ProfilesToApply(profiles []datadoghqv1alpha1.DatadogAgentProfile, nodes []v1.Node, logger logr.Logger) ([]datadoghqv1alpha1.DatadogAgentProfile, map[string]types.NamespacedName, error) {
+	profilesToApply, profileAppliedPerNode, _, err := evaluateProfiles(profiles, nodes, logger)
+	return profilesToApply, profileAppliedPerNode, err
+}
+
+// ProfilesStatus returns the status of each of the given profiles, as
+// evaluated by ProfilesToApply.
+func ProfilesStatus(profiles []datadoghqv1alpha1.DatadogAgentProfile, nodes []v1.Node, logger logr.Logger) (map[types.NamespacedName]ProfileStatus, error) {
+	_, _, statuses, err := evaluateProfiles(profiles, nodes, logger)
+	return statuses, err
+}
+
+func evaluateProfiles(profiles []datadoghqv1alpha1.DatadogAgentProfile, nodes []v1.Node, logger logr.Logger) ([]datadoghqv1alpha1.DatadogAgentProfile, map[string]types.NamespacedName, map[types.NamespacedName]ProfileStatus, error) {
 	var profilesToApply []datadoghqv1alpha1.DatadogAgentProfile
 	profileAppliedPerNode := make(map[string]types.NamespacedName, len(nodes))
+	statuses := make(map[types.NamespacedName]ProfileStatus, len(profiles))
+	// profilesPerNode contains the profiles applied on each node, the first one
+	// being the one with precedence. There are several profiles only when they
+	// are merged, configPerNode then contains their merged config.
+	profilesPerNode := make(map[string][]types.NamespacedName, len(nodes))
+	configPerNode := make(map[string]*datadoghqv1alpha1.Config, len(nodes))
 
 	sortedProfiles := sortProfiles(profiles)
 
 	for _, profile := range sortedProfiles {
-		conflicts := false
+		profileNamespacedName := types.NamespacedName{
+			Namespace: profile.Namespace,
+			Name:      profile.Name,
+		}
+		status := ProfileStatus{}
 		nodesThatMatchProfile := map[string]bool{}
+		mergedConfigPerNode := map[string]*datadoghqv1alpha1.Config{}
 
 		if err := datadoghqv1alpha1.ValidateDatadogAgentProfileSpec(&profile.Spec); err != nil {
 			logger.Error(err, "profile spec is invalid, skipping", "name", profile.Name, "namespace", profile.Namespace)
+			status.ValidationError = err
+			statuses[profileNamespacedName] = status
 			continue
 		}
 
 		for _, node := range nodes {
-			matchesNode, err := profileMatchesNode(&profile, node.Labels)
+			matchesNode, err := ProfileMatchesNode(&profile, &node)
 			if err != nil {
-				return nil, nil, err
+				return nil, nil, nil, err
 			}
 
-			if matchesNode {
-				if existingProfile, found := profileAppliedPerNode[node.Name]; found {
-					// Conflict. This profile should not be applied.
-					conflicts = true
-					logger.Info("conflict with existing profile, skipping", "conflicting profile", profile.Namespace+"/"+profile.Name, "existing profile", existingProfile.String())
-					break
-				} else {
-					nodesThatMatchProfile[node.Name] = true
+			if !matchesNode {
+				continue
+			}
+			status.MatchedNodes++
+
+			existingProfiles, found := profilesPerNode[node.Name]
+			if !found {
+				nodesThatMatchProfile[node.Name] = true
+				continue
+			}
+
+			if profile.Spec.ConflictMode == datadoghqv1alpha1.ConflictModeMerge {
+				if mergedConfig, merged := mergeConfigs(configPerNode[node.Name], profile.Spec.Config); merged {
+					mergedConfigPerNode[node.Name] = mergedConfig
+					continue
 				}
 			}
+
+			// Conflict. This profile should not be applied.
+			if status.ConflictingProfile == nil {
+				logger.Info("conflict with existing profile, skipping", "conflicting profile", profileNamespacedName.String(), "existing profile", existingProfiles[0].String())
+				conflictingProfile := existingProfiles[0]
+				status.ConflictingProfile = &conflictingProfile
+			}
 		}
 
-		if conflicts {
+		if status.ConflictingProfile != nil {
+			statuses[profileNamespacedName] = status
 			continue
 		}
 
 		for node := range nodesThatMatchProfile {
-			profileAppliedPerNode[node] = types.NamespacedName{
-				Namespace: profile.Namespace,
-				Name:      profile.Name,
-			}
+			profilesPerNode[node] = []types.NamespacedName{profileNamespacedName}
+			configPerNode[node] = profile.Spec.Config
+		}
+		for node, mergedConfig := range mergedConfigPerNode {
+			profilesPerNode[node] = append(profilesPerNode[node], profileNamespacedName)
+			configPerNode[node] = mergedConfig
 		}
 
+		status.Applied = true
+		statuses[profileNamespacedName] = status
 		profilesToApply = append(profilesToApply, profile)
 	}
 
+	profilesToApply = append(profilesToApply, mergedProfiles(profilesToApply, profilesPerNode, configPerNode, profileAppliedPerNode)...)
 	profilesToApply = append(profilesToApply, defaultProfile())
 
 	// Apply the default profile to all nodes that don't have a profile applied
@@ -97,23 +185,299 @@ func ProfilesToApply(profiles []datadoghqv1alpha1.DatadogAgentProfile, nodes []v
 		}
 	}
 
-	return profilesToApply, profileAppliedPerNode, nil
+	// The nodes move to the DaemonSet of their profile once they are labeled
+	for _, node := range nodes {
+		migrated := node.Labels[ProfileLabelKey] == NodeLabelValue(profileAppliedPerNode[node.Name])
+		for _, profile := range profilesPerNode[node.Name] {
+			status := statuses[profile]
+			if migrated {
+				status.MigratedNodes++
+			} else {
+				status.PendingNodes++
+			}
+			statuses[profile] = status
+		}
+	}
+
+	return profilesToApply, profileAppliedPerNode, statuses, nil
+}
+
+// mergedProfiles fills profileAppliedPerNode and returns a merged profile for
+// each set of profiles applied on the same nodes, with the rollout policy of
+// the profile with precedence and the taints of all the merged profiles. The affinity of the profiles
+// in profilesToApply is updated to exclude the nodes of their merged profiles.
+func mergedProfiles(profilesToApply []datadoghqv1alpha1.DatadogAgentProfile, profilesPerNode map[string][]types.NamespacedName,
+	configPerNode map[string]*datadoghqv1alpha1.Config, profileAppliedPerNode map[string]types.NamespacedName) []datadoghqv1alpha1.DatadogAgentProfile {
+	merged := map[types.NamespacedName]datadoghqv1alpha1.DatadogAgentProfile{}
+	excludedLabelsPerProfile := map[types.NamespacedName][]string{}
+	profilesByName := map[types.NamespacedName]*datadoghqv1alpha1.DatadogAgentProfile{}
+	for i := range profilesToApply {
+		profilesByName[types.NamespacedName{Namespace: profilesToApply[i].Namespace, Name: profilesToApply[i].Name}] = &profilesToApply[i]
+	}
+
+	for node, nodeProfiles := range profilesPerNode {
+		if len(nodeProfiles) == 1 {
+			profileAppliedPerNode[node] = nodeProfiles[0]
+			continue
+		}
+
+		mergedNamespacedName := mergedProfileNamespacedName(nodeProfiles)
+		profileAppliedPerNode[node] = mergedNamespacedName
+		if _, found := merged[mergedNamespacedName]; found {
+			continue
+		}
+
+		labelValue := profileLabelValue(mergedNamespacedName)
+		// The Agent pods of the merged profile tolerate the taints that the merged profiles select the nodes by
+		var taints []v1.Taint
+		for _, profile := range nodeProfiles {
+			if affinity := profilesByName[profile].Spec.ProfileAffinity; affinity != nil {
+				taints = append(taints, affinity.ProfileNodeTaints...)
+			}
+		}
+		merged[mergedNamespacedName] = datadoghqv1alpha1.DatadogAgentProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: mergedNamespacedName.Namespace,
+				Name:      mergedNamespacedName.Name,
+			},
+			Spec: datadoghqv1alpha1.DatadogAgentProfileSpec{
+				ProfileAffinity: &datadoghqv1alpha1.ProfileAffinity{
+					ProfileNodeAffinity: []v1.NodeSelectorRequirement{
+						{
+							Key:      ProfileLabelKey,
+							Operator: v1.NodeSelectorOpIn,
+							Values:   []string{labelValue},
+						},
+					},
+					ProfileNodeTaints: taints,
+				},
+				Config: configPerNode[node],
+				// The merged profile is rolled out like the profile with precedence
+				RolloutPolicy: profilesByName[nodeProfiles[0]].Spec.RolloutPolicy.DeepCopy(),
+			},
+		}
+		for _, profile := range nodeProfiles {
+			excludedLabelsPerProfile[profile] = append(excludedLabelsPerProfile[profile], labelValue)
+		}
+	}
+
+	for i := range profilesToApply {
+		excludedLabels, found := excludedLabelsPerProfile[types.NamespacedName{Namespace: profilesToApply[i].Namespace, Name: profilesToApply[i].Name}]
+		if !found {
+			continue
+		}
+		sort.Strings(excludedLabels)
+		profile := profilesToApply[i].DeepCopy()
+		profile.Spec.ProfileAffinity.ProfileNodeAffinity = append(profile.Spec.ProfileAffinity.ProfileNodeAffinity, v1.NodeSelectorRequirement{
+			Key:      ProfileLabelKey,
+			Operator: v1.NodeSelectorOpNotIn,
+			Values:   excludedLabels,
+		})
+		profilesToApply[i] = *profile
+	}
+
+	res := make([]datadoghqv1alpha1.DatadogAgentProfile, 0, len(merged))
+	for _, profile := range merged {
+		res = append(res, profile)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// mergedProfileNamespacedName returns the name of the profile that merges the
+// given profiles. It's based on the name of the profile with precedence and on
+// a hash of the names of all the profiles.
+func mergedProfileNamespacedName(profiles []types.NamespacedName) types.NamespacedName {
+	hash := fnv.New32a()
+	for _, profile := range profiles {
+		_, _ = hash.Write([]byte(profile.String() + ","))
+	}
+	return types.NamespacedName{
+		Namespace: profiles[0].Namespace,
+		Name:      fmt.Sprintf("%s%s%08x", profiles[0].Name, mergedProfileNameSeparator, hash.Sum32()),
+	}
+}
+
+// mergeConfigs returns the combination of two profile configs. It returns
+// false when the configs override the same settings.
+func mergeConfigs(config, other *datadoghqv1alpha1.Config) (*datadoghqv1alpha1.Config, bool) {
+	merged := config.DeepCopy()
+	if merged == nil {
+		merged = &datadoghqv1alpha1.Config{}
+	}
+	if other == nil {
+		return merged, true
+	}
+
+	for componentName, otherOverride := range other.Override {
+		if otherOverride == nil {
+			continue
+		}
+		if merged.Override == nil {
+			merged.Override = map[datadoghqv1alpha1.ComponentName]*datadoghqv1alpha1.Override{}
+		}
+		override, found := merged.Override[componentName]
+		if !found || override == nil {
+			merged.Override[componentName] = otherOverride.DeepCopy()
+			continue
+		}
+		if !mergeOverrides(override, otherOverride) {
+			return nil, false
+		}
+	}
+
+	return merged, true
+}
+
+// mergeOverrides adds the settings of other to override. It returns false
+// when both override the same settings.
+func mergeOverrides(override, other *datadoghqv1alpha1.Override) bool {
+	if other.PriorityClassName != nil && override.PriorityClassName != nil ||
+		haveCommonKeys(envVarNames(override.Env), envVarNames(other.Env)) ||
+		haveCommonKeys(volumeNames(override.Volumes), volumeNames(other.Volumes)) ||
+		haveCommonKeys(mapKeys(override.NodeSelector), mapKeys(other.NodeSelector)) ||
+		haveCommonKeys(mapKeys(override.Annotations), mapKeys(other.Annotations)) ||
+		haveCommonKeys(featureIDs(override.Features), featureIDs(other.Features)) {
+		return false
+	}
+
+	if other.PriorityClassName != nil {
+		override.PriorityClassName = other.PriorityClassName
+	}
+	override.Env = append(override.Env, other.Env...)
+	override.Volumes = append(override.Volumes, other.Volumes...)
+	// The tolerations are additive, they can't overlap
+	override.Tolerations = append(override.Tolerations, other.Tolerations...)
+	for key, value := range other.NodeSelector {
+		if override.NodeSelector == nil {
+			override.NodeSelector = map[string]string{}
+		}
+		override.NodeSelector[key] = value
+	}
+	for key, value := range other.Annotations {
+		if override.Annotations == nil {
+			override.Annotations = map[string]string{}
+		}
+		override.Annotations[key] = value
+	}
+	for featureID, toggle := range other.Features {
+		if override.Features == nil {
+			override.Features = map[string]*datadoghqv1alpha1.FeatureToggle{}
+		}
+		override.Features[featureID] = toggle
+	}
+
+	for containerName, otherContainer := range other.Containers {
+		if otherContainer == nil {
+			continue
+		}
+		if override.Containers == nil {
+			override.Containers = map[common.AgentContainerName]*datadoghqv1alpha1.Container{}
+		}
+		container, found := override.Containers[containerName]
+		if !found || container == nil {
+			override.Containers[containerName] = otherContainer.DeepCopy()
+			continue
+		}
+		if !mergeContainers(container, otherContainer) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mergeContainers adds the settings of other to container. It returns false
+// when both override the same settings.
+func mergeContainers(container, other *datadoghqv1alpha1.Container) bool {
+	if other.Resources != nil && container.Resources != nil ||
+		other.Args != nil && container.Args != nil ||
+		haveCommonKeys(envVarNames(container.Env), envVarNames(other.Env)) ||
+		haveCommonKeys(mountPaths(container.VolumeMounts), mountPaths(other.VolumeMounts)) {
+		return false
+	}
+
+	if other.Resources != nil {
+		container.Resources = other.Resources.DeepCopy()
+	}
+	if other.Args != nil {
+		container.Args = other.Args
+	}
+	container.Env = append(container.Env, other.Env...)
+	container.VolumeMounts = append(container.VolumeMounts, other.VolumeMounts...)
+
+	return true
+}
+
+func haveCommonKeys(keys, otherKeys []string) bool {
+	keySet := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		keySet[key] = true
+	}
+	for _, key := range otherKeys {
+		if keySet[key] {
+			return true
+		}
+	}
+	return false
+}
+
+func envVarNames(envs []v1.EnvVar) []string {
+	names := make([]string, 0, len(envs))
+	for _, env := range envs {
+		names = append(names, env.Name)
+	}
+	return names
+}
+
+func volumeNames(volumes []v1.Volume) []string {
+	names := make([]string, 0, len(volumes))
+	for _, volume := range volumes {
+		names = append(names, volume.Name)
+	}
+	return names
+}
+
+func mountPaths(mounts []v1.VolumeMount) []string {
+	paths := make([]string, 0, len(mounts))
+	for _, mount := range mounts {
+		paths = append(paths, mount.MountPath)
+	}
+	return paths
+}
+
+func mapKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func featureIDs(features map[string]*datadoghqv1alpha1.FeatureToggle) []string {
+	ids := make([]string, 0, len(features))
+	for id := range features {
+		ids = append(ids, id)
+	}
+	return ids
 }
 
 // OverrideFromProfile returns the component override that should be
-// applied according to the given profile.
-func OverrideFromProfile(profile *datadoghqv1alpha1.DatadogAgentProfile) v2alpha1.DatadogAgentComponentOverride {
+// applied according to the given profile of the given DatadogAgent.
+func OverrideFromProfile(profile *datadoghqv1alpha1.DatadogAgentProfile, dda metav1.Object) v2alpha1.DatadogAgentComponentOverride {
 	if profile.Name == "" && profile.Namespace == "" {
 		return v2alpha1.DatadogAgentComponentOverride{}
 	}
 	overrideDSName := DaemonSetName(types.NamespacedName{
 		Namespace: profile.Namespace,
 		Name:      profile.Name,
-	})
+	}, dda.GetName())
 
 	profileComponentOverride := v2alpha1.DatadogAgentComponentOverride{
 		Name:       &overrideDSName,
-		Affinity:   affinityOverride(profile),
+		Affinity:   affinityOverride(profile, dda),
 		Containers: containersOverride(profile),
 		Labels:     labelsOverride(profile),
 	}
@@ -122,9 +486,77 @@ func OverrideFromProfile(profile *datadoghqv1alpha1.DatadogAgentProfile) v2alpha
 		profileComponentOverride.PriorityClassName = priorityClassName
 	}
 
+	if nodeAgentOverride := nodeAgentOverride(profile); nodeAgentOverride != nil {
+		profileComponentOverride.Env = nodeAgentOverride.Env
+		profileComponentOverride.Volumes = nodeAgentOverride.Volumes
+		profileComponentOverride.Tolerations = nodeAgentOverride.Tolerations
+		profileComponentOverride.NodeSelector = nodeAgentOverride.NodeSelector
+		profileComponentOverride.Annotations = nodeAgentOverride.Annotations
+	}
+
+	// The Agent pods must tolerate the taints of the nodes selected by the profile
+	if tolerations := taintTolerations(profile); len(tolerations) > 0 {
+		profileComponentOverride.Tolerations = append(append([]v1.Toleration{}, profileComponentOverride.Tolerations...), tolerations...)
+	}
+
 	return profileComponentOverride
 }
 
+// taintTolerations returns the tolerations of the taints that the profile
+// selects the nodes by.
+func taintTolerations(profile *datadoghqv1alpha1.DatadogAgentProfile) []v1.Toleration {
+	if profile.Spec.ProfileAffinity == nil {
+		return nil
+	}
+
+	var tolerations []v1.Toleration
+	for _, taint := range profile.Spec.ProfileAffinity.ProfileNodeTaints {
+		toleration := v1.Toleration{
+			Key:      taint.Key,
+			Operator: v1.TolerationOpExists,
+			Effect:   taint.Effect,
+		}
+		if taint.Value != "" {
+			toleration.Operator = v1.TolerationOpEqual
+			toleration.Value = taint.Value
+		}
+		tolerations = append(tolerations, toleration)
+	}
+
+	return tolerations
+}
+
+// DisabledFeatures returns the IDs of the features that the given profile
+// turns off on its nodes.
+func DisabledFeatures(profile *datadoghqv1alpha1.DatadogAgentProfile) []string {
+	nodeAgentOverride := nodeAgentOverride(profile)
+	if nodeAgentOverride == nil {
+		return nil
+	}
+
+	var disabledFeatures []string
+	for featureID, toggle := range nodeAgentOverride.Features {
+		if toggle != nil && toggle.Enabled != nil && !*toggle.Enabled {
+			disabledFeatures = append(disabledFeatures, featureID)
+		}
+	}
+	sort.Strings(disabledFeatures)
+
+	return disabledFeatures
+}
+
+// nodeAgentOverride returns the node agent override of the given profile, or
+// nil if the profile doesn't define one. It's always nil for the default
+// profile.
+func nodeAgentOverride(profile *datadoghqv1alpha1.DatadogAgentProfile) *datadoghqv1alpha1.Override {
+	if IsDefaultProfile(profile.Namespace, profile.Name) || profile.Spec.Config == nil {
+		return nil
+	}
+
+	// We only support overrides for the node agent
+	return profile.Spec.Config.Override[datadoghqv1alpha1.NodeAgentComponentName]
+}
+
 // IsDefaultProfile returns true if the given profile namespace and name
 // correspond to the default profile.
 func IsDefaultProfile(profileNamespace string, profileName string) bool {
@@ -132,13 +564,15 @@ func IsDefaultProfile(profileNamespace string, profileName string) bool {
 }
 
 // DaemonSetName returns the name that the DaemonSet should have according to
-// the name of the profile associated with it.
-func DaemonSetName(profileNamespacedName types.NamespacedName) string {
+// the name of the profile associated with it and the name of the DatadogAgent
+// it belongs to. A profile applied to several DatadogAgents has a DaemonSet
+// per DatadogAgent.
+func DaemonSetName(profileNamespacedName types.NamespacedName, ddaName string) string {
 	if IsDefaultProfile(profileNamespacedName.Namespace, profileNamespacedName.Name) {
 		return "" // Return empty so it does not override the default DaemonSet name
 	}
 
-	return daemonSetNamePrefix + profileNamespacedName.Namespace + "-" + profileNamespacedName.Name
+	return ddaName + daemonSetNameInfix + profileNamespacedName.Namespace + "-" + profileNamespacedName.Name
 }
 
 // defaultProfile returns the default profile, we just need a name to identify
@@ -152,13 +586,35 @@ func defaultProfile() datadoghqv1alpha1.DatadogAgentProfile {
 	}
 }
 
-func affinityOverride(profile *datadoghqv1alpha1.DatadogAgentProfile) *v1.Affinity {
+func affinityOverride(profile *datadoghqv1alpha1.DatadogAgentProfile, dda metav1.Object) *v1.Affinity {
 	if IsDefaultProfile(profile.Namespace, profile.Name) {
-		return affinityOverrideForDefaultProfile()
+		return affinityOverrideForDefaultProfile(dda)
 	}
 
 	affinity := &v1.Affinity{
-		PodAntiAffinity: podAntiAffinityOverride(),
+		PodAntiAffinity: podAntiAffinityOverride(dda.GetName()),
+	}
+
+	// The Agent pods only run on the nodes that have the profile label when
+	// the node affinity can't express the profile affinity, or must not follow
+	// the changes of the node labels.
+	if usesProfileLabelAffinity(profile) {
+		affinity.NodeAffinity = &v1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+				NodeSelectorTerms: []v1.NodeSelectorTerm{
+					{
+						MatchExpressions: []v1.NodeSelectorRequirement{
+							{
+								Key:      ProfileLabelKey,
+								Operator: v1.NodeSelectorOpIn,
+								Values:   []string{profileLabelValue(types.NamespacedName{Namespace: profile.Namespace, Name: profile.Name})},
+							},
+						},
+					},
+				},
+			},
+		}
+		return affinity
 	}
 
 	if profile.Spec.ProfileAffinity == nil || len(profile.Spec.ProfileAffinity.ProfileNodeAffinity) == 0 {
@@ -178,10 +634,28 @@ func affinityOverride(profile *datadoghqv1alpha1.DatadogAgentProfile) *v1.Affini
 	return affinity
 }
 
+// usesProfileLabelAffinity returns true if the Agent pods of the profile must
+// be scheduled on the nodes that have the profile label, which the reconciler
+// maintains, instead of the nodes that match the profile node affinity. It's
+// the case when the profile has a rollout policy, since the reconciler sets
+// the label progressively, or when the profile selects the nodes by other
+// means than their labels.
+func usesProfileLabelAffinity(profile *datadoghqv1alpha1.DatadogAgentProfile) bool {
+	if profile.Spec.RolloutPolicy != nil {
+		return true
+	}
+	affinity := profile.Spec.ProfileAffinity
+	return affinity != nil && (len(affinity.ProfileNodeAnnotations) > 0 || len(affinity.ProfileNodeTaints) > 0 || affinity.ProfileNodeResources != nil)
+}
+
 // affinityOverrideForDefaultProfile returns the affinity override that should
-// be applied to the default profile. The default profile should be applied to
-// all nodes that don't have the agent.datadoghq.com/profile label.
-func affinityOverrideForDefaultProfile() *v1.Affinity {
+// be applied to the default profile of a DatadogAgent. The default profile
+// should be applied to all nodes that don't have the profile label of the
+// DatadogAgent: the nodes labeled with the profiles of other DatadogAgents
+// keep an Agent of the default DaemonSet. The affinity doesn't depend on the
+// profiles, so that their changes don't restart the Agents of the default
+// DaemonSet.
+func affinityOverrideForDefaultProfile(dda metav1.Object) *v1.Affinity {
 	return &v1.Affinity{
 		NodeAffinity: &v1.NodeAffinity{
 			RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
@@ -189,7 +663,7 @@ func affinityOverrideForDefaultProfile() *v1.Affinity {
 					{
 						MatchExpressions: []v1.NodeSelectorRequirement{
 							{
-								Key:      ProfileLabelKey,
+								Key:      DatadogAgentProfileLabelKey(dda),
 								Operator: v1.NodeSelectorOpDoesNotExist,
 							},
 						},
@@ -197,14 +671,15 @@ func affinityOverrideForDefaultProfile() *v1.Affinity {
 				},
 			},
 		},
-		PodAntiAffinity: podAntiAffinityOverride(),
+		PodAntiAffinity: podAntiAffinityOverride(dda.GetName()),
 	}
 }
 
 // podAntiAffinityOverride returns the pod anti-affinity used to avoid
-// scheduling multiple agent pods of different profiles on the same node during
-// rollouts.
-func podAntiAffinityOverride() *v1.PodAntiAffinity {
+// scheduling multiple agent pods of different profiles of a DatadogAgent on
+// the same node during rollouts. The Agent pods of other DatadogAgents don't
+// prevent the scheduling.
+func podAntiAffinityOverride(ddaName string) *v1.PodAntiAffinity {
 	return &v1.PodAntiAffinity{
 		RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{
 			{
@@ -215,6 +690,11 @@ func podAntiAffinityOverride() *v1.PodAntiAffinity {
 							Operator: metav1.LabelSelectorOpIn,
 							Values:   []string{"agent"},
 						},
+						{
+							Key:      apicommon.AgentDeploymentNameLabelKey,
+							Operator: metav1.LabelSelectorOpIn,
+							Values:   []string{ddaName},
+						},
 					},
 				},
 				TopologyKey: v1.LabelHostname, // Applies to all nodes
@@ -250,7 +730,10 @@ func containersOverride(profile *datadoghqv1alpha1.DatadogAgentProfile) map[comm
 	for _, containerName := range containersInNodeAgent {
 		if overrideForContainer, overrideIsDefined := nodeAgentOverride.Containers[containerName]; overrideIsDefined {
 			res[containerName] = &v2alpha1.DatadogAgentGenericContainer{
-				Resources: overrideForContainer.Resources,
+				Resources:    overrideForContainer.Resources,
+				Env:          overrideForContainer.Env,
+				VolumeMounts: overrideForContainer.VolumeMounts,
+				Args:         overrideForContainer.Args,
 			}
 		}
 	}
@@ -264,10 +747,75 @@ func labelsOverride(profile *datadoghqv1alpha1.DatadogAgentProfile) map[string]s
 	}
 
 	return map[string]string{
-		// Can't use the namespaced name because it includes "/" which is not
-		// accepted in labels.
-		ProfileLabelKey: fmt.Sprintf("%s-%s", profile.Namespace, profile.Name),
+		ProfileLabelKey: profileLabelValue(types.NamespacedName{Namespace: profile.Namespace, Name: profile.Name}),
+	}
+}
+
+// DatadogAgentProfileLabelKey returns the key of the label that the
+// DatadogAgent sets, in addition to the profile label and with the same value,
+// on the nodes where one of its profiles is applied. Label names are limited
+// to 63 characters, so long DatadogAgent names are shortened.
+func DatadogAgentProfileLabelKey(dda metav1.Object) string {
+	// The name of the label key is "profile.<namespace>-<name>"
+	maxLength := validation.LabelValueMaxLength - len("profile.")
+	return ProfileLabelKey + "." + shortenLabelValue(fmt.Sprintf("%s-%s", dda.GetNamespace(), dda.GetName()), maxLength)
+}
+
+// profileLabelValue returns the value of the profile label for the given profile.
+// Label values are limited to 63 characters, so long values are shortened.
+// The label value of a merged profile starts with the mergedLabelPrefix of the
+// profile with precedence.
+func profileLabelValue(profileNamespacedName types.NamespacedName) string {
+	if name, hash, merged := splitMergedProfileName(profileNamespacedName.Name); merged {
+		return mergedLabelPrefix(types.NamespacedName{Namespace: profileNamespacedName.Namespace, Name: name}) + hash
+	}
+	// Can't use the namespaced name because it includes "/" which is not
+	// accepted in labels.
+	return shortenLabelValue(fmt.Sprintf("%s-%s", profileNamespacedName.Namespace, profileNamespacedName.Name), validation.LabelValueMaxLength)
+}
+
+// mergedLabelPrefix returns the prefix of the label values of the merged
+// profiles in which the given profile takes precedence. It leaves room for the
+// hash of the merged profile name within the label value length limit.
+func mergedLabelPrefix(profileNamespacedName types.NamespacedName) string {
+	maxLength := validation.LabelValueMaxLength - len(mergedProfileNameSeparator) - mergedProfileHashLength
+	return shortenLabelValue(fmt.Sprintf("%s-%s", profileNamespacedName.Namespace, profileNamespacedName.Name), maxLength) + mergedProfileNameSeparator
+}
+
+// splitMergedProfileName returns the name of the profile with precedence and
+// the hash of a merged profile name built by mergedProfileNamespacedName.
+func splitMergedProfileName(name string) (string, string, bool) {
+	i := strings.LastIndex(name, mergedProfileNameSeparator)
+	if i < 0 {
+		return "", "", false
+	}
+	hash := name[i+len(mergedProfileNameSeparator):]
+	if len(hash) != mergedProfileHashLength || strings.Trim(hash, "0123456789abcdef") != "" {
+		return "", "", false
 	}
+	return name[:i], hash, true
+}
+
+// shortenLabelValue truncates the values longer than maxLength and suffixes
+// them with a hash of the full value, so that they stay unique.
+func shortenLabelValue(value string, maxLength int) string {
+	if len(value) <= maxLength {
+		return value
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(value))
+	suffix := fmt.Sprintf("-%08x", hash.Sum32())
+	return value[:maxLength-len(suffix)] + suffix
+}
+
+// NodeLabelValue returns the value of the profile label of the nodes on which
+// the given profile is applied. It's empty for the default profile, since its
+// nodes don't have the label.
+func NodeLabelValue(profileNamespacedName types.NamespacedName) string {
+	if IsDefaultProfile(profileNamespacedName.Namespace, profileNamespacedName.Name) {
+		return ""
+	}
+	return profileLabelValue(profileNamespacedName)
 }
 
 func priorityClassNameOverride(profile *datadoghqv1alpha1.DatadogAgentProfile) *string {
@@ -287,13 +835,17 @@ func priorityClassNameOverride(profile *datadoghqv1alpha1.DatadogAgentProfile) *
 	return nodeAgentOverride.PriorityClassName
 }
 
-// sortProfiles sorts the profiles by creation timestamp. If two profiles have
-// the same creation timestamp, it sorts them by name.
+// sortProfiles sorts the profiles by decreasing priority. If two profiles have
+// the same priority, it sorts them by creation timestamp, and then by name.
 func sortProfiles(profiles []datadoghqv1alpha1.DatadogAgentProfile) []datadoghqv1alpha1.DatadogAgentProfile {
 	sortedProfiles := make([]datadoghqv1alpha1.DatadogAgentProfile, len(profiles))
 	copy(sortedProfiles, profiles)
 
 	sort.Slice(sortedProfiles, func(i, j int) bool {
+		if priorityI, priorityJ := profilePriority(&sortedProfiles[i]), profilePriority(&sortedProfiles[j]); priorityI != priorityJ {
+			return priorityI > priorityJ
+		}
+
 		if !sortedProfiles[i].CreationTimestamp.Equal(&sortedProfiles[j].CreationTimestamp) {
 			return sortedProfiles[i].CreationTimestamp.Before(&sortedProfiles[j].CreationTimestamp)
 		}
@@ -304,7 +856,16 @@ func sortProfiles(profiles []datadoghqv1alpha1.DatadogAgentProfile) []datadoghqv
 	return sortedProfiles
 }
 
-func profileMatchesNode(profile *datadoghqv1alpha1.DatadogAgentProfile, nodeLabels map[string]string) (bool, error) {
+func profilePriority(profile *datadoghqv1alpha1.DatadogAgentProfile) int32 {
+	if profile.Spec.Priority == nil {
+		return 0
+	}
+	return *profile.Spec.Priority
+}
+
+// ProfileMatchesNode returns true if the node matches the profile affinity:
+// its node labels, annotations, taints and allocatable resources.
+func ProfileMatchesNode(profile *datadoghqv1alpha1.DatadogAgentProfile, node *v1.Node) (bool, error) {
 	if profile.Spec.ProfileAffinity == nil {
 		return true, nil
 	}
@@ -319,7 +880,25 @@ func profileMatchesNode(profile *datadoghqv1alpha1.DatadogAgentProfile, nodeLabe
 			return false, err
 		}
 
-		if !selector.Matches(labels.Set(nodeLabels)) {
+		if !selector.Matches(labels.Set(node.Labels)) {
+			return false, nil
+		}
+	}
+
+	for _, requirement := range profile.Spec.ProfileAffinity.ProfileNodeAnnotations {
+		if !annotationsMatchRequirement(node.Annotations, requirement) {
+			return false, nil
+		}
+	}
+
+	for _, taint := range profile.Spec.ProfileAffinity.ProfileNodeTaints {
+		if !nodeHasTaint(node, taint) {
+			return false, nil
+		}
+	}
+
+	if resources := profile.Spec.ProfileAffinity.ProfileNodeResources; resources != nil {
+		if !allocatableInRange(node, v1.ResourceCPU, resources.CPU) || !allocatableInRange(node, v1.ResourceMemory, resources.Memory) {
 			return false, nil
 		}
 	}
@@ -327,6 +906,99 @@ func profileMatchesNode(profile *datadoghqv1alpha1.DatadogAgentProfile, nodeLabe
 	return true, nil
 }
 
+// annotationsMatchRequirement returns true if the node annotations match the
+// requirement. The label selectors can't be used because the annotation values
+// don't have the format constraints of the label values.
+func annotationsMatchRequirement(annotations map[string]string, requirement v1.NodeSelectorRequirement) bool {
+	value, found := annotations[requirement.Key]
+	switch requirement.Operator {
+	case v1.NodeSelectorOpExists:
+		return found
+	case v1.NodeSelectorOpDoesNotExist:
+		return !found
+	case v1.NodeSelectorOpIn:
+		return found && containsString(requirement.Values, value)
+	case v1.NodeSelectorOpNotIn:
+		return !found || !containsString(requirement.Values, value)
+	default:
+		return false
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeHasTaint returns true if the node has a taint with the key and the
+// effect of the given taint, and with its value when it is defined.
+func nodeHasTaint(node *v1.Node, taint v1.Taint) bool {
+	for _, nodeTaint := range node.Spec.Taints {
+		if nodeTaint.Key == taint.Key &&
+			(taint.Value == "" || nodeTaint.Value == taint.Value) &&
+			(taint.Effect == "" || nodeTaint.Effect == taint.Effect) {
+			return true
+		}
+	}
+	return false
+}
+
+// allocatableInRange returns true if the allocatable quantity of the resource
+// of the node is in the range. The minimum is included, the maximum excluded.
+func allocatableInRange(node *v1.Node, resourceName v1.ResourceName, resourceRange *datadoghqv1alpha1.ResourceRange) bool {
+	if resourceRange == nil {
+		return true
+	}
+	allocatable, found := node.Status.Allocatable[resourceName]
+	if !found {
+		return false
+	}
+	if resourceRange.Min != nil && allocatable.Cmp(*resourceRange.Min) < 0 {
+		return false
+	}
+	if resourceRange.Max != nil && allocatable.Cmp(*resourceRange.Max) >= 0 {
+		return false
+	}
+	return true
+}
+
+// NodeChanged returns true if the fields of the node that the profiles select
+// the nodes by are different in the new node: the labels, the annotations
+// whose keys are in annotationKeys, the taints and the allocatable resources.
+// The other annotations are ignored, since they change often and no profile
+// selects the nodes by them.
+func NodeChanged(oldNode, newNode *v1.Node, annotationKeys map[string]struct{}) bool {
+	for key := range annotationKeys {
+		oldValue, oldFound := oldNode.Annotations[key]
+		newValue, newFound := newNode.Annotations[key]
+		if oldFound != newFound || oldValue != newValue {
+			return true
+		}
+	}
+	return !apiequality.Semantic.DeepEqual(oldNode.Labels, newNode.Labels) ||
+		!apiequality.Semantic.DeepEqual(oldNode.Spec.Taints, newNode.Spec.Taints) ||
+		!apiequality.Semantic.DeepEqual(oldNode.Status.Allocatable, newNode.Status.Allocatable)
+}
+
+// NodeAnnotationKeys returns the keys of the node annotations that the given
+// profiles select the nodes by.
+func NodeAnnotationKeys(profiles []datadoghqv1alpha1.DatadogAgentProfile) map[string]struct{} {
+	keys := map[string]struct{}{}
+	for _, profile := range profiles {
+		if profile.Spec.ProfileAffinity == nil {
+			continue
+		}
+		for _, requirement := range profile.Spec.ProfileAffinity.ProfileNodeAnnotations {
+			keys[requirement.Key] = struct{}{}
+		}
+	}
+	return keys
+}
+
 func nodeSelectorOperatorToSelectionOperator(op v1.NodeSelectorOperator) selection.Operator {
 	switch op {
 	case v1.NodeSelectorOpIn:
@@ -7,6 +7,7 @@ package agentprofile
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
@@ -462,9 +464,300 @@ func TestProfilesToApply(t *testing.T) {
 	}
 }
 
+func TestProfilesToApplyWithPriority(t *testing.T) {
+	now := time.Now()
+
+	oldProfile := exampleProfileForLinux()
+	oldProfile.Name = "old"
+	oldProfile.CreationTimestamp = metav1.NewTime(now)
+
+	newProfile := exampleProfileForLinux()
+	newProfile.Name = "new"
+	newProfile.CreationTimestamp = metav1.NewTime(now.Add(time.Minute))
+	newProfile.Spec.Priority = apiutils.NewInt32Pointer(10)
+
+	nodes := []v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"os": "linux"}}},
+	}
+
+	testLogger := zap.New(zap.UseDevMode(true))
+	profilesToApply, profileAppliedPerNode, err := ProfilesToApply([]v1alpha1.DatadogAgentProfile{oldProfile, newProfile}, nodes, testLogger)
+	require.NoError(t, err)
+
+	// The newer profile wins because of its higher priority
+	assert.ElementsMatch(t, []v1alpha1.DatadogAgentProfile{newProfile, defaultProfile()}, profilesToApply)
+	assert.Equal(t, map[string]types.NamespacedName{"node1": {Namespace: testNamespace, Name: "new"}}, profileAppliedPerNode)
+}
+
+func TestProfilesToApplyWithNodeTaintsAnnotationsAndResources(t *testing.T) {
+	now := time.Now()
+
+	gpuProfile := exampleProfileForLinux()
+	gpuProfile.Name = "gpu"
+	gpuProfile.CreationTimestamp = metav1.NewTime(now)
+	gpuProfile.Spec.ProfileAffinity = &v1alpha1.ProfileAffinity{
+		ProfileNodeTaints: []v1.Taint{{Key: "dedicated", Value: "gpu"}},
+	}
+
+	largeProfile := exampleProfileForLinux()
+	largeProfile.Name = "large"
+	largeProfile.CreationTimestamp = metav1.NewTime(now.Add(time.Minute))
+	largeProfile.Spec.ProfileAffinity.ProfileNodeResources = &v1alpha1.ProfileNodeResources{
+		CPU:    &v1alpha1.ResourceRange{Min: resource.NewQuantity(16, resource.DecimalSI)},
+		Memory: &v1alpha1.ResourceRange{Max: resource.NewQuantity(256<<30, resource.BinarySI)},
+	}
+
+	edgeProfile := exampleProfileForLinux()
+	edgeProfile.Name = "edge"
+	edgeProfile.CreationTimestamp = metav1.NewTime(now.Add(2 * time.Minute))
+	edgeProfile.Spec.ProfileAffinity = &v1alpha1.ProfileAffinity{
+		ProfileNodeAnnotations: []v1.NodeSelectorRequirement{
+			{Key: "example.com/location", Operator: v1.NodeSelectorOpIn, Values: []string{"edge site"}},
+		},
+	}
+
+	node := func(name string, cpu int64, taints []v1.Taint, annotations map[string]string) v1.Node {
+		return v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"os": "linux"}, Annotations: annotations},
+			Spec:       v1.NodeSpec{Taints: taints},
+			Status: v1.NodeStatus{
+				Allocatable: v1.ResourceList{
+					v1.ResourceCPU:    *resource.NewQuantity(cpu, resource.DecimalSI),
+					v1.ResourceMemory: resource.MustParse("64Gi"),
+				},
+			},
+		}
+	}
+	nodes := []v1.Node{
+		node("gpu-node", 4, []v1.Taint{{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectNoSchedule}}, nil),
+		node("other-taint-node", 4, []v1.Taint{{Key: "dedicated", Value: "infra", Effect: v1.TaintEffectNoSchedule}}, nil),
+		node("large-node", 16, nil, nil),
+		node("small-node", 8, nil, nil),
+		node("edge-node", 4, nil, map[string]string{"example.com/location": "edge site"}),
+	}
+
+	testLogger := zap.New(zap.UseDevMode(true))
+	profilesToApply, profileAppliedPerNode, err := ProfilesToApply([]v1alpha1.DatadogAgentProfile{gpuProfile, largeProfile, edgeProfile}, nodes, testLogger)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []v1alpha1.DatadogAgentProfile{gpuProfile, largeProfile, edgeProfile, defaultProfile()}, profilesToApply)
+	assert.Equal(t, map[string]types.NamespacedName{
+		"gpu-node":         {Namespace: testNamespace, Name: "gpu"},
+		"other-taint-node": {Name: defaultProfileName},
+		"large-node":       {Namespace: testNamespace, Name: "large"},
+		"small-node":       {Name: defaultProfileName},
+		"edge-node":        {Namespace: testNamespace, Name: "edge"},
+	}, profileAppliedPerNode)
+
+	// The Agent pods tolerate the taints and are scheduled through the profile label
+	override := OverrideFromProfile(&gpuProfile, testDatadogAgent())
+	assert.Equal(t, []v1.Toleration{{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "gpu"}}, override.Tolerations)
+	assert.Equal(t, []v1.NodeSelectorRequirement{
+		{Key: ProfileLabelKey, Operator: v1.NodeSelectorOpIn, Values: []string{"default-gpu"}},
+	}, override.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions)
+
+	override = OverrideFromProfile(&largeProfile, testDatadogAgent())
+	assert.Empty(t, override.Tolerations)
+	assert.Equal(t, []v1.NodeSelectorRequirement{
+		{Key: ProfileLabelKey, Operator: v1.NodeSelectorOpIn, Values: []string{"default-large"}},
+	}, override.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions)
+}
+
+func TestNodeChanged(t *testing.T) {
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"os": "linux"}},
+		Status:     v1.NodeStatus{Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")}},
+	}
+
+	annotationKeys := map[string]struct{}{"example.com/location": {}}
+
+	sameNode := node.DeepCopy()
+	sameNode.Status.Allocatable[v1.ResourceCPU] = resource.MustParse("4000m")
+	assert.False(t, NodeChanged(node, sameNode, annotationKeys))
+
+	newLabels := node.DeepCopy()
+	newLabels.Labels["pool"] = "large"
+	assert.True(t, NodeChanged(node, newLabels, annotationKeys))
+
+	newAnnotations := node.DeepCopy()
+	newAnnotations.Annotations = map[string]string{"example.com/location": "edge"}
+	assert.True(t, NodeChanged(node, newAnnotations, annotationKeys))
+	assert.False(t, NodeChanged(node, newAnnotations, nil), "annotations not referenced by a profile are ignored")
+
+	otherAnnotations := node.DeepCopy()
+	otherAnnotations.Annotations = map[string]string{"node.alpha.kubernetes.io/ttl": "0"}
+	assert.False(t, NodeChanged(node, otherAnnotations, annotationKeys))
+
+	newTaints := node.DeepCopy()
+	newTaints.Spec.Taints = []v1.Taint{{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectNoSchedule}}
+	assert.True(t, NodeChanged(node, newTaints, annotationKeys))
+
+	newAllocatable := node.DeepCopy()
+	newAllocatable.Status.Allocatable[v1.ResourceCPU] = resource.MustParse("8")
+	assert.True(t, NodeChanged(node, newAllocatable, annotationKeys))
+}
+
+func TestNodeAnnotationKeys(t *testing.T) {
+	annotationProfile := exampleProfileForLinux()
+	annotationProfile.Spec.ProfileAffinity.ProfileNodeAnnotations = []v1.NodeSelectorRequirement{
+		{Key: "example.com/location", Operator: v1.NodeSelectorOpIn, Values: []string{"edge"}},
+		{Key: "example.com/team", Operator: v1.NodeSelectorOpExists},
+	}
+	noAffinityProfile := exampleProfileForWindows()
+	noAffinityProfile.Spec.ProfileAffinity = nil
+
+	assert.Empty(t, NodeAnnotationKeys(nil))
+	assert.Empty(t, NodeAnnotationKeys([]v1alpha1.DatadogAgentProfile{exampleProfileForWindows(), noAffinityProfile}))
+	assert.Equal(t, map[string]struct{}{"example.com/location": {}, "example.com/team": {}}, NodeAnnotationKeys([]v1alpha1.DatadogAgentProfile{annotationProfile, noAffinityProfile}))
+}
+
+func TestProfilesToApplyWithMergeConflictMode(t *testing.T) {
+	now := time.Now()
+
+	// Overrides the core agent resources on all the linux nodes
+	linuxProfile := exampleProfileForLinux()
+	linuxProfile.CreationTimestamp = metav1.NewTime(now)
+
+	// Overrides the priority class on the linux nodes with a GPU
+	gpuProfile := v1alpha1.DatadogAgentProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         testNamespace,
+			Name:              "gpu",
+			CreationTimestamp: metav1.NewTime(now.Add(time.Minute)),
+		},
+		Spec: v1alpha1.DatadogAgentProfileSpec{
+			ProfileAffinity: &v1alpha1.ProfileAffinity{
+				ProfileNodeAffinity: []v1.NodeSelectorRequirement{
+					{
+						Key:      "gpu",
+						Operator: v1.NodeSelectorOpExists,
+					},
+				},
+			},
+			Config:       configWithCPURequestOverrideForCoreAgent("200m"),
+			ConflictMode: v1alpha1.ConflictModeMerge,
+		},
+	}
+	gpuProfile.Spec.Config.Override[v1alpha1.NodeAgentComponentName].Containers = map[common.AgentContainerName]*v1alpha1.Container{
+		common.TraceAgentContainerName: {
+			Resources: &v1.ResourceRequirements{
+				Requests: v1.ResourceList{
+					v1.ResourceCPU: resource.MustParse("50m"),
+				},
+			},
+		},
+	}
+	gpuProfile.Spec.Config.Override[v1alpha1.NodeAgentComponentName].PriorityClassName = apiutils.NewStringPointer("gpu")
+
+	nodes := []v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"os": "linux"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: map[string]string{"os": "linux", "gpu": "true"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node3", Labels: map[string]string{"os": "windows", "gpu": "true"}}},
+	}
+
+	testLogger := zap.New(zap.UseDevMode(true))
+	profilesToApply, profileAppliedPerNode, err := ProfilesToApply([]v1alpha1.DatadogAgentProfile{gpuProfile, linuxProfile}, nodes, testLogger)
+	require.NoError(t, err)
+	require.Len(t, profilesToApply, 4)
+
+	mergedName := mergedProfileNamespacedName([]types.NamespacedName{
+		{Namespace: testNamespace, Name: "linux"},
+		{Namespace: testNamespace, Name: "gpu"},
+	})
+	assert.Equal(t, map[string]types.NamespacedName{
+		"node1": {Namespace: testNamespace, Name: "linux"},
+		"node2": mergedName,
+		"node3": {Namespace: testNamespace, Name: "gpu"},
+	}, profileAppliedPerNode)
+
+	excludeMerged := v1.NodeSelectorRequirement{
+		Key:      ProfileLabelKey,
+		Operator: v1.NodeSelectorOpNotIn,
+		Values:   []string{testNamespace + "-" + mergedName.Name},
+	}
+
+	// The linux and gpu profiles don't apply on the merged nodes anymore
+	assert.Equal(t, "linux", profilesToApply[0].Name)
+	assert.Contains(t, profilesToApply[0].Spec.ProfileAffinity.ProfileNodeAffinity, excludeMerged)
+	assert.Equal(t, "gpu", profilesToApply[1].Name)
+	assert.Contains(t, profilesToApply[1].Spec.ProfileAffinity.ProfileNodeAffinity, excludeMerged)
+	// The profiles given as input are not modified
+	assert.Len(t, gpuProfile.Spec.ProfileAffinity.ProfileNodeAffinity, 1)
+
+	// The merged profile applies the overrides of both profiles on the labeled nodes
+	merged := profilesToApply[2]
+	assert.Equal(t, mergedName.Name, merged.Name)
+	assert.Equal(t, []v1.NodeSelectorRequirement{
+		{
+			Key:      ProfileLabelKey,
+			Operator: v1.NodeSelectorOpIn,
+			Values:   []string{testNamespace + "-" + mergedName.Name},
+		},
+	}, merged.Spec.ProfileAffinity.ProfileNodeAffinity)
+	mergedOverride := merged.Spec.Config.Override[v1alpha1.NodeAgentComponentName]
+	assert.Equal(t, resource.MustParse("100m"), mergedOverride.Containers[common.CoreAgentContainerName].Resources.Requests[v1.ResourceCPU])
+	assert.Equal(t, resource.MustParse("50m"), mergedOverride.Containers[common.TraceAgentContainerName].Resources.Requests[v1.ResourceCPU])
+	assert.Equal(t, apiutils.NewStringPointer("gpu"), mergedOverride.PriorityClassName)
+
+	assert.Equal(t, defaultProfile(), profilesToApply[3])
+
+	// With overlapping overrides, the profile is skipped
+	gpuProfile.Spec.Config = configWithCPURequestOverrideForCoreAgent("200m")
+	profilesToApply, profileAppliedPerNode, err = ProfilesToApply([]v1alpha1.DatadogAgentProfile{gpuProfile, linuxProfile}, nodes, testLogger)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []v1alpha1.DatadogAgentProfile{linuxProfile, defaultProfile()}, profilesToApply)
+	assert.Equal(t, types.NamespacedName{Namespace: testNamespace, Name: "linux"}, profileAppliedPerNode["node2"])
+}
+
+func TestProfilesStatus(t *testing.T) {
+	now := time.Now()
+
+	linuxProfile := exampleProfileForLinux()
+	linuxProfile.CreationTimestamp = metav1.NewTime(now)
+
+	// Matches the same nodes as the linux profile, but it's newer
+	conflictingProfile := exampleProfileForLinux()
+	conflictingProfile.Name = "linux-conflict"
+	conflictingProfile.CreationTimestamp = metav1.NewTime(now.Add(time.Minute))
+
+	invalidProfile := exampleProfileForWindows()
+	invalidProfile.Spec.Config = nil
+
+	nodes := []v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"os": "linux", ProfileLabelKey: "default-linux"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: map[string]string{"os": "linux"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node3", Labels: map[string]string{"os": "windows"}}},
+	}
+
+	testLogger := zap.New(zap.UseDevMode(true))
+	statuses, err := ProfilesStatus([]v1alpha1.DatadogAgentProfile{conflictingProfile, linuxProfile, invalidProfile}, nodes, testLogger)
+	require.NoError(t, err)
+
+	linuxStatus := statuses[types.NamespacedName{Namespace: testNamespace, Name: "linux"}]
+	assert.True(t, linuxStatus.Applied)
+	assert.NoError(t, linuxStatus.ValidationError)
+	assert.Nil(t, linuxStatus.ConflictingProfile)
+	assert.Equal(t, 2, linuxStatus.MatchedNodes)
+	assert.Equal(t, 1, linuxStatus.MigratedNodes)
+	assert.Equal(t, 1, linuxStatus.PendingNodes)
+
+	conflictStatus := statuses[types.NamespacedName{Namespace: testNamespace, Name: "linux-conflict"}]
+	assert.False(t, conflictStatus.Applied)
+	assert.NoError(t, conflictStatus.ValidationError)
+	assert.Equal(t, &types.NamespacedName{Namespace: testNamespace, Name: "linux"}, conflictStatus.ConflictingProfile)
+	assert.Equal(t, 2, conflictStatus.MatchedNodes)
+	assert.Equal(t, 0, conflictStatus.MigratedNodes)
+	assert.Equal(t, 0, conflictStatus.PendingNodes)
+
+	invalidStatus := statuses[types.NamespacedName{Namespace: testNamespace, Name: "windows"}]
+	assert.False(t, invalidStatus.Applied)
+	assert.Error(t, invalidStatus.ValidationError)
+	assert.Equal(t, 0, invalidStatus.MatchedNodes)
+}
+
 func TestOverrideFromProfile(t *testing.T) {
-	overrideNameForLinuxProfile := "datadog-agent-with-profile-default-linux"
-	overrideNameForExampleProfile := "datadog-agent-with-profile-default-example"
+	overrideNameForLinuxProfile := "datadog-agent-agent-with-profile-default-linux"
+	overrideNameForExampleProfile := "datadog-agent-agent-with-profile-default-example"
 
 	tests := []struct {
 		name             string
@@ -500,6 +793,11 @@ func TestOverrideFromProfile(t *testing.T) {
 											Operator: metav1.LabelSelectorOpIn,
 											Values:   []string{"agent"},
 										},
+										{
+											Key:      apicommon.AgentDeploymentNameLabelKey,
+											Operator: metav1.LabelSelectorOpIn,
+											Values:   []string{"datadog-agent"},
+										},
 									},
 								},
 								TopologyKey: v1.LabelHostname,
@@ -540,6 +838,11 @@ func TestOverrideFromProfile(t *testing.T) {
 											Operator: metav1.LabelSelectorOpIn,
 											Values:   []string{"agent"},
 										},
+										{
+											Key:      apicommon.AgentDeploymentNameLabelKey,
+											Operator: metav1.LabelSelectorOpIn,
+											Values:   []string{"datadog-agent"},
+										},
 									},
 								},
 								TopologyKey: v1.LabelHostname,
@@ -565,15 +868,105 @@ func TestOverrideFromProfile(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			assert.Equal(t, test.expectedOverride, OverrideFromProfile(&test.profile))
+			assert.Equal(t, test.expectedOverride, OverrideFromProfile(&test.profile, testDatadogAgent()))
 		})
 	}
 }
 
+func TestOverrideFromProfileWithPodSettings(t *testing.T) {
+	profile := exampleProfileForLinux()
+	nodeAgentOverride := profile.Spec.Config.Override[v1alpha1.NodeAgentComponentName]
+	nodeAgentOverride.Env = []v1.EnvVar{{Name: "DD_LOGS_CONFIG_RUN_PATH", Value: "/data/datadog"}}
+	nodeAgentOverride.Volumes = []v1.Volume{{Name: "data", VolumeSource: v1.VolumeSource{HostPath: &v1.HostPathVolumeSource{Path: "/data"}}}}
+	nodeAgentOverride.Tolerations = []v1.Toleration{{Key: "nvidia.com/gpu", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule}}
+	nodeAgentOverride.NodeSelector = map[string]string{"pool": "edge"}
+	nodeAgentOverride.Annotations = map[string]string{"foo": "bar"}
+	nodeAgentOverride.Containers[common.CoreAgentContainerName].Env = []v1.EnvVar{{Name: "DD_LOG_LEVEL", Value: "debug"}}
+	nodeAgentOverride.Containers[common.CoreAgentContainerName].VolumeMounts = []v1.VolumeMount{{Name: "data", MountPath: "/data"}}
+	nodeAgentOverride.Containers[common.CoreAgentContainerName].Args = []string{"run", "--verbose"}
+
+	override := OverrideFromProfile(&profile, testDatadogAgent())
+
+	assert.Equal(t, nodeAgentOverride.Env, override.Env)
+	assert.Equal(t, nodeAgentOverride.Volumes, override.Volumes)
+	assert.Equal(t, nodeAgentOverride.Tolerations, override.Tolerations)
+	assert.Equal(t, nodeAgentOverride.NodeSelector, override.NodeSelector)
+	assert.Equal(t, nodeAgentOverride.Annotations, override.Annotations)
+	assert.Equal(t, &v2alpha1.DatadogAgentGenericContainer{
+		Resources: &v1.ResourceRequirements{
+			Requests: v1.ResourceList{
+				v1.ResourceCPU: resource.MustParse("100m"),
+			},
+		},
+		Env:          []v1.EnvVar{{Name: "DD_LOG_LEVEL", Value: "debug"}},
+		VolumeMounts: []v1.VolumeMount{{Name: "data", MountPath: "/data"}},
+		Args:         []string{"run", "--verbose"},
+	}, override.Containers[common.CoreAgentContainerName])
+}
+
+func TestDisabledFeatures(t *testing.T) {
+	profile := exampleProfileForLinux()
+	assert.Empty(t, DisabledFeatures(&profile))
+
+	profile.Spec.Config.Override[v1alpha1.NodeAgentComponentName].Features = map[string]*v1alpha1.FeatureToggle{
+		"npm":            {Enabled: apiutils.NewBoolPointer(false)},
+		"cws":            {Enabled: apiutils.NewBoolPointer(false)},
+		"log_collection": {},
+	}
+	assert.Equal(t, []string{"cws", "npm"}, DisabledFeatures(&profile))
+
+	// The default profile doesn't have overrides
+	assert.Empty(t, DisabledFeatures(&v1alpha1.DatadogAgentProfile{ObjectMeta: metav1.ObjectMeta{Name: defaultProfileName}}))
+}
+
+func TestMergeConfigs(t *testing.T) {
+	newConfig := func(override *v1alpha1.Override) *v1alpha1.Config {
+		return &v1alpha1.Config{
+			Override: map[v1alpha1.ComponentName]*v1alpha1.Override{
+				v1alpha1.NodeAgentComponentName: override,
+			},
+		}
+	}
+	gpuConfig := newConfig(&v1alpha1.Override{
+		Env:         []v1.EnvVar{{Name: "NVIDIA_VISIBLE_DEVICES", Value: "all"}},
+		Tolerations: []v1.Toleration{{Key: "nvidia.com/gpu", Operator: v1.TolerationOpExists}},
+	})
+	edgeConfig := newConfig(&v1alpha1.Override{
+		Env:         []v1.EnvVar{{Name: "DD_LOGS_CONFIG_RUN_PATH", Value: "/data/datadog"}},
+		Tolerations: []v1.Toleration{{Key: "edge", Operator: v1.TolerationOpExists}},
+		Features:    map[string]*v1alpha1.FeatureToggle{"npm": {Enabled: apiutils.NewBoolPointer(false)}},
+	})
+
+	merged, ok := mergeConfigs(gpuConfig, edgeConfig)
+	require.True(t, ok)
+	mergedOverride := merged.Override[v1alpha1.NodeAgentComponentName]
+	assert.Equal(t, []v1.EnvVar{{Name: "NVIDIA_VISIBLE_DEVICES", Value: "all"}, {Name: "DD_LOGS_CONFIG_RUN_PATH", Value: "/data/datadog"}}, mergedOverride.Env)
+	assert.Len(t, mergedOverride.Tolerations, 2)
+	assert.Contains(t, mergedOverride.Features, "npm")
+	// The merged configs are not modified
+	assert.Len(t, gpuConfig.Override[v1alpha1.NodeAgentComponentName].Env, 1)
+
+	// Both configs set the same env var
+	_, ok = mergeConfigs(gpuConfig, newConfig(&v1alpha1.Override{
+		Env: []v1.EnvVar{{Name: "NVIDIA_VISIBLE_DEVICES", Value: "none"}},
+	}))
+	assert.False(t, ok)
+
+	// Both configs set the args of the same container
+	withArgs := newConfig(&v1alpha1.Override{
+		Containers: map[common.AgentContainerName]*v1alpha1.Container{
+			common.CoreAgentContainerName: {Args: []string{"run"}},
+		},
+	})
+	_, ok = mergeConfigs(withArgs, withArgs)
+	assert.False(t, ok)
+}
+
 func TestDaemonSetName(t *testing.T) {
 	tests := []struct {
 		name                  string
 		profileNamespacedName types.NamespacedName
+		ddaName               string
 		expectedDaemonSetName string
 	}{
 		{
@@ -582,6 +975,7 @@ func TestDaemonSetName(t *testing.T) {
 				Namespace: "",
 				Name:      "default",
 			},
+			ddaName:               "datadog",
 			expectedDaemonSetName: "",
 		},
 		{
@@ -590,17 +984,72 @@ func TestDaemonSetName(t *testing.T) {
 				Namespace: "agent",
 				Name:      "linux",
 			},
+			ddaName:               "datadog",
 			expectedDaemonSetName: "datadog-agent-with-profile-agent-linux",
 		},
+		{
+			name: "non-default profile name of another DatadogAgent",
+			profileNamespacedName: types.NamespacedName{
+				Namespace: "agent",
+				Name:      "linux",
+			},
+			ddaName:               "other-dda",
+			expectedDaemonSetName: "other-dda-agent-with-profile-agent-linux",
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			assert.Equal(t, test.expectedDaemonSetName, DaemonSetName(test.profileNamespacedName))
+			assert.Equal(t, test.expectedDaemonSetName, DaemonSetName(test.profileNamespacedName, test.ddaName))
 		})
 	}
 }
 
+func TestNodeLabelValue(t *testing.T) {
+	longNamespace := strings.Repeat("n", validation.DNS1123LabelMaxLength)
+	longName := strings.Repeat("p", validation.DNS1123SubdomainMaxLength)
+	longProfile := types.NamespacedName{Namespace: longNamespace, Name: longName}
+	otherLongProfile := types.NamespacedName{Namespace: longNamespace, Name: longName[:len(longName)-1] + "q"}
+	mergedProfile := mergedProfileNamespacedName([]types.NamespacedName{longProfile, otherLongProfile})
+	otherMergedProfile := mergedProfileNamespacedName([]types.NamespacedName{otherLongProfile, longProfile})
+
+	assert.Equal(t, "", NodeLabelValue(types.NamespacedName{Name: "default"}))
+	assert.Equal(t, "agent-linux", NodeLabelValue(types.NamespacedName{Namespace: "agent", Name: "linux"}))
+	assert.Equal(t, "agent-linux-merged-0123abcd", NodeLabelValue(types.NamespacedName{Namespace: "agent", Name: "linux-merged-0123abcd"}))
+
+	values := map[string]struct{}{}
+	for _, profile := range []types.NamespacedName{longProfile, otherLongProfile, mergedProfile, otherMergedProfile} {
+		value := NodeLabelValue(profile)
+		assert.Empty(t, validation.IsValidLabelValue(value), "invalid label value for %s", profile)
+		assert.Equal(t, value, NodeLabelValue(profile))
+		values[value] = struct{}{}
+	}
+	assert.Len(t, values, 4, "the label values of the profiles must be unique")
+
+	// The label of a merged profile is identified by the profile with precedence
+	assert.True(t, strings.HasPrefix(NodeLabelValue(mergedProfile), mergedLabelPrefix(longProfile)))
+	assert.False(t, strings.HasPrefix(NodeLabelValue(mergedProfile), mergedLabelPrefix(otherLongProfile)))
+	assert.True(t, strings.HasPrefix(NodeLabelValue(otherMergedProfile), mergedLabelPrefix(otherLongProfile)))
+}
+
+func TestDatadogAgentProfileLabelKey(t *testing.T) {
+	longNamespace := strings.Repeat("n", validation.DNS1123LabelMaxLength)
+	longName := strings.Repeat("d", validation.DNS1123SubdomainMaxLength)
+
+	assert.Equal(t, "agent.datadoghq.com/profile.agent-datadog", DatadogAgentProfileLabelKey(&metav1.ObjectMeta{Namespace: "agent", Name: "datadog"}))
+
+	keys := map[string]struct{}{}
+	for _, dda := range []*metav1.ObjectMeta{
+		{Namespace: longNamespace, Name: longName},
+		{Namespace: longNamespace, Name: longName[:len(longName)-1] + "e"},
+	} {
+		key := DatadogAgentProfileLabelKey(dda)
+		assert.Empty(t, validation.IsQualifiedName(key), "invalid label key for %s/%s", dda.Namespace, dda.Name)
+		keys[key] = struct{}{}
+	}
+	assert.Len(t, keys, 2, "the label keys of the DatadogAgents must be unique")
+}
+
 func TestPriorityClassNameOverride(t *testing.T) {
 	tests := []struct {
 		name                  string
@@ -741,3 +1190,16 @@ func configWithCPURequestOverrideForCoreAgent(cpuRequest string) *v1alpha1.Confi
 		},
 	}
 }
+
+// profilesNamed returns profiles of the test namespace with the given names.
+func profilesNamed(names ...string) []v1alpha1.DatadogAgentProfile {
+	var profiles []v1alpha1.DatadogAgentProfile
+	for _, name := range names {
+		profiles = append(profiles, v1alpha1.DatadogAgentProfile{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name}})
+	}
+	return profiles
+}
+
+func testDatadogAgent() *metav1.ObjectMeta {
+	return &metav1.ObjectMeta{Namespace: testNamespace, Name: "datadog-agent"}
+}
@@ -0,0 +1,129 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package agentprofile
+
+import (
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+// ProfilesToApplyNow returns, among the profiles that should be applied on each
+// node, the ones that can be applied during this reconcile according to the
+// rollout policies of the profiles.
+// A node moves from one profile to another when its profile label changes.
+// The move is limited by the rollout policy of the profile that the node moves
+// to or, when it doesn't have one, by the rollout policy of the profile that
+// the node leaves. The number of nodes of the destination profile whose Agent
+// pod is unavailable can't exceed the maxUnavailable of the policy. The nodes
+// that can't move yet keep their current profile label.
+// It also returns the number of nodes that are waiting to move.
+func ProfilesToApplyNow(profiles []datadoghqv1alpha1.DatadogAgentProfile, profilesByNode map[string]types.NamespacedName, nodes []v1.Node, agentPods []v1.Pod, now time.Time) (map[string]types.NamespacedName, int) {
+	rolloutPolicies := make(map[string]*datadoghqv1alpha1.ProfileRolloutPolicy, len(profiles))
+	for _, profile := range profiles {
+		if profile.Spec.RolloutPolicy != nil {
+			rolloutPolicies[NodeLabelValue(types.NamespacedName{Namespace: profile.Namespace, Name: profile.Name})] = profile.Spec.RolloutPolicy
+		}
+	}
+
+	agentPodsByNode := make(map[string][]v1.Pod, len(nodes))
+	for _, pod := range agentPods {
+		agentPodsByNode[pod.Spec.NodeName] = append(agentPodsByNode[pod.Spec.NodeName], pod)
+	}
+
+	res := make(map[string]types.NamespacedName, len(profilesByNode))
+	nodesPerLabel := map[string]int{}
+	unavailableNodesPerLabel := map[string]int{}
+	var pendingNodes []v1.Node
+
+	for _, node := range nodes {
+		profile, found := profilesByNode[node.Name]
+		if !found {
+			continue
+		}
+		label := NodeLabelValue(profile)
+		nodesPerLabel[label]++
+
+		if node.Labels[ProfileLabelKey] != label {
+			pendingNodes = append(pendingNodes, node)
+			continue
+		}
+
+		res[node.Name] = profile
+		var minReadySeconds int32
+		if policy := rolloutPolicies[label]; policy != nil {
+			minReadySeconds = policy.MinReadySeconds
+		}
+		if !hasAvailableAgentPod(agentPodsByNode[node.Name], label, minReadySeconds, now) {
+			unavailableNodesPerLabel[label]++
+		}
+	}
+
+	// Move the nodes always in the same order, so that the nodes that are
+	// moving keep moving in the next reconciles
+	sort.Slice(pendingNodes, func(i, j int) bool {
+		return pendingNodes[i].Name < pendingNodes[j].Name
+	})
+
+	waitingNodes := 0
+	for _, node := range pendingNodes {
+		profile := profilesByNode[node.Name]
+		label := NodeLabelValue(profile)
+
+		policy := rolloutPolicies[label]
+		if policy == nil {
+			policy = rolloutPolicies[node.Labels[ProfileLabelKey]]
+		}
+		if policy != nil && unavailableNodesPerLabel[label] >= maxUnavailable(policy, nodesPerLabel[label]) {
+			waitingNodes++
+			continue
+		}
+
+		// The Agent pod of the node is replaced, the node is unavailable until the new one is ready
+		unavailableNodesPerLabel[label]++
+		res[node.Name] = profile
+	}
+
+	return res, waitingNodes
+}
+
+// maxUnavailable returns the number of nodes that can be unavailable according
+// to the rollout policy. At least one node can be unavailable, so that the
+// nodes can always move.
+func maxUnavailable(policy *datadoghqv1alpha1.ProfileRolloutPolicy, nodes int) int {
+	if policy.MaxUnavailable == nil {
+		return 1
+	}
+	value, err := intstr.GetScaledValueFromIntOrPercent(policy.MaxUnavailable, nodes, true)
+	if err != nil || value < 1 {
+		return 1
+	}
+	return value
+}
+
+// hasAvailableAgentPod returns true if one of the Agent pods of a node has the
+// given profile label value and has been ready for at least minReadySeconds.
+func hasAvailableAgentPod(agentPods []v1.Pod, label string, minReadySeconds int32, now time.Time) bool {
+	for _, pod := range agentPods {
+		if pod.Labels[ProfileLabelKey] != label || pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type != v1.PodReady || condition.Status != v1.ConditionTrue {
+				continue
+			}
+			if minReadySeconds == 0 || condition.LastTransitionTime.Add(time.Duration(minReadySeconds)*time.Second).Before(now) {
+				return true
+			}
+		}
+	}
+	return false
+}
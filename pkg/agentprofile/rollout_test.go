@@ -0,0 +1,202 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package agentprofile
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+func TestProfilesToApplyNow(t *testing.T) {
+	now := time.Now()
+	linux := types.NamespacedName{Namespace: testNamespace, Name: "linux"}
+	defaultNamespacedName := types.NamespacedName{Name: defaultProfileName}
+
+	node := func(name string, profileLabel string) v1.Node {
+		node := v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"os": "linux"}}}
+		if profileLabel != "" {
+			node.Labels[ProfileLabelKey] = profileLabel
+		}
+		return node
+	}
+	agentPod := func(nodeName string, profileLabel string, readySince time.Time) v1.Pod {
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent-" + nodeName, Labels: map[string]string{}},
+			Spec:       v1.PodSpec{NodeName: nodeName},
+			Status: v1.PodStatus{
+				Conditions: []v1.PodCondition{
+					{Type: v1.PodReady, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(readySince)},
+				},
+			},
+		}
+		if profileLabel != "" {
+			pod.Labels[ProfileLabelKey] = profileLabel
+		}
+		return pod
+	}
+	profileWithPolicy := func(policy *v1alpha1.ProfileRolloutPolicy) []v1alpha1.DatadogAgentProfile {
+		profile := exampleProfileForLinux()
+		profile.Spec.RolloutPolicy = policy
+		return []v1alpha1.DatadogAgentProfile{profile, defaultProfile()}
+	}
+	nodeNames := func(profilesByNode map[string]types.NamespacedName) []string {
+		var names []string
+		for name := range profilesByNode {
+			names = append(names, name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name             string
+		profiles         []v1alpha1.DatadogAgentProfile
+		nodes            []v1.Node
+		agentPods        []v1.Pod
+		profilesByNode   map[string]types.NamespacedName
+		wantNodes        []string
+		wantWaitingNodes int
+	}{
+		{
+			name:     "no rollout policy, all the nodes move at once",
+			profiles: profileWithPolicy(nil),
+			nodes:    []v1.Node{node("node1", ""), node("node2", ""), node("node3", "")},
+			profilesByNode: map[string]types.NamespacedName{
+				"node1": linux,
+				"node2": linux,
+				"node3": linux,
+			},
+			wantNodes: []string{"node1", "node2", "node3"},
+		},
+		{
+			name:     "default max unavailable, one node moves at a time",
+			profiles: profileWithPolicy(&v1alpha1.ProfileRolloutPolicy{}),
+			nodes:    []v1.Node{node("node1", ""), node("node2", ""), node("node3", "")},
+			profilesByNode: map[string]types.NamespacedName{
+				"node1": linux,
+				"node2": linux,
+				"node3": linux,
+			},
+			wantNodes:        []string{"node1"},
+			wantWaitingNodes: 2,
+		},
+		{
+			name: "percentage of max unavailable",
+			profiles: profileWithPolicy(&v1alpha1.ProfileRolloutPolicy{
+				MaxUnavailable: &intstr.IntOrString{Type: intstr.String, StrVal: "50%"},
+			}),
+			nodes: []v1.Node{node("node1", ""), node("node2", ""), node("node3", "")},
+			profilesByNode: map[string]types.NamespacedName{
+				"node1": linux,
+				"node2": linux,
+				"node3": linux,
+			},
+			wantNodes:        []string{"node1", "node2"},
+			wantWaitingNodes: 1,
+		},
+		{
+			name:     "moved node not ready yet, the next nodes wait",
+			profiles: profileWithPolicy(&v1alpha1.ProfileRolloutPolicy{}),
+			nodes:    []v1.Node{node("node1", "default-linux"), node("node2", ""), node("node3", "")},
+			agentPods: []v1.Pod{
+				agentPod("node2", "", now.Add(-time.Hour)),
+				agentPod("node3", "", now.Add(-time.Hour)),
+			},
+			profilesByNode: map[string]types.NamespacedName{
+				"node1": linux,
+				"node2": linux,
+				"node3": linux,
+			},
+			wantNodes:        []string{"node1"},
+			wantWaitingNodes: 2,
+		},
+		{
+			name:     "moved node ready for less than min ready seconds, the next nodes wait",
+			profiles: profileWithPolicy(&v1alpha1.ProfileRolloutPolicy{MinReadySeconds: 60}),
+			nodes:    []v1.Node{node("node1", "default-linux"), node("node2", ""), node("node3", "")},
+			agentPods: []v1.Pod{
+				agentPod("node1", "default-linux", now.Add(-30*time.Second)),
+			},
+			profilesByNode: map[string]types.NamespacedName{
+				"node1": linux,
+				"node2": linux,
+				"node3": linux,
+			},
+			wantNodes:        []string{"node1"},
+			wantWaitingNodes: 2,
+		},
+		{
+			name:     "moved node available, the next node moves",
+			profiles: profileWithPolicy(&v1alpha1.ProfileRolloutPolicy{MinReadySeconds: 60}),
+			nodes:    []v1.Node{node("node1", "default-linux"), node("node2", ""), node("node3", "")},
+			agentPods: []v1.Pod{
+				agentPod("node1", "default-linux", now.Add(-2*time.Minute)),
+			},
+			profilesByNode: map[string]types.NamespacedName{
+				"node1": linux,
+				"node2": linux,
+				"node3": linux,
+			},
+			wantNodes:        []string{"node1", "node2"},
+			wantWaitingNodes: 1,
+		},
+		{
+			name:     "nodes leaving a profile with a rollout policy",
+			profiles: profileWithPolicy(&v1alpha1.ProfileRolloutPolicy{}),
+			nodes:    []v1.Node{node("node1", "default-linux"), node("node2", "default-linux")},
+			agentPods: []v1.Pod{
+				agentPod("node1", "default-linux", now.Add(-time.Hour)),
+				agentPod("node2", "default-linux", now.Add(-time.Hour)),
+			},
+			profilesByNode: map[string]types.NamespacedName{
+				"node1": defaultNamespacedName,
+				"node2": defaultNamespacedName,
+			},
+			wantNodes:        []string{"node1"},
+			wantWaitingNodes: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			profilesToApplyNow, waitingNodes := ProfilesToApplyNow(test.profiles, test.profilesByNode, test.nodes, test.agentPods, now)
+			assert.ElementsMatch(t, test.wantNodes, nodeNames(profilesToApplyNow))
+			assert.Equal(t, test.wantWaitingNodes, waitingNodes)
+			for name, profile := range profilesToApplyNow {
+				assert.Equal(t, test.profilesByNode[name], profile, fmt.Sprintf("profile of %s", name))
+			}
+		})
+	}
+}
+
+func TestAffinityOverrideWithRolloutPolicy(t *testing.T) {
+	profile := exampleProfileForLinux()
+	profile.Spec.RolloutPolicy = &v1alpha1.ProfileRolloutPolicy{}
+
+	affinity := affinityOverride(&profile, testDatadogAgent())
+
+	// The Agent pods only run on the nodes with the profile label
+	assert.Equal(t, []v1.NodeSelectorTerm{
+		{
+			MatchExpressions: []v1.NodeSelectorRequirement{
+				{
+					Key:      ProfileLabelKey,
+					Operator: v1.NodeSelectorOpIn,
+					Values:   []string{"default-linux"},
+				},
+			},
+		},
+	}, affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+	assert.Equal(t, podAntiAffinityOverride("datadog-agent"), affinity.PodAntiAffinity)
+}
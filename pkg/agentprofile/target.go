@@ -0,0 +1,133 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package agentprofile
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+// ProfileAppliesToDatadogAgent returns true if the profile applies to the
+// given DatadogAgent, according to its datadogAgentRef or its
+// datadogAgentSelector. A profile without any of them applies to all the
+// DatadogAgents.
+func ProfileAppliesToDatadogAgent(profile *datadoghqv1alpha1.DatadogAgentProfile, dda metav1.Object) bool {
+	if ref := profile.Spec.DatadogAgentRef; ref != nil {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = profile.Namespace
+		}
+		return ref.Name == dda.GetName() && namespace == dda.GetNamespace()
+	}
+
+	if profile.Spec.DatadogAgentSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(profile.Spec.DatadogAgentSelector)
+		if err != nil {
+			// The profile is invalid, it doesn't apply to any DatadogAgent
+			return false
+		}
+		return selector.Matches(labels.Set(dda.GetLabels()))
+	}
+
+	return true
+}
+
+// ProfilesForDatadogAgent returns the profiles that apply to the given
+// DatadogAgent.
+func ProfilesForDatadogAgent(profiles []datadoghqv1alpha1.DatadogAgentProfile, dda metav1.Object) []datadoghqv1alpha1.DatadogAgentProfile {
+	var res []datadoghqv1alpha1.DatadogAgentProfile
+	for i := range profiles {
+		if ProfileAppliesToDatadogAgent(&profiles[i], dda) {
+			res = append(res, profiles[i])
+		}
+	}
+	return res
+}
+
+// NodesWithProfileOfOtherDatadogAgents returns the names of the nodes whose
+// profile label was set for a profile, or a merged profile, that doesn't apply
+// to the given DatadogAgent. The profile labels of these nodes are managed by
+// the reconcile of the other DatadogAgents.
+func NodesWithProfileOfOtherDatadogAgents(nodes []v1.Node, profiles []datadoghqv1alpha1.DatadogAgentProfile, dda metav1.Object) map[string]bool {
+	var otherProfiles []types.NamespacedName
+	for i := range profiles {
+		if !ProfileAppliesToDatadogAgent(&profiles[i], dda) {
+			otherProfiles = append(otherProfiles, types.NamespacedName{Namespace: profiles[i].Namespace, Name: profiles[i].Name})
+		}
+	}
+
+	res := map[string]bool{}
+	for _, node := range nodes {
+		label, found := node.Labels[ProfileLabelKey]
+		if !found {
+			continue
+		}
+		for _, otherProfile := range otherProfiles {
+			// The label of a merged profile starts with the prefix of the profile with precedence
+			if label == NodeLabelValue(otherProfile) || strings.HasPrefix(label, mergedLabelPrefix(otherProfile)) {
+				res[node.Name] = true
+				break
+			}
+		}
+	}
+	return res
+}
+
+// ProfilesStatusForDatadogAgents returns the status of each of the given
+// profiles. The profiles are evaluated separately for each DatadogAgent, with
+// the other profiles that apply to the same DatadogAgent, since only they can
+// conflict. When a profile applies to several DatadogAgents, the status of the
+// evaluation that reports a conflict is kept, and the status lists the
+// DaemonSets of the profile in each of them. The profiles that don't apply to
+// any of the DatadogAgents are not applied. Without DatadogAgents, all the
+// profiles are evaluated together.
+func ProfilesStatusForDatadogAgents(profiles []datadoghqv1alpha1.DatadogAgentProfile, ddas []metav1.Object, nodes []v1.Node, logger logr.Logger) (map[types.NamespacedName]ProfileStatus, error) {
+	if len(ddas) == 0 {
+		return ProfilesStatus(profiles, nodes, logger)
+	}
+
+	statuses := make(map[types.NamespacedName]ProfileStatus, len(profiles))
+	for _, dda := range ddas {
+		ddaStatuses, err := ProfilesStatus(ProfilesForDatadogAgent(profiles, dda), nodes, logger)
+		if err != nil {
+			return nil, err
+		}
+		for profile, status := range ddaStatuses {
+			previous, found := statuses[profile]
+			daemonSetNames := previous.DaemonSetNames
+			if status.Applied {
+				daemonSetNames = append(daemonSetNames, DaemonSetName(profile, dda.GetName()))
+			}
+			if !found || (previous.ConflictingProfile == nil && status.ConflictingProfile != nil) {
+				previous = status
+			}
+			previous.DaemonSetNames = daemonSetNames
+			statuses[profile] = previous
+		}
+	}
+
+	for _, profile := range profiles {
+		profileNamespacedName := types.NamespacedName{Namespace: profile.Namespace, Name: profile.Name}
+		if status, found := statuses[profileNamespacedName]; found {
+			sort.Strings(status.DaemonSetNames)
+		} else {
+			statuses[profileNamespacedName] = ProfileStatus{
+				ValidationError: datadoghqv1alpha1.ValidateDatadogAgentProfileSpec(&profile.Spec),
+				NoDatadogAgent:  true,
+			}
+		}
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,174 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package agentprofile
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+func TestProfileAppliesToDatadogAgent(t *testing.T) {
+	dda := &metav1.ObjectMeta{Namespace: testNamespace, Name: "datadog", Labels: map[string]string{"team": "infra"}}
+
+	tests := []struct {
+		name     string
+		ref      *v1alpha1.DatadogAgentReference
+		selector *metav1.LabelSelector
+		want     bool
+	}{
+		{
+			name: "no target, applies to all the DatadogAgents",
+			want: true,
+		},
+		{
+			name: "ref in the namespace of the profile",
+			ref:  &v1alpha1.DatadogAgentReference{Name: "datadog"},
+			want: true,
+		},
+		{
+			name: "ref with another name",
+			ref:  &v1alpha1.DatadogAgentReference{Name: "other"},
+			want: false,
+		},
+		{
+			name: "ref in another namespace",
+			ref:  &v1alpha1.DatadogAgentReference{Name: "datadog", Namespace: "other"},
+			want: false,
+		},
+		{
+			name:     "matching selector",
+			selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "infra"}},
+			want:     true,
+		},
+		{
+			name:     "selector that doesn't match",
+			selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "apm"}},
+			want:     false,
+		},
+		{
+			name: "invalid selector",
+			selector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Equals"}},
+			},
+			want: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			profile := exampleProfileForLinux()
+			profile.Spec.DatadogAgentRef = test.ref
+			profile.Spec.DatadogAgentSelector = test.selector
+			assert.Equal(t, test.want, ProfileAppliesToDatadogAgent(&profile, dda))
+		})
+	}
+}
+
+func TestNodesWithProfileOfOtherDatadogAgents(t *testing.T) {
+	dda := &metav1.ObjectMeta{Namespace: testNamespace, Name: "datadog"}
+
+	ddaProfile := exampleProfileForLinux()
+	otherProfile := exampleProfileForWindows()
+	otherProfile.Spec.DatadogAgentRef = &v1alpha1.DatadogAgentReference{Name: "other"}
+	otherMergedProfile := mergedProfileNamespacedName([]types.NamespacedName{
+		{Namespace: otherProfile.Namespace, Name: otherProfile.Name},
+		{Namespace: ddaProfile.Namespace, Name: ddaProfile.Name},
+	})
+
+	node := func(name string, profileLabel string) v1.Node {
+		node := v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}}}
+		if profileLabel != "" {
+			node.Labels[ProfileLabelKey] = profileLabel
+		}
+		return node
+	}
+	nodes := []v1.Node{
+		node("no-label", ""),
+		node("dda-profile", "default-linux"),
+		node("other-profile", "default-windows"),
+		node("other-merged-profile", NodeLabelValue(otherMergedProfile)),
+		node("deleted-profile", "default-deleted"),
+	}
+
+	got := NodesWithProfileOfOtherDatadogAgents(nodes, []v1alpha1.DatadogAgentProfile{ddaProfile, otherProfile}, dda)
+	assert.Equal(t, map[string]bool{"other-profile": true, "other-merged-profile": true}, got)
+
+	// The label values of the profiles with long names are shortened
+	longProfile := exampleProfileForWindows()
+	longProfile.Name = strings.Repeat("a", 100)
+	longProfile.Spec.DatadogAgentRef = &v1alpha1.DatadogAgentReference{Name: "other"}
+	longMergedProfile := mergedProfileNamespacedName([]types.NamespacedName{
+		{Namespace: longProfile.Namespace, Name: longProfile.Name},
+		{Namespace: ddaProfile.Namespace, Name: ddaProfile.Name},
+	})
+	nodes = []v1.Node{
+		node("dda-profile", "default-linux"),
+		node("long-profile", NodeLabelValue(types.NamespacedName{Namespace: longProfile.Namespace, Name: longProfile.Name})),
+		node("long-merged-profile", NodeLabelValue(longMergedProfile)),
+	}
+
+	got = NodesWithProfileOfOtherDatadogAgents(nodes, []v1alpha1.DatadogAgentProfile{ddaProfile, longProfile}, dda)
+	assert.Equal(t, map[string]bool{"long-profile": true, "long-merged-profile": true}, got)
+}
+
+func TestProfilesStatusForDatadogAgents(t *testing.T) {
+	logger := ctrl.Log.WithName(t.Name())
+	now := time.Now()
+	ddaA := &metav1.ObjectMeta{Namespace: testNamespace, Name: "agent-a"}
+	ddaB := &metav1.ObjectMeta{Namespace: testNamespace, Name: "agent-b"}
+
+	// Both profiles match the linux nodes, but apply to different DatadogAgents
+	profileA := exampleProfileForLinux()
+	profileA.Name = "linux-a"
+	profileA.CreationTimestamp = metav1.NewTime(now)
+	profileA.Spec.DatadogAgentRef = &v1alpha1.DatadogAgentReference{Name: "agent-a"}
+	profileB := exampleProfileForLinux()
+	profileB.Name = "linux-b"
+	profileB.CreationTimestamp = metav1.NewTime(now.Add(time.Minute))
+	profileB.Spec.DatadogAgentRef = &v1alpha1.DatadogAgentReference{Name: "agent-b"}
+	// This profile applies to both DatadogAgents and conflicts with profileA
+	profileAll := exampleProfileForLinux()
+	profileAll.Name = "linux-all"
+	profileAll.CreationTimestamp = metav1.NewTime(now.Add(2 * time.Minute))
+	profiles := []v1alpha1.DatadogAgentProfile{profileA, profileB, profileAll}
+
+	nodes := []v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"os": "linux"}}},
+	}
+
+	statuses, err := ProfilesStatusForDatadogAgents(profiles, []metav1.Object{ddaA, ddaB}, nodes, logger)
+	require.NoError(t, err)
+	assert.True(t, statuses[types.NamespacedName{Namespace: testNamespace, Name: "linux-a"}].Applied)
+	assert.True(t, statuses[types.NamespacedName{Namespace: testNamespace, Name: "linux-b"}].Applied)
+	allStatus := statuses[types.NamespacedName{Namespace: testNamespace, Name: "linux-all"}]
+	assert.False(t, allStatus.Applied)
+	require.NotNil(t, allStatus.ConflictingProfile)
+	assert.Equal(t, "linux-a", allStatus.ConflictingProfile.Name)
+
+	// Without agent-b, profileB doesn't apply to any DatadogAgent
+	statuses, err = ProfilesStatusForDatadogAgents(profiles, []metav1.Object{ddaA}, nodes, logger)
+	require.NoError(t, err)
+	bStatus := statuses[types.NamespacedName{Namespace: testNamespace, Name: "linux-b"}]
+	assert.False(t, bStatus.Applied)
+	assert.True(t, bStatus.NoDatadogAgent)
+	assert.NoError(t, bStatus.ValidationError)
+
+	// Without DatadogAgents, all the profiles are evaluated together
+	statuses, err = ProfilesStatusForDatadogAgents(profiles, nil, nodes, logger)
+	require.NoError(t, err)
+	assert.True(t, statuses[types.NamespacedName{Namespace: testNamespace, Name: "linux-a"}].Applied)
+	assert.NotNil(t, statuses[types.NamespacedName{Namespace: testNamespace, Name: "linux-b"}].ConflictingProfile)
+}
@@ -17,10 +17,12 @@ import (
 	"k8s.io/client-go/util/retry"
 )
 
-// Creds holds the api and app keys.
+// Creds holds the api and app keys, and optionally the Datadog site.
 type Creds struct {
 	APIKey string
 	AppKey string
+	// Site overrides the site of the operator configuration when set.
+	Site string
 }
 
 // CredentialManager provides the credentials from the operator configuration.
@@ -25,6 +25,12 @@ const (
 	UpdateEvent EventType = "Update"
 	// DeletionEvent should be used for resource deletion events
 	DeletionEvent EventType = "Delete"
+	// AdoptionEvent should be used for the adoption of existing resources
+	AdoptionEvent EventType = "Adopt"
+	// DriftEvent should be used for resources modified outside Kubernetes
+	DriftEvent EventType = "Drift"
+	// OrphanEvent should be used for resources kept in Datadog when their custom resource is deleted
+	OrphanEvent EventType = "Orphan"
 )
 
 // crDetected returns the detection event of a CR
@@ -0,0 +1,115 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+
+	datadogapi "github.com/DataDog/datadog-api-client-go/v2/api/datadog"
+	datadogV1 "github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
+
+	"github.com/DataDog/datadog-operator/pkg/config"
+)
+
+// ClientCache caches the Datadog API clients of the credentials stored in Secrets, with one client per credential set.
+// It is shared by the controllers, so that the resources using the same credentials share the same client.
+type ClientCache struct {
+	mutex   sync.Mutex
+	clients map[config.Creds]*cachedClient
+	secrets map[types.NamespacedName]config.Creds
+}
+
+type cachedClient struct {
+	client *datadogapi.APIClient
+	auth   context.Context
+}
+
+// NewClientCache returns an empty ClientCache.
+func NewClientCache() *ClientCache {
+	return &ClientCache{
+		clients: map[config.Creds]*cachedClient{},
+		secrets: map[types.NamespacedName]config.Creds{},
+	}
+}
+
+// MonitorClient returns the Datadog Monitor API Client of the credentials of a Secret.
+func (c *ClientCache) MonitorClient(logger logr.Logger, secret types.NamespacedName, creds config.Creds) (DatadogMonitorClient, error) {
+	cached, err := c.get(logger, secret, creds)
+	if err != nil {
+		return DatadogMonitorClient{}, err
+	}
+
+	return DatadogMonitorClient{Client: datadogV1.NewMonitorsApi(cached.client), Auth: cached.auth}, nil
+}
+
+// SLOClient returns the Datadog SLO API Client of the credentials of a Secret.
+func (c *ClientCache) SLOClient(logger logr.Logger, secret types.NamespacedName, creds config.Creds) (DatadogSLOClient, error) {
+	cached, err := c.get(logger, secret, creds)
+	if err != nil {
+		return DatadogSLOClient{}, err
+	}
+
+	return DatadogSLOClient{Client: datadogV1.NewServiceLevelObjectivesApi(cached.client), Auth: cached.auth}, nil
+}
+
+// Remove forgets the credentials of a deleted Secret, and drops their client if no other Secret holds them.
+func (c *ClientCache) Remove(secret types.NamespacedName) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	creds, found := c.secrets[secret]
+	if !found {
+		return
+	}
+	delete(c.secrets, secret)
+	c.release(creds)
+}
+
+func (c *ClientCache) get(logger logr.Logger, secret types.NamespacedName, creds config.Creds) (*cachedClient, error) {
+	if creds.APIKey == "" || creds.AppKey == "" {
+		return nil, errors.New("error obtaining API key and/or app key")
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// The credentials of the Secret were rotated
+	if previous, found := c.secrets[secret]; found && previous != creds {
+		c.secrets[secret] = creds
+		c.release(previous)
+	}
+
+	cached, found := c.clients[creds]
+	if !found {
+		auth, err := setupAuth(logger, creds)
+		if err != nil {
+			return nil, err
+		}
+		cached = &cachedClient{
+			client: newAPIClient(creds),
+			auth:   auth,
+		}
+		c.clients[creds] = cached
+	}
+	c.secrets[secret] = creds
+
+	return cached, nil
+}
+
+// release drops the client of credentials that no Secret holds anymore.
+func (c *ClientCache) release(creds config.Creds) {
+	for _, other := range c.secrets {
+		if other == creds {
+			return
+		}
+	}
+	delete(c.clients, creds)
+}
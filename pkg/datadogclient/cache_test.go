@@ -0,0 +1,60 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	datadogapi "github.com/DataDog/datadog-api-client-go/v2/api/datadog"
+
+	"github.com/DataDog/datadog-operator/pkg/config"
+)
+
+func TestClientCache(t *testing.T) {
+	logger := logf.Log.WithName("TestClientCache")
+	teamA := types.NamespacedName{Namespace: "team-a", Name: "datadog-credentials"}
+	teamB := types.NamespacedName{Namespace: "team-b", Name: "datadog-credentials"}
+	teamC := types.NamespacedName{Namespace: "team-c", Name: "datadog-credentials"}
+	orgCreds := config.Creds{APIKey: "api-key", AppKey: "app-key"}
+	euCreds := config.Creds{APIKey: "eu-api-key", AppKey: "eu-app-key", Site: "datadoghq.eu"}
+
+	cache := NewClientCache()
+
+	// Secrets holding the same credentials share a client
+	monitorClientA, err := cache.MonitorClient(logger, teamA, orgCreds)
+	require.NoError(t, err)
+	sloClientB, err := cache.SLOClient(logger, teamB, orgCreds)
+	require.NoError(t, err)
+	assert.Equal(t, monitorClientA.Auth, sloClientB.Auth)
+	assert.Len(t, cache.clients, 1)
+
+	// The site of the credentials selects the API URL
+	monitorClientC, err := cache.MonitorClient(logger, teamC, euCreds)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"name": "api.datadoghq.eu", "protocol": "https"}, monitorClientC.Auth.Value(datadogapi.ContextServerVariables))
+	assert.Len(t, cache.clients, 2)
+
+	// The client is kept while a Secret holds the credentials
+	cache.Remove(teamA)
+	assert.Len(t, cache.clients, 2)
+	cache.Remove(teamB)
+	assert.Len(t, cache.clients, 1)
+
+	// The client of rotated credentials is dropped
+	_, err = cache.MonitorClient(logger, teamC, orgCreds)
+	require.NoError(t, err)
+	assert.Len(t, cache.clients, 1)
+	assert.Contains(t, cache.clients, orgCreds)
+
+	// Incomplete credentials
+	_, err = cache.MonitorClient(logger, teamA, config.Creds{APIKey: "api-key"})
+	assert.EqualError(t, err, "error obtaining API key and/or app key")
+}
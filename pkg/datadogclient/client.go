@@ -19,6 +19,7 @@ import (
 
 	datadogapi "github.com/DataDog/datadog-api-client-go/v2/api/datadog"
 	datadogV1 "github.com/DataDog/datadog-api-client-go/v2/api/datadogV1"
+	datadogV2 "github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
 	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
 )
 
@@ -36,9 +37,7 @@ func InitDatadogMonitorClient(logger logr.Logger, creds config.Creds) (DatadogMo
 		return DatadogMonitorClient{}, errors.New("error obtaining API key and/or app key")
 	}
 
-	configV1 := datadogapi.NewConfiguration()
-	apiClient := datadogapi.NewAPIClient(configV1)
-	client := datadogV1.NewMonitorsApi(apiClient)
+	client := datadogV1.NewMonitorsApi(newAPIClient(creds))
 
 	authV1, err := setupAuth(logger, creds)
 	if err != nil {
@@ -60,9 +59,7 @@ func InitDatadogSLOClient(logger logr.Logger, creds config.Creds) (DatadogSLOCli
 		return DatadogSLOClient{}, errors.New("error obtaining API key and/or app key")
 	}
 
-	configV1 := datadogapi.NewConfiguration()
-	apiClient := datadogapi.NewAPIClient(configV1)
-	client := datadogV1.NewServiceLevelObjectivesApi(apiClient)
+	client := datadogV1.NewServiceLevelObjectivesApi(newAPIClient(creds))
 
 	authV1, err := setupAuth(logger, creds)
 	if err != nil {
@@ -72,6 +69,28 @@ func InitDatadogSLOClient(logger logr.Logger, creds config.Creds) (DatadogSLOCli
 	return DatadogSLOClient{Client: client, Auth: authV1}, nil
 }
 
+// DatadogDowntimeClient contains the Datadog Downtime API Client and Authentication context.
+type DatadogDowntimeClient struct {
+	Client *datadogV2.DowntimesApi
+	Auth   context.Context
+}
+
+// InitDatadogDowntimeClient initializes the Datadog Downtime API Client and establishes credentials.
+func InitDatadogDowntimeClient(logger logr.Logger, creds config.Creds) (DatadogDowntimeClient, error) {
+	if creds.APIKey == "" || creds.AppKey == "" {
+		return DatadogDowntimeClient{}, errors.New("error obtaining API key and/or app key")
+	}
+
+	client := datadogV2.NewDowntimesApi(newAPIClient(creds))
+
+	authV1, err := setupAuth(logger, creds)
+	if err != nil {
+		return DatadogDowntimeClient{}, err
+	}
+
+	return DatadogDowntimeClient{Client: client, Auth: authV1}, nil
+}
+
 func setupAuth(logger logr.Logger, creds config.Creds) (context.Context, error) {
 	// Initialize the official Datadog V1 API client.
 	authV1 := context.WithValue(
@@ -88,7 +107,9 @@ func setupAuth(logger logr.Logger, creds config.Creds) (context.Context, error)
 	)
 
 	apiURL := ""
-	if os.Getenv(config.DDURLEnvVar) != "" {
+	if creds.Site != "" {
+		apiURL = prefix + strings.TrimSpace(creds.Site)
+	} else if os.Getenv(config.DDURLEnvVar) != "" {
 		apiURL = os.Getenv(config.DDURLEnvVar)
 	} else if site := os.Getenv(apicommon.DDSite); site != "" {
 		apiURL = prefix + strings.TrimSpace(site)
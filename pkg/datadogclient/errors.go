@@ -0,0 +1,40 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogclient
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	datadogapi "github.com/DataDog/datadog-api-client-go/v2/api/datadog"
+)
+
+// TranslateClientError wraps an error returned by the Datadog API client with a message, and with the body of the API
+// response if any. The RateLimitErrors are kept in the chain, so that RetryAfter finds their reset time.
+func TranslateClientError(err error, msg string) error {
+	if msg == "" {
+		msg = "an error occurred"
+	}
+
+	var apiErr datadogapi.GenericOpenAPIError
+	var errURL *url.Error
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &apiErr) {
+		return fmt.Errorf(msg+": %w: %s", err, apiErr.Body())
+	}
+
+	// Keep the rate limit error, its reset time is used to requeue the resource
+	if errors.As(err, &rateLimitErr) {
+		return fmt.Errorf(msg+": %w", rateLimitErr)
+	}
+
+	if errors.As(err, &errURL) {
+		return fmt.Errorf(msg+" (url.Error): %s", errURL)
+	}
+
+	return fmt.Errorf(msg+": %w", err)
+}
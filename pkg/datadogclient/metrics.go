@@ -0,0 +1,39 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogclient
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// throttledByOperator labels the requests held back by the operator because the rate limit was reached
+	throttledByOperator = "operator"
+	// throttledByAPI labels the requests rejected by the Datadog API with a 429 response
+	throttledByAPI = "api"
+)
+
+var (
+	throttledRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "datadog_operator_api_throttled_requests_total",
+			Help: "Number of Datadog API requests throttled because the rate limit of their endpoint was reached",
+		},
+		[]string{"endpoint", "throttled_by"},
+	)
+	rateLimitRemaining = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "datadog_operator_api_rate_limit_remaining",
+			Help: "Number of requests left in the current rate limit period of a Datadog API endpoint",
+		},
+		[]string{"endpoint"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(throttledRequests, rateLimitRemaining)
+}
@@ -0,0 +1,237 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	datadogapi "github.com/DataDog/datadog-api-client-go/v2/api/datadog"
+
+	"github.com/DataDog/datadog-operator/pkg/config"
+)
+
+const (
+	rateLimitLimitHeader     = "X-RateLimit-Limit"
+	rateLimitPeriodHeader    = "X-RateLimit-Period"
+	rateLimitRemainingHeader = "X-RateLimit-Remaining"
+	rateLimitResetHeader     = "X-RateLimit-Reset"
+
+	// defaultRateLimitRetryAfter is used when a 429 response holds no rate limit headers.
+	defaultRateLimitRetryAfter = 10 * time.Second
+)
+
+// RateLimitError is returned when the rate limit of a Datadog API endpoint is reached: either the operator did not send
+// the request because the endpoint has no request left in the current period, or the API responded 429.
+type RateLimitError struct {
+	Endpoint   string
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit of the Datadog API endpoint %s reached, retry in %s", e.Endpoint, e.RetryAfter)
+}
+
+// RetryAfter returns the time until the rate limit is reset if err is, or wraps, a RateLimitError.
+func RetryAfter(err error) (time.Duration, bool) {
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return rateLimitErr.RetryAfter, true
+	}
+	return 0, false
+}
+
+// rateLimiters holds the rate limiter of each Datadog organization, identified by its credentials. The rate limits of
+// the Datadog API apply to an organization, so all the clients using its credentials share its limiter, whichever
+// controller they belong to.
+var rateLimiters = struct {
+	sync.Mutex
+	limiters map[config.Creds]*rateLimiter
+}{limiters: map[config.Creds]*rateLimiter{}}
+
+// newAPIClient returns a Datadog API client whose requests go through the rate limiter of the credentials.
+func newAPIClient(creds config.Creds) *datadogapi.APIClient {
+	configuration := datadogapi.NewConfiguration()
+	configuration.HTTPClient = &http.Client{
+		Transport: &rateLimitTransport{
+			next:    http.DefaultTransport,
+			limiter: rateLimiterFor(creds),
+		},
+	}
+	return datadogapi.NewAPIClient(configuration)
+}
+
+func rateLimiterFor(creds config.Creds) *rateLimiter {
+	rateLimiters.Lock()
+	defer rateLimiters.Unlock()
+
+	limiter, found := rateLimiters.limiters[creds]
+	if !found {
+		limiter = newRateLimiter()
+		rateLimiters.limiters[creds] = limiter
+	}
+	return limiter
+}
+
+// rateLimiter keeps a token bucket per Datadog API endpoint. The buckets are sized and refilled according to the
+// X-RateLimit-* headers of the responses, so an endpoint has no limit until it answered once.
+type rateLimiter struct {
+	mutex   sync.Mutex
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+type tokenBucket struct {
+	limit     int
+	period    time.Duration
+	remaining int
+	reset     time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		buckets: map[string]*tokenBucket{},
+		now:     time.Now,
+	}
+}
+
+// take takes a token from the bucket of an endpoint. When the bucket is empty, it returns the time until it is refilled
+// and false.
+func (l *rateLimiter) take(endpoint string) (time.Duration, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	bucket, found := l.buckets[endpoint]
+	if !found {
+		return 0, true
+	}
+
+	now := l.now()
+	if !now.Before(bucket.reset) {
+		bucket.remaining = bucket.limit
+		bucket.reset = now.Add(bucket.period)
+	}
+	if bucket.remaining <= 0 {
+		return bucket.reset.Sub(now), false
+	}
+	bucket.remaining--
+	rateLimitRemaining.WithLabelValues(endpoint).Set(float64(bucket.remaining))
+
+	return 0, true
+}
+
+// update resets the bucket of an endpoint from the rate limit headers of a response. It returns the time until the
+// rate limit is reset, and false if the response holds no rate limit headers.
+func (l *rateLimiter) update(endpoint string, header http.Header) (time.Duration, bool) {
+	remaining, err := strconv.Atoi(header.Get(rateLimitRemainingHeader))
+	if err != nil {
+		return 0, false
+	}
+	resetSeconds, err := strconv.Atoi(header.Get(rateLimitResetHeader))
+	if err != nil {
+		return 0, false
+	}
+	reset := time.Duration(resetSeconds) * time.Second
+
+	bucket := &tokenBucket{
+		limit:     remaining,
+		period:    reset,
+		remaining: remaining,
+	}
+	if limit, err := strconv.Atoi(header.Get(rateLimitLimitHeader)); err == nil {
+		bucket.limit = limit
+	}
+	if periodSeconds, err := strconv.Atoi(header.Get(rateLimitPeriodHeader)); err == nil {
+		bucket.period = time.Duration(periodSeconds) * time.Second
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	bucket.reset = l.now().Add(reset)
+	l.buckets[endpoint] = bucket
+	rateLimitRemaining.WithLabelValues(endpoint).Set(float64(remaining))
+
+	return reset, true
+}
+
+// rateLimitTransport is an http.RoundTripper that holds back the requests to the Datadog API endpoints whose rate limit
+// is reached, instead of sending them and getting 429 responses.
+type rateLimitTransport struct {
+	next    http.RoundTripper
+	limiter *rateLimiter
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := endpointName(req)
+	if retryAfter, ok := t.limiter.take(endpoint); !ok {
+		throttledRequests.WithLabelValues(endpoint, throttledByOperator).Inc()
+		return nil, &RateLimitError{Endpoint: endpoint, RetryAfter: retryAfter}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	retryAfter, found := t.limiter.update(endpoint, resp.Header)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		throttledRequests.WithLabelValues(endpoint, throttledByAPI).Inc()
+		_ = resp.Body.Close()
+		if !found || retryAfter <= 0 {
+			retryAfter = defaultRateLimitRetryAfter
+		}
+		return nil, &RateLimitError{Endpoint: endpoint, RetryAfter: retryAfter}
+	}
+
+	return resp, nil
+}
+
+// endpointName returns the method and path of a request, with the resource IDs replaced by a placeholder so that the
+// requests to the same endpoint share its rate limit.
+func endpointName(req *http.Request) string {
+	segments := strings.Split(req.URL.Path, "/")
+	for i, segment := range segments {
+		if isResourceID(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	return req.Method + " " + strings.Join(segments, "/")
+}
+
+// isResourceID returns true for numeric IDs (monitors), 32 characters hexadecimal IDs (SLOs) and UUIDs (downtimes).
+func isResourceID(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	if _, err := strconv.ParseUint(segment, 10, 64); err == nil {
+		return true
+	}
+	if len(segment) == 36 {
+		for _, i := range []int{8, 13, 18, 23} {
+			if segment[i] != '-' {
+				return false
+			}
+		}
+		segment = strings.ReplaceAll(segment, "-", "")
+	}
+	if len(segment) != 32 {
+		return false
+	}
+	for _, c := range segment {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-operator/pkg/config"
+)
+
+func TestRateLimitTransport(t *testing.T) {
+	var calls int32
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/monitor" {
+			w.Header().Set(rateLimitRemainingHeader, "9")
+			w.Header().Set(rateLimitResetHeader, "4")
+			return
+		}
+		remaining := 2 - atomic.AddInt32(&calls, 1)
+		w.Header().Set(rateLimitLimitHeader, "2")
+		w.Header().Set(rateLimitPeriodHeader, "10")
+		w.Header().Set(rateLimitRemainingHeader, fmt.Sprint(remaining))
+		w.Header().Set(rateLimitResetHeader, "4")
+		if remaining < 0 {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer httpServer.Close()
+
+	now := time.Now()
+	limiter := newRateLimiter()
+	limiter.now = func() time.Time { return now }
+	client := &http.Client{Transport: &rateLimitTransport{next: http.DefaultTransport, limiter: limiter}}
+
+	get := func(path string) error {
+		resp, err := client.Get(httpServer.URL + path)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	// The requests are sent while the endpoint has requests left
+	require.NoError(t, get("/api/v1/monitor/1"))
+	require.NoError(t, get("/api/v1/monitor/2"))
+
+	// The endpoint has no request left: the request is not sent
+	err := get("/api/v1/monitor/3")
+	retryAfter, throttled := RetryAfter(err)
+	assert.True(t, throttled)
+	assert.Equal(t, 4*time.Second, retryAfter)
+	assert.EqualError(t, err, fmt.Sprintf("Get %q: rate limit of the Datadog API endpoint GET /api/v1/monitor/{id} reached, retry in 4s", httpServer.URL+"/api/v1/monitor/3"))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// The other endpoints have their own limit
+	require.NoError(t, get("/api/v1/monitor"))
+
+	// The bucket is refilled once the rate limit is reset, and the API responses are still followed
+	now = now.Add(5 * time.Second)
+	err = get("/api/v1/monitor/4")
+	retryAfter, throttled = RetryAfter(err)
+	assert.True(t, throttled)
+	assert.Equal(t, 4*time.Second, retryAfter)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestRateLimitTransportTooManyRequests(t *testing.T) {
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer httpServer.Close()
+
+	client := &http.Client{Transport: &rateLimitTransport{next: http.DefaultTransport, limiter: newRateLimiter()}}
+
+	// Without rate limit headers, the request is retried after the default delay
+	_, err := client.Post(httpServer.URL+"/api/v1/slo", "application/json", nil)
+	retryAfter, throttled := RetryAfter(err)
+	assert.True(t, throttled)
+	assert.Equal(t, defaultRateLimitRetryAfter, retryAfter)
+}
+
+func TestRateLimiterFor(t *testing.T) {
+	creds := config.Creds{APIKey: "api-key", AppKey: "app-key"}
+	otherAppKey := config.Creds{APIKey: "api-key", AppKey: "other-app-key"}
+	otherOrg := config.Creds{APIKey: "other-api-key", AppKey: "app-key"}
+
+	assert.Same(t, rateLimiterFor(creds), rateLimiterFor(creds))
+	assert.NotSame(t, rateLimiterFor(creds), rateLimiterFor(otherAppKey))
+	assert.NotSame(t, rateLimiterFor(creds), rateLimiterFor(otherOrg))
+}
+
+func TestEndpointName(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{method: http.MethodGet, path: "/api/v1/monitor", want: "GET /api/v1/monitor"},
+		{method: http.MethodPut, path: "/api/v1/monitor/1234", want: "PUT /api/v1/monitor/{id}"},
+		{method: http.MethodDelete, path: "/api/v1/slo/0123456789abcdef0123456789abcdef", want: "DELETE /api/v1/slo/{id}"},
+		{method: http.MethodPost, path: "/api/v1/monitor/validate", want: "POST /api/v1/monitor/validate"},
+		{method: http.MethodDelete, path: "/api/v1/downtime/cancel/by_scope", want: "DELETE /api/v1/downtime/cancel/by_scope"},
+		{method: http.MethodPatch, path: "/api/v2/downtime/3ea2e1a4-cb6a-11ee-9b36-da7ad0900002", want: "PATCH /api/v2/downtime/{id}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, "https://api.datadoghq.com"+tt.path, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, endpointName(req))
+		})
+	}
+}
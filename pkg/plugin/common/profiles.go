@@ -0,0 +1,61 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/pkg/agentprofile"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultProfileDisplayName is the name displayed for the default profile, applied on the nodes without a profile.
+const DefaultProfileDisplayName = "default"
+
+// ListProfilesAndNodes returns the DatadogAgentProfiles of all the namespaces and the nodes of the cluster.
+// The profiles of all the namespaces are returned since they are evaluated together.
+func ListProfilesAndNodes(ctx context.Context, c client.Client) ([]v1alpha1.DatadogAgentProfile, []corev1.Node, error) {
+	profileList := &v1alpha1.DatadogAgentProfileList{}
+	if err := c.List(ctx, profileList); err != nil {
+		return nil, nil, fmt.Errorf("unable to list DatadogAgentProfiles: %w", err)
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := c.List(ctx, nodeList); err != nil {
+		return nil, nil, fmt.Errorf("unable to list nodes: %w", err)
+	}
+
+	return profileList.Items, nodeList.Items, nil
+}
+
+// ListDatadogAgents returns the v2alpha1 DatadogAgents of all the namespaces, which the profiles can target.
+func ListDatadogAgents(ctx context.Context, c client.Client) ([]metav1.Object, error) {
+	ddaList := &v2alpha1.DatadogAgentList{}
+	if err := c.List(ctx, ddaList); err != nil {
+		return nil, fmt.Errorf("unable to list DatadogAgents: %w", err)
+	}
+
+	ddas := make([]metav1.Object, 0, len(ddaList.Items))
+	for i := range ddaList.Items {
+		ddas = append(ddas, &ddaList.Items[i])
+	}
+	return ddas, nil
+}
+
+// ProfileDisplayName returns the name of a profile as displayed by the profiles commands.
+func ProfileDisplayName(profile types.NamespacedName) string {
+	if agentprofile.IsDefaultProfile(profile.Namespace, profile.Name) {
+		return DefaultProfileDisplayName
+	}
+	return profile.String()
+}
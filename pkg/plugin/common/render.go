@@ -0,0 +1,104 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package common
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	edsv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+const yamlSeparator = "---\n"
+
+// NewRenderScheme returns a scheme with all the types that the operator can create for a DatadogAgent.
+func NewRenderScheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(s))
+	utilruntime.Must(apiregistrationv1.AddToScheme(s))
+	utilruntime.Must(v1alpha1.AddToScheme(s))
+	utilruntime.Must(v2alpha1.AddToScheme(s))
+	utilruntime.Must(edsv1alpha1.AddToScheme(s))
+	return s
+}
+
+// DecodeDatadogAgent decodes a v2alpha1 DatadogAgent manifest.
+func DecodeDatadogAgent(data []byte) (*v2alpha1.DatadogAgent, error) {
+	dda := &v2alpha1.DatadogAgent{}
+	if err := yaml.UnmarshalStrict(data, dda); err != nil {
+		return nil, fmt.Errorf("unable to decode the DatadogAgent: %w", err)
+	}
+
+	gvk := dda.GroupVersionKind()
+	if gvk.GroupVersion() != v2alpha1.GroupVersion || gvk.Kind != "DatadogAgent" {
+		return nil, fmt.Errorf("unsupported object %s, only %s DatadogAgent is supported", gvk.String(), v2alpha1.GroupVersion.String())
+	}
+	if dda.Name == "" {
+		return nil, fmt.Errorf("the DatadogAgent name is missing")
+	}
+
+	return dda, nil
+}
+
+// DecodeDatadogAgentProfile decodes a v1alpha1 DatadogAgentProfile manifest.
+func DecodeDatadogAgentProfile(data []byte) (*v1alpha1.DatadogAgentProfile, error) {
+	profile := &v1alpha1.DatadogAgentProfile{}
+	if err := yaml.UnmarshalStrict(data, profile); err != nil {
+		return nil, fmt.Errorf("unable to decode the DatadogAgentProfile: %w", err)
+	}
+
+	gvk := profile.GroupVersionKind()
+	if gvk.GroupVersion() != v1alpha1.GroupVersion || gvk.Kind != "DatadogAgentProfile" {
+		return nil, fmt.Errorf("unsupported object %s, only %s DatadogAgentProfile is supported", gvk.String(), v1alpha1.GroupVersion.String())
+	}
+	if profile.Name == "" {
+		return nil, fmt.Errorf("the DatadogAgentProfile name is missing")
+	}
+
+	return profile, nil
+}
+
+// PrintObjectsYAML writes the objects as a multi-document YAML stream.
+// The apiVersion and kind of the objects are set from the scheme.
+func PrintObjectsYAML(out io.Writer, objs []client.Object, scheme *runtime.Scheme) error {
+	for _, obj := range objs {
+		data, err := MarshalObjectYAML(obj, scheme)
+		if err != nil {
+			return err
+		}
+		if _, err = fmt.Fprint(out, yamlSeparator+string(data)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MarshalObjectYAML returns the YAML representation of an object, with its apiVersion and kind.
+func MarshalObjectYAML(obj client.Object, scheme *runtime.Scheme) ([]byte, error) {
+	if obj.GetObjectKind().GroupVersionKind().Empty() {
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the kind of %s: %w", obj.GetName(), err)
+		}
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+	}
+
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode %s: %w", obj.GetName(), err)
+	}
+	return data, nil
+}